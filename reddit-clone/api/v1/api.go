@@ -1,13 +1,33 @@
 // api/v1/api.go
 package api
 
-import "time"
+import (
+    "strconv"
+    "time"
+)
 
 // Request types
 type RegisterRequest struct {
     Username  string `json:"username"`
     Password  string `json:"password"`
     PublicKey string `json:"public_key,omitempty"` // For bonus feature
+    Email     string `json:"email,omitempty"`      // Optional; the account starts unverified
+}
+
+type ChangeUsernameRequest struct {
+    Username string `json:"username"`
+}
+
+type PreferencesRequest struct {
+    FeedSort string `json:"feed_sort"` // "new", "personalized", or empty to reset
+}
+
+type PreferencesResponse struct {
+    FeedSort string `json:"feed_sort"`
+}
+
+type VerifyEmailRequest struct {
+    Token string `json:"token"`
 }
 
 type LoginRequest struct {
@@ -22,115 +42,491 @@ type LoginResponse struct {
 type SubredditRequest struct {
     Name        string `json:"name"`
     Description string `json:"description"`
+    MaxMembers  int64  `json:"max_members,omitempty"` // 0 means unlimited
+    Type        string `json:"type,omitempty"`        // "public" (default), "restricted" or "private"
+    QueuePosts  bool   `json:"queue_posts,omitempty"` // Restricted only: queue posts from non-approved users for review
+
+    AllowCrossposts *bool `json:"allow_crossposts,omitempty"` // Defaults to true
+    AllowAnonymous  bool  `json:"allow_anonymous,omitempty"`  // Let posts and comments hide their author
+}
+
+// UpdateSubredditRequest changes only the fields that are present
+type UpdateSubredditRequest struct {
+    Description *string `json:"description,omitempty"`
+    MaxMembers  *int64  `json:"max_members,omitempty"`
+    Type        *string `json:"type,omitempty"`
+    QueuePosts  *bool   `json:"queue_posts,omitempty"`
+
+    AllowCrossposts *bool `json:"allow_crossposts,omitempty"`
+    AllowAnonymous  *bool `json:"allow_anonymous,omitempty"`
 }
 
 type PostRequest struct {
-    Title       string `json:"title"`
-    Content     string `json:"content"`
-    SubredditID string `json:"subreddit_id"`
-    Signature   string `json:"signature,omitempty"` // For bonus feature
+    Title         string `json:"title"`
+    Content       string `json:"content"`
+    SubredditID   string `json:"subreddit_id"`
+    Signature     string `json:"signature,omitempty"`     // For bonus feature
+    Distinguished bool   `json:"distinguished,omitempty"` // Moderators only
+    Flair         string `json:"flair,omitempty"`         // One of the subreddit's flairs
+    NSFW          bool   `json:"nsfw,omitempty"`
+    Anonymous     bool   `json:"anonymous,omitempty"` // Only where the subreddit allows it
+
+    // ScheduledFor holds the post back until then; it must be in the future
+    ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
 }
 
 type CommentRequest struct {
-    Content    string  `json:"content"`
-    PostID     string  `json:"post_id"`
-    ParentID   *string `json:"parent_id,omitempty"`
+    Content       string  `json:"content"`
+    PostID        string  `json:"post_id"`
+    ParentID      *string `json:"parent_id,omitempty"`
+    Distinguished bool    `json:"distinguished,omitempty"` // Moderators only
+    Anonymous     bool    `json:"anonymous,omitempty"`     // Only where the subreddit allows it
+}
+
+// EditPostRequest replaces a post's title and content. Version is the
+// post's version as last read; when given, the edit is refused with 409
+// if someone else edited the post since.
+type EditPostRequest struct {
+    Title   string `json:"title"`
+    Content string `json:"content"`
+    Version *int64 `json:"version,omitempty"`
+}
+
+// EditCommentRequest replaces a comment's content; Version works as in
+// EditPostRequest
+type EditCommentRequest struct {
+    Content string `json:"content"`
+    Version *int64 `json:"version,omitempty"`
 }
 
+// VoteRequest casts a vote. POST treats is_upvote like clicking an arrow,
+// so repeating the current vote retracts it. PUT sets the vote outright:
+// direction (1, 0 or -1) when given, otherwise is_upvote.
 type VoteRequest struct {
-    IsUpvote bool `json:"is_upvote"`
+    IsUpvote  bool `json:"is_upvote"`
+    Direction *int `json:"direction,omitempty"` // PUT only; 0 retracts
+}
+
+// VoteResponse reports the target's counts after the vote
+type VoteResponse struct {
+    Upvotes   int64 `json:"upvotes"`
+    Downvotes int64 `json:"downvotes"`
+    Score     int64 `json:"score"`
+    UserVote  int   `json:"user_vote"` // 1 upvoted, -1 downvoted, 0 no vote
 }
 
 type MessageRequest struct {
-    ToID    string `json:"to_id"`
+    ToID       string `json:"to_id"`
+    Content    string `json:"content"`
+    TTLSeconds int64  `json:"ttl_seconds,omitempty"` // 0 means the message never expires
+}
+
+type EditMessageRequest struct {
     Content string `json:"content"`
 }
 
+type ReportRequest struct {
+    Reason string `json:"reason"`
+}
+
 // Response types
 type UserResponse struct {
     ID        string    `json:"id"`
     Username  string    `json:"username"`
     Karma     int64     `json:"karma"`
     CreatedAt time.Time `json:"created_at"`
+    Email     string    `json:"email,omitempty"`
+    Verified  bool      `json:"verified"`
+
+    // VerificationToken is only returned by registration with an email. It
+    // stands in for the link a real deployment would email to the user.
+    VerificationToken string `json:"verification_token,omitempty"`
 }
 
 type SubredditResponse struct {
     ID          string    `json:"id"`
     Name        string    `json:"name"`
     Description string    `json:"description"`
+    Type        string    `json:"type"`
+    QueuePosts  bool      `json:"queue_posts"`
     MemberCount int64     `json:"member_count"`
+    MaxMembers  int64     `json:"max_members,omitempty"`
     CreatorID   string    `json:"creator_id"`
     CreatedAt   time.Time `json:"created_at"`
+
+    AllowCrossposts bool `json:"allow_crossposts"`
+    Archived        bool `json:"archived"`
+    AllowAnonymous  bool `json:"allow_anonymous"`
 }
 
-type PostResponse struct {
+// FlairsRequest replaces a subreddit's allowed post flairs
+type FlairsRequest struct {
+    Flairs []string `json:"flairs"`
+}
+
+type FlairsResponse struct {
+    SubredditID string   `json:"subreddit_id"`
+    Flairs      []string `json:"flairs"`
+}
+
+// ArchiveRequest archives a subreddit, making it read-only, or unarchives it
+type ArchiveRequest struct {
+    Archived bool `json:"archived"`
+}
+
+// BannedWordsRequest replaces a subreddit's banned words and phrases
+type BannedWordsRequest struct {
+    Words []string `json:"words"`
+}
+
+type BannedWordsResponse struct {
+    SubredditID string   `json:"subreddit_id"`
+    Words       []string `json:"words"`
+}
+
+// WebhookRequest registers a URL for a subreddit's new posts and comments
+type WebhookRequest struct {
+    URL string `json:"url"`
+}
+
+type WebhookResponse struct {
     ID          string    `json:"id"`
-    Title       string    `json:"title"`
-    Content     string    `json:"content"`
-    AuthorID    string    `json:"author_id"`
     SubredditID string    `json:"subreddit_id"`
-    Upvotes     int64     `json:"upvotes"`
-    Downvotes   int64     `json:"downvotes"`
-    CommentCount int64    `json:"comment_count"`
+    URL         string    `json:"url"`
     CreatedAt   time.Time `json:"created_at"`
-    Signature   string    `json:"signature,omitempty"` // For bonus feature
+}
+
+type JoinResponse struct {
+    Status      string `json:"status"`
+    SubredditID string `json:"subreddit_id"`
+    Joined      bool   `json:"joined"` // false if the user was already a member
+}
+
+type PostResponse struct {
+    ID            string           `json:"id"`
+    Title         string           `json:"title"`
+    RawTitle      string           `json:"raw_title,omitempty"` // As written, when the title was sanitized for display
+    Slug          string           `json:"slug"`
+    Content       string           `json:"content"`
+    RawContent    string           `json:"raw_content,omitempty"` // As written, when content was sanitized for display
+    AuthorID      string           `json:"author_id"`
+    SubredditID   string           `json:"subreddit_id"`
+    Upvotes       int64            `json:"upvotes"`
+    Downvotes     int64            `json:"downvotes"`
+    Score         int64            `json:"score"` // upvotes - downvotes
+    CommentCount  int64            `json:"comment_count"`
+    CreatedAt     time.Time        `json:"created_at"`
+    Signature     string           `json:"signature,omitempty"` // For bonus feature
+    UserVote      int              `json:"user_vote,omitempty"` // 1 upvoted, -1 downvoted, 0 no vote
+    Edited        bool             `json:"edited"`
+    Version       int64            `json:"version"`
+    Distinguished bool             `json:"distinguished"`
+    TopComment    *CommentResponse `json:"top_comment,omitempty"` // Feed previews only
+    Flair         string           `json:"flair,omitempty"`
+    NSFW          bool             `json:"nsfw"`
+    Pending       bool             `json:"pending,omitempty"`      // Awaiting moderator approval
+    ContestMode   bool             `json:"contest_mode"`           // Comments are shuffled and show no votes
+    AutoRemoved   bool             `json:"auto_removed,omitempty"` // Voted below the removal threshold; hidden from listings
+    Anonymous     bool             `json:"anonymous,omitempty"`    // Author hidden from all but moderators
+    Pinned        bool             `json:"pinned,omitempty"`       // Listed first in its subreddit
+    Locked        bool             `json:"locked,omitempty"`       // Only moderators may comment
+
+    // ScheduledFor is set on scheduled posts, which only their author sees
+    ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
 }
 
 type CommentResponse struct {
-    ID        string    `json:"id"`
-    Content   string    `json:"content"`
-    AuthorID  string    `json:"author_id"`
-    PostID    string    `json:"post_id"`
-    ParentID  *string   `json:"parent_id"`
-    Depth     int32     `json:"depth"`
-    Upvotes   int64     `json:"upvotes"`
-    Downvotes int64     `json:"downvotes"`
-    CreatedAt time.Time `json:"created_at"`
+    ID            string    `json:"id"`
+    Content       string    `json:"content"`
+    RawContent    string    `json:"raw_content,omitempty"` // As written, when content was sanitized for display
+    AuthorID      string    `json:"author_id"`
+    PostID        string    `json:"post_id"`
+    ParentID      *string   `json:"parent_id"`
+    Depth         int32     `json:"depth"`
+    Upvotes       int64     `json:"upvotes"`
+    Downvotes     int64     `json:"downvotes"`
+    Score         int64     `json:"score"` // upvotes - downvotes
+    CreatedAt     time.Time `json:"created_at"`
+    Edited        bool      `json:"edited"`
+    Version       int64     `json:"version"`
+    Distinguished bool      `json:"distinguished"`
+    AutoRemoved   bool      `json:"auto_removed,omitempty"` // Voted below the removal threshold; hidden from listings
+    Anonymous     bool      `json:"anonymous,omitempty"`    // Author hidden from all but moderators
+
+    // UpdatedAt is when the author last edited the comment; nil if never
+    UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+    // Set on the deepest comments of a tree cut off with ?depth=; fetch
+    // the rest through /comments/{id}/replies
+    HasMoreReplies   bool `json:"has_more_replies,omitempty"`
+    RemainingReplies int  `json:"remaining_replies,omitempty"`
+
+    // Set on comments scored below the collapse threshold and on their
+    // replies; clients show them folded until the reader expands them
+    Collapsed   bool `json:"collapsed,omitempty"`
+    InCollapsed bool `json:"in_collapsed,omitempty"`
+}
+
+type EditRecordResponse struct {
+    PreviousTitle   string    `json:"previous_title,omitempty"`
+    PreviousContent string    `json:"previous_content"`
+    EditedAt        time.Time `json:"edited_at"`
+}
+
+type SubredditStatsResponse struct {
+    SubredditID  string `json:"subreddit_id"`
+    Name         string `json:"name"`
+    MemberCount  int64  `json:"member_count"`
+    PostCount    int64  `json:"post_count"`
+    CommentCount int64  `json:"comment_count"`
+    VoteCount    int64  `json:"vote_count"`
+}
+
+// ContributorResponse is one author's activity within a subreddit
+type ContributorResponse struct {
+    UserID       string `json:"user_id"`
+    Username     string `json:"username"`
+    PostCount    int64  `json:"post_count"`
+    CommentCount int64  `json:"comment_count"`
+    Karma        int64  `json:"karma"` // Net votes earned in the subreddit
+}
+
+// LeaderboardEntryResponse is one member's karma within a subreddit
+type LeaderboardEntryResponse struct {
+    UserID   string `json:"user_id"`
+    Username string `json:"username"`
+    Karma    int64  `json:"karma"`
+}
+
+// LeaderboardResponse ranks a subreddit's members by karma earned there
+type LeaderboardResponse struct {
+    SubredditID string                     `json:"subreddit_id"`
+    Window      string                     `json:"window,omitempty"` // Empty counts every vote
+    Entries     []LeaderboardEntryResponse `json:"entries"`
+}
+
+// ActivityDayResponse counts a subreddit's activity on one UTC day
+type ActivityDayResponse struct {
+    Date     string `json:"date"` // YYYY-MM-DD
+    Posts    int64  `json:"posts"`
+    Comments int64  `json:"comments"`
+    Votes    int64  `json:"votes"`
+}
+
+// SubredditActivityResponse is a subreddit's activity heatmap, oldest day first
+type SubredditActivityResponse struct {
+    SubredditID string                `json:"subreddit_id"`
+    Days        []ActivityDayResponse `json:"days"`
+}
+
+// TopContributorsResponse ranks a subreddit's most active authors
+type TopContributorsResponse struct {
+    SubredditID  string                `json:"subreddit_id"`
+    Contributors []ContributorResponse `json:"contributors"`
+}
+
+type GlobalStatsResponse struct {
+    TotalUsers      int64 `json:"total_users"`
+    TotalSubreddits int64 `json:"total_subreddits"`
+    TotalPosts      int64 `json:"total_posts"`
+    TotalComments   int64 `json:"total_comments"`
+    TotalVotes      int64 `json:"total_votes"`
+    TotalMessages   int64 `json:"total_messages"`
 }
 
 type MessageResponse struct {
-    ID        string    `json:"id"`
-    FromID    string    `json:"from_id"`
-    ToID      string    `json:"to_id"`
-    Content   string    `json:"content"`
-    IsRead    bool      `json:"is_read"`
-    CreatedAt time.Time `json:"created_at"`
+    ID        string     `json:"id"`
+    FromID    string     `json:"from_id"`
+    ToID      string     `json:"to_id"`
+    Content   string     `json:"content"`
+    IsRead    bool       `json:"is_read"`
+    CreatedAt time.Time  `json:"created_at"`
+    ExpiresAt *time.Time `json:"expires_at,omitempty"`
+    Edited    bool       `json:"edited"`
+    UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type ReportResponse struct {
+    ID          string    `json:"id"`
+    ReporterID  string    `json:"reporter_id"`
+    TargetID    string    `json:"target_id"`
+    TargetType  string    `json:"target_type"`
+    SubredditID string    `json:"subreddit_id"`
+    Reason      string    `json:"reason"`
+    CreatedAt   time.Time `json:"created_at"`
+    Status      string    `json:"status"` // pending, resolved or dismissed
+
+    // The moderator who closed the report, and when
+    ClosedBy string     `json:"closed_by,omitempty"`
+    ClosedAt *time.Time `json:"closed_at,omitempty"`
 }
 
 type FeedResponse struct {
     Posts []PostResponse `json:"posts"`
 }
 
+// MaintenanceRequest turns the engine's read-only maintenance mode on or off
+type MaintenanceRequest struct {
+    ReadOnly bool `json:"read_only"`
+}
+
 type StatusResponse struct {
     Success bool   `json:"success"`
     Message string `json:"message,omitempty"`
 }
 
 type ErrorResponse struct {
-    Error   string `json:"error"`
-    Code    int    `json:"code,omitempty"`
-    Details string `json:"details,omitempty"`
+    Error   string       `json:"error"`
+    Code    string       `json:"code,omitempty"` // One of the Code constants
+    Details []FieldError `json:"details,omitempty"` // Field-level validation problems
 }
 
 // List response types
+// PageInfo describes where a page sits in a paginated list. NextCursor is
+// the page parameter for the next page, set only when there is one.
+type PageInfo struct {
+    Page       int    `json:"page"`
+    Limit      int    `json:"limit"` // 0 means the whole list is on one page
+    Total      int    `json:"total"`
+    HasNext    bool   `json:"has_next"`
+    HasPrev    bool   `json:"has_prev"`
+    NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPageInfo describes one page of a list of total items. Page is
+// 1-based; a limit of 0 puts the whole list on one page.
+func NewPageInfo(page, limit, total int) PageInfo {
+    page = max(page, 1)
+    info := PageInfo{Page: page, Limit: limit, Total: total}
+    if limit > 0 {
+        info.HasPrev = page > 1
+        info.HasNext = page*limit < total
+    }
+    if info.HasNext {
+        info.NextCursor = strconv.Itoa(page + 1)
+    }
+    return info
+}
+
+// AdminUserResponse is a user as the admin directory lists them
+type AdminUserResponse struct {
+    UserResponse
+    Banned bool `json:"banned"`
+}
+
+// AnnouncementRequest broadcasts one pinned post to many subreddits
+type AnnouncementRequest struct {
+    AuthorID     string   `json:"author_id"` // Account the announcement is posted as
+    Title        string   `json:"title"`
+    Content      string   `json:"content"`
+    SubredditIDs []string `json:"subreddit_ids,omitempty"` // Omit for every subreddit
+}
+
+// MergeSubredditsRequest folds the subreddit in the path into TargetID
+type MergeSubredditsRequest struct {
+    AdminID  string `json:"admin_id"` // Operator account the merge is made as
+    TargetID string `json:"target_id"`
+}
+
+// AnnouncementResponse lists the post created in each subreddit
+type AnnouncementResponse struct {
+    Posts []AnnouncementPostResponse `json:"posts"`
+}
+
+type AnnouncementPostResponse struct {
+    PostID      string `json:"post_id"`
+    SubredditID string `json:"subreddit_id"`
+    Slug        string `json:"slug"`
+}
+
+type AdminUserListResponse struct {
+    Users    []AdminUserResponse `json:"users"`
+    Total    int                 `json:"total"` // Matching users across all pages
+    PageInfo PageInfo            `json:"page_info"`
+}
+
 type SubredditListResponse struct {
     Subreddits []SubredditResponse `json:"subreddits"`
     Total      int                 `json:"total"`
+    PageInfo   PageInfo            `json:"page_info"`
 }
 
 type PostListResponse struct {
-    Posts []PostResponse `json:"posts"`
-    Total int           `json:"total"`
+    Posts    []PostResponse `json:"posts"`
+    Total    int            `json:"total"`
+    PageInfo PageInfo       `json:"page_info"`
 }
 
 type CommentListResponse struct {
     Comments []CommentResponse `json:"comments"`
-    Total    int              `json:"total"`
+    Total    int              `json:"total"` // Top-level comments (or direct replies) across all pages
+    PageInfo PageInfo         `json:"page_info"`
 }
 
 type MessageListResponse struct {
     Messages []MessageResponse `json:"messages"`
-    Total    int              `json:"total"`
+    Total    int              `json:"total"`  // Messages across all pages
+    Unread   int              `json:"unread"` // Received messages not yet read
+    PageInfo PageInfo         `json:"page_info"`
+}
+
+// LimitsResponse lists the limits the server validates requests against,
+// so clients can check input before submitting it. A maximum of 0 means
+// no limit.
+type LimitsResponse struct {
+    MinUsernameLength      int `json:"min_username_length"`
+    MaxUsernameLength      int `json:"max_username_length"`
+    MinPasswordLength      int `json:"min_password_length"`
+    MinSubredditNameLength int `json:"min_subreddit_name_length"`
+    MaxSubredditNameLength int `json:"max_subreddit_name_length"`
+    MaxDescriptionLength   int `json:"max_description_length"`
+    MaxTitleLength         int `json:"max_title_length"`
+    MaxPostContentLength   int `json:"max_post_content_length"`
+    MaxCommentLength       int `json:"max_comment_length"`
+    MaxMessageLength       int `json:"max_message_length"`
+    MaxReportReasonLength  int `json:"max_report_reason_length"`
+    MaxCommentDepth        int `json:"max_comment_depth"`
+    MaxSubredditsPerUser   int `json:"max_subreddits_per_user"`
+}
+
+// VoteHistoryResponse is one of the user's votes with a line describing
+// what was voted on
+type VoteHistoryResponse struct {
+    TargetID   string    `json:"target_id"`
+    TargetType string    `json:"target_type"` // "post" or "comment"
+    Summary    string    `json:"summary"`     // Post title or the start of the comment
+    Direction  int       `json:"direction"`   // 1 upvote, -1 downvote
+    CreatedAt  time.Time `json:"created_at"`
+}
+
+type VoteHistoryListResponse struct {
+    Votes    []VoteHistoryResponse `json:"votes"`
+    Total    int                   `json:"total"` // Votes across all pages
+    PageInfo PageInfo              `json:"page_info"`
+}
+
+// UserDataExport is a copy of everything stored about the requesting user
+type UserDataExport struct {
+    ExportedAt    time.Time             `json:"exported_at"`
+    User          UserResponse          `json:"user"`
+    Posts         []PostResponse        `json:"posts"`
+    Comments      []CommentResponse     `json:"comments"`
+    Votes         []VoteHistoryResponse `json:"votes"`
+    Messages      []MessageResponse     `json:"messages"` // Sent or received by the user
+    Subscriptions []SubredditResponse   `json:"subscriptions"`
+}
+
+// MarkAllReadResponse reports how many messages a bulk mark-read changed
+type MarkAllReadResponse struct {
+    Updated int `json:"updated"`
+}
+
+// DigestResponse holds the best posts and comments from the user's
+// subreddits since a point in time
+type DigestResponse struct {
+    Since    time.Time         `json:"since"`
+    Posts    []PostResponse    `json:"posts"`
+    Comments []CommentResponse `json:"comments"`
 }
 
 // Search request/response
@@ -0,0 +1,27 @@
+// api/v1/api_test.go
+package api
+
+import "testing"
+
+func TestNewPageInfo(t *testing.T) {
+    tests := []struct {
+        name               string
+        page, limit, total int
+        want               PageInfo
+    }{
+        {"first page", 1, 10, 25, PageInfo{Page: 1, Limit: 10, Total: 25, HasNext: true, NextCursor: "2"}},
+        {"middle page", 2, 10, 25, PageInfo{Page: 2, Limit: 10, Total: 25, HasNext: true, HasPrev: true, NextCursor: "3"}},
+        {"last page", 3, 10, 25, PageInfo{Page: 3, Limit: 10, Total: 25, HasPrev: true}},
+        {"exactly full last page", 2, 10, 20, PageInfo{Page: 2, Limit: 10, Total: 20, HasPrev: true}},
+        {"past the end", 5, 10, 25, PageInfo{Page: 5, Limit: 10, Total: 25, HasPrev: true}},
+        {"only page", 1, 10, 4, PageInfo{Page: 1, Limit: 10, Total: 4}},
+        {"empty list", 1, 10, 0, PageInfo{Page: 1, Limit: 10}},
+        {"page 0 means the first", 0, 10, 25, PageInfo{Page: 1, Limit: 10, Total: 25, HasNext: true, NextCursor: "2"}},
+        {"no limit", 3, 0, 25, PageInfo{Page: 3, Total: 25}},
+    }
+    for _, tt := range tests {
+        if got := NewPageInfo(tt.page, tt.limit, tt.total); got != tt.want {
+            t.Errorf("%s: NewPageInfo(%d, %d, %d) = %+v, want %+v", tt.name, tt.page, tt.limit, tt.total, got, tt.want)
+        }
+    }
+}
\ No newline at end of file
@@ -0,0 +1,78 @@
+// api/v1/errors.go
+package api
+
+import "net/http"
+
+// Error codes set in ErrorResponse.Code. Clients should branch on these
+// rather than on the human-readable message, which may change.
+const (
+    // Generic codes, one per HTTP status the API returns
+    CodeBadRequest       = "BAD_REQUEST"
+    CodeValidationFailed = "VALIDATION_FAILED"
+    CodeUnauthorized     = "UNAUTHORIZED"
+    CodeForbidden        = "FORBIDDEN"
+    CodeNotFound         = "NOT_FOUND"
+    CodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+    CodeConflict         = "CONFLICT"
+    CodeRateLimited      = "RATE_LIMITED"
+    CodeInternal         = "INTERNAL"
+    CodeTimeout          = "TIMEOUT"
+
+    // Specific codes for failures a client may want to handle on their own
+    CodeUserExists        = "USER_EXISTS"
+    CodeUserBanned        = "USER_BANNED"
+    CodeNotMember         = "NOT_MEMBER"
+    CodeNotModerator      = "NOT_MODERATOR"
+    CodeNotAuthor         = "NOT_AUTHOR"
+    CodeNotCreator        = "NOT_CREATOR"
+    CodeNotSender         = "NOT_SENDER"
+    CodeSubredditFull     = "SUBREDDIT_FULL"
+    CodeSubredditLimit    = "SUBREDDIT_LIMIT"
+    CodeDuplicatePost     = "DUPLICATE_POST"
+    CodeAlreadyReported   = "ALREADY_REPORTED"
+    CodeInvalidFlair      = "INVALID_FLAIR"
+    CodeEditWindowExpired = "EDIT_WINDOW_EXPIRED"
+    CodeEmailNotVerified  = "EMAIL_NOT_VERIFIED"
+    CodeInvalidToken      = "INVALID_TOKEN"
+    CodeMaintenance       = "MAINTENANCE"
+    CodeSubredditPrivate  = "SUBREDDIT_PRIVATE"
+    CodeNotApproved       = "NOT_APPROVED"
+    CodePostPending       = "POST_PENDING"
+    CodePostNotPending    = "POST_NOT_PENDING"
+    CodeBannedWord        = "BANNED_WORD"
+    CodeVersionConflict   = "VERSION_CONFLICT"
+    CodeAccountTooNew     = "ACCOUNT_TOO_NEW"
+    CodeArchived          = "SUBREDDIT_ARCHIVED"
+    CodeNotAutoRemoved    = "NOT_AUTO_REMOVED"
+    CodeNoAnonymous       = "ANONYMOUS_NOT_ALLOWED"
+    CodePostScheduled     = "POST_SCHEDULED"
+    CodePostNotScheduled  = "POST_NOT_SCHEDULED"
+    CodePostLocked        = "POST_LOCKED"
+    CodeReportNotPending  = "REPORT_NOT_PENDING"
+)
+
+// CodeForStatus returns the generic error code for an HTTP status
+func CodeForStatus(status int) string {
+    switch status {
+    case http.StatusUnauthorized:
+        return CodeUnauthorized
+    case http.StatusForbidden:
+        return CodeForbidden
+    case http.StatusNotFound:
+        return CodeNotFound
+    case http.StatusMethodNotAllowed:
+        return CodeMethodNotAllowed
+    case http.StatusConflict:
+        return CodeConflict
+    case http.StatusUnprocessableEntity:
+        return CodeValidationFailed
+    case http.StatusTooManyRequests:
+        return CodeRateLimited
+    case http.StatusServiceUnavailable:
+        return CodeTimeout
+    }
+    if status >= http.StatusInternalServerError {
+        return CodeInternal
+    }
+    return CodeBadRequest
+}
\ No newline at end of file
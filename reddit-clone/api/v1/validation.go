@@ -0,0 +1,298 @@
+// api/v1/validation.go
+package api
+
+import (
+    "fmt"
+    "regexp"
+    "strings"
+    "unicode/utf8"
+)
+
+// Field limits enforced by the request Validate methods. The title,
+// content, comment and message maximums are defaults a server may change
+// through Limits.
+const (
+    MinUsernameLength      = 3
+    MaxUsernameLength      = 20
+    MinPasswordLength      = 8
+    MinSubredditNameLength = 3
+    MaxSubredditNameLength = 21
+    MaxDescriptionLength   = 500
+    MaxTitleLength         = 300
+    MaxPostContentLength   = 40000
+    MaxCommentLength       = 10000
+    MaxMessageLength       = 10000
+    MaxReportReasonLength  = 500
+)
+
+var (
+    usernamePattern      = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+    subredditNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+    emailPattern         = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// Validator is implemented by request types that can check their own fields
+type Validator interface {
+    Validate() error
+}
+
+// Limits are the configurable maximum lengths, in characters, of user
+// submitted text
+type Limits struct {
+    MaxTitleLength       int
+    MaxPostContentLength int
+    MaxCommentLength     int
+    MaxMessageLength     int
+}
+
+// DefaultLimits returns the limits Validate enforces
+func DefaultLimits() Limits {
+    return Limits{
+        MaxTitleLength:       MaxTitleLength,
+        MaxPostContentLength: MaxPostContentLength,
+        MaxCommentLength:     MaxCommentLength,
+        MaxMessageLength:     MaxMessageLength,
+    }
+}
+
+// LimitsValidator is implemented by request types whose checks depend on
+// Limits; their Validate uses DefaultLimits
+type LimitsValidator interface {
+    ValidateLimits(limits Limits) error
+}
+
+// FieldError describes a problem with a single request field
+type FieldError struct {
+    Field   string `json:"field"`
+    Message string `json:"message"`
+}
+
+// ValidationError collects every field problem found in a request
+type ValidationError struct {
+    Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+    msgs := make([]string, len(e.Fields))
+    for i, f := range e.Fields {
+        msgs[i] = f.Field + ": " + f.Message
+    }
+    return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+// fieldChecker accumulates field errors while a request is validated
+type fieldChecker struct {
+    fields []FieldError
+}
+
+func (c *fieldChecker) fail(field, format string, args ...interface{}) {
+    c.fields = append(c.fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// required checks a string is not blank
+func (c *fieldChecker) required(field, value string) bool {
+    if strings.TrimSpace(value) == "" {
+        c.fail(field, "is required")
+        return false
+    }
+    return true
+}
+
+// length checks a string's length in characters is within [min, max]; max 0 means no upper bound
+func (c *fieldChecker) length(field, value string, min, max int) {
+    n := utf8.RuneCountInString(value)
+    if n < min {
+        c.fail(field, "must be at least %d characters", min)
+    } else if max > 0 && n > max {
+        c.fail(field, "must be at most %d characters", max)
+    }
+}
+
+// username checks a username's length and characters
+func (c *fieldChecker) username(field, value string) {
+    if c.required(field, value) {
+        c.length(field, value, MinUsernameLength, MaxUsernameLength)
+        if !usernamePattern.MatchString(value) {
+            c.fail(field, "may only contain letters, digits, '_' and '-'")
+        }
+    }
+}
+
+// nonNegative checks a number is zero or more
+func (c *fieldChecker) nonNegative(field string, value int64) {
+    if value < 0 {
+        c.fail(field, "must not be negative")
+    }
+}
+
+// subredditType checks a subreddit type is one the engine knows
+func (c *fieldChecker) subredditType(field, value string) {
+    switch value {
+    case "public", "restricted", "private":
+    default:
+        c.fail(field, "must be public, restricted or private")
+    }
+}
+
+// err returns a *ValidationError if any check failed
+func (c *fieldChecker) err() error {
+    if len(c.fields) == 0 {
+        return nil
+    }
+    return &ValidationError{Fields: c.fields}
+}
+
+func (r *RegisterRequest) Validate() error {
+    var c fieldChecker
+    c.username("username", r.Username)
+    if c.required("password", r.Password) {
+        c.length("password", r.Password, MinPasswordLength, 0)
+    }
+    if r.Email != "" && !emailPattern.MatchString(r.Email) {
+        c.fail("email", "must be a valid email address")
+    }
+    return c.err()
+}
+
+func (r *ChangeUsernameRequest) Validate() error {
+    var c fieldChecker
+    c.username("username", r.Username)
+    return c.err()
+}
+
+func (r *VerifyEmailRequest) Validate() error {
+    var c fieldChecker
+    c.required("token", r.Token)
+    return c.err()
+}
+
+func (r *LoginRequest) Validate() error {
+    var c fieldChecker
+    c.required("username", r.Username)
+    c.required("password", r.Password)
+    return c.err()
+}
+
+func (r *SubredditRequest) Validate() error {
+    var c fieldChecker
+    if c.required("name", r.Name) {
+        c.length("name", r.Name, MinSubredditNameLength, MaxSubredditNameLength)
+        if !subredditNamePattern.MatchString(r.Name) {
+            c.fail("name", "may only contain letters, digits and '_'")
+        }
+    }
+    c.length("description", r.Description, 0, MaxDescriptionLength)
+    c.nonNegative("max_members", r.MaxMembers)
+    if r.Type != "" {
+        c.subredditType("type", r.Type)
+    }
+    return c.err()
+}
+
+func (r *UpdateSubredditRequest) Validate() error {
+    var c fieldChecker
+    if r.Description != nil {
+        c.length("description", *r.Description, 0, MaxDescriptionLength)
+    }
+    if r.MaxMembers != nil {
+        c.nonNegative("max_members", *r.MaxMembers)
+    }
+    if r.Type != nil {
+        c.subredditType("type", *r.Type)
+    }
+    return c.err()
+}
+
+func (r *PostRequest) Validate() error {
+    return r.ValidateLimits(DefaultLimits())
+}
+
+func (r *PostRequest) ValidateLimits(limits Limits) error {
+    var c fieldChecker
+    if c.required("title", r.Title) {
+        c.length("title", r.Title, 1, limits.MaxTitleLength)
+    }
+    c.length("content", r.Content, 0, limits.MaxPostContentLength)
+    c.required("subreddit_id", r.SubredditID)
+    return c.err()
+}
+
+func (r *EditPostRequest) Validate() error {
+    return r.ValidateLimits(DefaultLimits())
+}
+
+func (r *EditPostRequest) ValidateLimits(limits Limits) error {
+    var c fieldChecker
+    if c.required("title", r.Title) {
+        c.length("title", r.Title, 1, limits.MaxTitleLength)
+    }
+    c.length("content", r.Content, 0, limits.MaxPostContentLength)
+    if r.Version != nil {
+        c.nonNegative("version", *r.Version)
+    }
+    return c.err()
+}
+
+func (r *CommentRequest) Validate() error {
+    return r.ValidateLimits(DefaultLimits())
+}
+
+func (r *CommentRequest) ValidateLimits(limits Limits) error {
+    var c fieldChecker
+    if c.required("content", r.Content) {
+        c.length("content", r.Content, 1, limits.MaxCommentLength)
+    }
+    if r.ParentID != nil {
+        c.required("parent_id", *r.ParentID)
+    }
+    return c.err()
+}
+
+func (r *EditCommentRequest) Validate() error {
+    return r.ValidateLimits(DefaultLimits())
+}
+
+func (r *EditCommentRequest) ValidateLimits(limits Limits) error {
+    var c fieldChecker
+    if c.required("content", r.Content) {
+        c.length("content", r.Content, 1, limits.MaxCommentLength)
+    }
+    if r.Version != nil {
+        c.nonNegative("version", *r.Version)
+    }
+    return c.err()
+}
+
+func (r *MessageRequest) Validate() error {
+    return r.ValidateLimits(DefaultLimits())
+}
+
+func (r *MessageRequest) ValidateLimits(limits Limits) error {
+    var c fieldChecker
+    c.required("to_id", r.ToID)
+    if c.required("content", r.Content) {
+        c.length("content", r.Content, 1, limits.MaxMessageLength)
+    }
+    c.nonNegative("ttl_seconds", r.TTLSeconds)
+    return c.err()
+}
+
+func (r *EditMessageRequest) Validate() error {
+    return r.ValidateLimits(DefaultLimits())
+}
+
+func (r *EditMessageRequest) ValidateLimits(limits Limits) error {
+    var c fieldChecker
+    if c.required("content", r.Content) {
+        c.length("content", r.Content, 1, limits.MaxMessageLength)
+    }
+    return c.err()
+}
+
+func (r *ReportRequest) Validate() error {
+    var c fieldChecker
+    if c.required("reason", r.Reason) {
+        c.length("reason", r.Reason, 1, MaxReportReasonLength)
+    }
+    return c.err()
+}
\ No newline at end of file
@@ -0,0 +1,82 @@
+// cmd/bench/main.go
+package main
+
+import (
+    "flag"
+    "fmt"
+    "log"
+    "sort"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/bench"
+    "reddit-clone/internal/engine"
+)
+
+func main() {
+    opts := bench.DefaultOptions()
+    flag.IntVar(&opts.Users, "users", opts.Users, "Number of users in the fixture")
+    flag.IntVar(&opts.Subreddits, "subreddits", opts.Subreddits, "Number of subreddits in the fixture")
+    flag.IntVar(&opts.Posts, "posts", opts.Posts, "Posts created before the timed run")
+    flag.IntVar(&opts.Concurrency, "concurrency", opts.Concurrency, "Goroutines issuing operations")
+    flag.IntVar(&opts.Ops, "ops", opts.Ops, "Operations in the timed run")
+    flag.Float64Var(&opts.ReadRatio, "read-ratio", opts.ReadRatio, "Share (0-1) of operations that read a feed")
+    flag.Float64Var(&opts.RepostProbability, "repost-probability", opts.RepostProbability, "Chance (0-1) that a write is a repost")
+    flag.Int64Var(&opts.Seed, "seed", 0, "Random seed (0 seeds from the clock)")
+    feedBench := flag.Bool("feed-bench", false, fmt.Sprintf("Also run the GetFeed benchmark at %d posts", bench.FeedBenchmarkPosts))
+    shardBench := flag.Bool("shard-bench", false, fmt.Sprintf("Also compare a sharded post store with a single map at %d goroutines", bench.ShardBenchmarkGoroutines))
+    flag.IntVar(&opts.PostShards, "post-shards", engine.DefaultPostShards, "Post store shards for the main run and the sharded benchmark")
+    flag.Parse()
+
+    log.Printf("Building fixture: %d users, %d subreddits, %d posts\n", opts.Users, opts.Subreddits, opts.Posts)
+    setupStart := time.Now()
+    fixture, err := bench.Setup(opts)
+    if err != nil {
+        log.Fatalf("failed to build fixture: %v", err)
+    }
+    log.Printf("Fixture ready in %v\n", time.Since(setupStart))
+
+    result := bench.Run(fixture, opts)
+    fmt.Printf("ops:          %d (%d errors)\n", result.Ops, result.Errors)
+    fmt.Printf("elapsed:      %v\n", result.Elapsed)
+    fmt.Printf("throughput:   %.0f ops/sec at concurrency %d\n", result.OpsPerSec, opts.Concurrency)
+    fmt.Printf("allocations:  %d allocs/op, %d B/op\n", result.AllocsPerOp, result.BytesPerOp)
+    printSorted("operations", result.PerAction)
+
+    stats := fixture.Engine.Stats()
+    fmt.Printf("totals:       %d users, %d subreddits, %d posts, %d comments, %d votes, %d messages\n",
+        stats.Counts.TotalUsers, stats.Counts.TotalSubreddits, stats.Counts.TotalPosts,
+        stats.Counts.TotalComments, stats.Counts.TotalVotes, stats.Counts.TotalMessages)
+    sizes := make(map[string]int64, len(stats.MapSizes))
+    for name, size := range stats.MapSizes {
+        sizes[name] = int64(size)
+    }
+    printSorted("map sizes", sizes)
+
+    if *feedBench {
+        log.Printf("Running GetFeed benchmark at %d posts\n", bench.FeedBenchmarkPosts)
+        feedResult := testing.Benchmark(bench.BenchmarkGetFeed)
+        fmt.Printf("GetFeed:      %s %s\n", feedResult, feedResult.MemString())
+    }
+
+    if *shardBench {
+        log.Printf("Running post store benchmark at %d goroutines\n", bench.ShardBenchmarkGoroutines)
+        for _, shards := range []int{1, opts.PostShards} {
+            shardResult := testing.Benchmark(bench.BenchmarkPostStore(shards))
+            fmt.Printf("%2d shard(s):  %s %s\n", shards, shardResult, shardResult.MemString())
+        }
+    }
+}
+
+// printSorted prints a labelled map one entry per line, sorted by key
+func printSorted(label string, values map[string]int64) {
+    keys := make([]string, 0, len(values))
+    for key := range values {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    fmt.Printf("%s:\n", label)
+    for _, key := range keys {
+        fmt.Printf("  %-20s %d\n", key, values[key])
+    }
+}
\ No newline at end of file
@@ -21,6 +21,12 @@ type Config struct {
     Duration        time.Duration
     MetricsInterval time.Duration
     MetricsPort     int
+    UseTLS          bool
+    CAFile          string
+    ServerName      string
+    Retries         int
+    Seed            int64
+    RepostProb      float64
 }
 
 func main() {
@@ -31,17 +37,33 @@ func main() {
     flag.DurationVar(&config.Duration, "duration", 10*time.Minute, "Duration to run the simulation")
     flag.DurationVar(&config.MetricsInterval, "metrics-interval", time.Minute, "Interval for metrics collection")
     flag.IntVar(&config.MetricsPort, "metrics-port", 50053, "Port for metrics server")
+    flag.BoolVar(&config.UseTLS, "tls", false, "Connect to the server over TLS")
+    flag.StringVar(&config.CAFile, "ca-cert", "", "CA certificate used to verify the server (defaults to system roots)")
+    flag.StringVar(&config.ServerName, "server-name", "", "Override the server name checked against the TLS certificate")
+    flag.IntVar(&config.Retries, "retries", 0, "Retry calls that fail with Unavailable this many times")
+    flag.Int64Var(&config.Seed, "seed", 0, "Random seed for the simulation (0 seeds from the clock)")
+    flag.Float64Var(&config.RepostProb, "repost-probability", simulator.DefaultRepostProbability, "Chance (0-1) that an active user reposts on each tick")
     flag.Parse()
 
     // Create Reddit client
-    redditClient, err := client.NewRedditClient(config.ServerAddr)
+    var clientOpts []client.ClientOption
+    if config.UseTLS {
+        clientOpts = append(clientOpts, client.WithTLS(config.CAFile, config.ServerName))
+    }
+    if config.Retries > 0 {
+        clientOpts = append(clientOpts, client.WithRetry(config.Retries, 100*time.Millisecond))
+    }
+    redditClient, err := client.NewRedditClient(config.ServerAddr, clientOpts...)
     if err != nil {
         log.Fatalf("Failed to create client: %v", err)
     }
     defer redditClient.Close()
 
     // Create simulator
-    sim := simulator.NewSimulator(redditClient, config.NumUsers)
+    sim := simulator.NewSimulatorWithOptions(redditClient, config.NumUsers, simulator.Options{
+        Seed:              config.Seed,
+        RepostProbability: config.RepostProb,
+    })
     metricsCollector := metrics.NewCollector()
 
     // Setup metrics server
@@ -2,16 +2,20 @@
 package main
 
 import (
+    "context"
     "flag"
     "fmt"
     "log"
     "net"
     "os"
     "os/signal"
+    "path/filepath"
+    "strings"
     "syscall"
     "time"
     
     "google.golang.org/grpc"
+    "google.golang.org/grpc/credentials"
     "google.golang.org/grpc/reflection"
     
     "reddit-clone/internal/engine"
@@ -30,19 +34,114 @@ func main() {
     port := flag.Int("port", 50051, "The server port")
     metricsPort := flag.Int("metrics-port", 50052, "The metrics port")
     metricsInterval := flag.Duration("metrics-interval", time.Minute, "Metrics collection interval")
+    metricsRetention := flag.Duration("metrics-retention", metrics.DefaultRetention, "How much per-minute throughput history the metrics page keeps")
+    maxCommentDepth := flag.Int("max-comment-depth", engine.DefaultMaxCommentDepth, "Maximum nesting depth for comment replies")
+    messageSweepInterval := flag.Duration("message-sweep-interval", engine.DefaultMessageSweepInterval, "Interval for purging expired direct messages")
+    voteFuzzing := flag.Bool("vote-fuzzing", false, "Slightly obfuscate displayed vote counts")
+    postCooldown := flag.Duration("post-cooldown", engine.DefaultPostCooldown, "Minimum interval between posts by one user (0 disables)")
+    commentCooldown := flag.Duration("comment-cooldown", engine.DefaultCommentCooldown, "Minimum interval between comments by one user (0 disables)")
+    duplicatePostWindow := flag.Duration("duplicate-post-window", engine.DefaultDuplicatePostWindow, "How long identical posts by one author are rejected in a subreddit (0 disables)")
+    usernameChangeCooldown := flag.Duration("username-change-cooldown", engine.DefaultUsernameChangeCooldown, "Minimum interval between username changes by one user (0 disables)")
+    requireVerifiedEmail := flag.Bool("require-verified-email", false, "Only let users with a verified email post")
+    notificationBatchWindow := flag.Duration("notification-batch-window", engine.DefaultNotificationBatchWindow, "How long a user's notifications of one type are coalesced into one (0 disables)")
+    autoRemoveBelowScore := flag.Int64("auto-remove-below-score", 0, "Score under which votes hide a post or comment until a moderator restores it (0 disables)")
+    minAccountAge := flag.Duration("min-account-age", 0, "How old an account must be before it may post or comment (0 disables)")
+    defaultSubreddits := flag.String("default-subreddits", "", "Comma-separated subreddit names every new account joins")
+    messageEditWindow := flag.Duration("message-edit-window", engine.DefaultMessageEditWindow, "How long a sender may edit or delete a direct message (0 means no limit)")
+    maxSubredditsPerUser := flag.Int("max-subreddits-per-user", 0, "Maximum subreddits one user may create (0 means unlimited)")
+    rateBudget := flag.Int("rate-budget", 0, "Requests one user may make over REST and gRPC combined per rate budget window (0 disables)")
+    rateBudgetWindow := flag.Duration("rate-budget-window", engine.DefaultRateBudgetWindow, "Period each user's request budget covers")
+    maxFeedSubscriptions := flag.Int("max-feed-subscriptions-per-user", engine.DefaultMaxFeedSubscriptionsPerUser, "Maximum live feed connections one user may hold open (0 means unlimited)")
+    sanitizeOnRender := flag.Bool("sanitize-on-render", false, "Store post and comment content as written and sanitize it when served")
+    useTLS := flag.Bool("tls", false, "Serve gRPC over TLS")
+    certFile := flag.String("cert", "", "TLS certificate file (requires -tls)")
+    keyFile := flag.String("key", "", "TLS private key file (requires -tls)")
+    chaosLatency := flag.Duration("chaos-latency", 0, "Chaos mode: artificial latency added to every gRPC call")
+    chaosErrorRate := flag.Float64("chaos-error-rate", 0, "Chaos mode: probability (0-1) that a gRPC call fails with Unavailable")
+    dataDir := flag.String("data-dir", "", "Directory for the engine snapshot (empty disables persistence)")
+    autosaveInterval := flag.Duration("autosave-interval", time.Minute, "Interval between snapshot saves (requires -data-dir)")
+    adminKey := flag.String("admin-key", "", "Key required by the snapshot export and import RPCs (empty disables them)")
     flag.Parse()
 
     // Create components
-    redditEngine := engine.NewRedditEngine()
-    metricsCollector := metrics.NewCollector()
-    redditServer := server.NewRedditServer(redditEngine, metricsCollector)
+    engineConfig := engine.NewDefaultConfig()
+    engineConfig.MaxCommentDepth = *maxCommentDepth
+    engineConfig.MessageSweepInterval = *messageSweepInterval
+    engineConfig.VoteFuzzing = *voteFuzzing
+    engineConfig.PostCooldown = *postCooldown
+    engineConfig.CommentCooldown = *commentCooldown
+    engineConfig.DuplicatePostWindow = *duplicatePostWindow
+    engineConfig.MessageEditWindow = *messageEditWindow
+    engineConfig.RequireVerifiedEmail = *requireVerifiedEmail
+    engineConfig.MinAccountAge = *minAccountAge
+    engineConfig.AutoRemoveBelowScore = *autoRemoveBelowScore
+    engineConfig.NotificationBatchWindow = *notificationBatchWindow
+    if *defaultSubreddits != "" {
+        engineConfig.DefaultSubreddits = strings.Split(*defaultSubreddits, ",")
+    }
+    engineConfig.UsernameChangeCooldown = *usernameChangeCooldown
+    engineConfig.MaxSubredditsPerUser = *maxSubredditsPerUser
+    engineConfig.MaxFeedSubscriptionsPerUser = *maxFeedSubscriptions
+    engineConfig.RateBudgetSize = *rateBudget
+    engineConfig.RateBudgetWindow = *rateBudgetWindow
+    engineConfig.SanitizeOnRender = *sanitizeOnRender
+    redditEngine := engine.NewRedditEngineWithConfig(engineConfig)
+
+    // Run engine background maintenance until shutdown
+    engineCtx, stopEngine := context.WithCancel(context.Background())
+    defer stopEngine()
+    go redditEngine.Run(engineCtx)
+
+    // Restore the last snapshot and keep saving it until shutdown
+    autosaveDone := make(chan struct{})
+    if *dataDir != "" {
+        if err := os.MkdirAll(*dataDir, 0o755); err != nil {
+            log.Fatalf("failed to create data directory: %v", err)
+        }
+        snapshotPath := filepath.Join(*dataDir, "engine.snapshot")
+        if err := redditEngine.LoadState(snapshotPath); err == nil {
+            log.Printf("Restored engine state from %s\n", snapshotPath)
+        } else if !os.IsNotExist(err) {
+            log.Fatalf("failed to load engine state: %v", err)
+        }
+        go func() {
+            redditEngine.RunAutosave(engineCtx, *autosaveInterval, snapshotPath)
+            close(autosaveDone)
+        }()
+    } else {
+        close(autosaveDone)
+    }
+    metricsCollector := metrics.NewCollectorWithOptions(metrics.CollectorOptions{Retention: *metricsRetention})
+    redditServer := server.NewRedditServer(redditEngine, metricsCollector).WithAdminKey(*adminKey)
 
     // Create gRPC server
-    grpcServer := grpc.NewServer()
+    var serverOpts []grpc.ServerOption
+    if *useTLS {
+        creds, err := credentials.NewServerTLSFromFile(*certFile, *keyFile)
+        if err != nil {
+            log.Fatalf("failed to load TLS credentials: %v", err)
+        }
+        serverOpts = append(serverOpts, grpc.Creds(creds))
+    }
+    interceptors := []grpc.UnaryServerInterceptor{
+        redditServer.MetricsInterceptor(),
+        server.RateBudgetInterceptor(redditEngine.RateBudget()),
+    }
+    chaos := server.ChaosConfig{Latency: *chaosLatency, ErrorRate: *chaosErrorRate}
+    if chaos.Enabled() {
+        log.Printf("Chaos mode enabled: latency=%v error-rate=%.2f\n", chaos.Latency, chaos.ErrorRate)
+        interceptors = append(interceptors, server.ChaosInterceptor(chaos))
+    }
+    serverOpts = append(serverOpts,
+        grpc.ChainUnaryInterceptor(interceptors...),
+        grpc.ChainStreamInterceptor(redditServer.RateBudgetStreamInterceptor()),
+    )
+    grpcServer := grpc.NewServer(serverOpts...)
     proto.RegisterRedditServiceServer(grpcServer, redditServer)
     reflection.Register(grpcServer)
 
-    // Start metrics server
+    // Start metrics server with the engine's current counts
+    redditServer.PushMetrics()
     go startMetricsServer(metricsCollector, *metricsPort)
 
     // Start listening
@@ -71,6 +170,7 @@ func main() {
     for {
         select {
         case <-metricsTicker.C:
+            redditServer.PushMetrics()
             printMetrics(metricsCollector)
 
         case sig := <-stop:
@@ -78,6 +178,8 @@ func main() {
             log.Println("Gracefully shutting down server...")
             printMetrics(metricsCollector)
             grpcServer.GracefulStop()
+            stopEngine()
+            <-autosaveDone
             return
         }
     }
@@ -110,6 +212,7 @@ func printMetrics(collector *metrics.Collector) {
         log.Printf("  Posts: %d\n", stat.PostCount)
         log.Printf("  Comments: %d\n", stat.CommentCount)
         log.Printf("  Votes: %d\n", stat.VoteCount)
+        log.Printf("  Server calls: %v (%d failed)\n", stat.Operations, stat.OperationErrors)
     }
     log.Printf("\n")
 }
\ No newline at end of file
@@ -1,34 +1,128 @@
 package main
 
 import (
+    "context"
     "flag"
     "log"
     "os"
     "os/signal"
+    "strings"
     "syscall"
 
+    "reddit-clone/api/v1"
+    "reddit-clone/internal/admin"
     "reddit-clone/internal/engine"
+    "reddit-clone/internal/middleware"
     "reddit-clone/internal/rest"
+    _ "reddit-clone/internal/server" // registers the gRPC service served by engine.Start
+    "reddit-clone/pkg/config"
+    "reddit-clone/pkg/webhook"
 )
 
 func main() {
     // Parse command line arguments
     port := flag.String("port", ":8080", "REST server port")
     enginePort := flag.String("engine-port", ":50051", "gRPC engine port")
+    maxCommentDepth := flag.Int("max-comment-depth", engine.DefaultMaxCommentDepth, "Maximum nesting depth for comment replies")
+    messageSweepInterval := flag.Duration("message-sweep-interval", engine.DefaultMessageSweepInterval, "Interval for purging expired direct messages")
+    voteFuzzing := flag.Bool("vote-fuzzing", false, "Slightly obfuscate displayed vote counts")
+    postCooldown := flag.Duration("post-cooldown", engine.DefaultPostCooldown, "Minimum interval between posts by one user (0 disables)")
+    commentCooldown := flag.Duration("comment-cooldown", engine.DefaultCommentCooldown, "Minimum interval between comments by one user (0 disables)")
+    duplicatePostWindow := flag.Duration("duplicate-post-window", engine.DefaultDuplicatePostWindow, "How long identical posts by one author are rejected in a subreddit (0 disables)")
+    usernameChangeCooldown := flag.Duration("username-change-cooldown", engine.DefaultUsernameChangeCooldown, "Minimum interval between username changes by one user (0 disables)")
+    requireVerifiedEmail := flag.Bool("require-verified-email", false, "Only let users with a verified email post")
+    notificationBatchWindow := flag.Duration("notification-batch-window", engine.DefaultNotificationBatchWindow, "How long a user's notifications of one type are coalesced into one (0 disables)")
+    autoRemoveBelowScore := flag.Int64("auto-remove-below-score", 0, "Score under which votes hide a post or comment until a moderator restores it (0 disables)")
+    minAccountAge := flag.Duration("min-account-age", 0, "How old an account must be before it may post or comment (0 disables)")
+    defaultSubreddits := flag.String("default-subreddits", "", "Comma-separated subreddit names every new account joins")
+    messageEditWindow := flag.Duration("message-edit-window", engine.DefaultMessageEditWindow, "How long a sender may edit or delete a direct message (0 means no limit)")
+    maxSubredditsPerUser := flag.Int("max-subreddits-per-user", 0, "Maximum subreddits one user may create (0 means unlimited)")
+    rateBudget := flag.Int("rate-budget", 0, "Requests one user may make over REST and gRPC combined per rate budget window (0 disables)")
+    rateBudgetWindow := flag.Duration("rate-budget-window", engine.DefaultRateBudgetWindow, "Period each user's request budget covers")
+    maxFeedSubscriptions := flag.Int("max-feed-subscriptions-per-user", engine.DefaultMaxFeedSubscriptionsPerUser, "Maximum live feed connections one user may hold open (0 means unlimited)")
+    sanitizeOnRender := flag.Bool("sanitize-on-render", false, "Store post and comment content as written and sanitize it when served")
+    collapseBelowScore := flag.Int64("collapse-below-score", engine.DefaultCollapseBelowScore, "Score under which comments start collapsed (0 disables)")
+    adminKey := flag.String("admin-key", "", "API key for the /admin/ operator API (empty disables it)")
+    trustedProxies := flag.String("trusted-proxies", "", "Comma-separated CIDRs of proxies whose X-Forwarded-For and X-Real-IP headers are believed")
+    gzipEnabled := flag.Bool("gzip", true, "Compress large JSON responses for clients that accept gzip")
+    gzipMinSize := flag.Int("gzip-min-size", middleware.DefaultGzipMinSize, "Smallest response body, in bytes, to compress")
+    msgpackEnabled := flag.Bool("msgpack", true, "Answer in MessagePack, and accept MessagePack bodies, for clients that ask for it")
+    maxTitleLength := flag.Int("max-title-length", api.MaxTitleLength, "Longest post title, in characters, the API accepts (0 removes the limit)")
+    maxPostContentLength := flag.Int("max-post-content-length", api.MaxPostContentLength, "Longest post body, in characters, the API accepts (0 removes the limit)")
+    maxCommentLength := flag.Int("max-comment-length", api.MaxCommentLength, "Longest comment, in characters, the API accepts (0 removes the limit)")
+    maxMessageLength := flag.Int("max-message-length", api.MaxMessageLength, "Longest direct message, in characters, the API accepts (0 removes the limit)")
+    requestTimeout := flag.Duration("request-timeout", config.DefaultRequestTimeout, "Longest a REST handler may run before answering 503 (0 disables)")
     flag.Parse()
 
     // Create the Reddit engine
-    redditEngine := engine.NewRedditEngine()
+    engineConfig := engine.NewDefaultConfig()
+    engineConfig.MaxCommentDepth = *maxCommentDepth
+    engineConfig.MessageSweepInterval = *messageSweepInterval
+    engineConfig.VoteFuzzing = *voteFuzzing
+    engineConfig.PostCooldown = *postCooldown
+    engineConfig.CommentCooldown = *commentCooldown
+    engineConfig.DuplicatePostWindow = *duplicatePostWindow
+    engineConfig.MessageEditWindow = *messageEditWindow
+    engineConfig.RequireVerifiedEmail = *requireVerifiedEmail
+    engineConfig.MinAccountAge = *minAccountAge
+    engineConfig.AutoRemoveBelowScore = *autoRemoveBelowScore
+    engineConfig.NotificationBatchWindow = *notificationBatchWindow
+    if *defaultSubreddits != "" {
+        engineConfig.DefaultSubreddits = strings.Split(*defaultSubreddits, ",")
+    }
+    engineConfig.UsernameChangeCooldown = *usernameChangeCooldown
+    engineConfig.MaxSubredditsPerUser = *maxSubredditsPerUser
+    engineConfig.MaxFeedSubscriptionsPerUser = *maxFeedSubscriptions
+    engineConfig.RateBudgetSize = *rateBudget
+    engineConfig.RateBudgetWindow = *rateBudgetWindow
+    engineConfig.SanitizeOnRender = *sanitizeOnRender
+    engineConfig.CollapseBelowScore = *collapseBelowScore
+    redditEngine := engine.NewRedditEngineWithConfig(engineConfig)
+
+    // Run engine background maintenance until shutdown
+    engineCtx, stopEngine := context.WithCancel(context.Background())
+    defer stopEngine()
+    go redditEngine.Run(engineCtx)
+
+    // Deliver new posts and comments to subreddit webhooks
+    webhooks := webhook.NewDispatcher(redditEngine, webhook.Options{})
+    redditEngine.AddListener(webhooks)
+    go webhooks.Run(engineCtx)
 
     // Create and start gRPC server for the engine
     go func() {
+        log.Printf("Starting engine gRPC server on port %s", *enginePort)
         if err := redditEngine.Start(*enginePort); err != nil {
             log.Fatalf("Failed to start engine: %v", err)
         }
     }()
 
     // Create REST server
-    server := rest.NewServer(redditEngine)
+    serviceConfig := config.NewDefaultConfig()
+    serviceConfig.RequestTimeout = *requestTimeout
+    serviceConfig.MaxTitleLength = *maxTitleLength
+    serviceConfig.MaxPostContentLength = *maxPostContentLength
+    serviceConfig.MaxCommentLength = *maxCommentLength
+    serviceConfig.MaxMessageLength = *maxMessageLength
+    if err := serviceConfig.Validate(); err != nil {
+        log.Fatalf("Invalid configuration: %v", err)
+    }
+    server := rest.NewServerWithOptions(redditEngine, rest.ServerOptions{
+        Gzip:           *gzipEnabled,
+        GzipMinSize:    *gzipMinSize,
+        RequestTimeout: serviceConfig.RequestTimeout,
+        Limits:         serviceConfig.Limits(),
+        Msgpack:        *msgpackEnabled,
+    })
+    proxies, err := middleware.ParseTrustedProxies(strings.Split(*trustedProxies, ","))
+    if err != nil {
+        log.Fatalf("Invalid configuration: %v", err)
+    }
+    if *adminKey != "" {
+        adminHandler := admin.NewHandler(redditEngine, *adminKey)
+        adminHandler.TrustProxies(proxies)
+        server.MountAdmin(adminHandler)
+    }
 
     // Setup graceful shutdown
     stop := make(chan os.Signal, 1)
@@ -45,4 +139,5 @@ func main() {
     // Wait for interrupt signal
     <-stop
     log.Println("Shutting down server...")
+    redditEngine.Stop()
 }
\ No newline at end of file
@@ -65,11 +65,11 @@ func main() {
 
     // Test 5: Vote
     log.Println("\n=== Testing Voting ===")
-    err = client.Vote(post.ID, true) // Upvote
+    vote, err := client.Vote(post.ID, true) // Upvote
     if err != nil {
         log.Printf("Voting failed: %v\n", err)
     } else {
-        log.Printf("Successfully voted on post: %s\n", post.ID)
+        log.Printf("Successfully voted on post: %s (score %d)\n", post.ID, vote.Score)
     }
 
     // Test 6: Get Feed
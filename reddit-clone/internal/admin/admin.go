@@ -0,0 +1,313 @@
+// internal/admin/admin.go
+package admin
+
+import (
+    "crypto/subtle"
+    "encoding/json"
+    "errors"
+    "net/http"
+    "strconv"
+    "sync"
+    "time"
+
+    "github.com/gorilla/mux"
+
+    "reddit-clone/api/v1"
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/middleware"
+)
+
+// APIKeyHeader carries the operator API key on every admin request
+const APIKeyHeader = "X-Admin-Key"
+
+// defaultUserPageLimit is how many users a page of the directory holds by default
+const defaultUserPageLimit = 50
+
+// AuditEntry records one admin action
+type AuditEntry struct {
+    Action     string    `json:"action"`
+    TargetID   string    `json:"target_id"`
+    RemoteAddr string    `json:"remote_addr"` // Client IP, as forwarded by a trusted proxy
+    CreatedAt  time.Time `json:"created_at"`
+}
+
+// Handler serves the operator API under /admin/. It authenticates with a
+// shared API key rather than user tokens, and is meant to be mounted
+// outside the public router so it gets none of its middleware.
+type Handler struct {
+    engine *engine.RedditEngine
+    apiKey string
+    router *mux.Router
+
+    // proxies whose forwarding headers name the client in the audit log
+    proxies middleware.TrustedProxies
+
+    auditMtx sync.Mutex
+    audit    []AuditEntry
+}
+
+// NewHandler creates the admin API. An empty apiKey rejects every request.
+func NewHandler(engine *engine.RedditEngine, apiKey string) *Handler {
+    h := &Handler{
+        engine: engine,
+        apiKey: apiKey,
+        router: mux.NewRouter(),
+    }
+    h.setupRoutes()
+    return h
+}
+
+// TrustProxies makes the audit log record the client address forwarded
+// by these proxies instead of the proxy's own
+func (h *Handler) TrustProxies(proxies middleware.TrustedProxies) {
+    h.proxies = proxies
+}
+
+func (h *Handler) setupRoutes() {
+    h.router.HandleFunc("/admin/users", h.handleListUsers).Methods("GET")
+    h.router.HandleFunc("/admin/users/{id}/ban", h.handleBanUser).Methods("POST")
+    h.router.HandleFunc("/admin/users/{id}/unban", h.handleUnbanUser).Methods("POST")
+    h.router.HandleFunc("/admin/posts/{id}", h.handleRemovePost).Methods("DELETE")
+    h.router.HandleFunc("/admin/comments/{id}", h.handleRemoveComment).Methods("DELETE")
+    h.router.HandleFunc("/admin/stats", h.handleGetStats).Methods("GET")
+    h.router.HandleFunc("/admin/maintenance", h.handleSetMaintenance).Methods("PUT")
+    h.router.HandleFunc("/admin/announcements", h.handleBroadcastAnnouncement).Methods("POST")
+    h.router.HandleFunc("/admin/subreddits/{id}/merge", h.handleMergeSubreddits).Methods("POST")
+    h.router.HandleFunc("/admin/audit", h.handleGetAudit).Methods("GET")
+}
+
+// ServeHTTP checks the API key before routing: 401 if it is missing,
+// 403 if it doesn't match
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    key := r.Header.Get(APIKeyHeader)
+    if key == "" {
+        respondWithError(w, http.StatusUnauthorized, "Admin API key required")
+        return
+    }
+    if h.apiKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(h.apiKey)) != 1 {
+        respondWithError(w, http.StatusForbidden, "Invalid admin API key")
+        return
+    }
+    h.router.ServeHTTP(w, r)
+}
+
+// record appends an action to the audit log
+func (h *Handler) record(r *http.Request, action, targetID string) {
+    h.auditMtx.Lock()
+    defer h.auditMtx.Unlock()
+    h.audit = append(h.audit, AuditEntry{
+        Action:     action,
+        TargetID:   targetID,
+        RemoteAddr: h.proxies.ClientIP(r),
+        CreatedAt:  time.Now(),
+    })
+}
+
+// handleListUsers searches the user directory by username substring (?q=)
+// and serves one page of the matches
+func (h *Handler) handleListUsers(w http.ResponseWriter, r *http.Request) {
+    query := r.URL.Query()
+    page, ok := pageParam(w, query.Get("page"), 1)
+    if !ok {
+        return
+    }
+    limit, ok := pageParam(w, query.Get("limit"), defaultUserPageLimit)
+    if !ok {
+        return
+    }
+
+    users, total, err := h.engine.ListUsers(query.Get("q"), page, limit)
+    if err != nil {
+        respondWithError(w, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    resp := api.AdminUserListResponse{
+        Users:    make([]api.AdminUserResponse, len(users)),
+        Total:    total,
+        PageInfo: api.NewPageInfo(page, limit, total),
+    }
+    for i, user := range users {
+        resp.Users[i] = api.AdminUserResponse{
+            UserResponse: api.UserResponse{
+                ID:        user.ID,
+                Username:  user.Username,
+                Karma:     user.Karma,
+                CreatedAt: user.CreatedAt,
+                Email:     user.Email,
+                Verified:  user.Verified,
+            },
+            Banned: h.engine.IsBanned(user.ID),
+        }
+    }
+    respondWithJSON(w, http.StatusOK, resp)
+}
+
+// pageParam parses a positive page or limit parameter, writing a 400 if
+// it is malformed
+func pageParam(w http.ResponseWriter, value string, def int) (int, bool) {
+    if value == "" {
+        return def, true
+    }
+    n, err := strconv.Atoi(value)
+    if err != nil || n < 1 {
+        respondWithError(w, http.StatusBadRequest, "page and limit must be positive integers")
+        return 0, false
+    }
+    return n, true
+}
+
+func (h *Handler) handleBanUser(w http.ResponseWriter, r *http.Request) {
+    userID := mux.Vars(r)["id"]
+    if err := h.engine.BanUser(userID); err != nil {
+        respondWithError(w, http.StatusNotFound, err.Error())
+        return
+    }
+    h.record(r, "ban_user", userID)
+    respondWithJSON(w, http.StatusOK, api.StatusResponse{Success: true, Message: "User banned"})
+}
+
+func (h *Handler) handleUnbanUser(w http.ResponseWriter, r *http.Request) {
+    userID := mux.Vars(r)["id"]
+    if err := h.engine.UnbanUser(userID); err != nil {
+        respondWithError(w, http.StatusNotFound, err.Error())
+        return
+    }
+    h.record(r, "unban_user", userID)
+    respondWithJSON(w, http.StatusOK, api.StatusResponse{Success: true, Message: "User unbanned"})
+}
+
+func (h *Handler) handleRemovePost(w http.ResponseWriter, r *http.Request) {
+    postID := mux.Vars(r)["id"]
+    if err := h.engine.RemovePost(postID); err != nil {
+        respondWithError(w, http.StatusNotFound, err.Error())
+        return
+    }
+    h.record(r, "remove_post", postID)
+    respondWithJSON(w, http.StatusOK, api.StatusResponse{Success: true, Message: "Post removed"})
+}
+
+func (h *Handler) handleRemoveComment(w http.ResponseWriter, r *http.Request) {
+    commentID := mux.Vars(r)["id"]
+    if err := h.engine.RemoveComment(commentID); err != nil {
+        respondWithError(w, http.StatusNotFound, err.Error())
+        return
+    }
+    h.record(r, "remove_comment", commentID)
+    respondWithJSON(w, http.StatusOK, api.StatusResponse{Success: true, Message: "Comment removed"})
+}
+
+// handleSetMaintenance freezes or resumes writes, e.g. around a snapshot
+func (h *Handler) handleSetMaintenance(w http.ResponseWriter, r *http.Request) {
+    var req api.MaintenanceRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+        return
+    }
+
+    h.engine.SetReadOnly(req.ReadOnly)
+    if req.ReadOnly {
+        h.record(r, "enable_read_only", "")
+        respondWithJSON(w, http.StatusOK, api.StatusResponse{Success: true, Message: "Writes disabled"})
+        return
+    }
+    h.record(r, "disable_read_only", "")
+    respondWithJSON(w, http.StatusOK, api.StatusResponse{Success: true, Message: "Writes enabled"})
+}
+
+// handleBroadcastAnnouncement pins the same distinguished post in every
+// subreddit, or in those the request names
+func (h *Handler) handleBroadcastAnnouncement(w http.ResponseWriter, r *http.Request) {
+    var req api.AnnouncementRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+        return
+    }
+
+    posts, err := h.engine.BroadcastAnnouncementTo(req.AuthorID, req.Title, req.Content, req.SubredditIDs)
+    if errors.Is(err, engine.ErrSubredditNotFound) {
+        respondWithError(w, http.StatusNotFound, err.Error())
+        return
+    }
+    if err != nil {
+        respondWithError(w, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    resp := api.AnnouncementResponse{Posts: make([]api.AnnouncementPostResponse, len(posts))}
+    for i, post := range posts {
+        resp.Posts[i] = api.AnnouncementPostResponse{
+            PostID:      post.ID,
+            SubredditID: post.SubRedditID,
+            Slug:        post.Slug,
+        }
+        h.record(r, "broadcast_announcement", post.ID)
+    }
+    respondWithJSON(w, http.StatusCreated, resp)
+}
+
+// handleMergeSubreddits folds the subreddit in the path into the target
+// named in the body and deletes it
+func (h *Handler) handleMergeSubreddits(w http.ResponseWriter, r *http.Request) {
+    sourceID := mux.Vars(r)["id"]
+    var req api.MergeSubredditsRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+        return
+    }
+
+    err := h.engine.MergeSubreddits(req.AdminID, sourceID, req.TargetID)
+    if errors.Is(err, engine.ErrSubredditNotFound) {
+        respondWithError(w, http.StatusNotFound, err.Error())
+        return
+    }
+    if err != nil {
+        respondWithError(w, http.StatusBadRequest, err.Error())
+        return
+    }
+    h.record(r, "merge_subreddit", sourceID)
+    respondWithJSON(w, http.StatusOK, api.StatusResponse{Success: true, Message: "Subreddit merged into " + req.TargetID})
+}
+
+func (h *Handler) handleGetStats(w http.ResponseWriter, r *http.Request) {
+    stats, err := h.engine.GlobalStats()
+    if err != nil {
+        respondWithError(w, http.StatusInternalServerError, err.Error())
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, api.GlobalStatsResponse{
+        TotalUsers:      stats.TotalUsers,
+        TotalSubreddits: stats.TotalSubreddits,
+        TotalPosts:      stats.TotalPosts,
+        TotalComments:   stats.TotalComments,
+        TotalVotes:      stats.TotalVotes,
+        TotalMessages:   stats.TotalMessages,
+    })
+}
+
+func (h *Handler) handleGetAudit(w http.ResponseWriter, r *http.Request) {
+    h.auditMtx.Lock()
+    entries := make([]AuditEntry, len(h.audit))
+    copy(entries, h.audit)
+    h.auditMtx.Unlock()
+
+    respondWithJSON(w, http.StatusOK, entries)
+}
+
+func respondWithError(w http.ResponseWriter, code int, message string) {
+    respondWithJSON(w, code, api.ErrorResponse{Error: message, Code: api.CodeForStatus(code)})
+}
+
+func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
+    response, err := json.Marshal(payload)
+    if err != nil {
+        w.WriteHeader(http.StatusInternalServerError)
+        w.Write([]byte("Internal Server Error"))
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(code)
+    w.Write(response)
+}
\ No newline at end of file
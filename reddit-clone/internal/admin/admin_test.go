@@ -0,0 +1,131 @@
+// internal/admin/admin_test.go
+package admin
+
+import (
+    "encoding/json"
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+    "reddit-clone/internal/engine"
+)
+
+func TestAPIKeyAuthentication(t *testing.T) {
+    e := engine.NewRedditEngine()
+    h := NewHandler(e, testAPIKey)
+    aliceID := mustRegister(t, e, "alice")
+
+    wantStatus(t, serveAdmin(t, h, "GET", "/admin/stats", "", nil), http.StatusUnauthorized)
+    wantStatus(t, serveAdmin(t, h, "GET", "/admin/stats", "wrong-key", nil), http.StatusForbidden)
+    wantStatus(t, serveAdmin(t, h, "GET", "/admin/stats", testAPIKey+"x", nil), http.StatusForbidden)
+
+    // A refused request doesn't act
+    rec := serveAdmin(t, h, "POST", "/admin/users/"+aliceID+"/ban", "wrong-key", nil)
+    wantStatus(t, rec, http.StatusForbidden)
+    var errResp api.ErrorResponse
+    if err := json.NewDecoder(rec.Body).Decode(&errResp); err != nil || errResp.Code != api.CodeForStatus(http.StatusForbidden) {
+        t.Errorf("error body = %+v (%v), want the forbidden code", errResp, err)
+    }
+    if e.IsBanned(aliceID) {
+        t.Fatal("ban went through with the wrong key")
+    }
+
+    rec = serveAdmin(t, h, "GET", "/admin/stats", testAPIKey, nil)
+    wantStatus(t, rec, http.StatusOK)
+    var stats api.GlobalStatsResponse
+    if err := json.NewDecoder(rec.Body).Decode(&stats); err != nil || stats.TotalUsers != 1 {
+        t.Errorf("stats = %+v (%v), want 1 user", stats, err)
+    }
+
+    wantStatus(t, serveAdmin(t, h, "POST", "/admin/users/"+aliceID+"/ban", testAPIKey, nil), http.StatusOK)
+    if !e.IsBanned(aliceID) {
+        t.Error("ban with the right key didn't take effect")
+    }
+    rec = serveAdmin(t, h, "GET", "/admin/audit", testAPIKey, nil)
+    wantStatus(t, rec, http.StatusOK)
+    var audit []AuditEntry
+    if err := json.NewDecoder(rec.Body).Decode(&audit); err != nil {
+        t.Fatalf("decoding audit: %v", err)
+    }
+    if len(audit) != 1 || audit[0].Action != "ban_user" || audit[0].TargetID != aliceID {
+        t.Errorf("audit = %+v, want the one ban", audit)
+    }
+}
+
+func TestEmptyAPIKeyRejectsEverything(t *testing.T) {
+    h := NewHandler(engine.NewRedditEngine(), "")
+    wantStatus(t, serveAdmin(t, h, "GET", "/admin/stats", "", nil), http.StatusUnauthorized)
+    wantStatus(t, serveAdmin(t, h, "GET", "/admin/stats", "anything", nil), http.StatusForbidden)
+}
+
+func TestListUsersSearchesAndPages(t *testing.T) {
+    e := engine.NewRedditEngine()
+    h := NewHandler(e, testAPIKey)
+    for _, name := range []string{"gopher", "GoLang_fan", "rustacean", "go_getter"} {
+        mustRegister(t, e, name)
+    }
+    gopher, err := e.GetUserByUsername("gopher")
+    if err != nil {
+        t.Fatal(err)
+    }
+    wantStatus(t, serveAdmin(t, h, "POST", "/admin/users/"+gopher.ID+"/ban", testAPIKey, nil), http.StatusOK)
+
+    list := func(path string) api.AdminUserListResponse {
+        t.Helper()
+        rec := serveAdmin(t, h, "GET", path, testAPIKey, nil)
+        wantStatus(t, rec, http.StatusOK)
+        var resp api.AdminUserListResponse
+        if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+            t.Fatalf("decoding users: %v", err)
+        }
+        return resp
+    }
+    names := func(resp api.AdminUserListResponse) []string {
+        var names []string
+        for _, user := range resp.Users {
+            names = append(names, user.Username)
+        }
+        return names
+    }
+
+    // Matching ignores case and the matches are ordered by name
+    first := list("/admin/users?q=GO&limit=2")
+    if got := names(first); len(got) != 2 || got[0] != "go_getter" || got[1] != "GoLang_fan" {
+        t.Errorf("page 1 = %v, want [go_getter GoLang_fan]", got)
+    }
+    if first.Total != 3 || !first.PageInfo.HasNext || first.PageInfo.HasPrev {
+        t.Errorf("page 1 total %d, page info %+v; want 3 with a next page", first.Total, first.PageInfo)
+    }
+
+    second := list("/admin/users?q=GO&limit=2&page=2")
+    if got := names(second); len(got) != 1 || got[0] != "gopher" {
+        t.Errorf("page 2 = %v, want [gopher]", got)
+    }
+    if second.Total != 3 || second.PageInfo.HasNext || !second.PageInfo.HasPrev {
+        t.Errorf("page 2 total %d, page info %+v; want 3 with only a previous page", second.Total, second.PageInfo)
+    }
+    if !second.Users[0].Banned {
+        t.Error("banned user isn't reported as banned")
+    }
+
+    if all := list("/admin/users"); all.Total != 4 || len(all.Users) != 4 {
+        t.Errorf("unfiltered list has %d of %d users, want 4 of 4", len(all.Users), all.Total)
+    }
+    if none := list("/admin/users?q=python"); none.Total != 0 || len(none.Users) != 0 {
+        t.Errorf("query with no matches returned %v", names(none))
+    }
+
+    wantStatus(t, serveAdmin(t, h, "GET", "/admin/users?page=0", testAPIKey, nil), http.StatusBadRequest)
+    wantStatus(t, serveAdmin(t, h, "GET", "/admin/users", "", nil), http.StatusUnauthorized)
+
+    // Engine results never carry secrets
+    users, _, err := e.ListUsers("", 0, 0)
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, user := range users {
+        if user.Password != "" {
+            t.Errorf("%s has a password hash in the listing", user.Username)
+        }
+    }
+}
\ No newline at end of file
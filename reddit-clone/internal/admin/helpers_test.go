@@ -0,0 +1,48 @@
+// internal/admin/helpers_test.go
+package admin
+
+import (
+    "bytes"
+    "encoding/json"
+    "net/http/httptest"
+    "testing"
+
+    "reddit-clone/internal/engine"
+)
+
+const testAPIKey = "test-admin-key"
+
+// serveAdmin sends a request to h with the given API key, or none if it
+// is empty, and body encoded as JSON unless it is nil
+func serveAdmin(t *testing.T, h *Handler, method, path, key string, body interface{}) *httptest.ResponseRecorder {
+    t.Helper()
+    var buf bytes.Buffer
+    if body != nil {
+        if err := json.NewEncoder(&buf).Encode(body); err != nil {
+            t.Fatalf("encoding request: %v", err)
+        }
+    }
+    req := httptest.NewRequest(method, path, &buf)
+    if key != "" {
+        req.Header.Set(APIKeyHeader, key)
+    }
+    rec := httptest.NewRecorder()
+    h.ServeHTTP(rec, req)
+    return rec
+}
+
+func wantStatus(t *testing.T, rec *httptest.ResponseRecorder, status int) {
+    t.Helper()
+    if rec.Code != status {
+        t.Fatalf("status = %d, want %d; body: %s", rec.Code, status, rec.Body)
+    }
+}
+
+func mustRegister(t *testing.T, e *engine.RedditEngine, username string) string {
+    t.Helper()
+    user, err := e.RegisterAccount(username, "password123")
+    if err != nil {
+        t.Fatalf("RegisterAccount(%q): %v", username, err)
+    }
+    return user.ID
+}
\ No newline at end of file
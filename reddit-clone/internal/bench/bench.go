@@ -0,0 +1,375 @@
+// internal/bench/bench.go
+package bench
+
+import (
+    "fmt"
+    "math/rand"
+    "runtime"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/simulator"
+)
+
+// FeedBenchmarkPosts is the number of posts BenchmarkGetFeed runs against
+const FeedBenchmarkPosts = 100000
+
+// ShardBenchmarkGoroutines is how many goroutines BenchmarkPostStore runs
+const ShardBenchmarkGoroutines = 64
+
+// Options sizes the fixture and shapes the workload
+type Options struct {
+    Users             int
+    Subreddits        int
+    Posts             int     // Posts created before the timed run
+    Concurrency       int     // Goroutines issuing operations
+    Ops               int     // Operations in the timed run
+    ReadRatio         float64 // Share of operations that read a feed
+    RepostProbability float64 // Passed to the simulator's action mix
+    Seed              int64   // 0 seeds from the clock
+    PostShards        int     // Post store shards; 0 uses the engine default
+}
+
+// DefaultOptions returns a small workload that finishes in seconds
+func DefaultOptions() Options {
+    return Options{
+        Users:             100,
+        Subreddits:        20,
+        Posts:             10000,
+        Concurrency:       runtime.GOMAXPROCS(0),
+        Ops:               100000,
+        ReadRatio:         0.5,
+        RepostProbability: simulator.DefaultRepostProbability,
+    }
+}
+
+// Fixture is an in-process engine populated for a benchmark
+type Fixture struct {
+    Engine     *engine.RedditEngine
+    Users      []string
+    Subreddits []string
+    userSubs   map[string][]string // map[userID][]subredditID
+    posts      []string
+    postsMtx   sync.RWMutex
+}
+
+// Result summarizes a timed run
+type Result struct {
+    Ops         int64
+    Errors      int64
+    Elapsed     time.Duration
+    OpsPerSec   float64
+    AllocsPerOp uint64
+    BytesPerOp  uint64
+    PerAction   map[string]int64
+}
+
+// Setup builds an engine with the option's users, subreddits and posts.
+// Flood control and duplicate detection are off so setup isn't throttled.
+func Setup(opts Options) (*Fixture, error) {
+    if opts.Users < 2 || opts.Subreddits < 1 {
+        return nil, fmt.Errorf("need at least 2 users and 1 subreddit")
+    }
+    cfg := engine.NewDefaultConfig()
+    cfg.PostCooldown = 0
+    cfg.CommentCooldown = 0
+    cfg.DuplicatePostWindow = 0
+    if opts.PostShards > 0 {
+        cfg.PostShards = opts.PostShards
+    }
+    f := &Fixture{
+        Engine:   engine.NewRedditEngineWithConfig(cfg),
+        Users:    make([]string, opts.Users),
+        userSubs: make(map[string][]string),
+    }
+
+    // Password hashing dominates registration, so spread it over the CPUs
+    var wg sync.WaitGroup
+    errs := make(chan error, opts.Users)
+    for i := range f.Users {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            user, err := f.Engine.RegisterAccount(fmt.Sprintf("bench_user_%d", i), "password123")
+            if err != nil {
+                errs <- err
+                return
+            }
+            f.Users[i] = user.ID
+        }(i)
+    }
+    wg.Wait()
+    close(errs)
+    if err := <-errs; err != nil {
+        return nil, err
+    }
+
+    rng := newRand(opts.Seed)
+    for i := 0; i < opts.Subreddits; i++ {
+        creatorID := f.Users[i%len(f.Users)]
+        subreddit, err := f.Engine.CreateSubReddit(fmt.Sprintf("bench_%d", i), "Benchmark subreddit", creatorID)
+        if err != nil {
+            return nil, err
+        }
+        f.Subreddits = append(f.Subreddits, subreddit.ID)
+        f.userSubs[creatorID] = append(f.userSubs[creatorID], subreddit.ID)
+    }
+
+    // Each user joins 2-5 subreddits, like the simulator's users
+    for _, userID := range f.Users {
+        for j := 2 + rng.Intn(4); j > 0; j-- {
+            subredditID := f.Subreddits[rng.Intn(len(f.Subreddits))]
+            joined, err := f.Engine.JoinSubReddit(userID, subredditID)
+            if err != nil {
+                return nil, err
+            }
+            if joined {
+                f.userSubs[userID] = append(f.userSubs[userID], subredditID)
+            }
+        }
+    }
+
+    for i := 0; i < opts.Posts; i++ {
+        userID := f.Users[rng.Intn(len(f.Users))]
+        if err := f.createPost(rng, userID); err != nil {
+            return nil, err
+        }
+    }
+    return f, nil
+}
+
+// Run drives the engine with the simulator's action mix plus feed reads
+// and reports throughput and allocations
+func Run(f *Fixture, opts Options) Result {
+    if opts.Concurrency <= 0 {
+        opts.Concurrency = 1
+    }
+    var ops, failures atomic.Int64
+    var countsMtx sync.Mutex
+    perAction := make(map[string]int64)
+
+    var before, after runtime.MemStats
+    runtime.GC()
+    runtime.ReadMemStats(&before)
+    start := time.Now()
+
+    var wg sync.WaitGroup
+    remaining := int64(opts.Ops)
+    for w := 0; w < opts.Concurrency; w++ {
+        wg.Add(1)
+        go func(w int) {
+            defer wg.Done()
+            // Each worker gets its own source; a rand.Rand is not goroutine-safe
+            var seed int64
+            if opts.Seed != 0 {
+                seed = opts.Seed + int64(w) + 1
+            }
+            rng := newRand(seed)
+            counts := make(map[string]int64)
+            for atomic.AddInt64(&remaining, -1) >= 0 {
+                name, err := f.step(rng, opts)
+                counts[name]++
+                ops.Add(1)
+                if err != nil {
+                    failures.Add(1)
+                }
+            }
+            countsMtx.Lock()
+            for name, n := range counts {
+                perAction[name] += n
+            }
+            countsMtx.Unlock()
+        }(w)
+    }
+    wg.Wait()
+
+    elapsed := time.Since(start)
+    runtime.ReadMemStats(&after)
+    result := Result{
+        Ops:       ops.Load(),
+        Errors:    failures.Load(),
+        Elapsed:   elapsed,
+        PerAction: perAction,
+    }
+    if result.Ops > 0 {
+        result.OpsPerSec = float64(result.Ops) / elapsed.Seconds()
+        result.AllocsPerOp = (after.Mallocs - before.Mallocs) / uint64(result.Ops)
+        result.BytesPerOp = (after.TotalAlloc - before.TotalAlloc) / uint64(result.Ops)
+    }
+    return result
+}
+
+// BenchmarkGetFeed measures GetFeed against an engine holding
+// FeedBenchmarkPosts posts. Run it with testing.Benchmark, or call it from
+// a Benchmark function in a test file.
+func BenchmarkGetFeed(b *testing.B) {
+    f := feedFixture(b)
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        if _, err := f.Engine.GetFeed(f.Users[i%len(f.Users)]); err != nil {
+            b.Fatal(err)
+        }
+    }
+}
+
+var (
+    feedFixtureOnce sync.Once
+    feedFixtureVal  *Fixture
+    feedFixtureErr  error
+)
+
+// feedFixture builds the BenchmarkGetFeed engine once; testing.Benchmark
+// calls the benchmark repeatedly while it settles on b.N
+func feedFixture(b *testing.B) *Fixture {
+    b.StopTimer()
+    defer b.StartTimer()
+    feedFixtureOnce.Do(func() {
+        opts := DefaultOptions()
+        opts.Posts = FeedBenchmarkPosts
+        opts.Seed = 1
+        feedFixtureVal, feedFixtureErr = Setup(opts)
+    })
+    if feedFixtureErr != nil {
+        b.Fatal(feedFixtureErr)
+    }
+    return feedFixtureVal
+}
+
+// BenchmarkPostStore returns a benchmark in which ShardBenchmarkGoroutines
+// goroutines each create a post and read it back, against an engine whose
+// post store has the given number of shards. One shard is the single-map
+// baseline to compare engine.DefaultPostShards against.
+func BenchmarkPostStore(shards int) func(b *testing.B) {
+    var (
+        once    sync.Once
+        fixture *Fixture
+        err     error
+    )
+    return func(b *testing.B) {
+        b.StopTimer()
+        once.Do(func() {
+            opts := DefaultOptions()
+            opts.Users = ShardBenchmarkGoroutines
+            opts.Posts = 0
+            opts.Seed = 1
+            opts.PostShards = shards
+            fixture, err = Setup(opts)
+        })
+        if err != nil {
+            b.Fatal(err)
+        }
+        b.ReportAllocs()
+        b.StartTimer()
+
+        // Each goroutine posts as its own user so flood control and
+        // per-user state don't serialize them
+        var wg sync.WaitGroup
+        var failure atomic.Value
+        remaining := int64(b.N)
+        for w := 0; w < ShardBenchmarkGoroutines; w++ {
+            wg.Add(1)
+            go func(w int) {
+                defer wg.Done()
+                userID := fixture.Users[w]
+                rng := newRand(int64(w) + 1)
+                subs := fixture.userSubs[userID]
+                for atomic.AddInt64(&remaining, -1) >= 0 {
+                    post, err := fixture.Engine.CreatePost(
+                        fmt.Sprintf("Shard benchmark post %d", rng.Int63()),
+                        "Benchmark content",
+                        userID,
+                        subs[rng.Intn(len(subs))],
+                    )
+                    if err == nil {
+                        _, err = fixture.Engine.GetPost(post.ID)
+                    }
+                    if err != nil {
+                        failure.Store(err)
+                        return
+                    }
+                }
+            }(w)
+        }
+        wg.Wait()
+        if err, ok := failure.Load().(error); ok {
+            b.Fatal(err)
+        }
+    }
+}
+
+// step performs one operation and returns its name
+func (f *Fixture) step(rng *rand.Rand, opts Options) (string, error) {
+    userID := f.Users[rng.Intn(len(f.Users))]
+    if rng.Float64() < opts.ReadRatio {
+        _, err := f.Engine.GetFeed(userID)
+        return "feed", err
+    }
+
+    switch simulator.PickAction(rng, opts.RepostProbability) {
+    case simulator.ActionPost:
+        return "post", f.createPost(rng, userID)
+    case simulator.ActionComment:
+        _, err := f.Engine.CreateComment("Benchmark comment", userID, f.randomPost(rng), nil)
+        return "comment", err
+    case simulator.ActionVote:
+        direction := engine.VoteUp
+        if rng.Intn(2) == 0 {
+            direction = engine.VoteDown
+        }
+        _, err := f.Engine.SetVote(userID, f.randomPost(rng), direction)
+        return "vote", err
+    case simulator.ActionMessage:
+        _, err := f.Engine.SendDirectMessage(userID, f.Users[rng.Intn(len(f.Users))], "Benchmark message")
+        return "message", err
+    default:
+        // A repost reads the feed and posts a copy of one of its posts
+        feed, err := f.Engine.GetFeed(userID)
+        if err != nil || len(feed) == 0 {
+            return "repost", err
+        }
+        original := feed[rng.Intn(len(feed))]
+        subs := f.userSubs[userID]
+        _, err = f.Engine.CreatePost("[Repost] "+original.Title, original.Content, userID, subs[rng.Intn(len(subs))])
+        return "repost", err
+    }
+}
+
+// createPost posts to one of the user's subreddits and remembers the post
+func (f *Fixture) createPost(rng *rand.Rand, userID string) error {
+    subs := f.userSubs[userID]
+    post, err := f.Engine.CreatePost(
+        fmt.Sprintf("Benchmark post %d", rng.Int63()),
+        "Benchmark content",
+        userID,
+        subs[rng.Intn(len(subs))],
+    )
+    if err != nil {
+        return err
+    }
+    f.postsMtx.Lock()
+    f.posts = append(f.posts, post.ID)
+    f.postsMtx.Unlock()
+    return nil
+}
+
+// randomPost returns the ID of a post created through the fixture
+func (f *Fixture) randomPost(rng *rand.Rand) string {
+    f.postsMtx.RLock()
+    defer f.postsMtx.RUnlock()
+    if len(f.posts) == 0 {
+        return ""
+    }
+    return f.posts[rng.Intn(len(f.posts))]
+}
+
+// newRand seeds from the clock when seed is 0
+func newRand(seed int64) *rand.Rand {
+    if seed == 0 {
+        seed = time.Now().UnixNano()
+    }
+    return rand.New(rand.NewSource(seed))
+}
\ No newline at end of file
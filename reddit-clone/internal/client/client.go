@@ -3,11 +3,13 @@ package client
 
 import (
     "context"
+    "crypto/tls"
     "time"
     "sync"
     "errors"
     "google.golang.org/grpc"
     "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/credentials"
     "google.golang.org/grpc/status"
     
     "reddit-clone/internal/models"
@@ -23,17 +25,97 @@ type RedditClient struct {
     mtx       sync.RWMutex
 }
 
-func NewRedditClient(serverAddr string) (*RedditClient, error) {
+// clientOptions holds optional connection settings for NewRedditClient
+type clientOptions struct {
+    useTLS       bool
+    caFile       string
+    serverName   string
+    retries      int
+    retryBackoff time.Duration
+}
+
+// ClientOption configures how NewRedditClient connects to the engine
+type ClientOption func(*clientOptions)
+
+// WithTLS dials the engine over TLS. caFile may be empty to use the system
+// roots; serverName overrides the name checked against the server certificate.
+func WithTLS(caFile, serverName string) ClientOption {
+    return func(o *clientOptions) {
+        o.useTLS = true
+        o.caFile = caFile
+        o.serverName = serverName
+    }
+}
+
+// WithRetry retries calls that fail with codes.Unavailable up to retries
+// more times, doubling the wait between attempts starting from backoff
+func WithRetry(retries int, backoff time.Duration) ClientOption {
+    return func(o *clientOptions) {
+        o.retries = retries
+        o.retryBackoff = backoff
+    }
+}
+
+// retryInterceptor re-issues unary calls that fail with codes.Unavailable
+func (o *clientOptions) retryInterceptor() grpc.UnaryClientInterceptor {
+    return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+        backoff := o.retryBackoff
+        err := invoker(ctx, method, req, reply, cc, callOpts...)
+        for attempt := 0; attempt < o.retries && status.Code(err) == codes.Unavailable; attempt++ {
+            select {
+            case <-time.After(backoff):
+            case <-ctx.Done():
+                return err
+            }
+            backoff *= 2
+            err = invoker(ctx, method, req, reply, cc, callOpts...)
+        }
+        return err
+    }
+}
+
+// transportOption returns the dial option for plaintext or TLS transport
+func (o *clientOptions) transportOption() (grpc.DialOption, error) {
+    if !o.useTLS {
+        return grpc.WithInsecure(), nil
+    }
+    if o.caFile != "" {
+        creds, err := credentials.NewClientTLSFromFile(o.caFile, o.serverName)
+        if err != nil {
+            return nil, err
+        }
+        return grpc.WithTransportCredentials(creds), nil
+    }
+    return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+        ServerName: o.serverName,
+    })), nil
+}
+
+func NewRedditClient(serverAddr string, opts ...ClientOption) (*RedditClient, error) {
+    options := &clientOptions{}
+    for _, opt := range opts {
+        opt(options)
+    }
+    transportOpt, err := options.transportOption()
+    if err != nil {
+        return nil, err
+    }
+
+    dialOpts := []grpc.DialOption{
+        transportOpt,
+        grpc.WithBlock(),
+        grpc.WithTimeout(5*time.Second),
+    }
+    if options.retries > 0 {
+        dialOpts = append(dialOpts, grpc.WithUnaryInterceptor(options.retryInterceptor()))
+    }
+
     ctx, cancel := context.WithCancel(context.Background())
     
     // Set up connection with retry
     var conn *grpc.ClientConn
-    var err error
     for i := 0; i < 3; i++ {
-        conn, err = grpc.DialContext(ctx, serverAddr, 
-            grpc.WithInsecure(),
-            grpc.WithBlock(),
-            grpc.WithTimeout(5*time.Second))
+        conn, err = grpc.DialContext(ctx, serverAddr, dialOpts...)
         if err == nil {
             break
         }
@@ -154,16 +236,7 @@ func (c *RedditClient) CreatePost(title, content, authorID, subredditID string)
         return nil, handleError(err)
     }
 
-    return &models.Post{
-        ID:          resp.Id,
-        Title:       resp.Title,
-        Content:     resp.Content,
-        AuthorID:    resp.AuthorId,
-        SubRedditID: resp.SubredditId,
-        Upvotes:     resp.Upvotes,
-        Downvotes:   resp.Downvotes,
-        CreatedAt:   time.Unix(resp.CreatedAt, 0),
-    }, nil
+    return postFromProto(resp), nil
 }
 
 // CreateComment adds a comment to a post or another comment
@@ -231,20 +304,40 @@ func (c *RedditClient) GetFeed(userID string) ([]*models.Post, error) {
 
     posts := make([]*models.Post, len(resp.Posts))
     for i, p := range resp.Posts {
-        posts[i] = &models.Post{
-            ID:          p.Id,
-            Title:       p.Title,
-            Content:     p.Content,
-            AuthorID:    p.AuthorId,
-            SubRedditID: p.SubredditId,
-            Upvotes:     p.Upvotes,
-            Downvotes:   p.Downvotes,
-            CreatedAt:   time.Unix(p.CreatedAt, 0),
-        }
+        posts[i] = postFromProto(p)
     }
     return posts, nil
 }
 
+// StreamFeed returns a channel that receives the user's current feed and
+// then each new post in their subreddits. The channel is closed when ctx
+// is cancelled or the stream fails.
+func (c *RedditClient) StreamFeed(ctx context.Context, userID string) (<-chan *models.Post, error) {
+    stream, err := c.client.StreamFeed(ctx, &proto.FeedRequest{
+        UserId: userID,
+    })
+    if err != nil {
+        return nil, handleError(err)
+    }
+
+    posts := make(chan *models.Post)
+    go func() {
+        defer close(posts)
+        for {
+            resp, err := stream.Recv()
+            if err != nil {
+                return
+            }
+            select {
+            case posts <- postFromProto(resp):
+            case <-ctx.Done():
+                return
+            }
+        }
+    }()
+    return posts, nil
+}
+
 // SendDirectMessage sends a message from one user to another
 func (c *RedditClient) SendDirectMessage(fromID, toID, content string) (*models.DirectMessage, error) {
     start := time.Now()
@@ -267,6 +360,7 @@ func (c *RedditClient) SendDirectMessage(fromID, toID, content string) (*models.
         Content:   resp.Content,
         IsRead:    resp.IsRead,
         CreatedAt: time.Unix(resp.CreatedAt, 0),
+        ExpiresAt: expiresAtTime(resp.ExpiresAt),
     }, nil
 }
 
@@ -292,6 +386,7 @@ func (c *RedditClient) GetUserMessages(userID string) ([]*models.DirectMessage,
             Content:   m.Content,
             IsRead:    m.IsRead,
             CreatedAt: time.Unix(m.CreatedAt, 0),
+            ExpiresAt: expiresAtTime(m.ExpiresAt),
         }
     }
     return messages, nil
@@ -320,6 +415,29 @@ func (c *RedditClient) GetMetrics() *models.Metrics {
     return c.metrics
 }
 
+// postFromProto converts a gRPC post into the model type
+func postFromProto(p *proto.PostResponse) *models.Post {
+    return &models.Post{
+        ID:          p.Id,
+        Title:       p.Title,
+        Content:     p.Content,
+        AuthorID:    p.AuthorId,
+        SubRedditID: p.SubredditId,
+        Upvotes:     p.Upvotes,
+        Downvotes:   p.Downvotes,
+        CreatedAt:   time.Unix(p.CreatedAt, 0),
+    }
+}
+
+// expiresAtTime converts unix seconds into an optional expiry (nil for 0)
+func expiresAtTime(expiresAt int64) *time.Time {
+    if expiresAt == 0 {
+        return nil
+    }
+    t := time.Unix(expiresAt, 0)
+    return &t
+}
+
 // Error handling helper
 func handleError(err error) error {
     if err == nil {
@@ -0,0 +1,96 @@
+// internal/client/client_test.go
+package client
+
+import (
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/rand"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/pem"
+    "math/big"
+    "net"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/credentials"
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/proto"
+    "reddit-clone/internal/server"
+    "reddit-clone/pkg/metrics"
+)
+
+// writeSelfSignedCert writes a certificate for localhost and its key to
+// dir, returning their paths; the certificate doubles as its own CA
+func writeSelfSignedCert(t *testing.T, dir string) (certFile, keyFile string) {
+    t.Helper()
+    key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    if err != nil {
+        t.Fatalf("generating key: %v", err)
+    }
+    template := &x509.Certificate{
+        SerialNumber:          big.NewInt(1),
+        Subject:               pkix.Name{CommonName: "localhost"},
+        DNSNames:              []string{"localhost"},
+        IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+        NotBefore:             time.Now().Add(-time.Hour),
+        NotAfter:              time.Now().Add(time.Hour),
+        KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+        ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+        BasicConstraintsValid: true,
+        IsCA:                  true,
+    }
+    der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+    if err != nil {
+        t.Fatalf("creating certificate: %v", err)
+    }
+    keyDER, err := x509.MarshalECPrivateKey(key)
+    if err != nil {
+        t.Fatalf("marshalling key: %v", err)
+    }
+
+    certFile = filepath.Join(dir, "cert.pem")
+    keyFile = filepath.Join(dir, "key.pem")
+    if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+        t.Fatalf("writing certificate: %v", err)
+    }
+    if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+        t.Fatalf("writing key: %v", err)
+    }
+    return certFile, keyFile
+}
+
+func TestRegisterOverTLS(t *testing.T) {
+    certFile, keyFile := writeSelfSignedCert(t, t.TempDir())
+
+    // Serve the engine the way cmd/engine does with -tls
+    creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+    if err != nil {
+        t.Fatalf("NewServerTLSFromFile: %v", err)
+    }
+    grpcServer := grpc.NewServer(grpc.Creds(creds))
+    proto.RegisterRedditServiceServer(grpcServer, server.NewRedditServer(engine.NewRedditEngine(), metrics.NewCollector()))
+    lis, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatalf("Listen: %v", err)
+    }
+    go grpcServer.Serve(lis)
+    t.Cleanup(grpcServer.Stop)
+
+    c, err := NewRedditClient(lis.Addr().String(), WithTLS(certFile, "localhost"))
+    if err != nil {
+        t.Fatalf("NewRedditClient: %v", err)
+    }
+    defer c.Close()
+
+    user, err := c.RegisterAccount("alice", "password123")
+    if err != nil {
+        t.Fatalf("RegisterAccount over TLS: %v", err)
+    }
+    if user.Username != "alice" || user.ID == "" {
+        t.Errorf("registered user = %+v, want alice with an ID", user)
+    }
+}
\ No newline at end of file
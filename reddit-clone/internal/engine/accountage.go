@@ -0,0 +1,27 @@
+// internal/engine/accountage.go
+package engine
+
+import (
+    "errors"
+    "fmt"
+    "time"
+)
+
+var ErrAccountTooNew = errors.New("account is too new")
+
+// checkAccountAge returns ErrAccountTooNew when the user's account is
+// younger than Config.MinAccountAge. It is separate from flood control,
+// which limits how often an account of any age may post.
+func (e *RedditEngine) checkAccountAge(userID string) error {
+    if e.config.MinAccountAge <= 0 {
+        return nil
+    }
+    user, err := e.GetUser(userID)
+    if err != nil {
+        return err
+    }
+    if wait := e.config.MinAccountAge - e.clock.Now().Sub(user.CreatedAt); wait > 0 {
+        return fmt.Errorf("%w; accounts must be %v old to post or comment, try again in %v", ErrAccountTooNew, e.config.MinAccountAge, wait.Round(time.Second))
+    }
+    return nil
+}
\ No newline at end of file
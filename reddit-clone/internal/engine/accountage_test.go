@@ -0,0 +1,55 @@
+// internal/engine/accountage_test.go
+package engine
+
+import (
+    "errors"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestMinAccountAge(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.PostCooldown = 0
+    cfg.CommentCooldown = 0
+    cfg.MinAccountAge = 24 * time.Hour
+    e, clock := newTestEngineWithConfig(t, cfg)
+
+    aged := mustRegister(t, e, "aged")
+    subreddit := mustCreateSubreddit(t, e, "golang", aged.ID)
+    clock.Advance(24 * time.Hour)
+    fresh := mustRegister(t, e, "fresh")
+    mustJoin(t, e, fresh.ID, subreddit.ID)
+
+    // An account exactly MinAccountAge old may post and comment
+    post := mustPost(t, e, aged.ID, subreddit.ID)
+    mustComment(t, e, aged.ID, post.ID, nil)
+
+    _, err := e.CreatePost("Spam", "Content", fresh.ID, subreddit.ID)
+    if !errors.Is(err, ErrAccountTooNew) {
+        t.Fatalf("fresh account's post = %v, want ErrAccountTooNew", err)
+    }
+    if !strings.Contains(err.Error(), "try again in 24h0m0s") {
+        t.Errorf("error %q doesn't say how long to wait", err)
+    }
+    if _, err := e.CreateComment("Spam", fresh.ID, post.ID, nil); !errors.Is(err, ErrAccountTooNew) {
+        t.Errorf("fresh account's comment = %v, want ErrAccountTooNew", err)
+    }
+
+    clock.Advance(24*time.Hour - time.Second)
+    if _, err := e.CreatePost("Spam", "Content", fresh.ID, subreddit.ID); !errors.Is(err, ErrAccountTooNew) {
+        t.Errorf("post a second early = %v, want ErrAccountTooNew", err)
+    }
+    clock.Advance(time.Second)
+    mustPost(t, e, fresh.ID, subreddit.ID)
+}
+
+func TestMinAccountAgeOffByDefault(t *testing.T) {
+    e, _ := newTestEngine(t)
+    if e.config.MinAccountAge != 0 {
+        t.Fatalf("default MinAccountAge = %v, want 0", e.config.MinAccountAge)
+    }
+    alice := mustRegister(t, e, "alice")
+    post := mustPost(t, e, alice.ID, mustCreateSubreddit(t, e, "golang", alice.ID).ID)
+    mustComment(t, e, alice.ID, post.ID, nil)
+}
\ No newline at end of file
@@ -0,0 +1,69 @@
+// internal/engine/activity.go
+package engine
+
+import (
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// DayBucket counts what happened in a subreddit over one UTC day
+type DayBucket struct {
+    Day      time.Time // Midnight UTC at the start of the day
+    Posts    int64
+    Comments int64
+    Votes    int64 // Votes cast on the subreddit's posts and comments
+}
+
+// GetSubredditActivity returns one bucket per day for the last days days,
+// oldest first and ending with today, counting the posts, comments and
+// votes created in the subreddit on each day. days < 1 returns today only.
+func (e *RedditEngine) GetSubredditActivity(subredditID string, days int) ([]DayBucket, error) {
+    if _, err := e.GetSubReddit(subredditID); err != nil {
+        return nil, err
+    }
+    if days < 1 {
+        days = 1
+    }
+
+    today := e.clock.Now().UTC().Truncate(24 * time.Hour)
+    start := today.AddDate(0, 0, -(days - 1))
+    buckets := make([]DayBucket, days)
+    for i := range buckets {
+        buckets[i].Day = start.AddDate(0, 0, i)
+    }
+    // bucket returns the bucket t falls in, or nil outside the range
+    bucket := func(t time.Time) *DayBucket {
+        i := int(t.UTC().Sub(start) / (24 * time.Hour))
+        if t.Before(start) || i >= days {
+            return nil
+        }
+        return &buckets[i]
+    }
+
+    targets := make(map[string]bool) // Post and comment IDs in the subreddit
+    for _, post := range e.subredditPostList(subredditID) {
+        targets[post.ID] = true
+        if b := bucket(post.CreatedAt); b != nil {
+            b.Posts++
+        }
+        for _, comment := range e.postCommentList(post.ID) {
+            targets[comment.ID] = true
+            if b := bucket(comment.CreatedAt); b != nil {
+                b.Comments++
+            }
+        }
+    }
+
+    e.votes.Range(func(_, value interface{}) bool {
+        vote := value.(*models.Vote)
+        if !targets[vote.TargetID] {
+            return true
+        }
+        if b := bucket(vote.CreatedAt); b != nil {
+            b.Votes++
+        }
+        return true
+    })
+    return buckets, nil
+}
\ No newline at end of file
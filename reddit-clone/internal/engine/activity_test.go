@@ -0,0 +1,77 @@
+// internal/engine/activity_test.go
+package engine
+
+import (
+    "testing"
+    "time"
+)
+
+func TestGetSubredditActivityBucketsByDay(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    golang := mustCreateSubreddit(t, e, "golang", alice.ID)
+    rust := mustCreateSubreddit(t, e, "rust", alice.ID)
+    mustJoin(t, e, bob.ID, golang.ID)
+
+    day := func(d int) time.Time { return time.Date(2024, time.March, d, 0, 0, 0, 0, time.UTC) }
+
+    // March 1 falls before the window, but its post still collects
+    // activity on later days
+    early := mustPost(t, e, alice.ID, golang.ID)
+
+    clock.Set(day(2).Add(23*time.Hour + 30*time.Minute))
+    later := mustPost(t, e, alice.ID, golang.ID)
+    mustComment(t, e, bob.ID, early.ID, nil)
+    mustVote(t, e, bob.ID, early.ID, VoteUp)
+
+    // Nothing on March 3; just past midnight on March 4 is the next day
+    clock.Set(day(4).Add(10 * time.Minute))
+    comment := mustComment(t, e, alice.ID, later.ID, nil)
+    mustVote(t, e, bob.ID, later.ID, VoteDown)
+    mustVote(t, e, bob.ID, comment.ID, VoteUp)
+    // Other subreddits don't count
+    other := mustPost(t, e, alice.ID, rust.ID)
+    mustVote(t, e, alice.ID, other.ID, VoteUp)
+
+    buckets, err := e.GetSubredditActivity(golang.ID, 3)
+    if err != nil {
+        t.Fatalf("GetSubredditActivity: %v", err)
+    }
+    want := []DayBucket{
+        {Day: day(2), Posts: 1, Comments: 1, Votes: 1},
+        {Day: day(3)},
+        {Day: day(4), Comments: 1, Votes: 2},
+    }
+    if len(buckets) != len(want) {
+        t.Fatalf("got %d buckets, want %d: %+v", len(buckets), len(want), buckets)
+    }
+    for i := range want {
+        if !buckets[i].Day.Equal(want[i].Day) || buckets[i].Posts != want[i].Posts ||
+            buckets[i].Comments != want[i].Comments || buckets[i].Votes != want[i].Votes {
+            t.Errorf("bucket %d = %+v, want %+v", i, buckets[i], want[i])
+        }
+    }
+
+    // A wider window reaches the first post
+    buckets, err = e.GetSubredditActivity(golang.ID, 4)
+    if err != nil {
+        t.Fatalf("GetSubredditActivity: %v", err)
+    }
+    if len(buckets) != 4 || !buckets[0].Day.Equal(day(1)) || buckets[0].Posts != 1 {
+        t.Errorf("4 day window starts with %+v, want March 1 with one post", buckets[0])
+    }
+
+    // Fewer than one day means today alone
+    buckets, err = e.GetSubredditActivity(golang.ID, 0)
+    if err != nil {
+        t.Fatalf("GetSubredditActivity: %v", err)
+    }
+    if len(buckets) != 1 || !buckets[0].Day.Equal(day(4)) || buckets[0].Comments != 1 {
+        t.Errorf("buckets for 0 days = %+v, want today's", buckets)
+    }
+
+    if _, err := e.GetSubredditActivity("no-such-subreddit", 3); err == nil {
+        t.Error("activity for an unknown subreddit succeeded")
+    }
+}
\ No newline at end of file
@@ -0,0 +1,189 @@
+// internal/engine/admin.go
+package engine
+
+import (
+    "errors"
+    "sync"
+    "sync/atomic"
+
+    "reddit-clone/internal/models"
+)
+
+var ErrUserBanned = errors.New("user is banned")
+
+// BanUser bans a user site-wide. Banned users can't log in or create
+// subreddits, posts, comments, votes or messages; their existing content
+// is left in place.
+func (e *RedditEngine) BanUser(userID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    if _, exists := e.users.Load(userID); !exists {
+        return errors.New("user not found")
+    }
+    e.bannedUsers.Store(userID, e.clock.Now())
+    return nil
+}
+
+// UnbanUser lifts a site-wide ban
+func (e *RedditEngine) UnbanUser(userID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    if _, banned := e.bannedUsers.LoadAndDelete(userID); !banned {
+        return errors.New("user is not banned")
+    }
+    return nil
+}
+
+// IsBanned reports whether the user is banned site-wide
+func (e *RedditEngine) IsBanned(userID string) bool {
+    _, banned := e.bannedUsers.Load(userID)
+    return banned
+}
+
+// checkNotBanned returns ErrUserBanned if the user is banned
+func (e *RedditEngine) checkNotBanned(userID string) error {
+    if e.IsBanned(userID) {
+        return ErrUserBanned
+    }
+    return nil
+}
+
+// RemovePost force-deletes a post along with its comments and the votes
+// and reports on them
+func (e *RedditEngine) RemovePost(postID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    removed := make(map[string]bool)
+    if err := e.removePostAndComments(postID, removed); err != nil {
+        return err
+    }
+    e.removeTargetRecords(removed)
+    return nil
+}
+
+// removePostAndComments deletes a post and its comments, adding their IDs
+// to removed so the caller can clear the votes and reports on them
+func (e *RedditEngine) removePostAndComments(postID string, removed map[string]bool) error {
+    postI, ok := e.posts.LoadAndDelete(postID)
+    if !ok {
+        return errors.New("post not found")
+    }
+    post := postI.(*models.Post)
+    e.counters.posts.Add(-1)
+    if idxI, ok := e.subredditPosts.Load(post.SubRedditID); ok {
+        idxI.(*sync.Map).Delete(postID)
+    }
+    if idxI, ok := e.pendingPosts.Load(post.SubRedditID); ok {
+        idxI.(*sync.Map).Delete(postID)
+    }
+    if idxI, ok := e.userPosts.Load(post.AuthorID); ok {
+        idxI.(*sync.Map).Delete(postID)
+    }
+    e.scheduledPosts.Delete(postID)
+
+    removed[postID] = true
+    for _, comment := range e.postCommentList(postID) {
+        if _, ok := e.comments.LoadAndDelete(comment.ID); ok {
+            e.counters.comments.Add(-1)
+        }
+        if idxI, ok := e.userComments.Load(comment.AuthorID); ok {
+            idxI.(*sync.Map).Delete(comment.ID)
+        }
+        e.commentReplies.Delete(comment.ID)
+        removed[comment.ID] = true
+    }
+    e.postComments.Delete(postID)
+    return nil
+}
+
+// RemoveComment force-deletes a comment and every reply beneath it, along
+// with the votes and reports on them
+func (e *RedditEngine) RemoveComment(commentID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    comment, err := e.GetComment(commentID)
+    if err != nil {
+        return err
+    }
+
+    // Walk the post's reply tree down from the comment
+    children := make(map[string][]string)
+    for _, c := range e.postCommentList(comment.PostID) {
+        if c.ParentID != nil {
+            children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+        }
+    }
+    removed := make(map[string]bool)
+    queue := []string{commentID}
+    for len(queue) > 0 {
+        id := queue[0]
+        queue = queue[1:]
+        if removed[id] {
+            continue
+        }
+        removed[id] = true
+        queue = append(queue, children[id]...)
+    }
+
+    var count int64
+    idxI, _ := e.postComments.Load(comment.PostID)
+    for id := range removed {
+        if removedI, ok := e.comments.LoadAndDelete(id); ok {
+            count++
+            if authorI, ok := e.userComments.Load(removedI.(*models.Comment).AuthorID); ok {
+                authorI.(*sync.Map).Delete(id)
+            }
+        }
+        if idxI != nil {
+            idxI.(*sync.Map).Delete(id)
+        }
+        e.commentReplies.Delete(id)
+    }
+    if comment.ParentID != nil {
+        if repliesI, ok := e.commentReplies.Load(*comment.ParentID); ok {
+            repliesI.(*sync.Map).Delete(commentID)
+        }
+    }
+    e.counters.comments.Add(-count)
+    if postI, ok := e.posts.Load(comment.PostID); ok {
+        atomic.AddInt64(&postI.(*models.Post).CommentCount, -count)
+    }
+    e.removeTargetRecords(removed)
+    return nil
+}
+
+// removeTargetRecords deletes the votes and reports on removed content
+func (e *RedditEngine) removeTargetRecords(targets map[string]bool) {
+    e.votes.Range(func(key, value interface{}) bool {
+        vote := value.(*models.Vote)
+        if targets[vote.TargetID] {
+            if _, ok := e.votes.LoadAndDelete(key); ok {
+                e.unindexVote(vote.UserID, vote.TargetID)
+                e.counters.votes.Add(-1)
+            }
+        }
+        return true
+    })
+    e.reports.Range(func(key, value interface{}) bool {
+        if targets[value.(*models.Report).TargetID] {
+            e.reports.Delete(key)
+        }
+        return true
+    })
+}
\ No newline at end of file
@@ -0,0 +1,79 @@
+// internal/engine/announcement.go
+package engine
+
+import (
+    "errors"
+
+    "reddit-clone/internal/models"
+)
+
+// BroadcastAnnouncement posts the same pinned, distinguished announcement
+// to every subreddit that isn't archived, authored by adminID. It skips
+// the membership, approval, cooldown and banned word checks a post would
+// normally get, so callers must already have authenticated an operator.
+func (e *RedditEngine) BroadcastAnnouncement(adminID, title, content string) ([]*models.Post, error) {
+    return e.BroadcastAnnouncementTo(adminID, title, content, nil)
+}
+
+// BroadcastAnnouncementTo is BroadcastAnnouncement limited to the given
+// subreddits; nil means all of them. An unknown subreddit fails the whole
+// broadcast before anything is posted, while archived ones are skipped.
+func (e *RedditEngine) BroadcastAnnouncementTo(adminID, title, content string, subredditIDs []string) ([]*models.Post, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    if _, exists := e.users.Load(adminID); !exists {
+        return nil, errors.New("author not found")
+    }
+    if err := e.checkNotBanned(adminID); err != nil {
+        return nil, err
+    }
+    if title == "" {
+        return nil, errors.New("announcement title cannot be empty")
+    }
+
+    var targets []*models.SubReddit
+    if subredditIDs == nil {
+        e.subreddits.Range(func(_, value interface{}) bool {
+            targets = append(targets, value.(*models.SubReddit))
+            return true
+        })
+    } else {
+        for _, subredditID := range subredditIDs {
+            subreddit, err := e.GetSubReddit(subredditID)
+            if err != nil {
+                return nil, err
+            }
+            targets = append(targets, subreddit)
+        }
+    }
+
+    posts := []*models.Post{}
+    posted := make(map[string]bool)
+    for _, subreddit := range targets {
+        if subreddit.Archived || posted[subreddit.ID] {
+            continue
+        }
+        posted[subreddit.ID] = true
+
+        post := &models.Post{
+            ID:            e.generateID(),
+            Title:         title,
+            Content:       e.storedContent(content),
+            AuthorID:      adminID,
+            SubRedditID:   subreddit.ID,
+            CreatedAt:     e.clock.Now(),
+            Distinguished: true,
+            Pinned:        true,
+        }
+        e.assignSlug(post)
+        e.posts.Store(post.ID, post)
+        e.counters.posts.Add(1)
+        e.announcePost(post)
+        posts = append(posts, post)
+    }
+    return posts, nil
+}
\ No newline at end of file
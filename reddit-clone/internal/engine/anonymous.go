@@ -0,0 +1,54 @@
+// internal/engine/anonymous.go
+package engine
+
+import (
+    "errors"
+
+    "reddit-clone/internal/models"
+)
+
+// AnonymousAuthor stands in for the author of anonymous posts and comments
+const AnonymousAuthor = "anonymous"
+
+var ErrAnonymousNotAllowed = errors.New("this subreddit does not allow anonymous posting")
+
+// PostAuthorFor returns the post's author as the viewer may see it: the
+// real ID for the author and the subreddit's moderators, AnonymousAuthor
+// for everyone else when the post is anonymous
+func (e *RedditEngine) PostAuthorFor(viewerID string, post *models.Post) string {
+    if !post.Anonymous || e.canUnmask(viewerID, post.AuthorID, post.SubRedditID) {
+        return post.AuthorID
+    }
+    return AnonymousAuthor
+}
+
+// CommentAuthorFor is PostAuthorFor for comments
+func (e *RedditEngine) CommentAuthorFor(viewerID string, comment *models.Comment) string {
+    if !comment.Anonymous {
+        return comment.AuthorID
+    }
+    if postI, ok := e.posts.Load(comment.PostID); ok && e.canUnmask(viewerID, comment.AuthorID, postI.(*models.Post).SubRedditID) {
+        return comment.AuthorID
+    }
+    return AnonymousAuthor
+}
+
+// canUnmask reports whether the viewer may see an anonymous author
+func (e *RedditEngine) canUnmask(viewerID, authorID, subredditID string) bool {
+    if viewerID == "" {
+        return false
+    }
+    if viewerID == authorID {
+        return true
+    }
+    subreddit, err := e.GetSubReddit(subredditID)
+    return err == nil && isModerator(viewerID, subreddit)
+}
+
+// actorID is the user a notification names as its sender
+func actorID(authorID string, anonymous bool) string {
+    if anonymous {
+        return AnonymousAuthor
+    }
+    return authorID
+}
\ No newline at end of file
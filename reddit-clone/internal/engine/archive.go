@@ -0,0 +1,59 @@
+// internal/engine/archive.go
+package engine
+
+import (
+    "errors"
+
+    "reddit-clone/internal/models"
+)
+
+// ErrSubredditArchived is returned for posts, comments, edits, joins and
+// votes in an archived subreddit
+var ErrSubredditArchived = errors.New("subreddit is archived")
+
+// ArchiveSubreddit archives or unarchives a subreddit; only moderators may.
+// An archived subreddit is read-only: its content stays visible, but no one
+// can post, comment, edit, join or vote there until it is unarchived.
+func (e *RedditEngine) ArchiveSubreddit(modID, subredditID string, archived bool) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return err
+    }
+    if !isModerator(modID, subreddit) {
+        return ErrNotModerator
+    }
+    subreddit.Archived = archived
+    return nil
+}
+
+// checkNotArchived returns ErrSubredditArchived if the subreddit is archived
+func checkNotArchived(subreddit *models.SubReddit) error {
+    if subreddit.Archived {
+        return ErrSubredditArchived
+    }
+    return nil
+}
+
+// checkTargetNotArchived returns ErrSubredditArchived if a post, or the
+// post a comment is on, belongs to an archived subreddit
+func (e *RedditEngine) checkTargetNotArchived(targetID string) error {
+    postID := targetID
+    if commentI, ok := e.comments.Load(targetID); ok {
+        postID = commentI.(*models.Comment).PostID
+    }
+    postI, ok := e.posts.Load(postID)
+    if !ok {
+        return nil
+    }
+    subreddit, err := e.GetSubReddit(postI.(*models.Post).SubRedditID)
+    if err != nil {
+        return nil
+    }
+    return checkNotArchived(subreddit)
+}
\ No newline at end of file
@@ -0,0 +1,80 @@
+// internal/engine/archive_test.go
+package engine
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestArchivedSubredditRefusesWrites(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    mustJoin(t, e, bob.ID, subreddit.ID)
+    post := mustPost(t, e, bob.ID, subreddit.ID)
+    comment := mustComment(t, e, bob.ID, post.ID, nil)
+
+    if err := e.ArchiveSubreddit(alice.ID, subreddit.ID, true); err != nil {
+        t.Fatalf("ArchiveSubreddit: %v", err)
+    }
+
+    writes := map[string]func() error{
+        "CreatePost": func() error {
+            _, err := e.CreatePost("Another", "post", bob.ID, subreddit.ID)
+            return err
+        },
+        "CreateComment": func() error {
+            _, err := e.CreateComment("Another comment", bob.ID, post.ID, nil)
+            return err
+        },
+        "EditPost": func() error {
+            _, err := e.EditPost(bob.ID, post.ID, "Edited", "Edited content", AnyVersion)
+            return err
+        },
+        "EditComment": func() error {
+            _, err := e.EditComment(bob.ID, comment.ID, "Edited comment", AnyVersion)
+            return err
+        },
+        "Vote": func() error {
+            return e.Vote(alice.ID, post.ID, true)
+        },
+        "JoinSubReddit": func() error {
+            _, err := e.JoinSubReddit(carol.ID, subreddit.ID)
+            return err
+        },
+    }
+    for name, write := range writes {
+        if err := write(); !errors.Is(err, ErrSubredditArchived) {
+            t.Errorf("%s in an archived subreddit: err = %v, want ErrSubredditArchived", name, err)
+        }
+    }
+
+    // Nothing changed, and the content is still readable
+    got, err := e.GetPost(post.ID)
+    if err != nil {
+        t.Fatalf("GetPost: %v", err)
+    }
+    if got.Edited || got.Title != post.Title {
+        t.Errorf("archived post was edited: %+v", got)
+    }
+    comments, err := e.GetComments(post.ID)
+    if err != nil {
+        t.Fatalf("GetComments: %v", err)
+    }
+    if len(comments) != 1 || comments[0].Edited {
+        t.Errorf("GetComments = %+v, want the one unedited comment", comments)
+    }
+    if _, err := e.GetSubReddit(subreddit.ID); err != nil {
+        t.Errorf("GetSubReddit: %v", err)
+    }
+
+    // Unarchiving lets the author edit again
+    if err := e.ArchiveSubreddit(alice.ID, subreddit.ID, false); err != nil {
+        t.Fatalf("ArchiveSubreddit(false): %v", err)
+    }
+    if _, err := e.EditPost(bob.ID, post.ID, "Edited", "Edited content", AnyVersion); err != nil {
+        t.Errorf("EditPost after unarchiving: %v", err)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,83 @@
+// internal/engine/autocomplete.go
+package engine
+
+import (
+    "sort"
+    "strings"
+    "sync/atomic"
+
+    "reddit-clone/internal/models"
+)
+
+// subredditName is an entry in the sorted subreddit name index
+type subredditName struct {
+    lowerName   string
+    subredditID string
+}
+
+// indexSubredditName inserts a subreddit into the name index, keeping it sorted
+func (e *RedditEngine) indexSubredditName(subreddit *models.SubReddit) {
+    entry := subredditName{lowerName: strings.ToLower(subreddit.Name), subredditID: subreddit.ID}
+
+    e.nameIndexMtx.Lock()
+    defer e.nameIndexMtx.Unlock()
+    i := sort.Search(len(e.subredditNames), func(i int) bool {
+        return e.subredditNames[i].lowerName >= entry.lowerName
+    })
+    e.subredditNames = append(e.subredditNames, subredditName{})
+    copy(e.subredditNames[i+1:], e.subredditNames[i:])
+    e.subredditNames[i] = entry
+}
+
+// unindexSubredditName removes a subreddit from the name index
+func (e *RedditEngine) unindexSubredditName(subreddit *models.SubReddit) {
+    lowerName := strings.ToLower(subreddit.Name)
+
+    e.nameIndexMtx.Lock()
+    defer e.nameIndexMtx.Unlock()
+    i := sort.Search(len(e.subredditNames), func(i int) bool {
+        return e.subredditNames[i].lowerName >= lowerName
+    })
+    // Names needn't be unique, so scan the run of equal names for the ID
+    for ; i < len(e.subredditNames) && e.subredditNames[i].lowerName == lowerName; i++ {
+        if e.subredditNames[i].subredditID == subreddit.ID {
+            e.subredditNames = append(e.subredditNames[:i], e.subredditNames[i+1:]...)
+            return
+        }
+    }
+}
+
+// SearchSubredditsByPrefix returns up to limit subreddits whose name starts
+// with prefix (case-insensitive), most members first. A limit of 0 or less
+// returns every match.
+func (e *RedditEngine) SearchSubredditsByPrefix(prefix string, limit int) ([]*models.SubReddit, error) {
+    prefix = strings.ToLower(prefix)
+
+    e.nameIndexMtx.RLock()
+    start := sort.Search(len(e.subredditNames), func(i int) bool {
+        return e.subredditNames[i].lowerName >= prefix
+    })
+    var ids []string
+    for _, entry := range e.subredditNames[start:] {
+        if !strings.HasPrefix(entry.lowerName, prefix) {
+            break
+        }
+        ids = append(ids, entry.subredditID)
+    }
+    e.nameIndexMtx.RUnlock()
+
+    subreddits := make([]*models.SubReddit, 0, len(ids))
+    for _, id := range ids {
+        if subI, ok := e.subreddits.Load(id); ok {
+            subreddits = append(subreddits, subI.(*models.SubReddit))
+        }
+    }
+
+    sort.SliceStable(subreddits, func(i, j int) bool {
+        return atomic.LoadInt64(&subreddits[i].MemberCount) > atomic.LoadInt64(&subreddits[j].MemberCount)
+    })
+    if limit > 0 && len(subreddits) > limit {
+        subreddits = subreddits[:limit]
+    }
+    return subreddits, nil
+}
\ No newline at end of file
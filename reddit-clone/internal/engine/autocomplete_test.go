@@ -0,0 +1,64 @@
+// internal/engine/autocomplete_test.go
+package engine
+
+import (
+    "fmt"
+    "testing"
+
+    "reddit-clone/internal/models"
+)
+
+// subredditNames lists the names of subreddits in order
+func subredditNames(subreddits []*models.SubReddit) []string {
+    names := make([]string, len(subreddits))
+    for i, subreddit := range subreddits {
+        names[i] = subreddit.Name
+    }
+    return names
+}
+
+func TestSearchSubredditsByPrefix(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+    golang := mustCreateSubreddit(t, e, "GoLang", alice.ID)
+    mustCreateSubreddit(t, e, "golf", alice.ID)
+    gophers := mustCreateSubreddit(t, e, "gophers", bob.ID)
+    mustCreateSubreddit(t, e, "rust", carol.ID)
+    mustJoin(t, e, alice.ID, gophers.ID)
+    mustJoin(t, e, carol.ID, gophers.ID)
+    mustJoin(t, e, bob.ID, golang.ID)
+
+    tests := []struct {
+        prefix string
+        limit  int
+        want   []string
+    }{
+        // Most members first, the limit applied after ranking
+        {"go", 0, []string{"gophers", "GoLang", "golf"}},
+        {"GO", 2, []string{"gophers", "GoLang"}},
+        {"gol", 0, []string{"GoLang", "golf"}},
+        {"golang", 1, []string{"GoLang"}},
+        {"", 1, []string{"gophers"}},
+        {"x", 0, []string{}},
+    }
+    for _, tt := range tests {
+        got, err := e.SearchSubredditsByPrefix(tt.prefix, tt.limit)
+        if err != nil {
+            t.Fatalf("SearchSubredditsByPrefix(%q): %v", tt.prefix, err)
+        }
+        if names := subredditNames(got); fmt.Sprint(names) != fmt.Sprint(tt.want) {
+            t.Errorf("SearchSubredditsByPrefix(%q, %d) = %v, want %v", tt.prefix, tt.limit, names, tt.want)
+        }
+    }
+
+    // A deleted subreddit leaves the index
+    if err := e.DeleteSubReddit(bob.ID, gophers.ID); err != nil {
+        t.Fatalf("DeleteSubReddit: %v", err)
+    }
+    got, _ := e.SearchSubredditsByPrefix("gop", 0)
+    if len(got) != 0 {
+        t.Errorf("deleted subreddit still found: %v", subredditNames(got))
+    }
+}
\ No newline at end of file
@@ -0,0 +1,125 @@
+// internal/engine/autoremove.go
+package engine
+
+import (
+    "errors"
+
+    "reddit-clone/internal/models"
+)
+
+// ErrNotAutoRemoved is returned when restoring content that was not auto-removed
+var ErrNotAutoRemoved = errors.New("content was not auto-removed")
+
+// crossesRemovalThreshold reports whether a vote moved a score from at or
+// above Config.AutoRemoveBelowScore to below it
+func (e *RedditEngine) crossesRemovalThreshold(before, after int64) bool {
+    threshold := e.config.AutoRemoveBelowScore
+    return threshold != 0 && before >= threshold && after < threshold
+}
+
+// postAutoRemoved reports whether a post was voted out of listings. Votes
+// set the flag under editMtx, so it is read under it too.
+func (e *RedditEngine) postAutoRemoved(post *models.Post) bool {
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    return post.AutoRemoved
+}
+
+// commentAutoRemoved is postAutoRemoved for a comment
+func (e *RedditEngine) commentAutoRemoved(comment *models.Comment) bool {
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    return comment.AutoRemoved
+}
+
+// listedPosts drops auto-removed posts from a feed or listing
+func (e *RedditEngine) listedPosts(posts []*models.Post) []*models.Post {
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    listed := posts[:0:0]
+    for _, post := range posts {
+        if !post.AutoRemoved {
+            listed = append(listed, post)
+        }
+    }
+    return listed
+}
+
+// listedComments drops auto-removed comments from a listing
+func (e *RedditEngine) listedComments(comments []*models.Comment) []*models.Comment {
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    listed := comments[:0:0]
+    for _, comment := range comments {
+        if !comment.AutoRemoved {
+            listed = append(listed, comment)
+        }
+    }
+    return listed
+}
+
+// RestorePost puts an auto-removed post back in feeds and listings; only
+// the subreddit's moderators may restore it. It is removed again only if
+// its score later climbs back to the threshold and drops below it.
+func (e *RedditEngine) RestorePost(modID, postID string) (*models.Post, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    post, err := e.GetPost(postID)
+    if err != nil {
+        return nil, err
+    }
+    subreddit, err := e.GetSubReddit(post.SubRedditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(modID, subreddit) {
+        return nil, ErrNotModerator
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    if !post.AutoRemoved {
+        return nil, ErrNotAutoRemoved
+    }
+    post.AutoRemoved = false
+    return post, nil
+}
+
+// RestoreComment puts an auto-removed comment, and the replies hidden
+// with it, back in its post's comment tree; only the subreddit's
+// moderators may restore it
+func (e *RedditEngine) RestoreComment(modID, commentID string) (*models.Comment, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    comment, err := e.GetComment(commentID)
+    if err != nil {
+        return nil, err
+    }
+    post, err := e.GetPost(comment.PostID)
+    if err != nil {
+        return nil, err
+    }
+    subreddit, err := e.GetSubReddit(post.SubRedditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(modID, subreddit) {
+        return nil, ErrNotModerator
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    if !comment.AutoRemoved {
+        return nil, ErrNotAutoRemoved
+    }
+    comment.AutoRemoved = false
+    return comment, nil
+}
\ No newline at end of file
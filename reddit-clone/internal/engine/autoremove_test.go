@@ -0,0 +1,146 @@
+// internal/engine/autoremove_test.go
+package engine
+
+import (
+    "fmt"
+    "sync"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// newAutoRemoveEngine returns a test engine that hides content once its
+// score drops below -2
+func newAutoRemoveEngine(t *testing.T) *RedditEngine {
+    t.Helper()
+    cfg := NewDefaultConfig()
+    cfg.PostCooldown = 0
+    cfg.CommentCooldown = 0
+    cfg.DuplicatePostWindow = 0
+    cfg.AutoRemoveBelowScore = -2
+    e, _ := newTestEngineWithConfig(t, cfg)
+    return e
+}
+
+func containsPost(posts []*models.Post, postID string) bool {
+    for _, post := range posts {
+        if post.ID == postID {
+            return true
+        }
+    }
+    return false
+}
+
+func TestDownvotedPostLeavesFeedUntilRestored(t *testing.T) {
+    e := newAutoRemoveEngine(t)
+    mod := mustRegister(t, e, "mod")
+    author := mustRegister(t, e, "author")
+    subreddit := mustCreateSubreddit(t, e, "golang", mod.ID)
+    mustJoin(t, e, author.ID, subreddit.ID)
+    post := mustPost(t, e, author.ID, subreddit.ID)
+
+    listed := func() (feed, listing, popular bool) {
+        t.Helper()
+        feedPosts, err := e.GetFeed(mod.ID)
+        if err != nil {
+            t.Fatalf("GetFeed: %v", err)
+        }
+        listPosts, err := e.ListPosts(mod.ID, subreddit.ID)
+        if err != nil {
+            t.Fatalf("ListPosts: %v", err)
+        }
+        popularPosts, err := e.GetPopularPosts(24*time.Hour, 10)
+        if err != nil {
+            t.Fatalf("GetPopularPosts: %v", err)
+        }
+        return containsPost(feedPosts, post.ID), containsPost(listPosts, post.ID), containsPost(popularPosts, post.ID)
+    }
+
+    // Reaching the threshold isn't enough; dropping below it is
+    for i := 0; i < 2; i++ {
+        voter := mustRegister(t, e, fmt.Sprintf("voter%d", i))
+        mustVote(t, e, voter.ID, post.ID, VoteDown)
+    }
+    if feed, listing, popular := listed(); !feed || !listing || !popular {
+        t.Fatalf("post at the threshold listed in feed=%v listing=%v popular=%v, want all", feed, listing, popular)
+    }
+    voter := mustRegister(t, e, "voter2")
+    mustVote(t, e, voter.ID, post.ID, VoteDown)
+    if feed, listing, popular := listed(); feed || listing || popular {
+        t.Fatalf("post below the threshold listed in feed=%v listing=%v popular=%v, want none", feed, listing, popular)
+    }
+
+    if _, err := e.RestorePost(author.ID, post.ID); err != ErrNotModerator {
+        t.Errorf("RestorePost by the author: err = %v, want ErrNotModerator", err)
+    }
+    if _, err := e.RestorePost(mod.ID, post.ID); err != nil {
+        t.Fatalf("RestorePost: %v", err)
+    }
+    if feed, listing, popular := listed(); !feed || !listing || !popular {
+        t.Errorf("restored post listed in feed=%v listing=%v popular=%v, want all", feed, listing, popular)
+    }
+    if _, err := e.RestorePost(mod.ID, post.ID); err != ErrNotAutoRemoved {
+        t.Errorf("second RestorePost: err = %v, want ErrNotAutoRemoved", err)
+    }
+}
+
+func TestDownvotedCommentLeavesListingUntilRestored(t *testing.T) {
+    e := newAutoRemoveEngine(t)
+    mod := mustRegister(t, e, "mod")
+    subreddit := mustCreateSubreddit(t, e, "golang", mod.ID)
+    post := mustPost(t, e, mod.ID, subreddit.ID)
+    comment := mustComment(t, e, mod.ID, post.ID, nil)
+
+    for i := 0; i < 3; i++ {
+        voter := mustRegister(t, e, fmt.Sprintf("voter%d", i))
+        mustVote(t, e, voter.ID, comment.ID, VoteDown)
+    }
+    if comments, _ := e.GetComments(post.ID); len(comments) != 0 {
+        t.Fatalf("GetComments = %d comments, want the removed one hidden", len(comments))
+    }
+    if _, err := e.RestoreComment(mod.ID, comment.ID); err != nil {
+        t.Fatalf("RestoreComment: %v", err)
+    }
+    if comments, _ := e.GetComments(post.ID); len(comments) != 1 {
+        t.Errorf("GetComments = %d comments after restoring, want 1", len(comments))
+    }
+}
+
+// Listing while votes remove posts must not race; run with -race
+func TestListingWhileVotesRemovePosts(t *testing.T) {
+    e := newAutoRemoveEngine(t)
+    mod := mustRegister(t, e, "mod")
+    subreddit := mustCreateSubreddit(t, e, "golang", mod.ID)
+    var posts []*models.Post
+    for i := 0; i < 10; i++ {
+        posts = append(posts, mustPost(t, e, mod.ID, subreddit.ID))
+    }
+    var voters []*models.User
+    for i := 0; i < 3; i++ {
+        voters = append(voters, mustRegister(t, e, fmt.Sprintf("voter%d", i)))
+    }
+
+    var wg sync.WaitGroup
+    wg.Add(2)
+    go func() {
+        defer wg.Done()
+        for _, post := range posts {
+            for _, voter := range voters {
+                mustVote(t, e, voter.ID, post.ID, VoteDown)
+            }
+        }
+    }()
+    go func() {
+        defer wg.Done()
+        for i := 0; i < 50; i++ {
+            e.ListPosts(mod.ID, subreddit.ID)
+            e.GetPopularPosts(24*time.Hour, 10)
+        }
+    }()
+    wg.Wait()
+
+    if listed, _ := e.ListPosts(mod.ID, subreddit.ID); len(listed) != 0 {
+        t.Errorf("ListPosts = %d posts after downvoting all, want 0", len(listed))
+    }
+}
\ No newline at end of file
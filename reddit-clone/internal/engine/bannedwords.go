@@ -0,0 +1,144 @@
+// internal/engine/bannedwords.go
+package engine
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+    "unicode"
+
+    "reddit-clone/internal/models"
+)
+
+const (
+    MaxBannedWords      = 200 // Most entries a subreddit's banned word list may hold
+    MaxBannedWordLength = 64  // Longest entry, in bytes
+)
+
+var ErrBannedWord = errors.New("content contains a word banned in this subreddit")
+
+// SetBannedWords replaces a subreddit's banned word list; only moderators
+// may change it. An entry may be a single word or a phrase. Entries are
+// lowercased with their whitespace collapsed, and duplicates dropped. An
+// empty list turns the filter off.
+func (e *RedditEngine) SetBannedWords(userID, subredditID string, words []string) ([]string, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(userID, subreddit) {
+        return nil, ErrNotModerator
+    }
+
+    banned := make([]string, 0, len(words))
+    seen := make(map[string]bool)
+    for _, word := range words {
+        tokens := wordTokens(word)
+        if len(tokens) == 0 {
+            return nil, fmt.Errorf("banned word %q has no letters or digits", word)
+        }
+        word = strings.Join(tokens, " ")
+        if len(word) > MaxBannedWordLength {
+            return nil, fmt.Errorf("banned word %q exceeds the maximum length of %d", word, MaxBannedWordLength)
+        }
+        if !seen[word] {
+            seen[word] = true
+            banned = append(banned, word)
+        }
+    }
+    if len(banned) > MaxBannedWords {
+        return nil, fmt.Errorf("a subreddit may ban at most %d words", MaxBannedWords)
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    subreddit.BannedWords = banned
+    return append([]string(nil), banned...), nil
+}
+
+// GetBannedWords returns a subreddit's banned word list; only moderators
+// may read it
+func (e *RedditEngine) GetBannedWords(userID, subredditID string) ([]string, error) {
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(userID, subreddit) {
+        return nil, ErrNotModerator
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    return append([]string{}, subreddit.BannedWords...), nil
+}
+
+// checkBannedWords returns ErrBannedWord if any of the texts contains an
+// entry of the subreddit's banned word list
+func (e *RedditEngine) checkBannedWords(subreddit *models.SubReddit, texts ...string) error {
+    e.editMtx.Lock()
+    banned := subreddit.BannedWords
+    e.editMtx.Unlock()
+    if len(banned) == 0 {
+        return nil
+    }
+
+    for _, text := range texts {
+        if word := findBannedWord(wordTokens(text), banned); word != "" {
+            return fmt.Errorf("%w: %q", ErrBannedWord, word)
+        }
+    }
+    return nil
+}
+
+// findBannedWord returns the first banned entry whose words appear
+// consecutively in tokens, or "" if none does. Matching whole words
+// keeps "ass" from matching "class".
+func findBannedWord(tokens []string, banned []string) string {
+    for _, word := range banned {
+        phrase := strings.Fields(word)
+        for i := 0; i+len(phrase) <= len(tokens); i++ {
+            if equalTokens(tokens[i:i+len(phrase)], phrase) {
+                return word
+            }
+        }
+    }
+    return ""
+}
+
+// equalTokens reports whether two word slices are the same
+func equalTokens(a, b []string) bool {
+    for i := range a {
+        if a[i] != b[i] {
+            return false
+        }
+    }
+    return len(a) == len(b)
+}
+
+// wordTokens lowercases text and splits it into runs of letters and
+// digits, so punctuation and case never hide a word
+func wordTokens(text string) []string {
+    return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+        return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+    })
+}
+
+// checkCommentBannedWords checks a comment's new content against the list
+// of the subreddit its post belongs to
+func (e *RedditEngine) checkCommentBannedWords(comment *models.Comment, content string) error {
+    post, err := e.GetPost(comment.PostID)
+    if err != nil {
+        return nil
+    }
+    subreddit, err := e.GetSubReddit(post.SubRedditID)
+    if err != nil {
+        return nil
+    }
+    return e.checkBannedWords(subreddit, content)
+}
\ No newline at end of file
@@ -0,0 +1,103 @@
+// internal/engine/bannedwords_test.go
+package engine
+
+import (
+    "errors"
+    "reflect"
+    "testing"
+)
+
+func TestSetBannedWordsNormalizes(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    sub := mustCreateSubreddit(t, e, "golang", alice.ID)
+
+    got, err := e.SetBannedWords(alice.ID, sub.ID, []string{"Spam", "  buy   NOW ", "spam", "free-money"})
+    if err != nil {
+        t.Fatalf("SetBannedWords: %v", err)
+    }
+    want := []string{"spam", "buy now", "free money"}
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("SetBannedWords = %q, want %q", got, want)
+    }
+
+    if _, err := e.SetBannedWords(alice.ID, sub.ID, []string{"!!!"}); err == nil {
+        t.Error("an entry without letters or digits was accepted")
+    }
+}
+
+func TestSetBannedWordsRequiresModerator(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    sub := mustCreateSubreddit(t, e, "golang", alice.ID)
+
+    if _, err := e.SetBannedWords(bob.ID, sub.ID, []string{"spam"}); !errors.Is(err, ErrNotModerator) {
+        t.Errorf("SetBannedWords by a non-moderator: err = %v, want ErrNotModerator", err)
+    }
+    if _, err := e.GetBannedWords(bob.ID, sub.ID); !errors.Is(err, ErrNotModerator) {
+        t.Errorf("GetBannedWords by a non-moderator: err = %v, want ErrNotModerator", err)
+    }
+}
+
+func TestBannedWordsMatchWholeWords(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    sub := mustCreateSubreddit(t, e, "golang", alice.ID)
+    if _, err := e.SetBannedWords(alice.ID, sub.ID, []string{"ass", "buy now"}); err != nil {
+        t.Fatalf("SetBannedWords: %v", err)
+    }
+
+    tests := []struct {
+        content string
+        banned  bool
+    }{
+        {"What an ass", true},
+        {"ASS!", true},
+        {"a.s.s is fine, ass. is not", true},
+        {"Buy now, while stocks last", true},
+        {"buy\n\tnow", true},
+        {"First class passes", false},
+        {"Assembly language", false},
+        {"buy it now", false},
+        {"nowhere to buy", false},
+    }
+    for _, tt := range tests {
+        _, err := e.CreatePost("Title", tt.content, alice.ID, sub.ID)
+        if banned := errors.Is(err, ErrBannedWord); banned != tt.banned {
+            t.Errorf("CreatePost(%q): err = %v, want banned %v", tt.content, err, tt.banned)
+        }
+    }
+}
+
+func TestBannedWordsCoverTitlesCommentsAndEdits(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    sub := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, sub.ID)
+    comment := mustComment(t, e, alice.ID, post.ID, nil)
+    if _, err := e.SetBannedWords(alice.ID, sub.ID, []string{"spam"}); err != nil {
+        t.Fatalf("SetBannedWords: %v", err)
+    }
+
+    if _, err := e.CreatePost("Spam inside", "Clean body", alice.ID, sub.ID); !errors.Is(err, ErrBannedWord) {
+        t.Errorf("banned title: err = %v, want ErrBannedWord", err)
+    }
+    if _, err := e.CreateComment("more spam", alice.ID, post.ID, nil); !errors.Is(err, ErrBannedWord) {
+        t.Errorf("banned comment: err = %v, want ErrBannedWord", err)
+    }
+    if _, err := e.EditPost(alice.ID, post.ID, post.Title, "now with spam", post.Version); !errors.Is(err, ErrBannedWord) {
+        t.Errorf("banned post edit: err = %v, want ErrBannedWord", err)
+    }
+    if _, err := e.EditComment(alice.ID, comment.ID, "edited to spam", comment.Version); !errors.Is(err, ErrBannedWord) {
+        t.Errorf("banned comment edit: err = %v, want ErrBannedWord", err)
+    }
+
+    // An empty list turns the filter off
+    if _, err := e.SetBannedWords(alice.ID, sub.ID, nil); err != nil {
+        t.Fatalf("SetBannedWords: %v", err)
+    }
+    if _, err := e.CreateComment("spam is fine now", alice.ID, post.ID, nil); err != nil {
+        t.Errorf("comment after clearing the list: %v", err)
+    }
+}
\ No newline at end of file
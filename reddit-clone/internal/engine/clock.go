@@ -0,0 +1,61 @@
+// internal/engine/clock.go
+package engine
+
+import (
+    "sync"
+    "time"
+)
+
+// Clock tells the engine the current time. Cooldowns, edit windows,
+// message expiry, account age and timestamps on new records all read it,
+// so tests can control time instead of sleeping.
+type Clock interface {
+    Now() time.Time
+}
+
+// realClock is the default clock: the system time
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+    return time.Now()
+}
+
+// FakeClock is a Clock that only moves when told to, for deterministic
+// tests of time-based behaviour
+type FakeClock struct {
+    mtx sync.Mutex
+    now time.Time
+}
+
+// NewFakeClock creates a clock stopped at start
+func NewFakeClock(start time.Time) *FakeClock {
+    return &FakeClock{now: start}
+}
+
+func (c *FakeClock) Now() time.Time {
+    c.mtx.Lock()
+    defer c.mtx.Unlock()
+    return c.now
+}
+
+// Advance moves the clock forward by d
+func (c *FakeClock) Advance(d time.Duration) {
+    c.mtx.Lock()
+    c.now = c.now.Add(d)
+    c.mtx.Unlock()
+}
+
+// Set moves the clock to t
+func (c *FakeClock) Set(t time.Time) {
+    c.mtx.Lock()
+    c.now = t
+    c.mtx.Unlock()
+}
+
+// WithClock makes the engine read the time from clock and returns the
+// engine, so it can follow a constructor. Call it before the engine is
+// used; swapping clocks under load is not synchronized.
+func (e *RedditEngine) WithClock(clock Clock) *RedditEngine {
+    e.clock = clock
+    return e
+}
\ No newline at end of file
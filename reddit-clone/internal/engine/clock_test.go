@@ -0,0 +1,56 @@
+// internal/engine/clock_test.go
+package engine
+
+import (
+    "testing"
+    "time"
+)
+
+func TestFakeClockMovesOnlyWhenTold(t *testing.T) {
+    clock := NewFakeClock(testStart)
+    if !clock.Now().Equal(testStart) {
+        t.Fatalf("Now = %v, want %v", clock.Now(), testStart)
+    }
+    clock.Advance(time.Hour)
+    if want := testStart.Add(time.Hour); !clock.Now().Equal(want) {
+        t.Errorf("Now after Advance = %v, want %v", clock.Now(), want)
+    }
+    clock.Set(testStart)
+    if !clock.Now().Equal(testStart) {
+        t.Errorf("Now after Set = %v, want %v", clock.Now(), testStart)
+    }
+}
+
+// The edit window expires as the fake clock passes it, without sleeping
+func TestEditWindowExpiresOnTheFakeClock(t *testing.T) {
+    e, clock := newTestEngine(t)
+    window := e.config.MessageEditWindow
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+
+    msg, err := e.SendDirectMessage(alice.ID, bob.ID, "Hello")
+    if err != nil {
+        t.Fatalf("SendDirectMessage: %v", err)
+    }
+    if !msg.CreatedAt.Equal(testStart) {
+        t.Errorf("message CreatedAt = %v, want the fake clock's %v", msg.CreatedAt, testStart)
+    }
+
+    // The last moment of the window still counts
+    clock.Advance(window)
+    if err := e.EditMessage(alice.ID, msg.ID, "Hello again"); err != nil {
+        t.Fatalf("EditMessage at the end of the window: %v", err)
+    }
+
+    clock.Advance(time.Nanosecond)
+    if err := e.EditMessage(alice.ID, msg.ID, "Too late"); err != ErrMessageWindowExpired {
+        t.Fatalf("EditMessage past the window: err = %v, want ErrMessageWindowExpired", err)
+    }
+    got, err := e.GetMessage(alice.ID, msg.ID)
+    if err != nil {
+        t.Fatalf("GetMessage: %v", err)
+    }
+    if got.Content != "Hello again" {
+        t.Errorf("content = %q, want the edit made within the window", got.Content)
+    }
+}
\ No newline at end of file
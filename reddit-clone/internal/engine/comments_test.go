@@ -0,0 +1,85 @@
+// internal/engine/comments_test.go
+package engine
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestCreateCommentEnforcesMaxDepth(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.CommentCooldown = 0
+    cfg.MaxCommentDepth = 3
+    e, _ := newTestEngineWithConfig(t, cfg)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+
+    // Depths 0 through 3 are allowed
+    parent := mustComment(t, e, alice.ID, post.ID, nil)
+    for depth := 1; depth <= cfg.MaxCommentDepth; depth++ {
+        parent = mustComment(t, e, alice.ID, post.ID, &parent.ID)
+        if parent.Depth != depth {
+            t.Fatalf("reply depth = %d, want %d", parent.Depth, depth)
+        }
+    }
+
+    if _, err := e.CreateComment("Too deep", alice.ID, post.ID, &parent.ID); err == nil {
+        t.Fatal("reply past MaxCommentDepth was accepted")
+    }
+}
+
+func TestCreateCommentRejectsParentOnAnotherPost(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    first := mustPost(t, e, alice.ID, subreddit.ID)
+    second := mustPost(t, e, alice.ID, subreddit.ID)
+    parent := mustComment(t, e, alice.ID, first.ID, nil)
+
+    _, err := e.CreateComment("Misplaced reply", alice.ID, second.ID, &parent.ID)
+    if !errors.Is(err, ErrParentNotOnPost) {
+        t.Fatalf("err = %v, want ErrParentNotOnPost", err)
+    }
+    if second.CommentCount != 0 {
+        t.Errorf("second post comment count = %d, want 0", second.CommentCount)
+    }
+}
+
+func TestGetCommentContextWalksToTheRoot(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+    root := mustComment(t, e, alice.ID, post.ID, nil)
+    middle := mustComment(t, e, alice.ID, post.ID, &root.ID)
+    leaf := mustComment(t, e, alice.ID, post.ID, &middle.ID)
+    mustComment(t, e, alice.ID, post.ID, &root.ID) // A sibling stays out of the chain
+
+    chain, err := e.GetCommentContext(leaf.ID)
+    if err != nil {
+        t.Fatalf("GetCommentContext: %v", err)
+    }
+    want := []string{root.ID, middle.ID, leaf.ID}
+    if len(chain) != len(want) {
+        t.Fatalf("chain has %d comments, want %d", len(chain), len(want))
+    }
+    for i, comment := range chain {
+        if comment.ID != want[i] {
+            t.Errorf("chain[%d] = %s, want %s", i, comment.ID, want[i])
+        }
+    }
+
+    if chain, err := e.GetCommentContext(root.ID); err != nil || len(chain) != 1 || chain[0].ID != root.ID {
+        t.Errorf("context of a top-level comment = %d comments, %v; want just itself", len(chain), err)
+    }
+    if _, err := e.GetCommentContext("missing"); err == nil {
+        t.Error("context of an unknown comment succeeded")
+    }
+
+    // A malformed parent loop is reported rather than walked forever
+    root.ParentID = &leaf.ID
+    if _, err := e.GetCommentContext(leaf.ID); err == nil {
+        t.Error("context of a cyclic chain succeeded")
+    }
+}
\ No newline at end of file
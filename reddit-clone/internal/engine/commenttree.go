@@ -0,0 +1,199 @@
+// internal/engine/commenttree.go
+package engine
+
+import (
+    "fmt"
+    "sort"
+    "sync"
+
+    "reddit-clone/internal/models"
+)
+
+// Comment sort orders accepted by GetCommentTree
+const (
+    CommentSortTop = "top" // Highest score first
+    CommentSortNew = "new" // Newest first
+    CommentSortOld = "old" // Oldest first
+)
+
+// CommentTreeOptions selects the order and page of a post's comment tree
+type CommentTreeOptions struct {
+    Sort     string // CommentSortTop (default), CommentSortNew or CommentSortOld
+    Page     int    // 1-based; 0 means the first page
+    Limit    int    // Top-level comments per page; 0 means all of them
+    Levels   int    // Tree levels returned, 1 being top-level only; 0 means all
+    ViewerID string // Seeds the shuffled order of a contest-mode post, replacing Sort
+}
+
+// CommentNode is a comment in a fetched tree. When the tree is cut off by
+// CommentTreeOptions.Levels, the deepest returned comments that have
+// replies report how many comments were left out below them. Collapsed
+// comments are still returned in full; the flags only set how a client
+// first shows them.
+type CommentNode struct {
+    *models.Comment
+    HasMoreReplies   bool
+    RemainingReplies int  // Hidden replies at every depth below this comment
+    Collapsed        bool // Scored below Config.CollapseBelowScore
+    InCollapsed      bool // Below a collapsed comment, so hidden until it is expanded
+}
+
+// GetCommentTree returns one page of a post's comments in thread order:
+// each top-level comment is followed by its replies, depth first, with
+// siblings in the requested sort order. Pages split only between
+// top-level comments, so a thread is never cut across pages. It also
+// returns the total number of top-level comments on the post.
+func (e *RedditEngine) GetCommentTree(postID string, opts CommentTreeOptions) ([]CommentNode, int, error) {
+    post, err := e.GetPost(postID)
+    if err != nil {
+        return nil, 0, err
+    }
+    less, err := commentOrder(opts.Sort)
+    if err != nil {
+        return nil, 0, err
+    }
+    if post.ContestMode {
+        less = contestOrder(opts.ViewerID, postID)
+    }
+    if opts.Page < 0 || opts.Limit < 0 || opts.Levels < 0 {
+        return nil, 0, fmt.Errorf("page, limit and levels cannot be negative")
+    }
+
+    var topLevel []*models.Comment
+    children := make(map[string][]*models.Comment)
+    // An auto-removed comment's replies are never reached, so they are
+    // hidden with it
+    for _, comment := range e.listedComments(e.postCommentList(postID)) {
+        if comment.ParentID == nil {
+            topLevel = append(topLevel, comment)
+        } else {
+            children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+        }
+    }
+    total := len(topLevel)
+
+    sortComments(topLevel, less)
+    if opts.Limit > 0 {
+        start := (max(opts.Page, 1) - 1) * opts.Limit
+        if start > len(topLevel) {
+            start = len(topLevel)
+        }
+        end := min(start+opts.Limit, len(topLevel))
+        topLevel = topLevel[start:end]
+    }
+
+    // Contest mode hides scores, so collapsing would leak them
+    threshold := e.config.CollapseBelowScore
+    if post.ContestMode {
+        threshold = 0
+    }
+
+    var tree []CommentNode
+    var walk func(comment *models.Comment, level int, inCollapsed bool)
+    walk = func(comment *models.Comment, level int, inCollapsed bool) {
+        node := CommentNode{
+            Comment:     comment,
+            Collapsed:   threshold != 0 && comment.Score() < threshold,
+            InCollapsed: inCollapsed,
+        }
+        replies := children[comment.ID]
+        if opts.Levels > 0 && level == opts.Levels && len(replies) > 0 {
+            node.HasMoreReplies = true
+            node.RemainingReplies = countDescendants(comment.ID, children)
+            tree = append(tree, node)
+            return
+        }
+        tree = append(tree, node)
+        sortComments(replies, less)
+        for _, reply := range replies {
+            walk(reply, level+1, inCollapsed || node.Collapsed)
+        }
+    }
+    for _, comment := range topLevel {
+        walk(comment, 1, false)
+    }
+    return tree, total, nil
+}
+
+// countDescendants counts the replies below a comment at every depth
+func countDescendants(commentID string, children map[string][]*models.Comment) int {
+    count := 0
+    for _, reply := range children[commentID] {
+        count += 1 + countDescendants(reply.ID, children)
+    }
+    return count
+}
+
+// commentOrder returns the comparison for a sort order
+func commentOrder(order string) (func(a, b *models.Comment) bool, error) {
+    switch order {
+    case "", CommentSortTop:
+        return func(a, b *models.Comment) bool { return a.Score() > b.Score() }, nil
+    case CommentSortNew:
+        return func(a, b *models.Comment) bool { return a.CreatedAt.After(b.CreatedAt) }, nil
+    case CommentSortOld:
+        return func(a, b *models.Comment) bool { return a.CreatedAt.Before(b.CreatedAt) }, nil
+    }
+    return nil, fmt.Errorf("unknown comment sort %q", order)
+}
+
+// sortComments orders comments by less, falling back to oldest first so
+// pages are stable between requests
+func sortComments(comments []*models.Comment, less func(a, b *models.Comment) bool) {
+    sort.SliceStable(comments, func(i, j int) bool {
+        if less(comments[i], comments[j]) {
+            return true
+        }
+        if less(comments[j], comments[i]) {
+            return false
+        }
+        if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+            return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+        }
+        return comments[i].ID < comments[j].ID
+    })
+}
+
+// GetReplies returns one page of a comment's direct replies, highest
+// score first, and the total number of direct replies. Replies to those
+// replies are not included.
+func (e *RedditEngine) GetReplies(commentID string, page, limit int) ([]*models.Comment, int, error) {
+    return e.GetRepliesWithOptions(commentID, CommentTreeOptions{Page: page, Limit: limit})
+}
+
+// GetRepliesWithOptions is GetReplies with a choice of sort order; Limit
+// counts direct replies
+func (e *RedditEngine) GetRepliesWithOptions(commentID string, opts CommentTreeOptions) ([]*models.Comment, int, error) {
+    comment, err := e.GetComment(commentID)
+    if err != nil {
+        return nil, 0, err
+    }
+    less, err := commentOrder(opts.Sort)
+    if err != nil {
+        return nil, 0, err
+    }
+    if postI, ok := e.posts.Load(comment.PostID); ok && postI.(*models.Post).ContestMode {
+        less = contestOrder(opts.ViewerID, comment.PostID)
+    }
+    if opts.Page < 0 || opts.Limit < 0 {
+        return nil, 0, fmt.Errorf("page and limit cannot be negative")
+    }
+
+    replies := []*models.Comment{}
+    if idxI, ok := e.commentReplies.Load(commentID); ok {
+        idxI.(*sync.Map).Range(func(key, _ interface{}) bool {
+            if replyI, ok := e.comments.Load(key); ok && !e.commentAutoRemoved(replyI.(*models.Comment)) {
+                replies = append(replies, replyI.(*models.Comment))
+            }
+            return true
+        })
+    }
+    total := len(replies)
+
+    sortComments(replies, less)
+    if opts.Limit > 0 {
+        start := min((max(opts.Page, 1)-1)*opts.Limit, len(replies))
+        replies = replies[start:min(start+opts.Limit, len(replies))]
+    }
+    return replies, total, nil
+}
\ No newline at end of file
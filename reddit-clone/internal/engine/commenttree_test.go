@@ -0,0 +1,306 @@
+// internal/engine/commenttree_test.go
+package engine
+
+import (
+    "fmt"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// commentThread is a post with five top-level comments a minute apart,
+// where the second has a reply chain
+type commentThread struct {
+    e        *RedditEngine
+    post     *models.Post
+    topLevel []*models.Comment
+    replies  map[string]*models.Comment // By content
+}
+
+func newCommentThread(t *testing.T) *commentThread {
+    t.Helper()
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+    thread := &commentThread{e: e, post: post, replies: make(map[string]*models.Comment)}
+
+    comment := func(content string, parentID *string) *models.Comment {
+        t.Helper()
+        clock.Advance(time.Minute)
+        c, err := e.CreateComment(content, alice.ID, post.ID, parentID)
+        if err != nil {
+            t.Fatalf("CreateComment: %v", err)
+        }
+        return c
+    }
+    for i := 0; i < 5; i++ {
+        thread.topLevel = append(thread.topLevel, comment(fmt.Sprintf("t%d", i), nil))
+    }
+    t1 := thread.topLevel[1]
+    thread.replies["r1a"] = comment("r1a", &t1.ID)
+    thread.replies["r1b"] = comment("r1b", &t1.ID)
+    thread.replies["r1a1"] = comment("r1a1", &thread.replies["r1a"].ID)
+
+    // t3 scores 2 and t1 scores 1; the rest tie at 0
+    mustVote(t, e, alice.ID, thread.topLevel[3].ID, VoteUp)
+    mustVote(t, e, bob.ID, thread.topLevel[3].ID, VoteUp)
+    mustVote(t, e, bob.ID, t1.ID, VoteUp)
+    return thread
+}
+
+// contents lists the content of each node in order
+func contents(nodes []CommentNode) []string {
+    out := make([]string, len(nodes))
+    for i, node := range nodes {
+        out[i] = node.Content
+    }
+    return out
+}
+
+func TestCommentTreeSortAndPages(t *testing.T) {
+    thread := newCommentThread(t)
+
+    tests := []struct {
+        name string
+        opts CommentTreeOptions
+        want []string
+    }{
+        // Ties fall back to oldest first; replies follow their parent depth first
+        {"top", CommentTreeOptions{}, []string{"t3", "t1", "r1a", "r1a1", "r1b", "t0", "t2", "t4"}},
+        {"new", CommentTreeOptions{Sort: CommentSortNew}, []string{"t4", "t3", "t2", "t1", "r1b", "r1a", "r1a1", "t0"}},
+        {"old", CommentTreeOptions{Sort: CommentSortOld}, []string{"t0", "t1", "r1a", "r1a1", "r1b", "t2", "t3", "t4"}},
+        // Pages hold whole threads
+        {"page 1", CommentTreeOptions{Page: 1, Limit: 2}, []string{"t3", "t1", "r1a", "r1a1", "r1b"}},
+        {"page 2", CommentTreeOptions{Page: 2, Limit: 2}, []string{"t0", "t2"}},
+        {"last page", CommentTreeOptions{Page: 3, Limit: 2}, []string{"t4"}},
+        {"past the end", CommentTreeOptions{Page: 4, Limit: 2}, []string{}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            nodes, total, err := thread.e.GetCommentTree(thread.post.ID, tt.opts)
+            if err != nil {
+                t.Fatalf("GetCommentTree: %v", err)
+            }
+            if total != 5 {
+                t.Errorf("total = %d, want 5", total)
+            }
+            if got := contents(nodes); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+                t.Errorf("order = %v, want %v", got, tt.want)
+            }
+        })
+    }
+
+    if _, _, err := thread.e.GetCommentTree(thread.post.ID, CommentTreeOptions{Sort: "random"}); err == nil {
+        t.Error("unknown sort accepted")
+    }
+    if _, _, err := thread.e.GetCommentTree(thread.post.ID, CommentTreeOptions{Page: -1}); err == nil {
+        t.Error("negative page accepted")
+    }
+}
+
+func TestCommentTreeLevels(t *testing.T) {
+    thread := newCommentThread(t)
+
+    nodes, _, err := thread.e.GetCommentTree(thread.post.ID, CommentTreeOptions{Sort: CommentSortOld, Levels: 1})
+    if err != nil {
+        t.Fatalf("GetCommentTree: %v", err)
+    }
+    if got := contents(nodes); fmt.Sprint(got) != "[t0 t1 t2 t3 t4]" {
+        t.Fatalf("top level only = %v", got)
+    }
+    for _, node := range nodes {
+        wantMore := node.Content == "t1"
+        if node.HasMoreReplies != wantMore || (wantMore && node.RemainingReplies != 3) {
+            t.Errorf("%s: HasMoreReplies %v, RemainingReplies %d", node.Content, node.HasMoreReplies, node.RemainingReplies)
+        }
+    }
+
+    nodes, _, _ = thread.e.GetCommentTree(thread.post.ID, CommentTreeOptions{Sort: CommentSortOld, Levels: 2, Limit: 2})
+    if got := contents(nodes); fmt.Sprint(got) != "[t0 t1 r1a r1b]" {
+        t.Fatalf("two levels = %v", got)
+    }
+    if r1a := nodes[2]; !r1a.HasMoreReplies || r1a.RemainingReplies != 1 {
+        t.Errorf("r1a: HasMoreReplies %v, RemainingReplies %d; want true, 1", r1a.HasMoreReplies, r1a.RemainingReplies)
+    }
+}
+
+func TestGetRepliesPaginatesDirectReplies(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+    parent := mustComment(t, e, alice.ID, post.ID, nil)
+
+    // Seven direct replies a minute apart, each with a reply of its own
+    var replies []*models.Comment
+    for i := 0; i < 7; i++ {
+        clock.Advance(time.Minute)
+        reply, err := e.CreateComment(fmt.Sprintf("c%d", i), alice.ID, post.ID, &parent.ID)
+        if err != nil {
+            t.Fatalf("CreateComment: %v", err)
+        }
+        mustComment(t, e, bob.ID, post.ID, &reply.ID)
+        replies = append(replies, reply)
+    }
+    mustVote(t, e, bob.ID, replies[5].ID, VoteUp)
+
+    replyContents := func(comments []*models.Comment) string {
+        out := make([]string, len(comments))
+        for i, c := range comments {
+            out[i] = c.Content
+        }
+        return fmt.Sprint(out)
+    }
+    tests := []struct {
+        page, limit int
+        want        string
+    }{
+        // Top score first, then oldest first
+        {1, 3, "[c5 c0 c1]"},
+        {2, 3, "[c2 c3 c4]"},
+        {3, 3, "[c6]"},
+        {4, 3, "[]"},
+        {0, 0, "[c5 c0 c1 c2 c3 c4 c6]"},
+    }
+    for _, tt := range tests {
+        got, total, err := e.GetReplies(parent.ID, tt.page, tt.limit)
+        if err != nil {
+            t.Fatalf("GetReplies(page %d, limit %d): %v", tt.page, tt.limit, err)
+        }
+        if replyContents(got) != tt.want || total != 7 {
+            t.Errorf("GetReplies(page %d, limit %d) = %s of %d, want %s of 7", tt.page, tt.limit, replyContents(got), total, tt.want)
+        }
+    }
+
+    got, _, err := e.GetRepliesWithOptions(parent.ID, CommentTreeOptions{Sort: CommentSortNew, Page: 1, Limit: 2})
+    if err != nil || replyContents(got) != "[c6 c5]" {
+        t.Errorf("newest replies = %s, %v; want [c6 c5]", replyContents(got), err)
+    }
+
+    // The grandchildren are only reachable from their own parent
+    if got, total, err := e.GetReplies(replies[0].ID, 1, 10); err != nil || len(got) != 1 || total != 1 {
+        t.Errorf("replies to c0 = %d of %d, %v; want its single reply", len(got), total, err)
+    }
+    if _, _, err := e.GetReplies("missing", 1, 10); err == nil {
+        t.Error("GetReplies on an unknown comment succeeded")
+    }
+    if _, _, err := e.GetReplies(parent.ID, -1, 10); err == nil {
+        t.Error("GetReplies accepted a negative page")
+    }
+}
+
+func TestCommentTreeLevelsOnADeepThread(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+    comment := func(content string, parent *models.Comment) *models.Comment {
+        t.Helper()
+        clock.Advance(time.Minute)
+        var parentID *string
+        if parent != nil {
+            parentID = &parent.ID
+        }
+        c, err := e.CreateComment(content, alice.ID, post.ID, parentID)
+        if err != nil {
+            t.Fatalf("CreateComment: %v", err)
+        }
+        return c
+    }
+
+    // root has a chain five replies deep through a1, and a two-reply
+    // branch through b2
+    root := comment("root", nil)
+    parent := comment("a1", root)
+    a1 := parent
+    for _, content := range []string{"a2", "a3", "a4", "a5"} {
+        parent = comment(content, parent)
+    }
+    comment("b3", comment("b2", a1))
+
+    tests := []struct {
+        levels int
+        want   string // Content:remaining replies for each node, in thread order
+    }{
+        {1, "[root:7]"},
+        {2, "[root:0 a1:6]"},
+        {3, "[root:0 a1:0 a2:3 b2:1]"},
+        {5, "[root:0 a1:0 a2:0 a3:0 a4:1 b2:0 b3:0]"},
+        {6, "[root:0 a1:0 a2:0 a3:0 a4:0 a5:0 b2:0 b3:0]"},
+        {0, "[root:0 a1:0 a2:0 a3:0 a4:0 a5:0 b2:0 b3:0]"},
+    }
+    for _, tt := range tests {
+        nodes, _, err := e.GetCommentTree(post.ID, CommentTreeOptions{Sort: CommentSortOld, Levels: tt.levels})
+        if err != nil {
+            t.Fatalf("GetCommentTree(levels %d): %v", tt.levels, err)
+        }
+        got := make([]string, len(nodes))
+        for i, node := range nodes {
+            got[i] = fmt.Sprintf("%s:%d", node.Content, node.RemainingReplies)
+            if node.HasMoreReplies != (node.RemainingReplies > 0) {
+                t.Errorf("levels %d: %s has HasMoreReplies %v with %d remaining", tt.levels, node.Content, node.HasMoreReplies, node.RemainingReplies)
+            }
+        }
+        if fmt.Sprint(got) != tt.want {
+            t.Errorf("levels %d: tree = %v, want %s", tt.levels, got, tt.want)
+        }
+    }
+}
+
+func TestCommentTreeCollapsesLowScores(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.CommentCooldown = 0
+    cfg.CollapseBelowScore = -1
+    e, clock := newTestEngineWithConfig(t, cfg)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+    comment := func(content string, parentID *string) *models.Comment {
+        t.Helper()
+        clock.Advance(time.Minute)
+        c, err := e.CreateComment(content, alice.ID, post.ID, parentID)
+        if err != nil {
+            t.Fatalf("CreateComment: %v", err)
+        }
+        return c
+    }
+
+    buried := comment("buried", nil)
+    mustVote(t, e, bob.ID, buried.ID, VoteDown)
+    mustVote(t, e, carol.ID, buried.ID, VoteDown)
+    reply := comment("reply", &buried.ID)
+    comment("nested", &reply.ID)
+    // At the threshold, not below it
+    borderline := comment("borderline", nil)
+    mustVote(t, e, bob.ID, borderline.ID, VoteDown)
+    comment("fine", nil)
+
+    nodes, _, err := e.GetCommentTree(post.ID, CommentTreeOptions{Sort: CommentSortOld})
+    if err != nil {
+        t.Fatalf("GetCommentTree: %v", err)
+    }
+    var got []string
+    for _, node := range nodes {
+        got = append(got, fmt.Sprintf("%s:%t/%t", node.Content, node.Collapsed, node.InCollapsed))
+    }
+    // Everything is still returned, content included
+    want := "[buried:true/false reply:false/true nested:false/true borderline:false/false fine:false/false]"
+    if fmt.Sprint(got) != want {
+        t.Errorf("content:collapsed/inCollapsed = %v, want %s", got, want)
+    }
+
+    // A threshold of 0 turns collapsing off
+    e.config.CollapseBelowScore = 0
+    nodes, _, _ = e.GetCommentTree(post.ID, CommentTreeOptions{Sort: CommentSortOld})
+    for _, node := range nodes {
+        if node.Collapsed || node.InCollapsed {
+            t.Errorf("%s collapsed with collapsing off", node.Content)
+        }
+    }
+}
\ No newline at end of file
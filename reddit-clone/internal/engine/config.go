@@ -0,0 +1,180 @@
+// internal/engine/config.go
+package engine
+
+import "time"
+
+const (
+    // DefaultMaxCommentDepth is the deepest reply level allowed by default
+    DefaultMaxCommentDepth = 10
+    // DefaultMessageSweepInterval is how often expired messages are purged
+    DefaultMessageSweepInterval = time.Minute
+    // DefaultMaxEditHistory is how many past revisions are kept per post or comment
+    DefaultMaxEditHistory = 10
+    // DefaultVoteFuzzRange is the largest shift applied to a displayed vote count
+    DefaultVoteFuzzRange = 3
+    // DefaultPostCooldown is the minimum time between two posts by one user.
+    // The simulator posts at most once per second, so it is unaffected.
+    DefaultPostCooldown = 500 * time.Millisecond
+    // DefaultCommentCooldown is disabled because the simulator replies to its
+    // own comments immediately
+    DefaultCommentCooldown = 0
+    // DefaultDuplicatePostWindow is how long an identical post by the same
+    // author is rejected in the same subreddit
+    DefaultDuplicatePostWindow = time.Hour
+    // DefaultPersonalizedFeedTTL is how long a user's personalized ranking is reused
+    DefaultPersonalizedFeedTTL = 30 * time.Second
+    // DefaultLeaderboardTTL is how long a subreddit's karma leaderboard is reused
+    DefaultLeaderboardTTL = time.Minute
+    // DefaultMessageEditWindow is how long a sender may edit or delete a direct message
+    DefaultMessageEditWindow = 15 * time.Minute
+    // DefaultUsernameChangeCooldown is the minimum time between two renames by one user
+    DefaultUsernameChangeCooldown = 30 * 24 * time.Hour
+    // DefaultMaxUsernameHistory is how many past usernames are kept per user
+    DefaultMaxUsernameHistory = 5
+    // DefaultPostShards is how many maps the post store is split across
+    DefaultPostShards = 32
+    // DefaultCollapseBelowScore is the score under which a comment starts collapsed
+    DefaultCollapseBelowScore = -5
+    // DefaultMaxFeedSubscriptionsPerUser is how many live feeds one user may have open
+    DefaultMaxFeedSubscriptionsPerUser = 5
+    // DefaultRateBudgetWindow is the period a user's request budget covers
+    DefaultRateBudgetWindow = time.Minute
+    // DefaultNotificationBatchWindow is how long a notification keeps
+    // absorbing later ones of the same type for the same user
+    DefaultNotificationBatchWindow = time.Minute
+)
+
+// Config holds tunable engine behaviour
+type Config struct {
+    // MaxCommentDepth is the maximum nesting depth of a comment.
+    // Top-level comments have depth 0; replies deeper than this are rejected.
+    MaxCommentDepth int
+
+    // MessageSweepInterval is how often Run purges expired direct messages
+    MessageSweepInterval time.Duration
+
+    // MaxEditHistory caps the number of edit records kept per post or comment
+    MaxEditHistory int
+
+    // VoteFuzzing shifts displayed vote counts slightly to confound bots.
+    // Off by default so callers see exact counts.
+    VoteFuzzing bool
+
+    // VoteFuzzRange is the maximum amount a displayed count is shifted by
+    VoteFuzzRange int64
+
+    // PostCooldown is the minimum interval between posts by the same user;
+    // zero disables the check
+    PostCooldown time.Duration
+
+    // CommentCooldown is the minimum interval between comments by the same
+    // user; zero disables the check
+    CommentCooldown time.Duration
+
+    // DuplicatePostWindow is how long after a post the same author may not
+    // post identical title and content to the same subreddit; zero disables
+    // the check
+    DuplicatePostWindow time.Duration
+
+    // PersonalizedFeedTTL is how long a computed personalized feed is
+    // served from cache before it is ranked again
+    PersonalizedFeedTTL time.Duration
+
+    // LeaderboardTTL is how long a computed subreddit karma leaderboard
+    // is served from cache before it is ranked again
+    LeaderboardTTL time.Duration
+
+    // MaxSubredditsPerUser caps how many subreddits one user may create;
+    // zero means unlimited
+    MaxSubredditsPerUser int
+
+    // MessageEditWindow is how long after sending a direct message its
+    // sender may edit or delete it; zero removes the limit
+    MessageEditWindow time.Duration
+
+    // RequireVerifiedEmail stops users without a verified email from
+    // posting. Off by default so username-only accounts can post.
+    RequireVerifiedEmail bool
+
+    // UsernameChangeCooldown is the minimum interval between renames by
+    // the same user; zero disables the check
+    UsernameChangeCooldown time.Duration
+
+    // MaxUsernameHistory caps the number of past usernames kept per user
+    MaxUsernameHistory int
+
+    // SanitizeOnRender stores post and comment content as written and
+    // sanitizes it in RenderContent instead, so authors can edit their
+    // original markdown. Off by default, so stored content is already safe.
+    SanitizeOnRender bool
+
+    // PostShards is how many maps the post store is split across, chosen
+    // by hashing the post ID. More shards spread concurrent post writes
+    // over more locks; 1 keeps a single map. Read when the engine is
+    // created.
+    PostShards int
+
+    // MinAccountAge is how old an account must be before it may post or
+    // comment; zero disables the check so the simulator's fresh accounts
+    // can post
+    MinAccountAge time.Duration
+
+    // CollapseBelowScore marks comment tree entries scoring below it as
+    // collapsed, so clients hide them and their replies until expanded;
+    // zero disables collapsing
+    CollapseBelowScore int64
+
+    // AutoRemoveBelowScore hides a post or comment from feeds and listings
+    // when a vote drops its score below this value, until a moderator
+    // restores it; zero disables auto-removal
+    AutoRemoveBelowScore int64
+
+    // NotificationBatchWindow coalesces a user's notifications of one type
+    // into a single summary while they arrive within this long of the
+    // first, unless it has been read; zero gives each its own entry
+    NotificationBatchWindow time.Duration
+
+    // MaxFeedSubscriptionsPerUser caps how many live feed subscriptions,
+    // such as feed WebSockets, one user may hold open at once; zero
+    // means unlimited
+    MaxFeedSubscriptionsPerUser int
+
+    // RateBudgetSize is how many requests one user may make over REST and
+    // gRPC combined in each RateBudgetWindow; zero disables the budget
+    RateBudgetSize   int
+    RateBudgetWindow time.Duration
+
+    // DefaultSubreddits names the subreddits every new account joins, so
+    // its feed isn't empty; names with no subreddit are skipped. Empty
+    // by default.
+    DefaultSubreddits []string
+}
+
+// NewDefaultConfig creates a Config with default values
+func NewDefaultConfig() *Config {
+    return &Config{
+        MaxCommentDepth:        DefaultMaxCommentDepth,
+        MessageSweepInterval:   DefaultMessageSweepInterval,
+        MaxEditHistory:         DefaultMaxEditHistory,
+        VoteFuzzRange:          DefaultVoteFuzzRange,
+        PostCooldown:           DefaultPostCooldown,
+        CommentCooldown:        DefaultCommentCooldown,
+        DuplicatePostWindow:    DefaultDuplicatePostWindow,
+        PersonalizedFeedTTL:    DefaultPersonalizedFeedTTL,
+        LeaderboardTTL:         DefaultLeaderboardTTL,
+        MessageEditWindow:      DefaultMessageEditWindow,
+        UsernameChangeCooldown: DefaultUsernameChangeCooldown,
+        MaxUsernameHistory:     DefaultMaxUsernameHistory,
+        PostShards:             DefaultPostShards,
+        CollapseBelowScore:     DefaultCollapseBelowScore,
+
+        NotificationBatchWindow:     DefaultNotificationBatchWindow,
+        MaxFeedSubscriptionsPerUser: DefaultMaxFeedSubscriptionsPerUser,
+        RateBudgetWindow:            DefaultRateBudgetWindow,
+    }
+}
+
+// Config returns a copy of the engine's configuration
+func (e *RedditEngine) Config() Config {
+    return *e.config
+}
\ No newline at end of file
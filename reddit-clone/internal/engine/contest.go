@@ -0,0 +1,60 @@
+// internal/engine/contest.go
+package engine
+
+import (
+    "hash/fnv"
+
+    "reddit-clone/internal/models"
+)
+
+// ToggleContestMode flips contest mode on a post. While it is on, the
+// post's comments come back in a shuffled order and show no votes, so
+// early leaders don't pick up votes for being on top. The post's author
+// and the subreddit's moderators may toggle it.
+func (e *RedditEngine) ToggleContestMode(userID, postID string) (*models.Post, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    post, err := e.GetPost(postID)
+    if err != nil {
+        return nil, err
+    }
+    subreddit, err := e.GetSubReddit(post.SubRedditID)
+    if err != nil {
+        return nil, err
+    }
+    if post.AuthorID != userID && !isModerator(userID, subreddit) {
+        return nil, ErrNotModerator
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    post.ContestMode = !post.ContestMode
+    return post, nil
+}
+
+// inContest reports whether targetID is a comment on a post in contest mode
+func (e *RedditEngine) inContest(targetID string) bool {
+    commentI, ok := e.comments.Load(targetID)
+    if !ok {
+        return false
+    }
+    postI, ok := e.posts.Load(commentI.(*models.Comment).PostID)
+    return ok && postI.(*models.Post).ContestMode
+}
+
+// contestOrder shuffles a contest-mode post's comments for one viewer.
+// Each comment's place comes from a hash of the viewer, post and comment,
+// so the viewer sees the same order on every refresh while other viewers
+// see different ones.
+func contestOrder(viewerID, postID string) func(a, b *models.Comment) bool {
+    rank := func(commentID string) uint64 {
+        h := fnv.New64a()
+        h.Write([]byte(viewerID + ":" + postID + ":" + commentID))
+        return h.Sum64()
+    }
+    return func(a, b *models.Comment) bool { return rank(a.ID) < rank(b.ID) }
+}
\ No newline at end of file
@@ -0,0 +1,89 @@
+// internal/engine/contest_test.go
+package engine
+
+import (
+    "fmt"
+    "slices"
+    "testing"
+    "time"
+)
+
+func TestContestModeShufflesAndHidesScores(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+    subreddit := mustCreateSubreddit(t, e, "golang", bob.ID)
+    mustJoin(t, e, alice.ID, subreddit.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+    var comments []string
+    for i := 0; i < 12; i++ {
+        clock.Advance(time.Minute)
+        comment, err := e.CreateComment(fmt.Sprintf("c%d", i), alice.ID, post.ID, nil)
+        if err != nil {
+            t.Fatalf("CreateComment: %v", err)
+        }
+        comments = append(comments, comment.ID)
+    }
+    winner := comments[7]
+    mustVote(t, e, bob.ID, winner, VoteUp)
+    mustVote(t, e, carol.ID, winner, VoteUp)
+
+    order := func(viewerID string) []string {
+        t.Helper()
+        nodes, _, err := e.GetCommentTree(post.ID, CommentTreeOptions{ViewerID: viewerID})
+        if err != nil {
+            t.Fatalf("GetCommentTree: %v", err)
+        }
+        ids := make([]string, len(nodes))
+        for i, node := range nodes {
+            ids[i] = node.ID
+        }
+        return ids
+    }
+    byScore := order(alice.ID)
+    if byScore[0] != winner {
+        t.Fatalf("top comment outside contest mode = %s, want the upvoted one", byScore[0])
+    }
+
+    if _, err := e.ToggleContestMode(carol.ID, post.ID); err != ErrNotModerator {
+        t.Errorf("toggle by a bystander = %v, want ErrNotModerator", err)
+    }
+    if p, err := e.ToggleContestMode(alice.ID, post.ID); err != nil || !p.ContestMode {
+        t.Fatalf("author's toggle = %v, %v; want contest mode on", p, err)
+    }
+
+    // Each viewer gets their own shuffle, the same on every read
+    forAlice, forCarol := order(alice.ID), order(carol.ID)
+    if slices.Equal(forAlice, byScore) {
+        t.Error("contest order matches the score order")
+    }
+    if slices.Equal(forAlice, forCarol) {
+        t.Error("two viewers see the same contest order")
+    }
+    if again := order(alice.ID); !slices.Equal(again, forAlice) {
+        t.Error("contest order changed between reads")
+    }
+
+    // Displayed votes are hidden; the real ones are kept
+    comment, _ := e.GetComment(winner)
+    up, down := comment.Votes()
+    if shownUp, shownDown := e.DisplayVotes(winner, up, down); shownUp != 0 || shownDown != 0 {
+        t.Errorf("displayed votes = %d/%d, want 0/0", shownUp, shownDown)
+    }
+    if comment.Score() != 2 {
+        t.Errorf("stored score = %d, want 2", comment.Score())
+    }
+    // The post's own votes aren't hidden
+    if shownUp, _ := e.DisplayVotes(post.ID, 3, 0); shownUp != 3 {
+        t.Errorf("post's displayed upvotes = %d, want 3", shownUp)
+    }
+
+    // The subreddit's moderator can switch it back off
+    if p, err := e.ToggleContestMode(bob.ID, post.ID); err != nil || p.ContestMode {
+        t.Fatalf("moderator's toggle = %v, %v; want contest mode off", p, err)
+    }
+    if got := order(alice.ID); !slices.Equal(got, byScore) {
+        t.Error("score order not restored after contest mode")
+    }
+}
\ No newline at end of file
@@ -0,0 +1,72 @@
+// internal/engine/contributors.go
+package engine
+
+import "sort"
+
+// ContributorStat is one author's activity within a subreddit
+type ContributorStat struct {
+    UserID       string
+    Username     string
+    PostCount    int64
+    CommentCount int64
+    Karma        int64 // Net votes on the author's posts and comments there
+}
+
+// GetTopContributors ranks the authors of a subreddit's posts and comments
+// by how much they contributed, then by the karma they earned there, and
+// returns at most limit of them; limit <= 0 returns every contributor.
+// Queued posts don't count until they are approved.
+func (e *RedditEngine) GetTopContributors(subredditID string, limit int) ([]ContributorStat, error) {
+    if _, err := e.GetSubReddit(subredditID); err != nil {
+        return nil, err
+    }
+
+    byAuthor := make(map[string]*ContributorStat)
+    statFor := func(authorID string) *ContributorStat {
+        stat, ok := byAuthor[authorID]
+        if !ok {
+            stat = &ContributorStat{UserID: authorID}
+            byAuthor[authorID] = stat
+        }
+        return stat
+    }
+    // Anonymous posts and comments are left out so the ranking can't
+    // reveal who wrote them
+    for _, post := range e.subredditPostList(subredditID) {
+        if !post.Anonymous {
+            stat := statFor(post.AuthorID)
+            stat.PostCount++
+            stat.Karma += post.Score()
+        }
+        for _, comment := range e.postCommentList(post.ID) {
+            if comment.Anonymous {
+                continue
+            }
+            stat := statFor(comment.AuthorID)
+            stat.CommentCount++
+            stat.Karma += comment.Score()
+        }
+    }
+
+    contributors := make([]ContributorStat, 0, len(byAuthor))
+    for _, stat := range byAuthor {
+        if user, err := e.GetUser(stat.UserID); err == nil {
+            stat.Username = user.Username
+        }
+        contributors = append(contributors, *stat)
+    }
+    sort.Slice(contributors, func(i, j int) bool {
+        a, b := contributors[i], contributors[j]
+        if a.PostCount+a.CommentCount != b.PostCount+b.CommentCount {
+            return a.PostCount+a.CommentCount > b.PostCount+b.CommentCount
+        }
+        if a.Karma != b.Karma {
+            return a.Karma > b.Karma
+        }
+        return a.UserID < b.UserID
+    })
+    if limit > 0 && len(contributors) > limit {
+        contributors = contributors[:limit]
+    }
+    return contributors, nil
+}
\ No newline at end of file
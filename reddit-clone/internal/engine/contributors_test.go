@@ -0,0 +1,62 @@
+// internal/engine/contributors_test.go
+package engine
+
+import (
+    "fmt"
+    "testing"
+)
+
+func TestGetTopContributors(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+    dave := mustRegister(t, e, "dave")
+    subreddit, err := e.CreateSubRedditWithOptions("golang", "Go", alice.ID, SubredditOptions{AllowAnonymous: true})
+    if err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+    elsewhere := mustCreateSubreddit(t, e, "rust", dave.ID)
+    for _, user := range []string{bob.ID, carol.ID, dave.ID} {
+        mustJoin(t, e, user, subreddit.ID)
+    }
+
+    // Alice and Bob each contribute twice, but Alice earns more there
+    first := mustPost(t, e, alice.ID, subreddit.ID)
+    mustPost(t, e, alice.ID, subreddit.ID)
+    mustVote(t, e, bob.ID, first.ID, VoteUp)
+    mustVote(t, e, carol.ID, first.ID, VoteUp)
+    mustPost(t, e, bob.ID, subreddit.ID)
+    bobComment := mustComment(t, e, bob.ID, first.ID, nil)
+    mustVote(t, e, carol.ID, bobComment.ID, VoteDown)
+    mustComment(t, e, carol.ID, first.ID, nil)
+
+    // Anonymous posts and activity in other subreddits don't count
+    if _, err := e.CreatePostWithOptions("Secret", "Content", dave.ID, subreddit.ID, PostOptions{Anonymous: true}); err != nil {
+        t.Fatalf("CreatePostWithOptions: %v", err)
+    }
+    daves := mustPost(t, e, dave.ID, elsewhere.ID)
+    mustComment(t, e, dave.ID, daves.ID, nil)
+
+    contributors, err := e.GetTopContributors(subreddit.ID, 0)
+    if err != nil {
+        t.Fatalf("GetTopContributors: %v", err)
+    }
+    var got []string
+    for _, c := range contributors {
+        got = append(got, fmt.Sprintf("%s:%d/%d/%d", c.Username, c.PostCount, c.CommentCount, c.Karma))
+    }
+    if want := "[alice:2/0/2 bob:1/1/-1 carol:0/1/0]"; fmt.Sprint(got) != want {
+        t.Errorf("contributors = %v, want %s", got, want)
+    }
+    if contributors[0].UserID != alice.ID {
+        t.Errorf("top contributor ID = %s, want alice's", contributors[0].UserID)
+    }
+
+    if top, _ := e.GetTopContributors(subreddit.ID, 1); len(top) != 1 || top[0].UserID != alice.ID {
+        t.Errorf("top 1 = %+v, want only alice", top)
+    }
+    if _, err := e.GetTopContributors("missing", 10); err != ErrSubredditNotFound {
+        t.Errorf("GetTopContributors for an unknown subreddit = %v, want ErrSubredditNotFound", err)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,36 @@
+// internal/engine/creationlimit.go
+package engine
+
+import (
+    "errors"
+    "sync/atomic"
+)
+
+var ErrSubredditLimit = errors.New("subreddit creation limit reached")
+
+// createdSubreddits returns the counter of subreddits the user has created
+func (e *RedditEngine) createdSubreddits(userID string) *atomic.Int64 {
+    countI, _ := e.subredditsCreated.LoadOrStore(userID, new(atomic.Int64))
+    return countI.(*atomic.Int64)
+}
+
+// reserveSubredditSlot counts a new subreddit against the creator's
+// Config.MaxSubredditsPerUser, returning ErrSubredditLimit once it's reached
+func (e *RedditEngine) reserveSubredditSlot(userID string) error {
+    count := e.createdSubreddits(userID)
+    limit := int64(e.config.MaxSubredditsPerUser)
+    for {
+        n := count.Load()
+        if limit > 0 && n >= limit {
+            return ErrSubredditLimit
+        }
+        if count.CompareAndSwap(n, n+1) {
+            return nil
+        }
+    }
+}
+
+// releaseSubredditSlot gives back a slot taken by reserveSubredditSlot
+func (e *RedditEngine) releaseSubredditSlot(userID string) {
+    e.createdSubreddits(userID).Add(-1)
+}
\ No newline at end of file
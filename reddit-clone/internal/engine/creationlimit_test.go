@@ -0,0 +1,48 @@
+// internal/engine/creationlimit_test.go
+package engine
+
+import (
+    "fmt"
+    "testing"
+)
+
+func TestSubredditCreationLimit(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.MaxSubredditsPerUser = 2
+    e, _ := newTestEngineWithConfig(t, cfg)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+
+    first := mustCreateSubreddit(t, e, "golang", alice.ID)
+    // A refused creation doesn't use up a slot
+    if _, err := e.CreateSubRedditWithOptions("bad", "Bad", alice.ID, SubredditOptions{MaxMembers: -1}); err == nil {
+        t.Fatal("created a subreddit with a negative member cap")
+    }
+    mustCreateSubreddit(t, e, "rust", alice.ID)
+    if _, err := e.CreateSubReddit("zig", "Zig", alice.ID); err != ErrSubredditLimit {
+        t.Fatalf("creation past the cap = %v, want ErrSubredditLimit", err)
+    }
+
+    // The cap is per user
+    mustCreateSubreddit(t, e, "python", bob.ID)
+
+    // Deleting a subreddit frees its creator's slot
+    if err := e.DeleteSubReddit(alice.ID, first.ID); err != nil {
+        t.Fatalf("DeleteSubReddit: %v", err)
+    }
+    mustCreateSubreddit(t, e, "zig", alice.ID)
+    if _, err := e.CreateSubReddit("odin", "Odin", alice.ID); err != ErrSubredditLimit {
+        t.Errorf("creation past the cap after a delete = %v, want ErrSubredditLimit", err)
+    }
+}
+
+func TestSubredditCreationUnlimitedByDefault(t *testing.T) {
+    e, _ := newTestEngine(t)
+    if e.config.MaxSubredditsPerUser != 0 {
+        t.Fatalf("default MaxSubredditsPerUser = %d, want 0", e.config.MaxSubredditsPerUser)
+    }
+    alice := mustRegister(t, e, "alice")
+    for i := range 25 {
+        mustCreateSubreddit(t, e, fmt.Sprintf("sub%d", i), alice.ID)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,46 @@
+// internal/engine/crosspost.go
+package engine
+
+import (
+    "errors"
+    "sort"
+
+    "reddit-clone/internal/models"
+)
+
+// GetCrosspostTargets returns the subreddits the user could crosspost the
+// post to: those they have joined, other than the post's own subreddit and
+// any that don't allow crossposts or are archived. The list is sorted by
+// name.
+func (e *RedditEngine) GetCrosspostTargets(userID, postID string) ([]*models.SubReddit, error) {
+    if _, exists := e.users.Load(userID); !exists {
+        return nil, ErrUserNotFound
+    }
+    post, err := e.GetPost(postID)
+    if err != nil {
+        return nil, err
+    }
+    if !e.CanSeePost(userID, post) {
+        return nil, errors.New("post not found")
+    }
+
+    targets := []*models.SubReddit{}
+    for _, subredditID := range e.userSubredditIDs(userID) {
+        if subredditID == post.SubRedditID {
+            continue
+        }
+        subredditI, ok := e.subreddits.Load(subredditID)
+        if !ok {
+            continue
+        }
+        subreddit := subredditI.(*models.SubReddit)
+        if !subreddit.AllowCrossposts || subreddit.Archived {
+            continue
+        }
+        targets = append(targets, subreddit)
+    }
+    sort.Slice(targets, func(i, j int) bool {
+        return targets[i].Name < targets[j].Name
+    })
+    return targets, nil
+}
\ No newline at end of file
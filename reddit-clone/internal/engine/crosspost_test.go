@@ -0,0 +1,55 @@
+// internal/engine/crosspost_test.go
+package engine
+
+import (
+    "fmt"
+    "testing"
+)
+
+func TestGetCrosspostTargets(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    origin := mustCreateSubreddit(t, e, "golang", alice.ID)
+    mustCreateSubreddit(t, e, "rust", alice.ID)
+    mustCreateSubreddit(t, e, "python", alice.ID)
+    closed, err := e.CreateSubRedditWithOptions("closed", "No crossposts", alice.ID, SubredditOptions{NoCrossposts: true})
+    if err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+    toggled := mustCreateSubreddit(t, e, "toggled", alice.ID)
+    archived := mustCreateSubreddit(t, e, "archived", alice.ID)
+    mustCreateSubreddit(t, e, "unjoined", bob.ID)
+    post := mustPost(t, e, alice.ID, origin.ID)
+
+    off := false
+    if _, err := e.UpdateSubReddit(alice.ID, toggled.ID, SubredditUpdate{AllowCrossposts: &off}); err != nil {
+        t.Fatalf("UpdateSubReddit: %v", err)
+    }
+    if err := e.ArchiveSubreddit(alice.ID, archived.ID, true); err != nil {
+        t.Fatalf("ArchiveSubreddit: %v", err)
+    }
+
+    targets, err := e.GetCrosspostTargets(alice.ID, post.ID)
+    if err != nil {
+        t.Fatalf("GetCrosspostTargets: %v", err)
+    }
+    if got := fmt.Sprint(subredditNames(targets)); got != "[python rust]" {
+        t.Errorf("targets = %s, want [python rust]", got)
+    }
+    if closed.AllowCrossposts {
+        t.Error("subreddit created with NoCrossposts allows them")
+    }
+
+    // Bob has only joined his own subreddit
+    if targets, err := e.GetCrosspostTargets(bob.ID, post.ID); err != nil || fmt.Sprint(subredditNames(targets)) != "[unjoined]" {
+        t.Errorf("bob's targets = %v, %v; want [unjoined]", subredditNames(targets), err)
+    }
+
+    if _, err := e.GetCrosspostTargets(alice.ID, "missing"); err == nil {
+        t.Error("GetCrosspostTargets for an unknown post succeeded")
+    }
+    if _, err := e.GetCrosspostTargets("missing", post.ID); err != ErrUserNotFound {
+        t.Errorf("GetCrosspostTargets for an unknown user = %v, want ErrUserNotFound", err)
+    }
+}
\ No newline at end of file
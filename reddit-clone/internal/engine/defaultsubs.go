@@ -0,0 +1,44 @@
+// internal/engine/defaultsubs.go
+package engine
+
+import (
+    "sort"
+    "strings"
+)
+
+// joinDefaultSubreddits subscribes a new user to the subreddits named in
+// Config.DefaultSubreddits, matching names case-insensitively. Names with
+// no subreddit, and subreddits the user can't join or that are full, are
+// skipped; registration never fails because of them.
+func (e *RedditEngine) joinDefaultSubreddits(userID string) {
+    for _, name := range e.config.DefaultSubreddits {
+        name = strings.TrimSpace(name)
+        if name == "" {
+            continue
+        }
+        for _, subredditID := range e.subredditIDsNamed(name) {
+            subreddit, err := e.GetSubReddit(subredditID)
+            if err != nil || checkCanJoin(userID, subreddit) != nil {
+                continue
+            }
+            e.subscribe(userID, subreddit)
+        }
+    }
+}
+
+// subredditIDsNamed looks up the subreddits with a name, ignoring case,
+// in the name index. Names aren't unique, so there may be several.
+func (e *RedditEngine) subredditIDsNamed(name string) []string {
+    lowerName := strings.ToLower(name)
+
+    e.nameIndexMtx.RLock()
+    defer e.nameIndexMtx.RUnlock()
+    var ids []string
+    i := sort.Search(len(e.subredditNames), func(i int) bool {
+        return e.subredditNames[i].lowerName >= lowerName
+    })
+    for ; i < len(e.subredditNames) && e.subredditNames[i].lowerName == lowerName; i++ {
+        ids = append(ids, e.subredditNames[i].subredditID)
+    }
+    return ids
+}
\ No newline at end of file
@@ -0,0 +1,67 @@
+// internal/engine/defaultsubs_test.go
+package engine
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "sync/atomic"
+    "testing"
+
+    "reddit-clone/internal/models"
+)
+
+func TestRegisterJoinsDefaultSubreddits(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.PostCooldown = 0
+    cfg.DefaultSubreddits = []string{"Golang", " rust ", "missing", "secret", "full", ""}
+    e, _ := newTestEngineWithConfig(t, cfg)
+
+    admin := mustRegister(t, e, "admin")
+    golang := mustCreateSubreddit(t, e, "golang", admin.ID)
+    rust := mustCreateSubreddit(t, e, "rust", admin.ID)
+    mustCreateSubreddit(t, e, "python", admin.ID)
+    if _, err := e.CreateSubRedditWithOptions("secret", "Private", admin.ID, SubredditOptions{Type: models.SubredditPrivate}); err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+    if _, err := e.CreateSubRedditWithOptions("full", "No room", admin.ID, SubredditOptions{MaxMembers: 1}); err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+    post := mustPost(t, e, admin.ID, golang.ID)
+
+    // Names match case-insensitively; private, full and unknown ones are skipped
+    newbie := mustRegister(t, e, "newbie")
+    subreddits, err := e.GetUserSubreddits(newbie.ID)
+    if err != nil {
+        t.Fatalf("GetUserSubreddits: %v", err)
+    }
+    names := subredditNames(subreddits)
+    sort.Strings(names)
+    if fmt.Sprint(names) != "[golang rust]" {
+        t.Errorf("newbie's subreddits = %v, want [golang rust]", names)
+    }
+    for _, subreddit := range []*models.SubReddit{golang, rust} {
+        if n := atomic.LoadInt64(&subreddit.MemberCount); n != 2 {
+            t.Errorf("%s has %d members, want 2", subreddit.Name, n)
+        }
+    }
+
+    feed, err := e.GetFeedWithOptions(context.Background(), newbie.ID, FeedOptions{})
+    if err != nil {
+        t.Fatalf("GetFeedWithOptions: %v", err)
+    }
+    if !containsPost(feed, post.ID) {
+        t.Error("the new user's feed is missing the default subreddit's post")
+    }
+}
+
+func TestNoDefaultSubredditsByDefault(t *testing.T) {
+    e, _ := newTestEngine(t)
+    admin := mustRegister(t, e, "admin")
+    mustCreateSubreddit(t, e, "golang", admin.ID)
+
+    newbie := mustRegister(t, e, "newbie")
+    if subreddits, _ := e.GetUserSubreddits(newbie.ID); len(subreddits) != 0 {
+        t.Errorf("newbie joined %v without defaults configured", subredditNames(subreddits))
+    }
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+// internal/engine/deletesubreddit.go
+package engine
+
+import "errors"
+
+var ErrNotCreator = errors.New("only the subreddit's creator can delete it")
+
+// DeleteSubReddit deletes a subreddit along with its posts, their comments,
+// the votes and reports on them, its webhooks and its memberships. Only the
+// subreddit's creator may delete it; doing so frees one of their creation slots.
+func (e *RedditEngine) DeleteSubReddit(userID, subredditID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return err
+    }
+    if subreddit.CreatorID != userID {
+        return ErrNotCreator
+    }
+
+    // Only one concurrent delete gets past here
+    if _, ok := e.subreddits.LoadAndDelete(subredditID); !ok {
+        return ErrSubredditNotFound
+    }
+    e.counters.subreddits.Add(-1)
+    e.unindexSubredditName(subreddit)
+    e.releaseSubredditSlot(subreddit.CreatorID)
+
+    subreddit.Members.Range(func(key, _ interface{}) bool {
+        e.unsubscribe(key.(string), subreddit)
+        return true
+    })
+
+    removed := make(map[string]bool)
+    for _, post := range e.subredditPostList(subredditID) {
+        e.removePostAndComments(post.ID, removed)
+    }
+    for _, post := range e.pendingPostList(subredditID) {
+        e.removePostAndComments(post.ID, removed)
+    }
+    e.removeTargetRecords(removed)
+
+    for _, webhook := range e.GetWebhooks(subredditID) {
+        e.webhooks.Delete(webhook.ID)
+    }
+    e.subredditPosts.Delete(subredditID)
+    e.pendingPosts.Delete(subredditID)
+    e.subredditSlugs.Delete(subredditID)
+    e.subredditWebhooks.Delete(subredditID)
+    e.recentPosts.Delete(subredditID)
+    e.forgetLeaderboards(subredditID)
+    return nil
+}
\ No newline at end of file
@@ -0,0 +1,106 @@
+// internal/engine/deletesubreddit_test.go
+package engine
+
+import (
+    "testing"
+
+    "reddit-clone/internal/models"
+)
+
+func TestDeleteSubRedditCascades(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    doomed := mustCreateSubreddit(t, e, "doomed", alice.ID)
+    kept := mustCreateSubreddit(t, e, "kept", bob.ID)
+    mustJoin(t, e, bob.ID, doomed.ID)
+    mustJoin(t, e, alice.ID, kept.ID)
+
+    post := mustPost(t, e, bob.ID, doomed.ID)
+    comment := mustComment(t, e, alice.ID, post.ID, nil)
+    reply := mustComment(t, e, bob.ID, post.ID, &comment.ID)
+    mustVote(t, e, alice.ID, post.ID, VoteUp)
+    mustVote(t, e, bob.ID, comment.ID, VoteDown)
+    if err := e.Report(alice.ID, reply.ID, "spam"); err != nil {
+        t.Fatalf("Report: %v", err)
+    }
+    if _, err := e.AddWebhook(alice.ID, doomed.ID, "https://example.com/hook"); err != nil {
+        t.Fatalf("AddWebhook: %v", err)
+    }
+
+    keptPost := mustPost(t, e, alice.ID, kept.ID)
+    keptComment := mustComment(t, e, bob.ID, keptPost.ID, nil)
+    mustVote(t, e, bob.ID, keptPost.ID, VoteUp)
+
+    if err := e.DeleteSubReddit(bob.ID, doomed.ID); err != ErrNotCreator {
+        t.Fatalf("DeleteSubReddit by a member: err = %v, want ErrNotCreator", err)
+    }
+    if err := e.DeleteSubReddit(alice.ID, doomed.ID); err != nil {
+        t.Fatalf("DeleteSubReddit: %v", err)
+    }
+
+    if _, err := e.GetSubReddit(doomed.ID); err == nil {
+        t.Error("deleted subreddit is still found")
+    }
+    if _, err := e.GetPost(post.ID); err == nil {
+        t.Error("post in the deleted subreddit is still found")
+    }
+    for _, id := range []string{comment.ID, reply.ID} {
+        if _, err := e.GetComment(id); err == nil {
+            t.Errorf("comment %s in the deleted subreddit is still found", id)
+        }
+    }
+    if _, err := e.GetPostBySlug(doomed.ID, post.Slug); err == nil {
+        t.Error("deleted post still resolves by slug")
+    }
+    if hooks := e.GetWebhooks(doomed.ID); len(hooks) != 0 {
+        t.Errorf("%d webhooks left on the deleted subreddit", len(hooks))
+    }
+
+    // Only the kept subreddit's records and index entries remain
+    stats := e.Stats()
+    want := map[string]int{
+        "subreddits":      1,
+        "posts":           1,
+        "comments":        1,
+        "votes":           1,
+        "reports":         0,
+        "webhooks":        0,
+        "subreddit_posts": 1,
+        "post_comments":   1,
+        "comment_replies": 0,
+    }
+    for name, size := range want {
+        if got := stats.MapSizes[name]; got != size {
+            t.Errorf("%s holds %d entries, want %d", name, got, size)
+        }
+    }
+    if stats.Counts.TotalSubreddits != 1 || stats.Counts.TotalPosts != 1 || stats.Counts.TotalComments != 1 {
+        t.Errorf("counts = %+v, want one subreddit, post and comment", stats.Counts)
+    }
+
+    votes, err := e.GetUserVotes(alice.ID, []string{post.ID})
+    if err != nil {
+        t.Fatalf("GetUserVotes: %v", err)
+    }
+    if len(votes) != 0 {
+        t.Errorf("alice's vote on the deleted post is still indexed: %v", votes)
+    }
+    for _, user := range []*models.User{alice, bob} {
+        subreddits, err := e.GetUserSubreddits(user.ID)
+        if err != nil {
+            t.Fatalf("GetUserSubreddits: %v", err)
+        }
+        if len(subreddits) != 1 || subreddits[0].ID != kept.ID {
+            t.Errorf("%s's subreddits = %v, want only kept", user.Username, subreddits)
+        }
+    }
+    if comments, _ := e.GetComments(keptPost.ID); len(comments) != 1 || comments[0].ID != keptComment.ID {
+        t.Errorf("kept post's comments = %v, want its one comment", comments)
+    }
+
+    // The name is free again
+    if _, err := e.CreateSubReddit("doomed", "Back again", alice.ID); err != nil {
+        t.Errorf("recreating the deleted name: %v", err)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,67 @@
+// internal/engine/digest.go
+package engine
+
+import (
+    "sort"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// DigestPostLimit and DigestCommentLimit cap how many items a digest holds
+const (
+    DigestPostLimit    = 10
+    DigestCommentLimit = 10
+)
+
+// Digest is the best content from a user's subreddits over a period
+type Digest struct {
+    Since    time.Time
+    Posts    []*models.Post    // Highest scored first
+    Comments []*models.Comment // Highest scored first
+}
+
+// GetDigest returns the highest-scored posts and comments created since
+// the given time in the subreddits the user belongs to. Comments count
+// wherever they were made in those subreddits, including on older posts.
+func (e *RedditEngine) GetDigest(userID string, since time.Time) (*Digest, error) {
+    if _, exists := e.users.Load(userID); !exists {
+        return nil, ErrUserNotFound
+    }
+
+    digest := &Digest{Since: since}
+    for _, subredditID := range e.userSubredditIDs(userID) {
+        for _, post := range e.listedPosts(e.subredditPostList(subredditID)) {
+            if !post.CreatedAt.Before(since) {
+                digest.Posts = append(digest.Posts, post)
+            }
+            for _, comment := range e.listedComments(e.postCommentList(post.ID)) {
+                if !comment.CreatedAt.Before(since) {
+                    digest.Comments = append(digest.Comments, comment)
+                }
+            }
+        }
+    }
+
+    sort.Slice(digest.Posts, func(i, j int) bool {
+        return ranksAbove(digest.Posts[i].Score(), digest.Posts[i].CreatedAt, digest.Posts[j].Score(), digest.Posts[j].CreatedAt)
+    })
+    sort.Slice(digest.Comments, func(i, j int) bool {
+        return ranksAbove(digest.Comments[i].Score(), digest.Comments[i].CreatedAt, digest.Comments[j].Score(), digest.Comments[j].CreatedAt)
+    })
+    if len(digest.Posts) > DigestPostLimit {
+        digest.Posts = digest.Posts[:DigestPostLimit]
+    }
+    if len(digest.Comments) > DigestCommentLimit {
+        digest.Comments = digest.Comments[:DigestCommentLimit]
+    }
+    return digest, nil
+}
+
+// ranksAbove orders digest items by score, newest first on a tie
+func ranksAbove(scoreA int64, createdA time.Time, scoreB int64, createdB time.Time) bool {
+    if scoreA != scoreB {
+        return scoreA > scoreB
+    }
+    return createdA.After(createdB)
+}
\ No newline at end of file
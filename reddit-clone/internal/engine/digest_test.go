@@ -0,0 +1,89 @@
+// internal/engine/digest_test.go
+package engine
+
+import (
+    "slices"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+func TestGetDigest(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+    golang := mustCreateSubreddit(t, e, "golang", alice.ID)
+    rust := mustCreateSubreddit(t, e, "rust", bob.ID)
+    python := mustCreateSubreddit(t, e, "python", bob.ID)
+    mustJoin(t, e, alice.ID, rust.ID)
+
+    // Content from before the period
+    oldPost := mustPost(t, e, alice.ID, golang.ID)
+    oldComment := mustComment(t, e, alice.ID, oldPost.ID, nil)
+    mustVote(t, e, bob.ID, oldPost.ID, VoteUp)
+    mustVote(t, e, bob.ID, oldComment.ID, VoteUp)
+
+    clock.Advance(8 * 24 * time.Hour)
+    since := clock.Now().Add(-7 * 24 * time.Hour)
+    quiet := mustPost(t, e, bob.ID, rust.ID)
+    clock.Advance(time.Minute)
+    upvoted := mustPost(t, e, alice.ID, golang.ID)
+    mustVote(t, e, bob.ID, upvoted.ID, VoteUp)
+    mustVote(t, e, carol.ID, upvoted.ID, VoteUp)
+    clock.Advance(time.Minute)
+    fresh := mustPost(t, e, bob.ID, rust.ID)
+    // A recent comment on an old post counts; one outside alice's subreddits doesn't
+    lateComment := mustComment(t, e, bob.ID, oldPost.ID, nil)
+    mustVote(t, e, carol.ID, lateComment.ID, VoteUp)
+    unsubscribed := mustPost(t, e, bob.ID, python.ID)
+    for _, voter := range []*models.User{alice, bob, carol} {
+        mustVote(t, e, voter.ID, unsubscribed.ID, VoteUp)
+    }
+    otherComment := mustComment(t, e, bob.ID, unsubscribed.ID, nil)
+    mustVote(t, e, carol.ID, otherComment.ID, VoteUp)
+    reply := mustComment(t, e, alice.ID, oldPost.ID, &lateComment.ID)
+
+    digest, err := e.GetDigest(alice.ID, since)
+    if err != nil {
+        t.Fatalf("GetDigest: %v", err)
+    }
+    // Highest score first, then newest first
+    if got, want := postIDs(digest.Posts), postIDs([]*models.Post{upvoted, fresh, quiet}); !slices.Equal(got, want) {
+        t.Errorf("digest posts = %v, want %v", got, want)
+    }
+    var commentIDs []string
+    for _, c := range digest.Comments {
+        commentIDs = append(commentIDs, c.ID)
+    }
+    if want := []string{lateComment.ID, reply.ID}; !slices.Equal(commentIDs, want) {
+        t.Errorf("digest comments = %v, want %v", commentIDs, want)
+    }
+    if !digest.Since.Equal(since) {
+        t.Errorf("Since = %v, want %v", digest.Since, since)
+    }
+
+    if _, err := e.GetDigest("missing", since); err != ErrUserNotFound {
+        t.Errorf("GetDigest for an unknown user = %v, want ErrUserNotFound", err)
+    }
+}
+
+func TestGetDigestIsCapped(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+    for i := 0; i < DigestPostLimit+DigestCommentLimit; i++ {
+        mustPost(t, e, alice.ID, subreddit.ID)
+        mustComment(t, e, alice.ID, post.ID, nil)
+    }
+
+    digest, err := e.GetDigest(alice.ID, testStart)
+    if err != nil {
+        t.Fatalf("GetDigest: %v", err)
+    }
+    if len(digest.Posts) != DigestPostLimit || len(digest.Comments) != DigestCommentLimit {
+        t.Errorf("digest holds %d posts and %d comments, want %d and %d", len(digest.Posts), len(digest.Comments), DigestPostLimit, DigestCommentLimit)
+    }
+}
\ No newline at end of file
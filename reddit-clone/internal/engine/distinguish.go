@@ -0,0 +1,64 @@
+// internal/engine/distinguish.go
+package engine
+
+import (
+    "reddit-clone/internal/models"
+)
+
+// ToggleDistinguishPost flips whether a post is marked as an official
+// moderator post; only moderators of its subreddit may toggle it
+func (e *RedditEngine) ToggleDistinguishPost(userID, postID string) (*models.Post, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    post, err := e.GetPost(postID)
+    if err != nil {
+        return nil, err
+    }
+    subreddit, err := e.GetSubReddit(post.SubRedditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(userID, subreddit) {
+        return nil, ErrNotModerator
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    post.Distinguished = !post.Distinguished
+    return post, nil
+}
+
+// ToggleDistinguishComment flips whether a comment is marked as an official
+// moderator comment; only moderators of the post's subreddit may toggle it
+func (e *RedditEngine) ToggleDistinguishComment(userID, commentID string) (*models.Comment, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    comment, err := e.GetComment(commentID)
+    if err != nil {
+        return nil, err
+    }
+    post, err := e.GetPost(comment.PostID)
+    if err != nil {
+        return nil, err
+    }
+    subreddit, err := e.GetSubReddit(post.SubRedditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(userID, subreddit) {
+        return nil, ErrNotModerator
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    comment.Distinguished = !comment.Distinguished
+    return comment, nil
+}
\ No newline at end of file
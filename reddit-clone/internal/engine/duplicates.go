@@ -0,0 +1,76 @@
+// internal/engine/duplicates.go
+package engine
+
+import (
+    "crypto/sha256"
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+var ErrDuplicatePost = errors.New("duplicate post")
+
+// recentPostIndex remembers the content hashes of a subreddit's posts made
+// within the duplicate window
+type recentPostIndex struct {
+    mtx     sync.Mutex
+    entries map[[sha256.Size]byte]recentPost
+}
+
+type recentPost struct {
+    postID    string
+    createdAt time.Time
+}
+
+// postContentHash identifies a post by its author, title and content, so
+// the same text from a different author isn't a duplicate
+func postContentHash(authorID, title, content string) [sha256.Size]byte {
+    h := sha256.New()
+    for _, part := range []string{authorID, title, content} {
+        h.Write([]byte(part))
+        h.Write([]byte{0})
+    }
+    var sum [sha256.Size]byte
+    copy(sum[:], h.Sum(nil))
+    return sum
+}
+
+// checkDuplicatePost records the post's content hash in its subreddit's
+// index, or returns ErrDuplicatePost naming the existing post if the author
+// submitted an identical post there within the window. The window runs from
+// submission, so a scheduled post's future CreatedAt doesn't affect it.
+func (e *RedditEngine) checkDuplicatePost(post *models.Post) error {
+    window := e.config.DuplicatePostWindow
+    if window <= 0 {
+        return nil
+    }
+
+    idxI, _ := e.recentPosts.LoadOrStore(post.SubRedditID, &recentPostIndex{
+        entries: make(map[[sha256.Size]byte]recentPost),
+    })
+    idx := idxI.(*recentPostIndex)
+    hash := postContentHash(post.AuthorID, post.Title, post.Content)
+    now := e.clock.Now()
+
+    idx.mtx.Lock()
+    defer idx.mtx.Unlock()
+
+    // Forget posts that have aged out of the window
+    for key, recent := range idx.entries {
+        if now.Sub(recent.createdAt) >= window {
+            delete(idx.entries, key)
+        }
+    }
+
+    // A duplicate of a since-removed post is allowed
+    if recent, ok := idx.entries[hash]; ok {
+        if _, exists := e.posts.Load(recent.postID); exists {
+            return fmt.Errorf("%w; see existing post %s", ErrDuplicatePost, recent.postID)
+        }
+    }
+    idx.entries[hash] = recentPost{postID: post.ID, createdAt: now}
+    return nil
+}
\ No newline at end of file
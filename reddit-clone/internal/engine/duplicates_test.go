@@ -0,0 +1,76 @@
+// internal/engine/duplicates_test.go
+package engine
+
+import (
+    "errors"
+    "testing"
+    "time"
+)
+
+// newDuplicateTestEngine returns a test engine with duplicate detection on
+func newDuplicateTestEngine(t *testing.T, cooldown time.Duration) (*RedditEngine, *FakeClock) {
+    t.Helper()
+    cfg := NewDefaultConfig()
+    cfg.PostCooldown = cooldown
+    cfg.CommentCooldown = 0
+    cfg.DuplicatePostWindow = time.Hour
+    return newTestEngineWithConfig(t, cfg)
+}
+
+func TestCreatePostRejectsDuplicateWithinWindow(t *testing.T) {
+    e, clock := newDuplicateTestEngine(t, 0)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    sub := mustCreateSubreddit(t, e, "golang", alice.ID)
+    mustJoin(t, e, bob.ID, sub.ID)
+
+    if _, err := e.CreatePost("Same title", "Same body", alice.ID, sub.ID); err != nil {
+        t.Fatalf("first post: %v", err)
+    }
+    if _, err := e.CreatePost("Same title", "Same body", alice.ID, sub.ID); !errors.Is(err, ErrDuplicatePost) {
+        t.Fatalf("repost err = %v, want ErrDuplicatePost", err)
+    }
+    // The same text from another author isn't a duplicate
+    if _, err := e.CreatePost("Same title", "Same body", bob.ID, sub.ID); err != nil {
+        t.Fatalf("other author's post: %v", err)
+    }
+
+    clock.Advance(time.Hour)
+    if _, err := e.CreatePost("Same title", "Same body", alice.ID, sub.ID); err != nil {
+        t.Fatalf("repost after the window: %v", err)
+    }
+}
+
+func TestRejectedDuplicateKeepsCooldown(t *testing.T) {
+    e, clock := newDuplicateTestEngine(t, time.Minute)
+    alice := mustRegister(t, e, "alice")
+    sub := mustCreateSubreddit(t, e, "golang", alice.ID)
+
+    if _, err := e.CreatePost("Same title", "Same body", alice.ID, sub.ID); err != nil {
+        t.Fatalf("first post: %v", err)
+    }
+    clock.Advance(time.Minute)
+    if _, err := e.CreatePost("Same title", "Same body", alice.ID, sub.ID); !errors.Is(err, ErrDuplicatePost) {
+        t.Fatalf("repost err = %v, want ErrDuplicatePost", err)
+    }
+    if _, err := e.CreatePost("New title", "New body", alice.ID, sub.ID); err != nil {
+        t.Fatalf("post after a rejected duplicate: %v", err)
+    }
+}
+
+func TestScheduledPostDoesNotFlushDuplicateIndex(t *testing.T) {
+    e, _ := newDuplicateTestEngine(t, 0)
+    alice := mustRegister(t, e, "alice")
+    sub := mustCreateSubreddit(t, e, "golang", alice.ID)
+
+    if _, err := e.CreatePost("Same title", "Same body", alice.ID, sub.ID); err != nil {
+        t.Fatalf("first post: %v", err)
+    }
+    later := testStart.Add(30 * 24 * time.Hour)
+    if _, err := e.CreatePostWithOptions("Scheduled", "Later", alice.ID, sub.ID, PostOptions{ScheduledFor: &later}); err != nil {
+        t.Fatalf("scheduled post: %v", err)
+    }
+    if _, err := e.CreatePost("Same title", "Same body", alice.ID, sub.ID); !errors.Is(err, ErrDuplicatePost) {
+        t.Fatalf("repost err = %v, want ErrDuplicatePost", err)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,152 @@
+// internal/engine/edits.go
+package engine
+
+import (
+    "errors"
+
+    "reddit-clone/internal/models"
+)
+
+var (
+    ErrNotAuthor       = errors.New("only the author can edit this content")
+    ErrVersionConflict = errors.New("content was edited since it was read; refetch it and retry")
+)
+
+// AnyVersion skips the version check in EditPost and EditComment
+const AnyVersion int64 = -1
+
+// EditPost updates a post's title and content; only the author may edit,
+// and not while its subreddit is archived. expectedVersion is the post's Version when the editor read it: if the
+// post was edited since, ErrVersionConflict is returned and nothing
+// changes. Pass AnyVersion to overwrite regardless.
+func (e *RedditEngine) EditPost(userID, postID, title, content string, expectedVersion int64) (*models.Post, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    post, err := e.GetPost(postID)
+    if err != nil {
+        return nil, err
+    }
+    if post.AuthorID != userID {
+        return nil, ErrNotAuthor
+    }
+    if err := e.checkTargetNotArchived(postID); err != nil {
+        return nil, err
+    }
+    if subreddit, err := e.GetSubReddit(post.SubRedditID); err == nil {
+        if err := e.checkBannedWords(subreddit, title, content); err != nil {
+            return nil, err
+        }
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    if expectedVersion != AnyVersion && post.Version != expectedVersion {
+        return nil, ErrVersionConflict
+    }
+
+    post.EditHistory = e.appendEditRecord(post.EditHistory, models.EditRecord{
+        PreviousTitle:   post.Title,
+        PreviousContent: post.Content,
+        EditedAt:        e.clock.Now(),
+    })
+    post.Title = title
+    post.Content = e.storedContent(content)
+    post.Edited = true
+    post.Version++
+    return post, nil
+}
+
+// EditComment updates a comment's content; only the author may edit, and
+// not while the post is locked or its subreddit archived. expectedVersion
+// works as in EditPost.
+func (e *RedditEngine) EditComment(userID, commentID, content string, expectedVersion int64) (*models.Comment, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    comment, err := e.GetComment(commentID)
+    if err != nil {
+        return nil, err
+    }
+    if comment.AuthorID != userID {
+        return nil, ErrNotAuthor
+    }
+    post, err := e.GetPost(comment.PostID)
+    if err != nil {
+        return nil, err
+    }
+    if err := e.checkTargetNotArchived(commentID); err != nil {
+        return nil, err
+    }
+    if err := e.checkCommentBannedWords(comment, content); err != nil {
+        return nil, err
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    if post.Locked {
+        return nil, ErrPostLocked
+    }
+    if expectedVersion != AnyVersion && comment.Version != expectedVersion {
+        return nil, ErrVersionConflict
+    }
+
+    now := e.clock.Now()
+    comment.EditHistory = e.appendEditRecord(comment.EditHistory, models.EditRecord{
+        PreviousContent: comment.Content,
+        EditedAt:        now,
+    })
+    comment.Content = e.storedContent(content)
+    comment.Edited = true
+    comment.UpdatedAt = &now
+    comment.Version++
+    return comment, nil
+}
+
+// GetComment retrieves a single comment by ID
+func (e *RedditEngine) GetComment(commentID string) (*models.Comment, error) {
+    commentI, ok := e.comments.Load(commentID)
+    if !ok {
+        return nil, errors.New("comment not found")
+    }
+    return commentI.(*models.Comment), nil
+}
+
+// GetPostHistory returns a post's edit history, oldest first
+func (e *RedditEngine) GetPostHistory(postID string) ([]models.EditRecord, error) {
+    post, err := e.GetPost(postID)
+    if err != nil {
+        return nil, err
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    return append([]models.EditRecord(nil), post.EditHistory...), nil
+}
+
+// GetCommentHistory returns a comment's edit history, oldest first
+func (e *RedditEngine) GetCommentHistory(commentID string) ([]models.EditRecord, error) {
+    comment, err := e.GetComment(commentID)
+    if err != nil {
+        return nil, err
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    return append([]models.EditRecord(nil), comment.EditHistory...), nil
+}
+
+// appendEditRecord adds a record, dropping the oldest ones beyond MaxEditHistory
+func (e *RedditEngine) appendEditRecord(history []models.EditRecord, record models.EditRecord) []models.EditRecord {
+    history = append(history, record)
+    if limit := e.config.MaxEditHistory; limit > 0 && len(history) > limit {
+        history = append([]models.EditRecord(nil), history[len(history)-limit:]...)
+    }
+    return history
+}
\ No newline at end of file
@@ -0,0 +1,135 @@
+// internal/engine/edits_test.go
+package engine
+
+import (
+    "testing"
+    "time"
+)
+
+func TestStaleEditIsAVersionConflict(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+    comment := mustComment(t, e, alice.ID, post.ID, nil)
+
+    // Two editors read version 0; the first edit wins
+    edited, err := e.EditPost(alice.ID, post.ID, "First", "First edit", 0)
+    if err != nil {
+        t.Fatalf("EditPost at the current version: %v", err)
+    }
+    if edited.Version != 1 {
+        t.Errorf("Version after one edit = %d, want 1", edited.Version)
+    }
+    if _, err := e.EditPost(alice.ID, post.ID, "Second", "Stale edit", 0); err != ErrVersionConflict {
+        t.Fatalf("EditPost at a stale version: err = %v, want ErrVersionConflict", err)
+    }
+    if got, _ := e.GetPost(post.ID); got.Title != "First" || got.Version != 1 {
+        t.Errorf("post after a refused edit = %q v%d, want %q v1", got.Title, got.Version, "First")
+    }
+    if _, err := e.EditPost(alice.ID, post.ID, "Forced", "Any version", AnyVersion); err != nil {
+        t.Errorf("EditPost with AnyVersion: %v", err)
+    }
+
+    if _, err := e.EditComment(alice.ID, comment.ID, "First edit", 0); err != nil {
+        t.Fatalf("EditComment at the current version: %v", err)
+    }
+    if _, err := e.EditComment(alice.ID, comment.ID, "Stale edit", 0); err != ErrVersionConflict {
+        t.Errorf("EditComment at a stale version: err = %v, want ErrVersionConflict", err)
+    }
+}
+
+func TestEditComment(t *testing.T) {
+    e, clock := newTestEngine(t)
+    mod := mustRegister(t, e, "mod")
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", mod.ID)
+    post := mustPost(t, e, mod.ID, subreddit.ID)
+    comment := mustComment(t, e, alice.ID, post.ID, nil)
+    createdAt := comment.CreatedAt
+
+    clock.Advance(time.Minute)
+    edited, err := e.EditComment(alice.ID, comment.ID, "Edited comment", AnyVersion)
+    if err != nil {
+        t.Fatalf("EditComment: %v", err)
+    }
+    if edited.Content != "Edited comment" || !edited.Edited || edited.Version != 1 {
+        t.Errorf("edited comment = %q edited=%v v%d, want the new content, edited, v1", edited.Content, edited.Edited, edited.Version)
+    }
+    if !edited.CreatedAt.Equal(createdAt) {
+        t.Errorf("CreatedAt moved from %v to %v", createdAt, edited.CreatedAt)
+    }
+    if want := testStart.Add(time.Minute); edited.UpdatedAt == nil || !edited.UpdatedAt.Equal(want) {
+        t.Errorf("UpdatedAt = %v, want %v", edited.UpdatedAt, want)
+    }
+
+    if _, err := e.EditComment(mod.ID, comment.ID, "Not mine", AnyVersion); err != ErrNotAuthor {
+        t.Errorf("EditComment by another user: err = %v, want ErrNotAuthor", err)
+    }
+
+    if _, err := e.ToggleLockPost(mod.ID, post.ID); err != nil {
+        t.Fatalf("ToggleLockPost: %v", err)
+    }
+    if _, err := e.EditComment(alice.ID, comment.ID, "While locked", AnyVersion); err != ErrPostLocked {
+        t.Errorf("EditComment on a locked post: err = %v, want ErrPostLocked", err)
+    }
+    if got, _ := e.GetComment(comment.ID); got.Content != "Edited comment" {
+        t.Errorf("content = %q after a refused edit, want %q", got.Content, "Edited comment")
+    }
+}
+
+func TestEditHistoryKeepsNewestRevisions(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.PostCooldown = 0
+    cfg.CommentCooldown = 0
+    cfg.MaxEditHistory = 2
+    e, clock := newTestEngineWithConfig(t, cfg)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post, err := e.CreatePost("v0", "content v0", alice.ID, subreddit.ID)
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    comment := mustComment(t, e, alice.ID, post.ID, nil)
+
+    for _, v := range []string{"v1", "v2", "v3"} {
+        clock.Advance(time.Minute)
+        if _, err := e.EditPost(alice.ID, post.ID, v, "content "+v, AnyVersion); err != nil {
+            t.Fatalf("EditPost(%s): %v", v, err)
+        }
+    }
+    history, err := e.GetPostHistory(post.ID)
+    if err != nil {
+        t.Fatalf("GetPostHistory: %v", err)
+    }
+    // The record of v0 fell off; the kept ones hold v1 and v2, oldest first
+    if len(history) != 2 {
+        t.Fatalf("history has %d records, want 2", len(history))
+    }
+    for i, want := range []string{"v1", "v2"} {
+        record := history[i]
+        if record.PreviousTitle != want || record.PreviousContent != "content "+want {
+            t.Errorf("record %d = %q/%q, want %q", i, record.PreviousTitle, record.PreviousContent, want)
+        }
+        if at := testStart.Add(time.Duration(i+2) * time.Minute); !record.EditedAt.Equal(at) {
+            t.Errorf("record %d edited at %v, want %v", i, record.EditedAt, at)
+        }
+    }
+
+    // The copy returned is the caller's own
+    history[0].PreviousTitle = "tampered"
+    if again, _ := e.GetPostHistory(post.ID); again[0].PreviousTitle != "v1" {
+        t.Error("changing the returned history changed the post's")
+    }
+
+    if _, err := e.EditComment(alice.ID, comment.ID, "Edited comment", AnyVersion); err != nil {
+        t.Fatalf("EditComment: %v", err)
+    }
+    commentHistory, err := e.GetCommentHistory(comment.ID)
+    if err != nil {
+        t.Fatalf("GetCommentHistory: %v", err)
+    }
+    if len(commentHistory) != 1 || commentHistory[0].PreviousContent != "Test comment" {
+        t.Errorf("comment history = %+v, want the original content", commentHistory)
+    }
+}
\ No newline at end of file
@@ -2,118 +2,333 @@
 package engine
 
 import (
+    "context"
     "errors"
+    "fmt"
+    "net"
+    "sort"
     "sync"
+    "sync/atomic"
     "time"
-    "crypto/rand"
-    "encoding/hex"
     "golang.org/x/crypto/bcrypt"
+    "google.golang.org/grpc"
     
     "reddit-clone/internal/models"
 )
 
 type RedditEngine struct {
-    users      sync.Map // map[string]*models.User
-    subreddits sync.Map // map[string]*models.SubReddit
-    posts      sync.Map // map[string]*models.Post
-    comments   sync.Map // map[string]*models.Comment
-    messages   sync.Map // map[string]*models.DirectMessage
-    votes      sync.Map // map[string]*models.Vote
+    config *Config
+    idGen  IDGenerator
+    clock  Clock
+
+    users      sync.Map    // map[string]*models.User
+    subreddits sync.Map    // map[string]*models.SubReddit
+    posts      *shardedMap // map[string]*models.Post, sharded by ID
+    comments   sync.Map    // map[string]*models.Comment
+    messages   sync.Map    // map[string]*models.DirectMessage
+    votes      sync.Map    // map[string]*models.Vote
+    reports    sync.Map    // map[reporterID:targetID]*models.Report
+    webhooks   sync.Map    // map[string]*models.Webhook
+
+    notifications sync.Map // map[string]*models.Notification
+    bannedUsers   sync.Map // map[userID]time.Time of the ban
+
+    // Running totals reported by GlobalStats
+    counters globalCounters
+
+    // Indexes
+    usernames         sync.Map // map[username]userID
+    subredditPosts    sync.Map // map[subredditID]*sync.Map of postID -> bool
+    pendingPosts      sync.Map // map[subredditID]*sync.Map of queued postID -> bool
+    scheduledPosts    sync.Map // map[postID]*models.Post awaiting publication
+    postComments      sync.Map // map[postID]*sync.Map of commentID -> bool
+    commentReplies    sync.Map // map[commentID]*sync.Map of direct reply commentID -> bool
+    userSubscriptions sync.Map // map[userID]*sync.Map of subredditID -> bool
+    userNotifications sync.Map // map[userID]*sync.Map of notificationID -> bool
+    notifyBatches     sync.Map // map[userID:type]*models.Notification still taking additions
+    userVotes         sync.Map // map[userID]*sync.Map of voted targetID -> bool
+    userPosts         sync.Map // map[authorID]*sync.Map of listed postID -> bool
+    userComments      sync.Map // map[authorID]*sync.Map of commentID -> bool
+    subredditSlugs    sync.Map // map[subredditID]*slugIndex
+    subredditWebhooks sync.Map // map[subredditID]*sync.Map of webhookID -> bool
+    subredditsCreated sync.Map // map[userID]*atomic.Int64 of subreddits created
+
+    // Subreddit names sorted by lowercase name, for prefix search
+    nameIndexMtx   sync.RWMutex
+    subredditNames []subredditName
+
+    // Serializes edits to post and comment content, subreddit flairs and
+    // direct messages, including their read state, and email verification
+    editMtx sync.Mutex
+
+    // Flood control: time of each user's latest post and comment
+    lastPostAt    sync.Map // map[userID]time.Time
+    lastCommentAt sync.Map // map[userID]time.Time
+
+    // Personalized feed rankings cached per user
+    personalizedFeeds sync.Map // map[userID]*personalizedFeed
+
+    // Karma leaderboards cached per subreddit and window
+    leaderboards sync.Map // map[subredditID:window]*leaderboard
+
+    // Requests each user may still make across REST and gRPC
+    rateBudget *RateBudget
+
+    // Live feed subscribers notified of new posts
+    feedListeners     sync.Map // map[*feedListener]bool
+    feedSubscriptions sync.Map // map[userID]*atomic.Int64 of open feed subscriptions
+
+    // Duplicate detection: recent post content hashes per subreddit
+    recentPosts sync.Map // map[subredditID]*recentPostIndex
+
+    // Observers of engine mutations, fed by one dispatcher goroutine
+    listenerMtx    sync.RWMutex
+    listeners      []EngineListener
+    listenerOnce   sync.Once
+    listenerEvents chan listenerEvent
+
+    // Guards the read state of notifications and their batching
+    notificationMtx sync.Mutex
+
+    // Maintenance mode: mutating methods hold a read lock while they run
+    maintenanceMtx sync.RWMutex
+    readOnly       bool
+
+    // Striped locks serializing votes on each post or comment
+    targetLocks [targetLockStripes]sync.Mutex
+
+    // gRPC server started by Start
+    serverMtx  sync.Mutex
+    grpcServer *grpc.Server
+    listenAddr net.Addr
 }
 
 func NewRedditEngine() *RedditEngine {
-    return &RedditEngine{}
+    return NewRedditEngineWithConfig(NewDefaultConfig())
 }
 
-func generateID() string {
-    bytes := make([]byte, 16)
-    rand.Read(bytes)
-    return hex.EncodeToString(bytes)
+// NewRedditEngineWithConfig creates an engine using the given configuration
+func NewRedditEngineWithConfig(config *Config) *RedditEngine {
+    e := &RedditEngine{
+        config: config,
+        idGen:  randomIDGenerator{},
+        clock:  realClock{},
+        posts:  newShardedMap(config.PostShards),
+    }
+    e.rateBudget = &RateBudget{e: e}
+    return e
 }
 
-// Start the engine server
-func (e *RedditEngine) Start(port string) error {
-    return nil
+// NewRedditEngineWith creates an engine with the default configuration that
+// takes record IDs from idGen
+func NewRedditEngineWith(idGen IDGenerator) *RedditEngine {
+    e := NewRedditEngine()
+    e.idGen = idGen
+    return e
+}
+
+// generateID returns a new record ID from the engine's generator
+func (e *RedditEngine) generateID() string {
+    return e.idGen.NewID()
+}
+
+// Run performs background maintenance, such as purging expired
+// messages, publishing scheduled posts and dropping spent rate budget
+// windows, until the context is cancelled. All happen every
+// Config.MessageSweepInterval.
+func (e *RedditEngine) Run(ctx context.Context) {
+    ticker := time.NewTicker(e.config.MessageSweepInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            e.sweepExpiredMessages()
+            e.publishScheduledPosts()
+            e.rateBudget.sweep()
+        }
+    }
 }
 
 // RegisterAccount creates a new user account
 func (e *RedditEngine) RegisterAccount(username, password string) (*models.User, error) {
-    // Check if username already exists
-    var exists bool
-    e.users.Range(func(key, value interface{}) bool {
-        user := value.(*models.User)
-        if user.Username == username {
-            exists = true
-            return false
-        }
-        return true
-    })
+    user, _, err := e.RegisterAccountWithEmail(username, password, "")
+    return user, err
+}
 
-    if exists {
-        return nil, errors.New("username already exists")
+// RegisterAccountWithEmail creates a user account with an optional email.
+// An account with an email starts unverified, and the returned token must
+// be passed to VerifyEmail; without an email the token is empty.
+func (e *RedditEngine) RegisterAccountWithEmail(username, password, email string) (*models.User, string, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, "", err
+    }
+    defer done()
+
+    // Reserve the username, which also checks it isn't taken
+    userID := e.generateID()
+    if _, exists := e.usernames.LoadOrStore(username, userID); exists {
+        return nil, "", ErrUsernameTaken
     }
 
     // Hash password
     hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
     if err != nil {
-        return nil, err
+        e.usernames.Delete(username)
+        return nil, "", err
     }
 
     user := &models.User{
-        ID:        generateID(),
+        ID:        userID,
         Username:  username,
         Password:  string(hashedPassword),
         Karma:     0,
-        CreatedAt: time.Now(),
+        CreatedAt: e.clock.Now(),
+        Email:     email,
+    }
+    var token string
+    if email != "" {
+        token = randomIDGenerator{}.NewID()
+        user.VerificationToken = hashVerificationToken(token)
     }
 
     e.users.Store(user.ID, user)
-    return user, nil
+    e.counters.users.Add(1)
+    e.joinDefaultSubreddits(user.ID)
+    registered := *user
+    e.emit("user registered", func(l EngineListener) { l.OnUserRegistered(registered) })
+    return user, token, nil
 }
 
 // AuthenticateUser validates credentials and returns a token
 func (e *RedditEngine) AuthenticateUser(username, password string) (string, error) {
-    var user *models.User
-    e.users.Range(func(key, value interface{}) bool {
-        u := value.(*models.User)
-        if u.Username == username {
-            user = u
-            return false
-        }
-        return true
-    })
-
-    if user == nil {
-        return "", errors.New("user not found")
+    user, err := e.GetUserByUsername(username)
+    if err != nil {
+        return "", err
     }
 
     if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
         return "", errors.New("invalid password")
     }
+    if err := e.checkNotBanned(user.ID); err != nil {
+        return "", err
+    }
 
     return user.ID, nil // Using user ID as token for simplicity
 }
 
+// GetUser retrieves a user by ID
+func (e *RedditEngine) GetUser(userID string) (*models.User, error) {
+    userI, ok := e.users.Load(userID)
+    if !ok {
+        return nil, ErrUserNotFound
+    }
+    return userI.(*models.User), nil
+}
+
+// GetUserByUsername retrieves a user by username using the username index
+func (e *RedditEngine) GetUserByUsername(username string) (*models.User, error) {
+    userIDI, ok := e.usernames.Load(username)
+    if !ok {
+        return nil, errors.New("user not found")
+    }
+    return e.GetUser(userIDI.(string))
+}
+
+var (
+    ErrSubredditFull     = errors.New("subreddit is full")
+    ErrSubredditNotFound = errors.New("subreddit not found")
+    ErrUserNotFound      = errors.New("user not found")
+    ErrUsernameTaken     = errors.New("username already exists")
+    ErrNotMember         = errors.New("user is not a member of this subreddit")
+    ErrParentNotOnPost   = errors.New("parent comment belongs to a different post")
+)
+
+// SubredditOptions holds optional settings for a new subreddit
+type SubredditOptions struct {
+    MaxMembers int64  // 0 means unlimited
+    Type       string // models.SubredditPublic when empty
+    QueuePosts bool   // Queue posts from non-approved users for review
+
+    // NoCrossposts keeps the subreddit off crosspost target lists;
+    // crossposts are allowed by default
+    NoCrossposts bool
+
+    AllowAnonymous bool // Let posts and comments hide their author
+}
+
+// SubredditUpdate lists subreddit settings to change; nil fields are left as is
+type SubredditUpdate struct {
+    Description *string
+    MaxMembers  *int64
+    Type        *string
+    QueuePosts  *bool
+
+    AllowCrossposts *bool
+    AllowAnonymous  *bool
+}
+
 // CreateSubReddit creates a new subreddit
 func (e *RedditEngine) CreateSubReddit(name, description, creatorID string) (*models.SubReddit, error) {
+    return e.CreateSubRedditWithOptions(name, description, creatorID, SubredditOptions{})
+}
+
+// CreateSubRedditWithOptions creates a new subreddit with the given settings
+func (e *RedditEngine) CreateSubRedditWithOptions(name, description, creatorID string, opts SubredditOptions) (*models.SubReddit, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
     // Validate creator exists
     _, exists := e.users.Load(creatorID)
     if !exists {
         return nil, errors.New("creator not found")
     }
+    if err := e.checkNotBanned(creatorID); err != nil {
+        return nil, err
+    }
+    if opts.MaxMembers < 0 {
+        return nil, errors.New("max members cannot be negative")
+    }
+    if opts.Type == "" {
+        opts.Type = models.SubredditPublic
+    }
+    if err := checkSubredditType(opts.Type); err != nil {
+        return nil, err
+    }
+    if err := e.reserveSubredditSlot(creatorID); err != nil {
+        return nil, err
+    }
 
     subreddit := &models.SubReddit{
-        ID:          generateID(),
+        ID:          e.generateID(),
         Name:        name,
         Description: description,
+        Type:        opts.Type,
         CreatorID:   creatorID,
-        CreatedAt:   time.Now(),
+        MaxMembers:  opts.MaxMembers,
+        CreatedAt:   e.clock.Now(),
         Members:     sync.Map{},
+        QueuePosts:  opts.QueuePosts,
+
+        AllowCrossposts: !opts.NoCrossposts,
+        AllowAnonymous:  opts.AllowAnonymous,
     }
 
-    // Add creator as first member
-    subreddit.Members.Store(creatorID, true)
+    // Add creator as first member and moderator; any cap leaves room for them
+    if _, err := e.subscribe(creatorID, subreddit); err != nil {
+        e.releaseSubredditSlot(creatorID)
+        return nil, err
+    }
+    subreddit.Moderators.Store(creatorID, true)
     e.subreddits.Store(subreddit.ID, subreddit)
+    e.counters.subreddits.Add(1)
+    e.indexSubredditName(subreddit)
     return subreddit, nil
 }
 
@@ -121,11 +336,58 @@ func (e *RedditEngine) CreateSubReddit(name, description, creatorID string) (*mo
 func (e *RedditEngine) GetSubReddit(subredditID string) (*models.SubReddit, error) {
     subI, ok := e.subreddits.Load(subredditID)
     if !ok {
-        return nil, errors.New("subreddit not found")
+        return nil, ErrSubredditNotFound
     }
     return subI.(*models.SubReddit), nil
 }
 
+// UpdateSubReddit changes a subreddit's settings; only moderators may update.
+// Lowering MaxMembers below the current count keeps existing members but
+// blocks new joins until the count drops below the cap.
+func (e *RedditEngine) UpdateSubReddit(userID, subredditID string, update SubredditUpdate) (*models.SubReddit, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(userID, subreddit) {
+        return nil, ErrNotModerator
+    }
+    if update.MaxMembers != nil && *update.MaxMembers < 0 {
+        return nil, errors.New("max members cannot be negative")
+    }
+    if update.Type != nil {
+        if err := checkSubredditType(*update.Type); err != nil {
+            return nil, err
+        }
+    }
+
+    if update.Description != nil {
+        subreddit.Description = *update.Description
+    }
+    if update.MaxMembers != nil {
+        atomic.StoreInt64(&subreddit.MaxMembers, *update.MaxMembers)
+    }
+    if update.Type != nil {
+        subreddit.Type = *update.Type
+    }
+    if update.QueuePosts != nil {
+        subreddit.QueuePosts = *update.QueuePosts
+    }
+    if update.AllowCrossposts != nil {
+        subreddit.AllowCrossposts = *update.AllowCrossposts
+    }
+    if update.AllowAnonymous != nil {
+        subreddit.AllowAnonymous = *update.AllowAnonymous
+    }
+    return subreddit, nil
+}
+
 // ListSubreddits returns all subreddits
 func (e *RedditEngine) ListSubreddits() ([]*models.SubReddit, error) {
     var subreddits []*models.SubReddit
@@ -136,37 +398,157 @@ func (e *RedditEngine) ListSubreddits() ([]*models.SubReddit, error) {
     return subreddits, nil
 }
 
-// JoinSubReddit adds a user to a subreddit
-func (e *RedditEngine) JoinSubReddit(userID, subredditID string) error {
+// JoinSubReddit adds a user to a subreddit. Joining is idempotent: joined
+// reports whether the user became a member or already was one.
+func (e *RedditEngine) JoinSubReddit(userID, subredditID string) (joined bool, err error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return false, err
+    }
+    defer done()
+
     subredditI, exists := e.subreddits.Load(subredditID)
     if !exists {
-        return errors.New("subreddit not found")
+        return false, ErrSubredditNotFound
     }
 
     _, exists = e.users.Load(userID)
     if !exists {
-        return errors.New("user not found")
+        return false, ErrUserNotFound
+    }
+    if err := e.checkNotBanned(userID); err != nil {
+        return false, err
     }
 
     subreddit := subredditI.(*models.SubReddit)
-    subreddit.Members.Store(userID, true)
-    return nil
+    if err := checkNotArchived(subreddit); err != nil {
+        return false, err
+    }
+    if err := checkCanJoin(userID, subreddit); err != nil {
+        return false, err
+    }
+    return e.subscribe(userID, subreddit)
 }
 
 // LeaveSubReddit removes a user from a subreddit
 func (e *RedditEngine) LeaveSubReddit(userID, subredditID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
     subredditI, exists := e.subreddits.Load(subredditID)
     if !exists {
-        return errors.New("subreddit not found")
+        return ErrSubredditNotFound
     }
 
     subreddit := subredditI.(*models.SubReddit)
-    subreddit.Members.Delete(userID)
+    e.unsubscribe(userID, subreddit)
     return nil
 }
 
+// GetUserSubreddits returns the subreddits a user is a member of
+func (e *RedditEngine) GetUserSubreddits(userID string) ([]*models.SubReddit, error) {
+    if _, exists := e.users.Load(userID); !exists {
+        return nil, errors.New("user not found")
+    }
+
+    var subreddits []*models.SubReddit
+    for _, subredditID := range e.userSubredditIDs(userID) {
+        if subredditI, ok := e.subreddits.Load(subredditID); ok {
+            subreddits = append(subreddits, subredditI.(*models.SubReddit))
+        }
+    }
+    return subreddits, nil
+}
+
+// subscribe adds the user to the subreddit's members and to the reverse
+// subscription index, or returns ErrSubredditFull if the cap is reached.
+// joined is false if the user was already a member.
+func (e *RedditEngine) subscribe(userID string, subreddit *models.SubReddit) (joined bool, err error) {
+    if _, isMember := subreddit.Members.Load(userID); !isMember {
+        if !reserveMemberSlot(subreddit) {
+            return false, ErrSubredditFull
+        }
+        // Give the slot back if a concurrent join by the same user won
+        if _, loaded := subreddit.Members.LoadOrStore(userID, true); loaded {
+            atomic.AddInt64(&subreddit.MemberCount, -1)
+        } else {
+            joined = true
+        }
+    }
+    subsI, _ := e.userSubscriptions.LoadOrStore(userID, &sync.Map{})
+    subsI.(*sync.Map).Store(subreddit.ID, true)
+    return joined, nil
+}
+
+// reserveMemberSlot increments MemberCount unless that would exceed
+// MaxMembers. The compare-and-swap keeps concurrent joins from both
+// slipping past the cap.
+func reserveMemberSlot(subreddit *models.SubReddit) bool {
+    for {
+        count := atomic.LoadInt64(&subreddit.MemberCount)
+        maxMembers := atomic.LoadInt64(&subreddit.MaxMembers)
+        if maxMembers > 0 && count >= maxMembers {
+            return false
+        }
+        if atomic.CompareAndSwapInt64(&subreddit.MemberCount, count, count+1) {
+            return true
+        }
+    }
+}
+
+// unsubscribe removes the user from the subreddit's members and the reverse subscription index
+func (e *RedditEngine) unsubscribe(userID string, subreddit *models.SubReddit) {
+    if _, loaded := subreddit.Members.LoadAndDelete(userID); loaded {
+        atomic.AddInt64(&subreddit.MemberCount, -1)
+    }
+    if subsI, ok := e.userSubscriptions.Load(userID); ok {
+        subsI.(*sync.Map).Delete(subreddit.ID)
+    }
+}
+
+// userSubredditIDs returns the IDs of the subreddits a user belongs to
+func (e *RedditEngine) userSubredditIDs(userID string) []string {
+    var ids []string
+    subsI, ok := e.userSubscriptions.Load(userID)
+    if !ok {
+        return ids
+    }
+    subsI.(*sync.Map).Range(func(key, _ interface{}) bool {
+        ids = append(ids, key.(string))
+        return true
+    })
+    return ids
+}
+
 // CreatePost creates a new post in a subreddit
 func (e *RedditEngine) CreatePost(title, content, authorID, subredditID string) (*models.Post, error) {
+    return e.CreatePostWithOptions(title, content, authorID, subredditID, PostOptions{})
+}
+
+// PostOptions holds optional settings for a new post
+type PostOptions struct {
+    Distinguished bool   // Moderators only
+    Flair         string // Must be one of the subreddit's flairs; empty for none
+    NSFW          bool
+    Anonymous     bool // Hide the author; the subreddit must allow it
+
+    // ScheduledFor, when set, holds the post back until that time
+    ScheduledFor *time.Time
+
+    repostOf *models.Post // Set by RepostPost
+}
+
+// CreatePostWithOptions creates a post with the given settings
+func (e *RedditEngine) CreatePostWithOptions(title, content, authorID, subredditID string, opts PostOptions) (*models.Post, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
     // Validate author and subreddit exist
     _, authorExists := e.users.Load(authorID)
     subredditI, subredditExists := e.subreddits.Load(subredditID)
@@ -175,29 +557,111 @@ func (e *RedditEngine) CreatePost(title, content, authorID, subredditID string)
         return nil, errors.New("author not found")
     }
     if !subredditExists {
-        return nil, errors.New("subreddit not found")
+        return nil, ErrSubredditNotFound
+    }
+    if err := e.checkNotBanned(authorID); err != nil {
+        return nil, err
+    }
+    if err := e.checkVerified(authorID); err != nil {
+        return nil, err
+    }
+    if err := e.checkAccountAge(authorID); err != nil {
+        return nil, err
     }
 
     // Check if user is a member of the subreddit
     subreddit := subredditI.(*models.SubReddit)
+    if err := checkNotArchived(subreddit); err != nil {
+        return nil, err
+    }
     _, isMember := subreddit.Members.Load(authorID)
     if !isMember {
-        return nil, errors.New("user is not a member of this subreddit")
+        return nil, ErrNotMember
+    }
+    // A non-approved user's post is queued for review if the subreddit
+    // allows it
+    pending := false
+    if err := checkCanPost(authorID, subreddit); err != nil {
+        if !subreddit.QueuePosts {
+            return nil, err
+        }
+        pending = true
+    }
+    if opts.Distinguished && !isModerator(authorID, subreddit) {
+        return nil, ErrNotModerator
+    }
+    if opts.Anonymous && !subreddit.AllowAnonymous {
+        return nil, ErrAnonymousNotAllowed
+    }
+    if opts.ScheduledFor != nil && !opts.ScheduledFor.After(e.clock.Now()) {
+        return nil, errors.New("scheduled time must be in the future")
+    }
+    if opts.Flair != "" {
+        if err := e.checkFlair(subreddit, opts.Flair); err != nil {
+            return nil, err
+        }
+    }
+    if err := e.checkBannedWords(subreddit, title, content); err != nil {
+        return nil, err
     }
 
     post := &models.Post{
-        ID:          generateID(),
-        Title:       title,
-        Content:     content,
-        AuthorID:    authorID,
-        SubRedditID: subredditID,
-        CreatedAt:   time.Now(),
+        ID:            e.generateID(),
+        Title:         title,
+        Content:       e.storedContent(content),
+        AuthorID:      authorID,
+        SubRedditID:   subredditID,
+        CreatedAt:     e.clock.Now(),
+        Distinguished: opts.Distinguished,
+        Flair:         opts.Flair,
+        NSFW:          opts.NSFW,
+        Pending:       pending,
+        Anonymous:     opts.Anonymous,
+    }
+    if opts.repostOf != nil {
+        // The original's content is already in its stored form
+        post.Content = opts.repostOf.Content
+        post.IsRepost = true
+        post.OriginalID = originalPostID(opts.repostOf)
+    }
+    if opts.ScheduledFor != nil {
+        scheduledFor := *opts.ScheduledFor
+        post.ScheduledFor = &scheduledFor
+        post.CreatedAt = scheduledFor
+    }
+    // A rejected duplicate shouldn't cost the author their cooldown
+    if err := e.checkDuplicatePost(post); err != nil {
+        return nil, err
+    }
+    if err := e.checkCooldown(&e.lastPostAt, authorID, e.config.PostCooldown); err != nil {
+        return nil, err
     }
+    e.assignSlug(post)
 
     e.posts.Store(post.ID, post)
+    e.counters.posts.Add(1)
+    if post.ScheduledFor != nil {
+        e.scheduledPosts.Store(post.ID, post)
+        return post, nil
+    }
+    if post.Pending {
+        e.indexPendingPost(post)
+        return post, nil
+    }
+    e.announcePost(post)
     return post, nil
 }
 
+// announcePost makes a new post visible: it indexes the post, notifies
+// mentioned users and tells feed subscribers and listeners
+func (e *RedditEngine) announcePost(post *models.Post) {
+    e.indexPost(post)
+    e.notifyMentions(post.Content, post.AuthorID, actorID(post.AuthorID, post.Anonymous), post.ID, "")
+    e.publishPost(post)
+    created := e.copyPost(post)
+    e.emit("post created", func(l EngineListener) { l.OnPostCreated(created) })
+}
+
 // GetPost retrieves a single post by ID
 func (e *RedditEngine) GetPost(postID string) (*models.Post, error) {
     postI, ok := e.posts.Load(postID)
@@ -207,24 +671,80 @@ func (e *RedditEngine) GetPost(postID string) (*models.Post, error) {
     return postI.(*models.Post), nil
 }
 
-// ListPosts returns posts for a subreddit
-func (e *RedditEngine) ListPosts(subredditID string) ([]*models.Post, error) {
+// MaxPostBatchSize is the most posts GetPosts will look up in one call
+const MaxPostBatchSize = 100
+
+// GetPosts retrieves several posts by ID, skipping any that don't exist
+func (e *RedditEngine) GetPosts(postIDs []string) ([]*models.Post, error) {
+    if len(postIDs) > MaxPostBatchSize {
+        return nil, fmt.Errorf("batch size %d exceeds maximum of %d", len(postIDs), MaxPostBatchSize)
+    }
+
+    posts := make([]*models.Post, 0, len(postIDs))
+    for _, postID := range postIDs {
+        if postI, ok := e.posts.Load(postID); ok {
+            posts = append(posts, postI.(*models.Post))
+        }
+    }
+    return posts, nil
+}
+
+// ListPosts returns posts for a subreddit, as seen by userID; only members
+// can list a private subreddit's posts
+func (e *RedditEngine) ListPosts(userID, subredditID string) ([]*models.Post, error) {
+    if _, err := e.ViewSubReddit(userID, subredditID); err != nil {
+        return nil, err
+    }
+    return e.listedPosts(e.subredditPostList(subredditID)), nil
+}
+
+// indexPost records a post under its subreddit in the subreddit index and
+// under its author in the author index
+func (e *RedditEngine) indexPost(post *models.Post) {
+    idxI, _ := e.subredditPosts.LoadOrStore(post.SubRedditID, &sync.Map{})
+    idxI.(*sync.Map).Store(post.ID, true)
+    authorI, _ := e.userPosts.LoadOrStore(post.AuthorID, &sync.Map{})
+    authorI.(*sync.Map).Store(post.ID, true)
+}
+
+// subredditPostList returns the posts of a subreddit using the subreddit index
+func (e *RedditEngine) subredditPostList(subredditID string) []*models.Post {
     var posts []*models.Post
-    e.posts.Range(func(key, value interface{}) bool {
-        post := value.(*models.Post)
-        if post.SubRedditID == subredditID {
-            posts = append(posts, post)
+    idxI, ok := e.subredditPosts.Load(subredditID)
+    if !ok {
+        return posts
+    }
+    idxI.(*sync.Map).Range(func(key, _ interface{}) bool {
+        if postI, ok := e.posts.Load(key.(string)); ok {
+            posts = append(posts, postI.(*models.Post))
         }
         return true
     })
-    return posts, nil
+    return posts
 }
 
 // CreateComment adds a comment to a post or another comment
 func (e *RedditEngine) CreateComment(content, authorID, postID string, parentCommentID *string) (*models.Comment, error) {
+    return e.CreateCommentWithOptions(content, authorID, postID, parentCommentID, CommentOptions{})
+}
+
+// CommentOptions holds optional settings for a new comment
+type CommentOptions struct {
+    Distinguished bool // Moderators only
+    Anonymous     bool // Hide the author; the subreddit must allow it
+}
+
+// CreateCommentWithOptions creates a comment with the given settings
+func (e *RedditEngine) CreateCommentWithOptions(content, authorID, postID string, parentCommentID *string, opts CommentOptions) (*models.Comment, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
     // Validate author and post exist
     _, authorExists := e.users.Load(authorID)
-    _, postExists := e.posts.Load(postID)
+    postI, postExists := e.posts.Load(postID)
 
     if !authorExists {
         return nil, errors.New("author not found")
@@ -232,138 +752,363 @@ func (e *RedditEngine) CreateComment(content, authorID, postID string, parentCom
     if !postExists {
         return nil, errors.New("post not found")
     }
+    if err := e.checkNotBanned(authorID); err != nil {
+        return nil, err
+    }
+    if err := e.checkAccountAge(authorID); err != nil {
+        return nil, err
+    }
+    if _, err := e.ViewSubReddit(authorID, postI.(*models.Post).SubRedditID); err != nil {
+        return nil, err
+    }
+    if postI.(*models.Post).Pending {
+        return nil, ErrPostPending
+    }
+    if e.isScheduled(postID) {
+        return nil, ErrPostScheduled
+    }
 
     // If parent comment ID is provided, validate it exists
+    depth := 0
     if parentCommentID != nil {
-        _, exists := e.comments.Load(*parentCommentID)
+        parentI, exists := e.comments.Load(*parentCommentID)
         if !exists {
             return nil, errors.New("parent comment not found")
         }
+        parent := parentI.(*models.Comment)
+        if parent.PostID != postID {
+            return nil, ErrParentNotOnPost
+        }
+        depth = parent.Depth + 1
+    }
+
+    // Replies past the configured depth are rejected rather than re-parented
+    if depth > e.config.MaxCommentDepth {
+        return nil, fmt.Errorf("comment depth %d exceeds the maximum of %d; reply to a shallower comment instead", depth, e.config.MaxCommentDepth)
+    }
+
+    subreddit, err := e.GetSubReddit(postI.(*models.Post).SubRedditID)
+    if err != nil {
+        return nil, err
+    }
+    if err := checkNotArchived(subreddit); err != nil {
+        return nil, err
+    }
+    if e.isLocked(postI.(*models.Post)) && !isModerator(authorID, subreddit) {
+        return nil, ErrPostLocked
+    }
+    if opts.Distinguished && !isModerator(authorID, subreddit) {
+        return nil, ErrNotModerator
+    }
+    if opts.Anonymous && !subreddit.AllowAnonymous {
+        return nil, ErrAnonymousNotAllowed
+    }
+    if err := e.checkBannedWords(subreddit, content); err != nil {
+        return nil, err
+    }
+
+    if err := e.checkCooldown(&e.lastCommentAt, authorID, e.config.CommentCooldown); err != nil {
+        return nil, err
     }
 
     comment := &models.Comment{
-        ID:        generateID(),
-        Content:   content,
-        AuthorID:  authorID,
-        PostID:    postID,
-        ParentID:  parentCommentID,
-        CreatedAt: time.Now(),
+        ID:            e.generateID(),
+        Content:       e.storedContent(content),
+        AuthorID:      authorID,
+        PostID:        postID,
+        ParentID:      parentCommentID,
+        Depth:         depth,
+        CreatedAt:     e.clock.Now(),
+        Distinguished: opts.Distinguished,
+        Anonymous:     opts.Anonymous,
     }
 
     e.comments.Store(comment.ID, comment)
+    e.indexComment(comment)
+    atomic.AddInt64(&postI.(*models.Post).CommentCount, 1)
+    e.counters.comments.Add(1)
+    replyRecipient := e.notifyReply(comment)
+    e.notifyMentions(comment.Content, authorID, actorID(authorID, comment.Anonymous), postID, comment.ID, replyRecipient)
+    created := e.copyComment(comment)
+    e.emit("comment", func(l EngineListener) { l.OnComment(created) })
     return comment, nil
 }
 
+// GetCommentContext returns the chain of comments from the top-level
+// ancestor down to the given comment, inclusive
+func (e *RedditEngine) GetCommentContext(commentID string) ([]*models.Comment, error) {
+    comment, err := e.GetComment(commentID)
+    if err != nil {
+        return nil, err
+    }
+
+    chain := []*models.Comment{comment}
+    visited := map[string]bool{comment.ID: true}
+    for comment.ParentID != nil {
+        // Guard against a malformed ParentID loop
+        if visited[*comment.ParentID] {
+            return nil, fmt.Errorf("comment %s has a cyclic parent chain", commentID)
+        }
+        parent, err := e.GetComment(*comment.ParentID)
+        if err != nil {
+            return nil, fmt.Errorf("parent of comment %s: %w", comment.ID, err)
+        }
+        visited[parent.ID] = true
+        chain = append(chain, parent)
+        comment = parent
+    }
+
+    // Reverse so the chain reads root -> target
+    for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+        chain[i], chain[j] = chain[j], chain[i]
+    }
+    return chain, nil
+}
+
 // GetComments returns comments for a post
 func (e *RedditEngine) GetComments(postID string) ([]*models.Comment, error) {
+    return e.listedComments(e.postCommentList(postID)), nil
+}
+
+// indexComment records a comment under its post in the post index, under
+// its author in the author index and, for a reply, under its parent in the
+// reply index
+func (e *RedditEngine) indexComment(comment *models.Comment) {
+    idxI, _ := e.postComments.LoadOrStore(comment.PostID, &sync.Map{})
+    idxI.(*sync.Map).Store(comment.ID, true)
+    authorI, _ := e.userComments.LoadOrStore(comment.AuthorID, &sync.Map{})
+    authorI.(*sync.Map).Store(comment.ID, true)
+    if comment.ParentID != nil {
+        repliesI, _ := e.commentReplies.LoadOrStore(*comment.ParentID, &sync.Map{})
+        repliesI.(*sync.Map).Store(comment.ID, true)
+    }
+}
+
+// postCommentList returns the comments of a post using the post index
+func (e *RedditEngine) postCommentList(postID string) []*models.Comment {
     var comments []*models.Comment
-    e.comments.Range(func(key, value interface{}) bool {
-        comment := value.(*models.Comment)
-        if comment.PostID == postID {
-            comments = append(comments, comment)
+    idxI, ok := e.postComments.Load(postID)
+    if !ok {
+        return comments
+    }
+    idxI.(*sync.Map).Range(func(key, _ interface{}) bool {
+        if commentI, ok := e.comments.Load(key); ok {
+            comments = append(comments, commentI.(*models.Comment))
         }
         return true
     })
-    return comments, nil
+    return comments
+}
+
+// Vote directions for SetVote and VoteResult
+const (
+    VoteDown = -1
+    VoteNone = 0
+    VoteUp   = 1
+)
+
+// VoteResult is a target's vote counts right after a vote, and the voter's
+// resulting vote direction
+type VoteResult struct {
+    Upvotes   int64
+    Downvotes int64
+    UserVote  int
 }
 
 // Vote handles upvoting and downvoting of posts and comments
 func (e *RedditEngine) Vote(userID, targetID string, isUpvote bool) error {
+    _, err := e.SetVote(userID, targetID, voteDirection(isUpvote))
+    return err
+}
+
+// SetVote sets the user's vote on a post or comment to direction, where
+// VoteNone retracts it. Repeating the same call changes nothing.
+func (e *RedditEngine) SetVote(userID, targetID string, direction int) (VoteResult, error) {
+    if direction < VoteDown || direction > VoteUp {
+        return VoteResult{}, fmt.Errorf("invalid vote direction %d", direction)
+    }
+    return e.castVote(userID, targetID, func(int) int { return direction })
+}
+
+// ToggleVote votes like clicking an arrow: voting the same way as the
+// user's current vote retracts it, otherwise the vote is set
+func (e *RedditEngine) ToggleVote(userID, targetID string, isUpvote bool) (VoteResult, error) {
+    direction := voteDirection(isUpvote)
+    return e.castVote(userID, targetID, func(current int) int {
+        if current == direction {
+            return VoteNone
+        }
+        return direction
+    })
+}
+
+// castVote validates the vote and applies it with the target locked; next
+// picks the new direction from the user's current one
+func (e *RedditEngine) castVote(userID, targetID string, next func(current int) int) (VoteResult, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return VoteResult{}, err
+    }
+    defer done()
+
     // Check if target exists (could be post or comment)
     postI, isPost := e.posts.Load(targetID)
     commentI, isComment := e.comments.Load(targetID)
 
     if !isPost && !isComment {
-        return errors.New("target not found")
+        return VoteResult{}, errors.New("target not found")
+    }
+    if err := e.checkCanSeeTarget(userID, targetID); err != nil {
+        return VoteResult{}, err
+    }
+    if isPost && postI.(*models.Post).Pending {
+        return VoteResult{}, ErrPostPending
+    }
+    if isPost && e.isScheduled(targetID) {
+        return VoteResult{}, ErrPostScheduled
+    }
+    if err := e.checkTargetNotArchived(targetID); err != nil {
+        return VoteResult{}, err
+    }
+    if err := e.checkNotBanned(userID); err != nil {
+        return VoteResult{}, err
     }
 
-    voteID := userID + ":" + targetID
-    existingVoteI, exists := e.votes.Load(voteID)
+    unlock := e.lockTarget(targetID)
+    defer unlock()
+    return e.applyVote(userID+":"+targetID, userID, targetID, next, postI, commentI), nil
+}
 
-    if exists {
-        // Update existing vote
-        existingVote := existingVoteI.(*models.Vote)
-        if existingVote.IsUpvote != isUpvote {
-            if isPost {
-                post := postI.(*models.Post)
-                if isUpvote {
-                    post.Upvotes++
-                    post.Downvotes--
-                } else {
-                    post.Downvotes++
-                    post.Upvotes--
-                }
-            } else {
-                comment := commentI.(*models.Comment)
-                if isUpvote {
-                    comment.Upvotes++
-                    comment.Downvotes--
-                } else {
-                    comment.Downvotes++
-                    comment.Upvotes--
-                }
-            }
-            existingVote.IsUpvote = isUpvote
-        }
+// applyVote moves the user's vote to the direction chosen by next and
+// adjusts the target's counts; the caller must hold the target's lock.
+// Counts change atomically because readers don't take that lock.
+func (e *RedditEngine) applyVote(voteID, userID, targetID string, next func(current int) int, postI, commentI interface{}) VoteResult {
+    var upvotes, downvotes *int64
+    var autoRemoved *bool
+    if postI != nil {
+        post := postI.(*models.Post)
+        upvotes, downvotes, autoRemoved = &post.Upvotes, &post.Downvotes, &post.AutoRemoved
     } else {
+        comment := commentI.(*models.Comment)
+        upvotes, downvotes, autoRemoved = &comment.Upvotes, &comment.Downvotes, &comment.AutoRemoved
+    }
+    adjust := func(direction int, delta int64) {
+        switch direction {
+        case VoteUp:
+            atomic.AddInt64(upvotes, delta)
+        case VoteDown:
+            atomic.AddInt64(downvotes, delta)
+        }
+    }
+    counts := func() (int64, int64) {
+        return atomic.LoadInt64(upvotes), atomic.LoadInt64(downvotes)
+    }
+
+    current := VoteNone
+    existingVoteI, exists := e.votes.Load(voteID)
+    if exists {
+        current = voteDirection(existingVoteI.(*models.Vote).IsUpvote)
+    }
+    direction := next(current)
+    up, down := counts()
+    if direction == current {
+        return VoteResult{Upvotes: up, Downvotes: down, UserVote: current}
+    }
+    before := up - down
+    adjust(current, -1)
+    adjust(direction, 1)
+    up, down = counts()
+    if e.crossesRemovalThreshold(before, up-down) {
+        e.editMtx.Lock()
+        *autoRemoved = true
+        e.editMtx.Unlock()
+    }
+
+    switch {
+    case direction == VoteNone:
+        // Retract the existing vote
+        e.votes.Delete(voteID)
+        e.unindexVote(userID, targetID)
+        e.counters.votes.Add(-1)
+    case exists:
+        // Flip the existing vote, replacing the record rather than
+        // changing one readers may hold
+        changed := *existingVoteI.(*models.Vote)
+        changed.IsUpvote = direction == VoteUp
+        e.votes.Store(voteID, &changed)
+        e.emit("vote", func(l EngineListener) { l.OnVote(changed) })
+    default:
         // Create new vote
         vote := &models.Vote{
             UserID:    userID,
             TargetID:  targetID,
-            IsUpvote:  isUpvote,
-            CreatedAt: time.Now(),
+            IsUpvote:  direction == VoteUp,
+            CreatedAt: e.clock.Now(),
         }
-
-        if isPost {
-            post := postI.(*models.Post)
-            if isUpvote {
-                post.Upvotes++
-            } else {
-                post.Downvotes++
-            }
-        } else {
-            comment := commentI.(*models.Comment)
-            if isUpvote {
-                comment.Upvotes++
-            } else {
-                comment.Downvotes++
-            }
-        }
-
         e.votes.Store(voteID, vote)
+        e.indexVote(vote)
+        e.counters.votes.Add(1)
+        cast := *vote
+        e.emit("vote", func(l EngineListener) { l.OnVote(cast) })
     }
+    return VoteResult{Upvotes: up, Downvotes: down, UserVote: direction}
+}
 
-    return nil
+// voteDirection converts an up/down flag into VoteUp or VoteDown
+func voteDirection(isUpvote bool) int {
+    if isUpvote {
+        return VoteUp
+    }
+    return VoteDown
+}
+
+// GetUserVotes returns the user's existing votes on the given targets (targetID -> isUpvote)
+func (e *RedditEngine) GetUserVotes(userID string, targetIDs []string) (map[string]bool, error) {
+    votes := make(map[string]bool)
+    for _, targetID := range targetIDs {
+        voteI, exists := e.votes.Load(userID + ":" + targetID)
+        if exists {
+            votes[targetID] = voteI.(*models.Vote).IsUpvote
+        }
+    }
+    return votes, nil
 }
 
 // GetFeed returns a list of posts from subscribed subreddits
 func (e *RedditEngine) GetFeed(userID string) ([]*models.Post, error) {
-    var feed []*models.Post
-    userSubscriptions := make(map[string]bool)
-
-    // Get user's subscribed subreddits
-    e.subreddits.Range(func(key, value interface{}) bool {
-        subreddit := value.(*models.SubReddit)
-        if _, isMember := subreddit.Members.Load(userID); isMember {
-            userSubscriptions[subreddit.ID] = true
-        }
-        return true
-    })
+    return e.feedPosts(context.Background(), userID)
+}
 
-    // Collect posts from subscribed subreddits
-    e.posts.Range(func(key, value interface{}) bool {
-        post := value.(*models.Post)
-        if userSubscriptions[post.SubRedditID] {
-            feed = append(feed, post)
+// feedPosts collects posts from subscribed subreddits using the
+// subscription index, checking the context between subreddits
+func (e *RedditEngine) feedPosts(ctx context.Context, userID string) ([]*models.Post, error) {
+    var feed []*models.Post
+    for _, subredditID := range e.userSubredditIDs(userID) {
+        if err := ctx.Err(); err != nil {
+            return nil, err
         }
-        return true
-    })
-
+        feed = append(feed, e.listedPosts(e.subredditPostList(subredditID))...)
+    }
     return feed, nil
 }
 
 // SendDirectMessage sends a direct message from one user to another
 func (e *RedditEngine) SendDirectMessage(fromID, toID, content string) (*models.DirectMessage, error) {
+    return e.SendDirectMessageWithTTL(fromID, toID, content, 0)
+}
+
+// SendDirectMessageWithTTL sends a direct message that expires after ttl (0 means never)
+func (e *RedditEngine) SendDirectMessageWithTTL(fromID, toID, content string, ttl time.Duration) (*models.DirectMessage, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    if ttl < 0 {
+        return nil, errors.New("message ttl cannot be negative")
+    }
+
     // Validate both users exist
     _, fromExists := e.users.Load(fromID)
     _, toExists := e.users.Load(toID)
@@ -374,16 +1119,24 @@ func (e *RedditEngine) SendDirectMessage(fromID, toID, content string) (*models.
     if !toExists {
         return nil, errors.New("recipient not found")
     }
+    if err := e.checkNotBanned(fromID); err != nil {
+        return nil, err
+    }
 
     message := &models.DirectMessage{
-        ID:        generateID(),
+        ID:        e.generateID(),
         FromID:    fromID,
         ToID:      toID,
         Content:   content,
-        CreatedAt: time.Now(),
+        CreatedAt: e.clock.Now(),
+    }
+    if ttl > 0 {
+        expiresAt := message.CreatedAt.Add(ttl)
+        message.ExpiresAt = &expiresAt
     }
 
     e.messages.Store(message.ID, message)
+    e.counters.messages.Add(1)
     return message, nil
 }
 
@@ -394,6 +1147,10 @@ func (e *RedditEngine) GetMessage(userID, messageID string) (*models.DirectMessa
         return nil, errors.New("message not found")
     }
     msg := msgI.(*models.DirectMessage)
+    // Expired messages are hidden even before the sweeper removes them
+    if isExpired(msg, e.clock.Now()) {
+        return nil, errors.New("message not found")
+    }
     // Check if user is either sender or recipient
     if msg.FromID != userID && msg.ToID != userID {
         return nil, errors.New("unauthorized access to message")
@@ -409,15 +1166,126 @@ func (e *RedditEngine) GetUserPublicKey(userID string) (string, error) {
     return "dummy-public-key", nil
 }
 
-// GetUserMessages returns all messages for a user
-func (e *RedditEngine) GetUserMessages(userID string) ([]*models.DirectMessage, error) {
-    var messages []*models.DirectMessage
+// MessagePage is one page of a user's messages with counts across all pages
+type MessagePage struct {
+    Messages []*models.DirectMessage
+    Total    int // Messages the user sent or received
+    Unread   int // Received messages not yet marked read
+}
+
+// GetUserMessages returns one page of the messages a user sent or
+// received, newest first. With unreadFirst, unread received messages come
+// before everything else. Page is 1-based; a limit of 0 returns them all.
+func (e *RedditEngine) GetUserMessages(userID string, page, limit int, unreadFirst bool) (*MessagePage, error) {
+    if page < 0 || limit < 0 {
+        return nil, errors.New("page and limit cannot be negative")
+    }
+
+    messages := []*models.DirectMessage{}
+    now := e.clock.Now()
     e.messages.Range(func(_, value interface{}) bool {
         msg := value.(*models.DirectMessage)
+        if isExpired(msg, now) {
+            return true
+        }
         if msg.ToID == userID || msg.FromID == userID {
             messages = append(messages, msg)
         }
         return true
     })
-    return messages, nil
+
+    // Snapshot read state under the lock that guards it
+    e.editMtx.Lock()
+    unread := make(map[string]bool)
+    for _, msg := range messages {
+        if msg.ToID == userID && !msg.IsRead {
+            unread[msg.ID] = true
+        }
+    }
+    e.editMtx.Unlock()
+
+    sort.Slice(messages, func(i, j int) bool {
+        if unreadFirst && unread[messages[i].ID] != unread[messages[j].ID] {
+            return unread[messages[i].ID]
+        }
+        return messages[i].CreatedAt.After(messages[j].CreatedAt)
+    })
+
+    result := &MessagePage{Messages: messages, Total: len(messages), Unread: len(unread)}
+    if limit > 0 {
+        start := min((max(page, 1)-1)*limit, len(messages))
+        result.Messages = messages[start:min(start+limit, len(messages))]
+    }
+    return result, nil
+}
+
+// MarkMessageRead marks a message the user received as read
+func (e *RedditEngine) MarkMessageRead(userID, messageID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    msg, err := e.GetMessage(userID, messageID)
+    if err != nil {
+        return err
+    }
+    if msg.ToID != userID {
+        return errors.New("only the recipient can mark a message read")
+    }
+
+    e.editMtx.Lock()
+    msg.IsRead = true
+    e.editMtx.Unlock()
+    return nil
+}
+
+// MarkAllMessagesRead marks every unread message the user received as read
+// and returns how many changed. Sent and expired messages are left alone.
+func (e *RedditEngine) MarkAllMessagesRead(userID string) (int, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return 0, err
+    }
+    defer done()
+
+    now := e.clock.Now()
+    updated := 0
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    e.messages.Range(func(_, value interface{}) bool {
+        msg := value.(*models.DirectMessage)
+        if msg.ToID == userID && !msg.IsRead && !isExpired(msg, now) {
+            msg.IsRead = true
+            updated++
+        }
+        return true
+    })
+    return updated, nil
+}
+
+// isExpired reports whether a message's TTL has elapsed
+func isExpired(msg *models.DirectMessage, now time.Time) bool {
+    return msg.ExpiresAt != nil && !now.Before(*msg.ExpiresAt)
+}
+
+// sweepExpiredMessages deletes expired messages from the store. It skips
+// the sweep in maintenance mode; GetMessage already hides expired messages.
+func (e *RedditEngine) sweepExpiredMessages() {
+    done, err := e.beginWrite()
+    if err != nil {
+        return
+    }
+    defer done()
+
+    now := e.clock.Now()
+    e.messages.Range(func(key, value interface{}) bool {
+        if isExpired(value.(*models.DirectMessage), now) {
+            if _, loaded := e.messages.LoadAndDelete(key); loaded {
+                e.counters.messages.Add(-1)
+            }
+        }
+        return true
+    })
 }
\ No newline at end of file
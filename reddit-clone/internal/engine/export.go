@@ -0,0 +1,83 @@
+// internal/engine/export.go
+package engine
+
+import (
+    "sort"
+    "sync"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// UserDataExport is everything the engine holds about one user, for
+// handing back to them
+type UserDataExport struct {
+    ExportedAt    time.Time
+    User          *models.User
+    Posts         []*models.Post          // Oldest first, queued and scheduled ones included
+    Comments      []*models.Comment       // Oldest first
+    Votes         []*models.Vote          // Newest first
+    Messages      []*models.DirectMessage // Sent or received and not yet expired, newest first
+    Subscriptions []*models.SubReddit
+}
+
+// ExportUserData gathers a user's profile, the posts and comments they
+// wrote, anonymous ones included, the votes they cast, the direct
+// messages they sent or received and the subreddits they belong to.
+// Nothing written by or about other users is included beyond messages
+// exchanged with them.
+func (e *RedditEngine) ExportUserData(userID string) (*UserDataExport, error) {
+    user, err := e.GetUser(userID)
+    if err != nil {
+        return nil, err
+    }
+
+    export := &UserDataExport{
+        ExportedAt: e.clock.Now(),
+        User:       user,
+        Posts:      []*models.Post{},
+        Comments:   []*models.Comment{},
+    }
+    rangeUserIndex(&e.userPosts, userID, func(postID string) {
+        if postI, ok := e.posts.Load(postID); ok {
+            export.Posts = append(export.Posts, postI.(*models.Post))
+        }
+    })
+    // Queued and scheduled posts join the author index only once published
+    e.pendingPosts.Range(func(_, idxI interface{}) bool {
+        idxI.(*sync.Map).Range(func(key, _ interface{}) bool {
+            if postI, ok := e.posts.Load(key.(string)); ok && postI.(*models.Post).AuthorID == userID {
+                export.Posts = append(export.Posts, postI.(*models.Post))
+            }
+            return true
+        })
+        return true
+    })
+    export.Posts = append(export.Posts, e.scheduledPostList(func(post *models.Post) bool {
+        return post.AuthorID == userID
+    })...)
+    rangeUserIndex(&e.userComments, userID, func(commentID string) {
+        if commentI, ok := e.comments.Load(commentID); ok {
+            export.Comments = append(export.Comments, commentI.(*models.Comment))
+        }
+    })
+    sort.Slice(export.Posts, func(i, j int) bool {
+        return export.Posts[i].CreatedAt.Before(export.Posts[j].CreatedAt)
+    })
+    sort.Slice(export.Comments, func(i, j int) bool {
+        return export.Comments[i].CreatedAt.Before(export.Comments[j].CreatedAt)
+    })
+
+    if export.Votes, _, err = e.GetVoteHistory(userID, 0, 0); err != nil {
+        return nil, err
+    }
+    messages, err := e.GetUserMessages(userID, 0, 0, false)
+    if err != nil {
+        return nil, err
+    }
+    export.Messages = messages.Messages
+    if export.Subscriptions, err = e.GetUserSubreddits(userID); err != nil {
+        return nil, err
+    }
+    return export, nil
+}
\ No newline at end of file
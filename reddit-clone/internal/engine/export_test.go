@@ -0,0 +1,81 @@
+// internal/engine/export_test.go
+package engine
+
+import (
+    "testing"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+func TestExportUserDataIsCompleteAndPrivate(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    open, err := e.CreateSubRedditWithOptions("open", "Anything goes", bob.ID, SubredditOptions{AllowAnonymous: true})
+    if err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+    queued, err := e.CreateSubRedditWithOptions("queued", "Reviewed posts", bob.ID, SubredditOptions{Type: models.SubredditRestricted, QueuePosts: true})
+    if err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+    mustJoin(t, e, alice.ID, open.ID)
+    mustJoin(t, e, alice.ID, queued.ID)
+
+    listed := mustPost(t, e, alice.ID, open.ID)
+    anonymous, err := e.CreatePostWithOptions("Anonymous", "Hidden author", alice.ID, open.ID, PostOptions{Anonymous: true})
+    if err != nil {
+        t.Fatalf("anonymous CreatePostWithOptions: %v", err)
+    }
+    pending, err := e.CreatePost("Queued", "Awaiting review", alice.ID, queued.ID)
+    if err != nil || !pending.Pending {
+        t.Fatalf("queued CreatePost = %+v, %v; want a pending post", pending, err)
+    }
+    later := testStart.Add(time.Hour)
+    scheduled, err := e.CreatePostWithOptions("Scheduled", "Coming soon", alice.ID, open.ID, PostOptions{ScheduledFor: &later})
+    if err != nil {
+        t.Fatalf("scheduled CreatePostWithOptions: %v", err)
+    }
+    bobsPost := mustPost(t, e, bob.ID, open.ID)
+    aliceComment := mustComment(t, e, alice.ID, bobsPost.ID, nil)
+    mustComment(t, e, bob.ID, listed.ID, nil)
+    mustVote(t, e, alice.ID, bobsPost.ID, VoteUp)
+    mustVote(t, e, bob.ID, listed.ID, VoteUp)
+    if _, err := e.SendDirectMessage(bob.ID, alice.ID, "Hi alice"); err != nil {
+        t.Fatalf("SendDirectMessage: %v", err)
+    }
+
+    export, err := e.ExportUserData(alice.ID)
+    if err != nil {
+        t.Fatalf("ExportUserData: %v", err)
+    }
+    if export.User.ID != alice.ID {
+        t.Errorf("export User = %s, want alice", export.User.ID)
+    }
+
+    wantPosts := map[string]bool{listed.ID: true, anonymous.ID: true, pending.ID: true, scheduled.ID: true}
+    if len(export.Posts) != len(wantPosts) {
+        t.Errorf("exported %d posts, want %d", len(export.Posts), len(wantPosts))
+    }
+    for i, post := range export.Posts {
+        if !wantPosts[post.ID] {
+            t.Errorf("exported post %s (%q) isn't alice's", post.ID, post.Title)
+        }
+        if i > 0 && post.CreatedAt.Before(export.Posts[i-1].CreatedAt) {
+            t.Error("exported posts aren't oldest first")
+        }
+    }
+    if len(export.Comments) != 1 || export.Comments[0].ID != aliceComment.ID {
+        t.Errorf("exported comments = %+v, want only alice's", export.Comments)
+    }
+    if len(export.Votes) != 1 || export.Votes[0].UserID != alice.ID || export.Votes[0].TargetID != bobsPost.ID {
+        t.Errorf("exported votes = %+v, want only alice's vote", export.Votes)
+    }
+    if len(export.Messages) != 1 {
+        t.Errorf("exported %d messages, want the one bob sent", len(export.Messages))
+    }
+    if len(export.Subscriptions) != 2 {
+        t.Errorf("exported %d subscriptions, want 2", len(export.Subscriptions))
+    }
+}
\ No newline at end of file
@@ -0,0 +1,84 @@
+// internal/engine/feed.go
+package engine
+
+import (
+    "context"
+
+    "reddit-clone/internal/models"
+)
+
+// FeedOptions adjusts how a user's feed is assembled
+type FeedOptions struct {
+    // DedupeReposts collapses an original post and its reposts into one entry
+    DedupeReposts bool
+
+    // Flair, when set, keeps only posts carrying that flair
+    Flair string
+
+    // HideOwn leaves out posts the user wrote
+    HideOwn bool
+}
+
+// GetFeedWithOptions returns posts from subscribed subreddits, shaped by
+// opts. The scan stops with the context's error once it is done.
+func (e *RedditEngine) GetFeedWithOptions(ctx context.Context, userID string, opts FeedOptions) ([]*models.Post, error) {
+    feed, err := e.feedPosts(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    if opts.HideOwn {
+        feed = excludeAuthor(feed, userID)
+    }
+    if opts.Flair != "" {
+        feed = FilterByFlair(feed, opts.Flair)
+    }
+    if opts.DedupeReposts {
+        feed = dedupeReposts(feed)
+    }
+    return feed, nil
+}
+
+// excludeAuthor returns the posts not written by authorID
+func excludeAuthor(posts []*models.Post, authorID string) []*models.Post {
+    kept := make([]*models.Post, 0, len(posts))
+    for _, post := range posts {
+        if post.AuthorID != authorID {
+            kept = append(kept, post)
+        }
+    }
+    return kept
+}
+
+// dedupeReposts keeps one post per original: the highest-scored of the
+// original and its reposts, preferring the original on a tie. Entries keep
+// the position of the first post seen for their group.
+func dedupeReposts(posts []*models.Post) []*models.Post {
+    var deduped []*models.Post
+    groupIndex := make(map[string]int)
+    for _, post := range posts {
+        key := post.ID
+        if post.OriginalID != "" {
+            key = post.OriginalID
+        }
+
+        i, seen := groupIndex[key]
+        if !seen {
+            groupIndex[key] = len(deduped)
+            deduped = append(deduped, post)
+            continue
+        }
+        if preferPost(post, deduped[i]) {
+            deduped[i] = post
+        }
+    }
+    return deduped
+}
+
+// preferPost reports whether candidate should replace current as its group's entry
+func preferPost(candidate, current *models.Post) bool {
+    if candidate.Score() != current.Score() {
+        return candidate.Score() > current.Score()
+    }
+    return candidate.OriginalID == "" && current.OriginalID != ""
+}
\ No newline at end of file
@@ -0,0 +1,14 @@
+// internal/engine/feed_bench_test.go
+package engine_test
+
+import (
+    "testing"
+
+    "reddit-clone/internal/bench"
+)
+
+// BenchmarkGetFeed runs the harness's GetFeed benchmark, so `go test -bench`
+// and cmd/bench -feed-bench measure the same fixture
+func BenchmarkGetFeed(b *testing.B) {
+    bench.BenchmarkGetFeed(b)
+}
\ No newline at end of file
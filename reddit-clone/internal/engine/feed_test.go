@@ -0,0 +1,70 @@
+// internal/engine/feed_test.go
+package engine
+
+import (
+    "context"
+    "testing"
+
+    "reddit-clone/internal/models"
+)
+
+// feedIDs returns the IDs of the posts in a feed as a set
+func feedIDs(feed []*models.Post) map[string]bool {
+    ids := make(map[string]bool, len(feed))
+    for _, post := range feed {
+        ids[post.ID] = true
+    }
+    return ids
+}
+
+func TestFeedDedupeCollapsesReposts(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+    reader := mustRegister(t, e, "reader")
+    golang := mustCreateSubreddit(t, e, "golang", alice.ID)
+    rust := mustCreateSubreddit(t, e, "rust", bob.ID)
+    zig := mustCreateSubreddit(t, e, "zig", carol.ID)
+    for _, subreddit := range []*models.SubReddit{golang, rust, zig} {
+        mustJoin(t, e, reader.ID, subreddit.ID)
+    }
+
+    original := mustPost(t, e, alice.ID, golang.ID)
+    other := mustPost(t, e, alice.ID, golang.ID)
+    first, err := e.RepostPost(bob.ID, original.ID, rust.ID)
+    if err != nil {
+        t.Fatalf("RepostPost: %v", err)
+    }
+    second, err := e.RepostPost(carol.ID, first.ID, zig.ID)
+    if err != nil {
+        t.Fatalf("RepostPost: %v", err)
+    }
+
+    feed := func(dedupe bool) map[string]bool {
+        t.Helper()
+        posts, err := e.GetFeedWithOptions(context.Background(), reader.ID, FeedOptions{DedupeReposts: dedupe})
+        if err != nil {
+            t.Fatalf("GetFeedWithOptions: %v", err)
+        }
+        if ids := feedIDs(posts); len(ids) != len(posts) {
+            t.Fatalf("feed repeats a post: %d entries, %d distinct", len(posts), len(ids))
+        }
+        return feedIDs(posts)
+    }
+
+    if got := feed(false); len(got) != 4 {
+        t.Fatalf("feed without dedupe has %d posts, want 4", len(got))
+    }
+
+    // On equal scores the original is kept
+    if got := feed(true); len(got) != 2 || !got[original.ID] || !got[other.ID] {
+        t.Errorf("deduplicated feed = %v, want the original and the unrelated post", got)
+    }
+
+    // A better-scored repost replaces the original
+    mustVote(t, e, reader.ID, second.ID, VoteUp)
+    if got := feed(true); len(got) != 2 || !got[second.ID] || !got[other.ID] {
+        t.Errorf("deduplicated feed = %v, want the upvoted repost and the unrelated post", got)
+    }
+}
\ No newline at end of file
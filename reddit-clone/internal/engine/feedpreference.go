@@ -0,0 +1,49 @@
+// internal/engine/feedpreference.go
+package engine
+
+import "errors"
+
+// Feed sorts a user can store as their default
+const (
+    FeedSortNew          = "new"
+    FeedSortPersonalized = "personalized"
+)
+
+var ErrInvalidFeedSort = errors.New("feed sort must be new or personalized")
+
+// SetFeedPreference stores the feed sort used when the user's feed is
+// requested without one. An empty sort clears the preference.
+func (e *RedditEngine) SetFeedPreference(userID, sort string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    switch sort {
+    case "", FeedSortNew, FeedSortPersonalized:
+    default:
+        return ErrInvalidFeedSort
+    }
+    user, err := e.GetUser(userID)
+    if err != nil {
+        return err
+    }
+
+    e.editMtx.Lock()
+    user.FeedPreference = sort
+    e.editMtx.Unlock()
+    return nil
+}
+
+// FeedPreference returns the user's stored feed sort, empty if none is set
+func (e *RedditEngine) FeedPreference(userID string) (string, error) {
+    user, err := e.GetUser(userID)
+    if err != nil {
+        return "", err
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    return user.FeedPreference, nil
+}
\ No newline at end of file
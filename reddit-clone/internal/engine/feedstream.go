@@ -0,0 +1,106 @@
+// internal/engine/feedstream.go
+package engine
+
+import (
+    "errors"
+    "sync"
+    "sync/atomic"
+
+    "reddit-clone/internal/models"
+)
+
+var ErrTooManyFeedSubscriptions = errors.New("too many open feed subscriptions")
+
+// feedListenerBuffer is how many new posts a listener may fall behind by
+// before further posts are dropped for it
+const feedListenerBuffer = 64
+
+// feedListener receives new posts from the subreddits a user belongs to
+type feedListener struct {
+    userID string
+    posts  chan *models.Post
+
+    mtx    sync.Mutex // Guards closed and sends on posts
+    closed bool
+}
+
+// SubscribeFeed returns a channel that receives each new post created in
+// the user's subreddits from now on, and a function that ends the
+// subscription and closes the channel. A listener that falls too far
+// behind misses posts rather than slowing down posting. A user already
+// holding Config.MaxFeedSubscriptionsPerUser subscriptions gets
+// ErrTooManyFeedSubscriptions until one of them is cancelled.
+func (e *RedditEngine) SubscribeFeed(userID string) (<-chan *models.Post, func(), error) {
+    if _, exists := e.users.Load(userID); !exists {
+        return nil, nil, errors.New("user not found")
+    }
+    if err := e.reserveFeedSubscription(userID); err != nil {
+        return nil, nil, err
+    }
+
+    listener := &feedListener{
+        userID: userID,
+        posts:  make(chan *models.Post, feedListenerBuffer),
+    }
+    e.feedListeners.Store(listener, true)
+
+    var once sync.Once
+    cancel := func() {
+        once.Do(func() {
+            e.feedListeners.Delete(listener)
+            e.openFeedSubscriptions(userID).Add(-1)
+            listener.mtx.Lock()
+            listener.closed = true
+            close(listener.posts)
+            listener.mtx.Unlock()
+        })
+    }
+    return listener.posts, cancel, nil
+}
+
+// openFeedSubscriptions returns the counter of the user's open feed subscriptions
+func (e *RedditEngine) openFeedSubscriptions(userID string) *atomic.Int64 {
+    countI, _ := e.feedSubscriptions.LoadOrStore(userID, new(atomic.Int64))
+    return countI.(*atomic.Int64)
+}
+
+// reserveFeedSubscription counts a new subscription against the user's
+// Config.MaxFeedSubscriptionsPerUser, returning ErrTooManyFeedSubscriptions
+// once it's reached
+func (e *RedditEngine) reserveFeedSubscription(userID string) error {
+    count := e.openFeedSubscriptions(userID)
+    limit := int64(e.config.MaxFeedSubscriptionsPerUser)
+    for {
+        n := count.Load()
+        if limit > 0 && n >= limit {
+            return ErrTooManyFeedSubscriptions
+        }
+        if count.CompareAndSwap(n, n+1) {
+            return nil
+        }
+    }
+}
+
+// publishPost delivers a new post to the listeners subscribed to its subreddit
+func (e *RedditEngine) publishPost(post *models.Post) {
+    e.feedListeners.Range(func(key, _ interface{}) bool {
+        listener := key.(*feedListener)
+        subsI, ok := e.userSubscriptions.Load(listener.userID)
+        if !ok {
+            return true
+        }
+        if _, subscribed := subsI.(*sync.Map).Load(post.SubRedditID); !subscribed {
+            return true
+        }
+
+        listener.mtx.Lock()
+        if !listener.closed {
+            select {
+            case listener.posts <- post:
+            default:
+            }
+        }
+        listener.mtx.Unlock()
+        return true
+    })
+}
\ No newline at end of file
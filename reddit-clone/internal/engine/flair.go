@@ -0,0 +1,95 @@
+// internal/engine/flair.go
+package engine
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+
+    "reddit-clone/internal/models"
+)
+
+const (
+    MaxSubredditFlairs = 20 // Most flairs a subreddit may offer
+    MaxFlairLength     = 32 // Longest flair, in bytes
+)
+
+var ErrInvalidFlair = errors.New("flair is not allowed in this subreddit")
+
+// SetSubredditFlairs replaces the flairs a subreddit's posts may carry;
+// only moderators may change them. Flairs are trimmed and duplicates
+// dropped, keeping the given order. An empty list disables flair.
+func (e *RedditEngine) SetSubredditFlairs(userID, subredditID string, flairs []string) ([]string, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(userID, subreddit) {
+        return nil, ErrNotModerator
+    }
+
+    allowed := make([]string, 0, len(flairs))
+    seen := make(map[string]bool)
+    for _, flair := range flairs {
+        flair = strings.TrimSpace(flair)
+        if flair == "" {
+            return nil, errors.New("flair cannot be empty")
+        }
+        if len(flair) > MaxFlairLength {
+            return nil, fmt.Errorf("flair %q exceeds the maximum length of %d", flair, MaxFlairLength)
+        }
+        if !seen[flair] {
+            seen[flair] = true
+            allowed = append(allowed, flair)
+        }
+    }
+    if len(allowed) > MaxSubredditFlairs {
+        return nil, fmt.Errorf("a subreddit may offer at most %d flairs", MaxSubredditFlairs)
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    subreddit.Flairs = allowed
+    return append([]string(nil), allowed...), nil
+}
+
+// GetSubredditFlairs returns the flairs a subreddit's posts may carry
+func (e *RedditEngine) GetSubredditFlairs(subredditID string) ([]string, error) {
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return nil, err
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    return append([]string{}, subreddit.Flairs...), nil
+}
+
+// checkFlair returns ErrInvalidFlair unless flair is one the subreddit allows
+func (e *RedditEngine) checkFlair(subreddit *models.SubReddit, flair string) error {
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    for _, allowed := range subreddit.Flairs {
+        if allowed == flair {
+            return nil
+        }
+    }
+    return fmt.Errorf("%w: %q", ErrInvalidFlair, flair)
+}
+
+// FilterByFlair keeps only the posts carrying the given flair
+func FilterByFlair(posts []*models.Post, flair string) []*models.Post {
+    filtered := make([]*models.Post, 0, len(posts))
+    for _, post := range posts {
+        if post.Flair == flair {
+            filtered = append(filtered, post)
+        }
+    }
+    return filtered
+}
\ No newline at end of file
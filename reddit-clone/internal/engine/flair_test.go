@@ -0,0 +1,78 @@
+// internal/engine/flair_test.go
+package engine
+
+import (
+    "context"
+    "errors"
+    "slices"
+    "testing"
+)
+
+func TestSetSubredditFlairs(t *testing.T) {
+    e, _ := newTestEngine(t)
+    mod := mustRegister(t, e, "mod")
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", mod.ID)
+
+    if _, err := e.SetSubredditFlairs(alice.ID, subreddit.ID, []string{"Question"}); err != ErrNotModerator {
+        t.Errorf("non-moderator SetSubredditFlairs = %v, want ErrNotModerator", err)
+    }
+
+    flairs, err := e.SetSubredditFlairs(mod.ID, subreddit.ID, []string{" Question ", "News", "Question"})
+    if err != nil {
+        t.Fatalf("SetSubredditFlairs: %v", err)
+    }
+    if want := []string{"Question", "News"}; !slices.Equal(flairs, want) {
+        t.Errorf("flairs = %v, want %v", flairs, want)
+    }
+    if got, _ := e.GetSubredditFlairs(subreddit.ID); !slices.Equal(got, flairs) {
+        t.Errorf("GetSubredditFlairs = %v, want %v", got, flairs)
+    }
+
+    // A bad list leaves the current one in place
+    if _, err := e.SetSubredditFlairs(mod.ID, subreddit.ID, []string{"News", "  "}); err == nil {
+        t.Error("SetSubredditFlairs accepted a blank flair")
+    }
+    if got, _ := e.GetSubredditFlairs(subreddit.ID); !slices.Equal(got, flairs) {
+        t.Errorf("flairs after a rejected update = %v, want %v", got, flairs)
+    }
+}
+
+func TestCreatePostChecksFlair(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+
+    // Without flairs configured, any flair is refused
+    if _, err := e.CreatePostWithOptions("Title", "Content", alice.ID, subreddit.ID, PostOptions{Flair: "News"}); !errors.Is(err, ErrInvalidFlair) {
+        t.Errorf("flair before any are allowed = %v, want ErrInvalidFlair", err)
+    }
+
+    if _, err := e.SetSubredditFlairs(alice.ID, subreddit.ID, []string{"Question", "News"}); err != nil {
+        t.Fatalf("SetSubredditFlairs: %v", err)
+    }
+    news, err := e.CreatePostWithOptions("Go 1.23", "Content", alice.ID, subreddit.ID, PostOptions{Flair: "News"})
+    if err != nil {
+        t.Fatalf("CreatePostWithOptions: %v", err)
+    }
+    if news.Flair != "News" {
+        t.Errorf("flair = %q, want News", news.Flair)
+    }
+    for _, flair := range []string{"Meme", "news"} {
+        if _, err := e.CreatePostWithOptions("Title", "Content", alice.ID, subreddit.ID, PostOptions{Flair: flair}); !errors.Is(err, ErrInvalidFlair) {
+            t.Errorf("flair %q = %v, want ErrInvalidFlair", flair, err)
+        }
+    }
+    plain := mustPost(t, e, alice.ID, subreddit.ID)
+
+    posts, err := e.GetFeedWithOptions(context.Background(), alice.ID, FeedOptions{Flair: "News"})
+    if err != nil {
+        t.Fatalf("GetFeedWithOptions: %v", err)
+    }
+    if len(posts) != 1 || posts[0].ID != news.ID {
+        t.Errorf("News feed = %v, want only %s", feedIDs(posts), news.ID)
+    }
+    if all, _ := e.GetFeedWithOptions(context.Background(), alice.ID, FeedOptions{}); !containsPost(all, plain.ID) || len(all) != 2 {
+        t.Errorf("unfiltered feed = %v, want both posts", feedIDs(all))
+    }
+}
\ No newline at end of file
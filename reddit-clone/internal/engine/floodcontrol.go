@@ -0,0 +1,36 @@
+// internal/engine/floodcontrol.go
+package engine
+
+import (
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+)
+
+var ErrPostingTooFast = errors.New("you're posting too fast")
+
+// checkCooldown records now as the user's latest action in last, or returns
+// ErrPostingTooFast if their previous action was less than cooldown ago
+func (e *RedditEngine) checkCooldown(last *sync.Map, userID string, cooldown time.Duration) error {
+    if cooldown <= 0 {
+        return nil
+    }
+
+    for {
+        now := e.clock.Now()
+        prevI, loaded := last.LoadOrStore(userID, now)
+        if !loaded {
+            return nil
+        }
+
+        prev := prevI.(time.Time)
+        if wait := cooldown - now.Sub(prev); wait > 0 {
+            return fmt.Errorf("%w; try again in %v", ErrPostingTooFast, wait.Round(time.Millisecond))
+        }
+        // Retry if another request from the same user got in first
+        if last.CompareAndSwap(userID, prev, now) {
+            return nil
+        }
+    }
+}
\ No newline at end of file
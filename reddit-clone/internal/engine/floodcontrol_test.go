@@ -0,0 +1,47 @@
+// internal/engine/floodcontrol_test.go
+package engine
+
+import (
+    "errors"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestCooldownsFollowTheClock(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.PostCooldown = 10 * time.Minute
+    cfg.CommentCooldown = time.Minute
+    cfg.DuplicatePostWindow = 0
+    e, clock := newTestEngineWithConfig(t, cfg)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    mustJoin(t, e, bob.ID, subreddit.ID)
+
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+    clock.Advance(time.Minute)
+    _, err := e.CreatePost("Too soon", "Again", alice.ID, subreddit.ID)
+    if !errors.Is(err, ErrPostingTooFast) {
+        t.Fatalf("second post inside the cooldown: err = %v, want ErrPostingTooFast", err)
+    }
+    if !strings.Contains(err.Error(), "try again in 9m0s") {
+        t.Errorf("error %q doesn't say how long to wait", err)
+    }
+
+    // Cooldowns are per user and per kind of content
+    mustPost(t, e, bob.ID, subreddit.ID)
+    mustComment(t, e, alice.ID, post.ID, nil)
+    if _, err := e.CreateComment("Too soon", alice.ID, post.ID, nil); !errors.Is(err, ErrPostingTooFast) {
+        t.Errorf("second comment inside the cooldown: err = %v, want ErrPostingTooFast", err)
+    }
+
+    // A refused post doesn't restart the cooldown
+    clock.Advance(9*time.Minute - time.Second)
+    if _, err := e.CreatePost("Still too soon", "Again", alice.ID, subreddit.ID); !errors.Is(err, ErrPostingTooFast) {
+        t.Errorf("post a second before the cooldown ends: err = %v, want ErrPostingTooFast", err)
+    }
+    clock.Advance(time.Second)
+    mustPost(t, e, alice.ID, subreddit.ID)
+    mustComment(t, e, alice.ID, post.ID, nil)
+}
\ No newline at end of file
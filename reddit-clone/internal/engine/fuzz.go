@@ -0,0 +1,43 @@
+// internal/engine/fuzz.go
+package engine
+
+import (
+    "encoding/binary"
+    "hash/fnv"
+)
+
+// DisplayVotes returns the vote counts to show for a post or comment.
+// With vote fuzzing enabled each count is shifted by up to VoteFuzzRange,
+// derived from the target and its true counts so repeated reads agree.
+// The stored counts are never changed and remain the basis for ranking.
+// Comments on a contest-mode post show no votes at all.
+func (e *RedditEngine) DisplayVotes(targetID string, upvotes, downvotes int64) (int64, int64) {
+    if e.inContest(targetID) {
+        return 0, 0
+    }
+    fuzzRange := e.config.VoteFuzzRange
+    if !e.config.VoteFuzzing || fuzzRange <= 0 {
+        return upvotes, downvotes
+    }
+
+    h := fnv.New64a()
+    h.Write([]byte(targetID))
+    var buf [16]byte
+    binary.LittleEndian.PutUint64(buf[:8], uint64(upvotes))
+    binary.LittleEndian.PutUint64(buf[8:], uint64(downvotes))
+    h.Write(buf[:])
+    sum := h.Sum64()
+
+    span := uint64(2*fuzzRange + 1)
+    upOffset := int64(sum%span) - fuzzRange
+    downOffset := int64((sum/span)%span) - fuzzRange
+    return clampVotes(upvotes + upOffset), clampVotes(downvotes + downOffset)
+}
+
+// clampVotes keeps a fuzzed count from going negative
+func clampVotes(n int64) int64 {
+    if n < 0 {
+        return 0
+    }
+    return n
+}
\ No newline at end of file
@@ -0,0 +1,65 @@
+// internal/engine/fuzz_test.go
+package engine
+
+import (
+    "fmt"
+    "testing"
+)
+
+func TestDisplayVotesFuzzesWithinRange(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.VoteFuzzing = true
+    cfg.VoteFuzzRange = 3
+    e, _ := newTestEngineWithConfig(t, cfg)
+
+    shifted := 0
+    for i := 0; i < 50; i++ {
+        targetID := fmt.Sprintf("target%d", i)
+        up, down := e.DisplayVotes(targetID, 100, 20)
+        if up < 97 || up > 103 || down < 17 || down > 23 {
+            t.Errorf("%s shows %d/%d, want within 3 of 100/20", targetID, up, down)
+        }
+        if up != 100 || down != 20 {
+            shifted++
+        }
+        if up2, down2 := e.DisplayVotes(targetID, 100, 20); up2 != up || down2 != down {
+            t.Errorf("%s shows %d/%d then %d/%d", targetID, up, down, up2, down2)
+        }
+    }
+    if shifted == 0 {
+        t.Error("no displayed count was fuzzed")
+    }
+
+    // Small counts never show as negative
+    for i := 0; i < 50; i++ {
+        if up, down := e.DisplayVotes(fmt.Sprintf("target%d", i), 0, 1); up < 0 || down < 0 {
+            t.Errorf("fuzzed counts %d/%d are negative", up, down)
+        }
+    }
+
+    e.config.VoteFuzzing = false
+    if up, down := e.DisplayVotes("target0", 100, 20); up != 100 || down != 20 {
+        t.Errorf("with fuzzing off shows %d/%d, want 100/20", up, down)
+    }
+}
+
+func TestDisplayVotesKeepsTheRealScore(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.PostCooldown = 0
+    cfg.VoteFuzzing = true
+    cfg.VoteFuzzRange = 3
+    e, _ := newTestEngineWithConfig(t, cfg)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    mustJoin(t, e, bob.ID, subreddit.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+    mustVote(t, e, alice.ID, post.ID, VoteUp)
+    mustVote(t, e, bob.ID, post.ID, VoteUp)
+
+    up, down := post.Votes()
+    e.DisplayVotes(post.ID, up, down)
+    if up, down := post.Votes(); up != 2 || down != 0 || post.Score() != 2 {
+        t.Errorf("stored counts %d/%d score %d after display, want 2/0 score 2", up, down, post.Score())
+    }
+}
\ No newline at end of file
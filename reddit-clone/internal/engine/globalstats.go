@@ -0,0 +1,118 @@
+// internal/engine/globalstats.go
+package engine
+
+import (
+    "sync/atomic"
+
+    "reddit-clone/internal/models"
+)
+
+// globalCounters are running site-wide totals, updated as content is
+// created and deleted so GlobalStats never has to scan the stores
+type globalCounters struct {
+    users      atomic.Int64
+    subreddits atomic.Int64
+    posts      atomic.Int64
+    comments   atomic.Int64
+    votes      atomic.Int64
+    messages   atomic.Int64
+}
+
+// GlobalStats returns site-wide totals for an admin dashboard
+func (e *RedditEngine) GlobalStats() (*models.GlobalStats, error) {
+    return &models.GlobalStats{
+        TotalUsers:      e.counters.users.Load(),
+        TotalSubreddits: e.counters.subreddits.Load(),
+        TotalPosts:      e.counters.posts.Load(),
+        TotalComments:   e.counters.comments.Load(),
+        TotalVotes:      e.counters.votes.Load(),
+        TotalMessages:   e.counters.messages.Load(),
+    }, nil
+}
+
+// LiveMetrics reports current content totals and per-subreddit counts in
+// the shape the metrics collector consumes. Subreddit counts come from the
+// post index and each post's running comment count, so this costs one
+// pass over the posts rather than over the comments.
+func (e *RedditEngine) LiveMetrics() *models.Metrics {
+    m := &models.Metrics{
+        TotalUsers:     e.counters.users.Load(),
+        TotalPosts:     e.counters.posts.Load(),
+        TotalComments:  e.counters.comments.Load(),
+        TotalVotes:     e.counters.votes.Load(),
+        SubredditStats: make(map[string]*models.SubredditMetrics),
+    }
+    e.subreddits.Range(func(key, value interface{}) bool {
+        subreddit := value.(*models.SubReddit)
+        stats := &models.SubredditMetrics{
+            Name:        subreddit.Name,
+            MemberCount: atomic.LoadInt64(&subreddit.MemberCount),
+        }
+        for _, post := range e.subredditPostList(subreddit.ID) {
+            stats.PostCount++
+            stats.CommentCount += atomic.LoadInt64(&post.CommentCount)
+            upvotes, downvotes := post.Votes()
+            stats.VoteCount += upvotes + downvotes
+        }
+        m.SubredditStats[key.(string)] = stats
+        return true
+    })
+    return m
+}
+
+// rangeable is a store Stats can count, either a sync.Map or a shardedMap
+type rangeable interface {
+    Range(f func(key, value interface{}) bool)
+}
+
+// EngineStats reports the running totals together with the number of
+// entries in each store and index
+type EngineStats struct {
+    Counts   models.GlobalStats
+    MapSizes map[string]int // Keyed by store or index name
+}
+
+// Stats returns the engine's totals and map sizes for profiling. It ranges
+// over every map, so it is meant for benchmarks and debugging rather than
+// request handling.
+func (e *RedditEngine) Stats() EngineStats {
+    counts, _ := e.GlobalStats()
+    maps := map[string]rangeable{
+        "users":              &e.users,
+        "subreddits":         &e.subreddits,
+        "posts":              e.posts,
+        "comments":           &e.comments,
+        "messages":           &e.messages,
+        "votes":              &e.votes,
+        "reports":            &e.reports,
+        "webhooks":           &e.webhooks,
+        "notifications":      &e.notifications,
+        "usernames":          &e.usernames,
+        "subreddit_posts":    &e.subredditPosts,
+        "post_comments":      &e.postComments,
+        "comment_replies":    &e.commentReplies,
+        "user_subscriptions": &e.userSubscriptions,
+        "user_notifications": &e.userNotifications,
+        "notify_batches":     &e.notifyBatches,
+        "user_votes":         &e.userVotes,
+        "user_posts":         &e.userPosts,
+        "user_comments":      &e.userComments,
+        "personalized_feeds": &e.personalizedFeeds,
+        "leaderboards":       &e.leaderboards,
+        "recent_posts":       &e.recentPosts,
+        "feed_subscriptions": &e.feedSubscriptions,
+        "rate_budgets":       &e.rateBudget.users,
+        "scheduled_posts":    &e.scheduledPosts,
+    }
+
+    stats := EngineStats{Counts: *counts, MapSizes: make(map[string]int, len(maps))}
+    for name, m := range maps {
+        size := 0
+        m.Range(func(_, _ interface{}) bool {
+            size++
+            return true
+        })
+        stats.MapSizes[name] = size
+    }
+    return stats
+}
\ No newline at end of file
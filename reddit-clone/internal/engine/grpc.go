@@ -0,0 +1,101 @@
+// internal/engine/grpc.go
+package engine
+
+import (
+    "errors"
+    "net"
+    "sync"
+
+    "google.golang.org/grpc"
+)
+
+var (
+    servicesMu            sync.RWMutex
+    registerServices      func(*grpc.Server, *RedditEngine)
+    interceptorsFor       func(*RedditEngine) []grpc.UnaryServerInterceptor
+    streamInterceptorsFor func(*RedditEngine) []grpc.StreamServerInterceptor
+)
+
+// RegisterServices installs the function used by Start to register gRPC
+// services for an engine. The server package calls this from init, which
+// avoids an import cycle between the engine and its gRPC wrapper.
+func RegisterServices(fn func(*grpc.Server, *RedditEngine)) {
+    servicesMu.Lock()
+    defer servicesMu.Unlock()
+    registerServices = fn
+}
+
+// RegisterInterceptors installs the function Start uses to build the
+// unary interceptors for an engine's gRPC server; like RegisterServices,
+// the server package calls it from init
+func RegisterInterceptors(fn func(*RedditEngine) []grpc.UnaryServerInterceptor) {
+    servicesMu.Lock()
+    defer servicesMu.Unlock()
+    interceptorsFor = fn
+}
+
+// RegisterStreamInterceptors is RegisterInterceptors for streaming calls
+func RegisterStreamInterceptors(fn func(*RedditEngine) []grpc.StreamServerInterceptor) {
+    servicesMu.Lock()
+    defer servicesMu.Unlock()
+    streamInterceptorsFor = fn
+}
+
+// Start the engine gRPC server on the given address and serve until Stop is called
+func (e *RedditEngine) Start(port string) error {
+    servicesMu.RLock()
+    register := registerServices
+    interceptors := interceptorsFor
+    streamInterceptors := streamInterceptorsFor
+    servicesMu.RUnlock()
+    if register == nil {
+        return errors.New("no gRPC services registered; import reddit-clone/internal/server")
+    }
+
+    lis, err := net.Listen("tcp", port)
+    if err != nil {
+        return err
+    }
+
+    var opts []grpc.ServerOption
+    if interceptors != nil {
+        opts = append(opts, grpc.ChainUnaryInterceptor(interceptors(e)...))
+    }
+    if streamInterceptors != nil {
+        opts = append(opts, grpc.ChainStreamInterceptor(streamInterceptors(e)...))
+    }
+    grpcServer := grpc.NewServer(opts...)
+    register(grpcServer, e)
+
+    e.serverMtx.Lock()
+    if e.grpcServer != nil {
+        e.serverMtx.Unlock()
+        lis.Close()
+        return errors.New("engine server already started")
+    }
+    e.grpcServer = grpcServer
+    e.listenAddr = lis.Addr()
+    e.serverMtx.Unlock()
+
+    return grpcServer.Serve(lis)
+}
+
+// Stop gracefully stops the engine gRPC server started by Start
+func (e *RedditEngine) Stop() {
+    e.serverMtx.Lock()
+    grpcServer := e.grpcServer
+    e.grpcServer = nil
+    e.listenAddr = nil
+    e.serverMtx.Unlock()
+
+    if grpcServer != nil {
+        grpcServer.GracefulStop()
+    }
+}
+
+// Addr returns the address the engine gRPC server is listening on, or nil if not started
+func (e *RedditEngine) Addr() net.Addr {
+    e.serverMtx.Lock()
+    defer e.serverMtx.Unlock()
+    return e.listenAddr
+}
\ No newline at end of file
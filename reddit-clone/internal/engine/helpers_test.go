@@ -0,0 +1,85 @@
+// internal/engine/helpers_test.go
+package engine
+
+import (
+    "fmt"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// testStart is where every test engine's fake clock starts
+var testStart = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestEngine returns an engine on a fake clock with flood control and
+// duplicate detection off, so tests can post freely
+func newTestEngine(t testing.TB) (*RedditEngine, *FakeClock) {
+    t.Helper()
+    cfg := NewDefaultConfig()
+    cfg.PostCooldown = 0
+    cfg.CommentCooldown = 0
+    cfg.DuplicatePostWindow = 0
+    return newTestEngineWithConfig(t, cfg)
+}
+
+func newTestEngineWithConfig(t testing.TB, cfg *Config) (*RedditEngine, *FakeClock) {
+    t.Helper()
+    clock := NewFakeClock(testStart)
+    return NewRedditEngineWithConfig(cfg).WithClock(clock), clock
+}
+
+func mustRegister(t testing.TB, e *RedditEngine, username string) *models.User {
+    t.Helper()
+    user, err := e.RegisterAccount(username, "password123")
+    if err != nil {
+        t.Fatalf("RegisterAccount(%q): %v", username, err)
+    }
+    return user
+}
+
+func mustCreateSubreddit(t testing.TB, e *RedditEngine, name, creatorID string) *models.SubReddit {
+    t.Helper()
+    subreddit, err := e.CreateSubReddit(name, "Test subreddit", creatorID)
+    if err != nil {
+        t.Fatalf("CreateSubReddit(%q): %v", name, err)
+    }
+    return subreddit
+}
+
+func mustJoin(t testing.TB, e *RedditEngine, userID, subredditID string) {
+    t.Helper()
+    if _, err := e.JoinSubReddit(userID, subredditID); err != nil {
+        t.Fatalf("JoinSubReddit: %v", err)
+    }
+}
+
+// postSeq keeps mustPost titles distinct
+var postSeq int
+
+// mustPost creates a post with a title unique to the test run
+func mustPost(t testing.TB, e *RedditEngine, authorID, subredditID string) *models.Post {
+    t.Helper()
+    postSeq++
+    post, err := e.CreatePost(fmt.Sprintf("Test post %d", postSeq), "Test content", authorID, subredditID)
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    return post
+}
+
+func mustComment(t testing.TB, e *RedditEngine, authorID, postID string, parentID *string) *models.Comment {
+    t.Helper()
+    comment, err := e.CreateComment("Test comment", authorID, postID, parentID)
+    if err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+    return comment
+}
+
+func mustVote(t testing.TB, e *RedditEngine, userID, targetID string, direction int) {
+    t.Helper()
+    if _, err := e.SetVote(userID, targetID, direction); err != nil {
+        t.Fatalf("SetVote: %v", err)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,41 @@
+// internal/engine/ids.go
+package engine
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+    "fmt"
+    "sync/atomic"
+)
+
+// IDGenerator produces the IDs of new users, subreddits, posts, comments,
+// messages, reports and notifications. Implementations must be safe for
+// concurrent use and never return the same ID twice.
+type IDGenerator interface {
+    NewID() string
+}
+
+// randomIDGenerator is the default generator: 128 random bits, hex encoded
+type randomIDGenerator struct{}
+
+func (randomIDGenerator) NewID() string {
+    bytes := make([]byte, 16)
+    rand.Read(bytes)
+    return hex.EncodeToString(bytes)
+}
+
+// SequentialIDGenerator returns predictable IDs (prefix-1, prefix-2, ...)
+// so tests can assert exact IDs
+type SequentialIDGenerator struct {
+    prefix string
+    next   atomic.Int64
+}
+
+// NewSequentialIDGenerator creates a generator whose IDs start with prefix
+func NewSequentialIDGenerator(prefix string) *SequentialIDGenerator {
+    return &SequentialIDGenerator{prefix: prefix}
+}
+
+func (g *SequentialIDGenerator) NewID() string {
+    return fmt.Sprintf("%s-%d", g.prefix, g.next.Add(1))
+}
\ No newline at end of file
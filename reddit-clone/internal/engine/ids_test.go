@@ -0,0 +1,54 @@
+// internal/engine/ids_test.go
+package engine
+
+import (
+    "sync"
+    "testing"
+)
+
+func TestSequentialIDsArePredictable(t *testing.T) {
+    // Two engines fed the same calls hand out the same IDs
+    for run := 0; run < 2; run++ {
+        e := NewRedditEngineWith(NewSequentialIDGenerator("test"))
+        alice := mustRegister(t, e, "alice")
+        subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+        post := mustPost(t, e, alice.ID, subreddit.ID)
+        comment := mustComment(t, e, alice.ID, post.ID, nil)
+
+        got := []string{alice.ID, subreddit.ID, post.ID, comment.ID}
+        want := []string{"test-1", "test-2", "test-3", "test-4"}
+        for i := range want {
+            if got[i] != want[i] {
+                t.Errorf("run %d: ID %d = %q, want %q", run, i, got[i], want[i])
+            }
+        }
+    }
+}
+
+func TestSequentialIDGeneratorIsConcurrencySafe(t *testing.T) {
+    gen := NewSequentialIDGenerator("id")
+    const goroutines, perGoroutine = 8, 500
+
+    var mu sync.Mutex
+    seen := make(map[string]bool)
+    var wg sync.WaitGroup
+    for i := 0; i < goroutines; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for j := 0; j < perGoroutine; j++ {
+                id := gen.NewID()
+                mu.Lock()
+                seen[id] = true
+                mu.Unlock()
+            }
+        }()
+    }
+    wg.Wait()
+    if len(seen) != goroutines*perGoroutine {
+        t.Errorf("%d distinct IDs from %d calls", len(seen), goroutines*perGoroutine)
+    }
+    if !seen["id-1"] || !seen["id-4000"] || seen["id-4001"] {
+        t.Error("IDs don't run from id-1 to id-4000")
+    }
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+// internal/engine/join_test.go
+package engine
+
+import (
+    "errors"
+    "fmt"
+    "sync"
+    "sync/atomic"
+    "testing"
+
+    "reddit-clone/internal/models"
+)
+
+func TestConcurrentJoinsHoldTheMemberCap(t *testing.T) {
+    const maxMembers, joiners = 10, 20
+    e, _ := newTestEngine(t)
+    creator := mustRegister(t, e, "creator")
+    subreddit, err := e.CreateSubRedditWithOptions("small", "Capped", creator.ID, SubredditOptions{MaxMembers: maxMembers})
+    if err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+    before := atomic.LoadInt64(&subreddit.MemberCount)
+
+    users := make([]*models.User, joiners)
+    for i := range users {
+        users[i] = mustRegister(t, e, fmt.Sprintf("user%d", i))
+    }
+
+    var joined, full atomic.Int64
+    var wg sync.WaitGroup
+    for _, user := range users {
+        // Each user joins twice at once; only one of the two takes a slot
+        for i := 0; i < 2; i++ {
+            wg.Add(1)
+            go func(userID string) {
+                defer wg.Done()
+                ok, err := e.JoinSubReddit(userID, subreddit.ID)
+                switch {
+                case errors.Is(err, ErrSubredditFull):
+                    full.Add(1)
+                case err != nil:
+                    t.Errorf("JoinSubReddit: %v", err)
+                case ok:
+                    joined.Add(1)
+                }
+            }(user.ID)
+        }
+    }
+    wg.Wait()
+
+    if want := maxMembers - before; joined.Load() != want {
+        t.Errorf("%d joins succeeded, want %d", joined.Load(), want)
+    }
+    if full.Load() == 0 {
+        t.Error("no join was refused with ErrSubredditFull")
+    }
+    if count := atomic.LoadInt64(&subreddit.MemberCount); count != maxMembers {
+        t.Errorf("MemberCount = %d, want exactly %d", count, maxMembers)
+    }
+    members := 0
+    subreddit.Members.Range(func(_, _ interface{}) bool {
+        members++
+        return true
+    })
+    if members != maxMembers {
+        t.Errorf("%d members stored, want %d", members, maxMembers)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,144 @@
+// internal/engine/leaderboard.go
+package engine
+
+import (
+    "errors"
+    "sort"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// Leaderboard windows. Only these are ranked, so the cache holds at most
+// one ranking per subreddit and window.
+const (
+    LeaderboardDay   = "day"
+    LeaderboardWeek  = "week"
+    LeaderboardMonth = "month"
+    LeaderboardAll   = "all"
+)
+
+// leaderboardWindows maps each window to how far back its votes go; 0
+// counts every vote
+var leaderboardWindows = map[string]time.Duration{
+    LeaderboardDay:   24 * time.Hour,
+    LeaderboardWeek:  7 * 24 * time.Hour,
+    LeaderboardMonth: 30 * 24 * time.Hour,
+    LeaderboardAll:   0,
+}
+
+var ErrInvalidLeaderboardWindow = errors.New("leaderboard window must be day, week, month or all")
+
+// LeaderboardEntry is one member's karma within a subreddit
+type LeaderboardEntry struct {
+    UserID   string
+    Username string
+    Karma    int64 // Net votes cast on the member's content in the window
+}
+
+// leaderboard is a subreddit's cached ranking for one window
+type leaderboard struct {
+    entries    []LeaderboardEntry
+    computedAt time.Time
+}
+
+// GetSubredditLeaderboard ranks a subreddit's members by the karma their
+// posts and comments there earned from votes cast within window, one of
+// LeaderboardDay, LeaderboardWeek, LeaderboardMonth or LeaderboardAll, and
+// returns at most limit of them; a limit <= 0 returns every ranked member.
+// Members nobody voted on are left out. The ranking is cached per
+// subreddit and window for Config.LeaderboardTTL.
+func (e *RedditEngine) GetSubredditLeaderboard(subredditID, window string, limit int) ([]LeaderboardEntry, error) {
+    if _, ok := leaderboardWindows[window]; !ok {
+        return nil, ErrInvalidLeaderboardWindow
+    }
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return nil, err
+    }
+
+    entries := e.cachedLeaderboard(subreddit, window)
+    if limit > 0 && len(entries) > limit {
+        entries = entries[:limit]
+    }
+    return append([]LeaderboardEntry(nil), entries...), nil
+}
+
+// cachedLeaderboard returns the subreddit's ranking for the window,
+// recomputing it once the cached copy expires
+func (e *RedditEngine) cachedLeaderboard(subreddit *models.SubReddit, window string) []LeaderboardEntry {
+    key := subreddit.ID + ":" + window
+    if cachedI, ok := e.leaderboards.Load(key); ok {
+        cached := cachedI.(*leaderboard)
+        if e.clock.Now().Sub(cached.computedAt) < e.config.LeaderboardTTL {
+            return cached.entries
+        }
+    }
+
+    entries := e.rankLeaderboard(subreddit, leaderboardWindows[window])
+    e.leaderboards.Store(key, &leaderboard{entries: entries, computedAt: e.clock.Now()})
+    return entries
+}
+
+// forgetLeaderboards drops a removed subreddit's cached rankings
+func (e *RedditEngine) forgetLeaderboards(subredditID string) {
+    for window := range leaderboardWindows {
+        e.leaderboards.Delete(subredditID + ":" + window)
+    }
+}
+
+// rankLeaderboard totals the votes on each member's content cast within
+// window, or ever when window is 0, highest first
+func (e *RedditEngine) rankLeaderboard(subreddit *models.SubReddit, window time.Duration) []LeaderboardEntry {
+    // Anonymous posts and comments are left out so the ranking can't
+    // reveal who wrote them
+    authors := make(map[string]string) // map[targetID]authorID
+    for _, post := range e.subredditPostList(subreddit.ID) {
+        if !post.Anonymous {
+            authors[post.ID] = post.AuthorID
+        }
+        for _, comment := range e.postCommentList(post.ID) {
+            if !comment.Anonymous {
+                authors[comment.ID] = comment.AuthorID
+            }
+        }
+    }
+
+    var since time.Time
+    if window > 0 {
+        since = e.clock.Now().Add(-window)
+    }
+    karma := make(map[string]int64)
+    e.votes.Range(func(_, value interface{}) bool {
+        vote := value.(*models.Vote)
+        authorID, ok := authors[vote.TargetID]
+        if !ok || vote.CreatedAt.Before(since) {
+            return true
+        }
+        if _, member := subreddit.Members.Load(authorID); !member {
+            return true
+        }
+        if vote.IsUpvote {
+            karma[authorID]++
+        } else {
+            karma[authorID]--
+        }
+        return true
+    })
+
+    entries := make([]LeaderboardEntry, 0, len(karma))
+    for userID, points := range karma {
+        entry := LeaderboardEntry{UserID: userID, Karma: points}
+        if user, err := e.GetUser(userID); err == nil {
+            entry.Username = user.Username
+        }
+        entries = append(entries, entry)
+    }
+    sort.Slice(entries, func(i, j int) bool {
+        if entries[i].Karma != entries[j].Karma {
+            return entries[i].Karma > entries[j].Karma
+        }
+        return entries[i].UserID < entries[j].UserID
+    })
+    return entries
+}
\ No newline at end of file
@@ -0,0 +1,87 @@
+// internal/engine/leaderboard_test.go
+package engine
+
+import (
+    "errors"
+    "testing"
+    "time"
+)
+
+func TestLeaderboardRejectsUnknownWindows(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    sub := mustCreateSubreddit(t, e, "golang", alice.ID)
+
+    for _, window := range []string{"", "1h", "24h", "1ns", "DAY"} {
+        if _, err := e.GetSubredditLeaderboard(sub.ID, window, 0); !errors.Is(err, ErrInvalidLeaderboardWindow) {
+            t.Errorf("window %q: err = %v, want ErrInvalidLeaderboardWindow", window, err)
+        }
+    }
+    if n := leaderboardCacheSize(e); n != 0 {
+        t.Errorf("rejected windows cached %d rankings, want 0", n)
+    }
+}
+
+func TestLeaderboardWindowsCountRecentVotes(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+    sub := mustCreateSubreddit(t, e, "golang", alice.ID)
+    mustJoin(t, e, bob.ID, sub.ID)
+    mustJoin(t, e, carol.ID, sub.ID)
+
+    post := mustPost(t, e, alice.ID, sub.ID)
+    mustVote(t, e, bob.ID, post.ID, 1)
+    clock.Advance(48 * time.Hour)
+    mustVote(t, e, carol.ID, post.ID, 1)
+
+    want := map[string]int64{
+        LeaderboardDay:   1,
+        LeaderboardWeek:  2,
+        LeaderboardMonth: 2,
+        LeaderboardAll:   2,
+    }
+    for window, karma := range want {
+        entries, err := e.GetSubredditLeaderboard(sub.ID, window, 0)
+        if err != nil {
+            t.Fatalf("GetSubredditLeaderboard(%q): %v", window, err)
+        }
+        if len(entries) != 1 || entries[0].UserID != alice.ID || entries[0].Karma != karma {
+            t.Errorf("window %q: entries = %+v, want alice with %d karma", window, entries, karma)
+        }
+    }
+}
+
+func TestLeaderboardCacheIsBounded(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    sub := mustCreateSubreddit(t, e, "golang", alice.ID)
+
+    for i := 0; i < 3; i++ {
+        for window := range leaderboardWindows {
+            if _, err := e.GetSubredditLeaderboard(sub.ID, window, 0); err != nil {
+                t.Fatalf("GetSubredditLeaderboard(%q): %v", window, err)
+            }
+        }
+    }
+    if n := leaderboardCacheSize(e); n != len(leaderboardWindows) {
+        t.Errorf("cached %d rankings, want one per window (%d)", n, len(leaderboardWindows))
+    }
+
+    if err := e.DeleteSubReddit(alice.ID, sub.ID); err != nil {
+        t.Fatalf("DeleteSubReddit: %v", err)
+    }
+    if n := leaderboardCacheSize(e); n != 0 {
+        t.Errorf("deleted subreddit left %d cached rankings, want 0", n)
+    }
+}
+
+func leaderboardCacheSize(e *RedditEngine) int {
+    n := 0
+    e.leaderboards.Range(func(_, _ interface{}) bool {
+        n++
+        return true
+    })
+    return n
+}
\ No newline at end of file
@@ -0,0 +1,33 @@
+// internal/engine/leaveall.go
+package engine
+
+import "reddit-clone/internal/models"
+
+// LeaveAllSubreddits removes the user from every subreddit they belong to.
+// Subreddits the user created are kept, since there is no way to hand them
+// to someone else and leaving would orphan them; the creator can delete
+// them with DeleteSubReddit instead.
+func (e *RedditEngine) LeaveAllSubreddits(userID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    if _, exists := e.users.Load(userID); !exists {
+        return ErrUserNotFound
+    }
+
+    for _, subredditID := range e.userSubredditIDs(userID) {
+        subredditI, ok := e.subreddits.Load(subredditID)
+        if !ok {
+            continue
+        }
+        subreddit := subredditI.(*models.SubReddit)
+        if subreddit.CreatorID == userID {
+            continue
+        }
+        e.unsubscribe(userID, subreddit)
+    }
+    return nil
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+// internal/engine/leaveall_test.go
+package engine
+
+import (
+    "sync/atomic"
+    "testing"
+)
+
+func TestLeaveAllSubreddits(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    owned := mustCreateSubreddit(t, e, "alices", alice.ID)
+    golang := mustCreateSubreddit(t, e, "golang", bob.ID)
+    rust := mustCreateSubreddit(t, e, "rust", bob.ID)
+    untouched := mustCreateSubreddit(t, e, "zig", bob.ID)
+    mustJoin(t, e, alice.ID, golang.ID)
+    mustJoin(t, e, alice.ID, rust.ID)
+    mustJoin(t, e, bob.ID, owned.ID)
+
+    if err := e.LeaveAllSubreddits(alice.ID); err != nil {
+        t.Fatalf("LeaveAllSubreddits: %v", err)
+    }
+
+    // Alice keeps only the subreddit she created
+    subreddits, err := e.GetUserSubreddits(alice.ID)
+    if err != nil {
+        t.Fatalf("GetUserSubreddits: %v", err)
+    }
+    if len(subreddits) != 1 || subreddits[0].ID != owned.ID {
+        t.Errorf("alice's subreddits = %v, want only %s", subreddits, owned.ID)
+    }
+    for _, sub := range []struct {
+        name  string
+        count *int64
+        want  int64
+    }{
+        {"alices", &owned.MemberCount, 2},
+        {"golang", &golang.MemberCount, 1},
+        {"rust", &rust.MemberCount, 1},
+        {"zig", &untouched.MemberCount, 1},
+    } {
+        if got := atomic.LoadInt64(sub.count); got != sub.want {
+            t.Errorf("%s has %d members, want %d", sub.name, got, sub.want)
+        }
+    }
+    for _, subreddit := range []string{golang.ID, rust.ID} {
+        sub, _ := e.GetSubReddit(subreddit)
+        if _, member := sub.Members.Load(alice.ID); member {
+            t.Errorf("alice is still a member of %s", sub.Name)
+        }
+    }
+
+    // Bob's memberships are his own
+    if subreddits, _ := e.GetUserSubreddits(bob.ID); len(subreddits) != 4 {
+        t.Errorf("bob is in %d subreddits, want 4", len(subreddits))
+    }
+
+    // Leaving again is a no-op
+    if err := e.LeaveAllSubreddits(alice.ID); err != nil {
+        t.Errorf("second LeaveAllSubreddits: %v", err)
+    }
+    if err := e.LeaveAllSubreddits("missing"); err != ErrUserNotFound {
+        t.Errorf("LeaveAllSubreddits for an unknown user = %v, want ErrUserNotFound", err)
+    }
+}
\ No newline at end of file
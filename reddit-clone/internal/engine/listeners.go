@@ -0,0 +1,91 @@
+// internal/engine/listeners.go
+package engine
+
+import (
+    "log"
+    "runtime/debug"
+
+    "reddit-clone/internal/models"
+)
+
+// listenerQueueSize is how many events may wait for delivery before further
+// events are dropped rather than slowing down the engine
+const listenerQueueSize = 1024
+
+// EngineListener observes engine mutations, for integrations such as search
+// indexing or webhooks. Callbacks run on a single background goroutine, one
+// event at a time, and receive copies taken when the mutation happened.
+type EngineListener interface {
+    OnUserRegistered(user models.User)
+    OnPostCreated(post models.Post)
+    OnComment(comment models.Comment)
+    OnVote(vote models.Vote)
+}
+
+// NopListener implements EngineListener with no-op callbacks, for embedding
+// in listeners that only care about some events
+type NopListener struct{}
+
+func (NopListener) OnUserRegistered(models.User) {}
+func (NopListener) OnPostCreated(models.Post)    {}
+func (NopListener) OnComment(models.Comment)     {}
+func (NopListener) OnVote(models.Vote)           {}
+
+// listenerEvent invokes one callback on a listener
+type listenerEvent struct {
+    name   string
+    invoke func(EngineListener)
+}
+
+// AddListener registers a listener for every later mutation. Events are
+// delivered in the order they happened, and each event reaches listeners in
+// the order they were added. A panicking callback is logged and skipped.
+func (e *RedditEngine) AddListener(l EngineListener) {
+    e.listenerMtx.Lock()
+    e.listeners = append(e.listeners, l)
+    e.listenerMtx.Unlock()
+
+    e.listenerOnce.Do(func() {
+        e.listenerEvents = make(chan listenerEvent, listenerQueueSize)
+        go e.dispatchEvents()
+    })
+}
+
+// emit queues an event for the registered listeners, if there are any
+func (e *RedditEngine) emit(name string, invoke func(EngineListener)) {
+    e.listenerMtx.RLock()
+    defer e.listenerMtx.RUnlock()
+    if len(e.listeners) == 0 {
+        return
+    }
+
+    // Queue under the read lock so events keep the order they were emitted in
+    select {
+    case e.listenerEvents <- listenerEvent{name: name, invoke: invoke}:
+    default:
+        log.Printf("engine listener queue full, dropping %s event", name)
+    }
+}
+
+// dispatchEvents delivers queued events to listeners until the engine is discarded
+func (e *RedditEngine) dispatchEvents() {
+    for event := range e.listenerEvents {
+        e.listenerMtx.RLock()
+        listeners := e.listeners
+        e.listenerMtx.RUnlock()
+
+        for _, l := range listeners {
+            invokeListener(l, event)
+        }
+    }
+}
+
+// invokeListener runs one callback, recovering from a panic in it
+func invokeListener(l EngineListener, event listenerEvent) {
+    defer func() {
+        if rec := recover(); rec != nil {
+            log.Printf("engine listener panicked handling %s: %v\n%s", event.name, rec, debug.Stack())
+        }
+    }()
+    event.invoke(l)
+}
\ No newline at end of file
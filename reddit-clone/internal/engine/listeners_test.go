@@ -0,0 +1,100 @@
+// internal/engine/listeners_test.go
+package engine
+
+import (
+    "fmt"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// recordingListener describes each callback it receives on a channel
+type recordingListener struct {
+    events chan string
+}
+
+func newRecordingListener() *recordingListener {
+    return &recordingListener{events: make(chan string, 64)}
+}
+
+func (l *recordingListener) OnUserRegistered(user models.User) {
+    l.events <- "user " + user.Username
+}
+
+func (l *recordingListener) OnPostCreated(post models.Post) {
+    l.events <- fmt.Sprintf("post %s by %s", post.Title, post.AuthorID)
+}
+
+func (l *recordingListener) OnComment(comment models.Comment) {
+    l.events <- fmt.Sprintf("comment on %s by %s", comment.PostID, comment.AuthorID)
+}
+
+func (l *recordingListener) OnVote(vote models.Vote) {
+    l.events <- fmt.Sprintf("vote %s on %s up=%t", vote.UserID, vote.TargetID, vote.IsUpvote)
+}
+
+// next waits for the listener's next event
+func (l *recordingListener) next(t *testing.T) string {
+    t.Helper()
+    select {
+    case event := <-l.events:
+        return event
+    case <-time.After(5 * time.Second):
+        t.Fatal("timed out waiting for a listener event")
+        return ""
+    }
+}
+
+// panickingListener panics in every callback
+type panickingListener struct{ NopListener }
+
+func (panickingListener) OnPostCreated(models.Post) { panic("listener bug") }
+
+func TestListenersReceiveEventsInOrder(t *testing.T) {
+    e, _ := newTestEngine(t)
+    // Events from before a listener is added aren't replayed
+    alice := mustRegister(t, e, "alice")
+
+    first, second := newRecordingListener(), newRecordingListener()
+    e.AddListener(panickingListener{})
+    e.AddListener(first)
+    e.AddListener(second)
+
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    mustJoin(t, e, bob.ID, subreddit.ID)
+    post, err := e.CreatePost("Hello", "Test content", alice.ID, subreddit.ID)
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    comment := mustComment(t, e, bob.ID, post.ID, nil)
+    mustVote(t, e, bob.ID, post.ID, VoteUp)
+    mustVote(t, e, bob.ID, post.ID, VoteDown)
+    // Retracting a vote isn't an event
+    mustVote(t, e, bob.ID, post.ID, VoteNone)
+    mustVote(t, e, alice.ID, comment.ID, VoteUp)
+
+    want := []string{
+        "user bob",
+        fmt.Sprintf("post Hello by %s", alice.ID),
+        fmt.Sprintf("comment on %s by %s", post.ID, bob.ID),
+        fmt.Sprintf("vote %s on %s up=true", bob.ID, post.ID),
+        fmt.Sprintf("vote %s on %s up=false", bob.ID, post.ID),
+        fmt.Sprintf("vote %s on %s up=true", alice.ID, comment.ID),
+    }
+    // The panicking listener runs first for every event; the others still
+    // see each one, in order
+    for _, l := range []*recordingListener{first, second} {
+        for i, w := range want {
+            if got := l.next(t); got != w {
+                t.Fatalf("event %d = %q, want %q", i, got, w)
+            }
+        }
+    }
+    select {
+    case extra := <-first.events:
+        t.Errorf("unexpected extra event %q", extra)
+    case <-time.After(50 * time.Millisecond):
+    }
+}
\ No newline at end of file
@@ -0,0 +1,47 @@
+// internal/engine/lock.go
+package engine
+
+import (
+    "errors"
+
+    "reddit-clone/internal/models"
+)
+
+// ErrPostLocked is returned for new comments by non-moderators and for
+// comment edits on a locked post
+var ErrPostLocked = errors.New("post is locked")
+
+// ToggleLockPost flips whether a post is locked; only moderators of its
+// subreddit may toggle it. While a post is locked only moderators may
+// comment on it and nobody may edit its comments.
+func (e *RedditEngine) ToggleLockPost(userID, postID string) (*models.Post, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    post, err := e.GetPost(postID)
+    if err != nil {
+        return nil, err
+    }
+    subreddit, err := e.GetSubReddit(post.SubRedditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(userID, subreddit) {
+        return nil, ErrNotModerator
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    post.Locked = !post.Locked
+    return post, nil
+}
+
+// isLocked reports whether the post is locked
+func (e *RedditEngine) isLocked(post *models.Post) bool {
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    return post.Locked
+}
\ No newline at end of file
@@ -0,0 +1,135 @@
+// internal/engine/merge.go
+package engine
+
+import (
+    "errors"
+    "sync"
+    "sync/atomic"
+
+    "reddit-clone/internal/models"
+)
+
+// MergeSubreddits folds the source subreddit into the target and deletes
+// the source. It is an operator action, like BroadcastAnnouncement: moderating
+// both subreddits isn't enough, so callers must already have authenticated
+// an operator, and adminID names the operator's account, which must exist
+// and not be banned. Source posts, including
+// queued and scheduled ones, and their reports move to the target. A post
+// keeps its slug unless the target already uses it, in which case it gets
+// a new one. Source members join the target even past its member cap, the way
+// lowering MaxMembers keeps existing members. Authors who left the source
+// are not joined, so their posts move without them. The target keeps its
+// own moderators, flairs and settings, and the source's webhooks are
+// dropped.
+//
+// Moved posts and reports are replaced by copies naming the target rather
+// than changed in place, because readers use their SubRedditID without a
+// lock. The merge runs alone, so no concurrent vote or comment updates a
+// post after it has been copied.
+func (e *RedditEngine) MergeSubreddits(adminID, sourceID, targetID string) error {
+    done, err := e.beginExclusiveWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    if _, exists := e.users.Load(adminID); !exists {
+        return ErrUserNotFound
+    }
+    if err := e.checkNotBanned(adminID); err != nil {
+        return err
+    }
+    if sourceID == targetID {
+        return errors.New("cannot merge a subreddit into itself")
+    }
+    source, err := e.GetSubReddit(sourceID)
+    if err != nil {
+        return err
+    }
+    target, err := e.GetSubReddit(targetID)
+    if err != nil {
+        return err
+    }
+    if err := checkNotArchived(target); err != nil {
+        return err
+    }
+
+    // Only one concurrent merge or delete of the source gets past here
+    if _, ok := e.subreddits.LoadAndDelete(sourceID); !ok {
+        return ErrSubredditNotFound
+    }
+    e.counters.subreddits.Add(-1)
+    e.unindexSubredditName(source)
+    e.releaseSubredditSlot(source.CreatorID)
+
+    source.Members.Range(func(key, _ interface{}) bool {
+        userID := key.(string)
+        e.unsubscribe(userID, source)
+        if _, loaded := target.Members.LoadOrStore(userID, true); !loaded {
+            atomic.AddInt64(&target.MemberCount, 1)
+        }
+        subsI, _ := e.userSubscriptions.LoadOrStore(userID, &sync.Map{})
+        subsI.(*sync.Map).Store(targetID, true)
+        return true
+    })
+
+    posts := e.subredditPostList(sourceID)
+    pending := e.pendingPostList(sourceID)
+    scheduled := e.scheduledPostList(func(post *models.Post) bool {
+        return post.SubRedditID == sourceID
+    })
+    for _, post := range posts {
+        e.indexPost(e.movePost(post, targetID))
+    }
+    for _, post := range pending {
+        e.indexPendingPost(e.movePost(post, targetID))
+    }
+    for _, post := range scheduled {
+        e.scheduledPosts.Store(post.ID, e.movePost(post, targetID))
+    }
+    e.reports.Range(func(key, value interface{}) bool {
+        if report := value.(*models.Report); report.SubRedditID == sourceID {
+            moved := *report
+            moved.SubRedditID = targetID
+            e.reports.Store(key, &moved)
+        }
+        return true
+    })
+    atomic.AddInt64(&target.PostCount, atomic.LoadInt64(&source.PostCount))
+
+    for _, webhook := range e.GetWebhooks(sourceID) {
+        e.webhooks.Delete(webhook.ID)
+    }
+    e.subredditPosts.Delete(sourceID)
+    e.pendingPosts.Delete(sourceID)
+    e.subredditSlugs.Delete(sourceID)
+    e.subredditWebhooks.Delete(sourceID)
+    e.recentPosts.Delete(sourceID)
+    e.forgetLeaderboards(sourceID)
+    e.forgetLeaderboards(targetID)
+    return nil
+}
+
+// movePost stores a copy of the post in the target subreddit, with its
+// slug registered there, and returns the copy
+func (e *RedditEngine) movePost(post *models.Post, targetID string) *models.Post {
+    moved := *post
+    moved.SubRedditID = targetID
+    e.moveSlug(&moved)
+    e.posts.Store(moved.ID, &moved)
+    return &moved
+}
+
+// moveSlug registers a post's slug in its new subreddit, assigning a fresh
+// one when the old slug is taken there
+func (e *RedditEngine) moveSlug(post *models.Post) {
+    idx := e.subredditSlugIndex(post.SubRedditID)
+    idx.mtx.Lock()
+    if _, taken := idx.slugs[post.Slug]; post.Slug != "" && !taken {
+        idx.slugs[post.Slug] = post.ID
+        idx.mtx.Unlock()
+        return
+    }
+    idx.mtx.Unlock()
+    e.assignSlug(post)
+}
\ No newline at end of file
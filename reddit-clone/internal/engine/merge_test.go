@@ -0,0 +1,100 @@
+// internal/engine/merge_test.go
+package engine
+
+import (
+    "errors"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+func TestMergeSubreddits(t *testing.T) {
+    e, clock := newTestEngine(t)
+    operator := mustRegister(t, e, "operator")
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    source := mustCreateSubreddit(t, e, "golang_old", alice.ID)
+    target := mustCreateSubreddit(t, e, "golang", bob.ID)
+    mustJoin(t, e, bob.ID, source.ID)
+
+    moved := mustPost(t, e, alice.ID, source.ID)
+    mustComment(t, e, bob.ID, moved.ID, nil)
+    staying := mustPost(t, e, bob.ID, target.ID)
+    later := clock.Now().Add(time.Hour)
+    scheduled, err := e.CreatePostWithOptions("Later", "Scheduled", alice.ID, source.ID, PostOptions{ScheduledFor: &later})
+    if err != nil {
+        t.Fatalf("scheduled post: %v", err)
+    }
+    if err := e.Report(bob.ID, moved.ID, "spam"); err != nil {
+        t.Fatalf("Report: %v", err)
+    }
+
+    if err := e.MergeSubreddits(operator.ID, source.ID, target.ID); err != nil {
+        t.Fatalf("MergeSubreddits: %v", err)
+    }
+
+    if _, err := e.GetSubReddit(source.ID); err == nil {
+        t.Error("source subreddit still exists")
+    }
+    posts := e.subredditPostList(target.ID)
+    if len(posts) != 2 || feedIndex(posts, moved.ID) < 0 || feedIndex(posts, staying.ID) < 0 {
+        t.Fatalf("target has %d posts, want the moved and existing posts", len(posts))
+    }
+    got, err := e.GetPost(moved.ID)
+    if err != nil || got.SubRedditID != target.ID {
+        t.Fatalf("moved post: %v, subreddit %q; want %q", err, got.SubRedditID, target.ID)
+    }
+    if moved.SubRedditID != source.ID {
+        t.Error("merge rewrote the original post in place")
+    }
+    if bySlug, err := e.GetPostBySlug(target.ID, got.Slug); err != nil || bySlug.ID != moved.ID {
+        t.Errorf("moved post's slug %q doesn't resolve in the target: %v", got.Slug, err)
+    }
+    if postI, ok := e.scheduledPosts.Load(scheduled.ID); !ok || postI.(*models.Post).SubRedditID != target.ID {
+        t.Error("scheduled post didn't move to the target")
+    }
+
+    if _, isMember := target.Members.Load(alice.ID); !isMember {
+        t.Error("source member didn't join the target")
+    }
+    if members := atomic.LoadInt64(&target.MemberCount); members != 2 {
+        t.Errorf("target has %d members, want 2", members)
+    }
+    stats, err := e.GetSubredditStats(target.ID)
+    if err != nil {
+        t.Fatalf("GetSubredditStats: %v", err)
+    }
+    if stats.PostCount != 2 || stats.CommentCount != 1 {
+        t.Errorf("target stats count %d posts and %d comments, want 2 and 1", stats.PostCount, stats.CommentCount)
+    }
+
+    reports, err := e.GetReports(bob.ID, target.ID)
+    if err != nil {
+        t.Fatalf("GetReports: %v", err)
+    }
+    if len(reports) != 1 || reports[0].TargetID != moved.ID {
+        t.Errorf("target has %d reports, want the one on the moved post", len(reports))
+    }
+}
+
+func TestMergeSubredditsNeedsOperatorAccount(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    source := mustCreateSubreddit(t, e, "golang_old", alice.ID)
+    target := mustCreateSubreddit(t, e, "golang", alice.ID)
+
+    if err := e.MergeSubreddits("nobody", source.ID, target.ID); !errors.Is(err, ErrUserNotFound) {
+        t.Errorf("unknown operator: err = %v, want ErrUserNotFound", err)
+    }
+    if err := e.MergeSubreddits(alice.ID, source.ID, source.ID); err == nil {
+        t.Error("merging a subreddit into itself was accepted")
+    }
+    if err := e.BanUser(alice.ID); err != nil {
+        t.Fatalf("BanUser: %v", err)
+    }
+    if err := e.MergeSubreddits(alice.ID, source.ID, target.ID); !errors.Is(err, ErrUserBanned) {
+        t.Errorf("banned operator: err = %v, want ErrUserBanned", err)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,71 @@
+// internal/engine/messageedits.go
+package engine
+
+import (
+    "errors"
+
+    "reddit-clone/internal/models"
+)
+
+var (
+    ErrNotSender            = errors.New("only the sender can change this message")
+    ErrMessageWindowExpired = errors.New("message can no longer be changed")
+)
+
+// EditMessage replaces a direct message's content. Only the sender may edit,
+// and only within Config.MessageEditWindow of sending it.
+func (e *RedditEngine) EditMessage(userID, messageID, newContent string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    msg, err := e.changeableMessage(userID, messageID)
+    if err != nil {
+        return err
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+
+    updatedAt := e.clock.Now()
+    msg.Content = newContent
+    msg.Edited = true
+    msg.UpdatedAt = &updatedAt
+    return nil
+}
+
+// DeleteMessage unsends a direct message. Only the sender may delete, and
+// only within Config.MessageEditWindow of sending it.
+func (e *RedditEngine) DeleteMessage(userID, messageID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    if _, err := e.changeableMessage(userID, messageID); err != nil {
+        return err
+    }
+    if _, loaded := e.messages.LoadAndDelete(messageID); !loaded {
+        return errors.New("message not found")
+    }
+    e.counters.messages.Add(-1)
+    return nil
+}
+
+// changeableMessage looks up a message the user may still edit or delete
+func (e *RedditEngine) changeableMessage(userID, messageID string) (*models.DirectMessage, error) {
+    msg, err := e.GetMessage(userID, messageID)
+    if err != nil {
+        return nil, err
+    }
+    if msg.FromID != userID {
+        return nil, ErrNotSender
+    }
+    if window := e.config.MessageEditWindow; window > 0 && e.clock.Now().Sub(msg.CreatedAt) > window {
+        return nil, ErrMessageWindowExpired
+    }
+    return msg, nil
+}
\ No newline at end of file
@@ -0,0 +1,81 @@
+// internal/engine/messageedits_test.go
+package engine
+
+import (
+    "testing"
+    "time"
+)
+
+func TestEditMessageWithinWindow(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    msg, err := e.SendDirectMessage(alice.ID, bob.ID, "helo")
+    if err != nil {
+        t.Fatalf("SendDirectMessage: %v", err)
+    }
+
+    if err := e.EditMessage(bob.ID, msg.ID, "hijacked"); err != ErrNotSender {
+        t.Errorf("recipient's edit = %v, want ErrNotSender", err)
+    }
+
+    clock.Advance(DefaultMessageEditWindow - time.Second)
+    if err := e.EditMessage(alice.ID, msg.ID, "hello"); err != nil {
+        t.Fatalf("EditMessage within the window: %v", err)
+    }
+    got, err := e.GetMessage(bob.ID, msg.ID)
+    if err != nil {
+        t.Fatalf("GetMessage: %v", err)
+    }
+    if got.Content != "hello" || !got.Edited || got.UpdatedAt == nil || !got.UpdatedAt.Equal(clock.Now()) {
+        t.Errorf("edited message = %q, edited %v, updated %v", got.Content, got.Edited, got.UpdatedAt)
+    }
+
+    clock.Advance(2 * time.Second)
+    if err := e.EditMessage(alice.ID, msg.ID, "too late"); err != ErrMessageWindowExpired {
+        t.Errorf("edit past the window = %v, want ErrMessageWindowExpired", err)
+    }
+    if got, _ := e.GetMessage(bob.ID, msg.ID); got.Content != "hello" {
+        t.Errorf("content after a refused edit = %q, want hello", got.Content)
+    }
+}
+
+func TestDeleteMessageWithinWindow(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    recent, _ := e.SendDirectMessage(alice.ID, bob.ID, "oops")
+    old, _ := e.SendDirectMessage(alice.ID, bob.ID, "keep")
+
+    if err := e.DeleteMessage(bob.ID, recent.ID); err != ErrNotSender {
+        t.Errorf("recipient's delete = %v, want ErrNotSender", err)
+    }
+    if err := e.DeleteMessage(alice.ID, recent.ID); err != nil {
+        t.Fatalf("DeleteMessage within the window: %v", err)
+    }
+    if _, err := e.GetMessage(bob.ID, recent.ID); err == nil {
+        t.Error("deleted message still readable")
+    }
+    if n := e.Stats().MapSizes["messages"]; n != 1 {
+        t.Errorf("%d messages stored, want 1", n)
+    }
+
+    clock.Advance(DefaultMessageEditWindow + time.Second)
+    if err := e.DeleteMessage(alice.ID, old.ID); err != ErrMessageWindowExpired {
+        t.Errorf("delete past the window = %v, want ErrMessageWindowExpired", err)
+    }
+}
+
+func TestMessageEditWindowDisabled(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.MessageEditWindow = 0
+    e, clock := newTestEngineWithConfig(t, cfg)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    msg, _ := e.SendDirectMessage(alice.ID, bob.ID, "hello")
+
+    clock.Advance(30 * 24 * time.Hour)
+    if err := e.EditMessage(alice.ID, msg.ID, "still editable"); err != nil {
+        t.Errorf("EditMessage with no window: %v", err)
+    }
+}
\ No newline at end of file
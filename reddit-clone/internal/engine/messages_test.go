@@ -0,0 +1,164 @@
+// internal/engine/messages_test.go
+package engine
+
+import (
+    "fmt"
+    "testing"
+    "time"
+)
+
+func TestExpiredMessagesAreHiddenThenSwept(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+
+    fleeting, err := e.SendDirectMessageWithTTL(alice.ID, bob.ID, "gone soon", time.Hour)
+    if err != nil {
+        t.Fatalf("SendDirectMessageWithTTL: %v", err)
+    }
+    lasting, err := e.SendDirectMessage(alice.ID, bob.ID, "here to stay")
+    if err != nil {
+        t.Fatalf("SendDirectMessage: %v", err)
+    }
+
+    clock.Advance(time.Hour - time.Minute)
+    e.sweepExpiredMessages()
+    if _, err := e.GetMessage(bob.ID, fleeting.ID); err != nil {
+        t.Fatalf("message before its TTL: %v", err)
+    }
+
+    // At the expiry instant the message is hidden, though still stored
+    clock.Advance(time.Minute)
+    if _, err := e.GetMessage(bob.ID, fleeting.ID); err == nil {
+        t.Error("expired message still readable")
+    }
+    page, err := e.GetUserMessages(bob.ID, 1, 0, false)
+    if err != nil {
+        t.Fatalf("GetUserMessages: %v", err)
+    }
+    if page.Total != 1 || page.Messages[0].ID != lasting.ID {
+        t.Errorf("inbox holds %d messages, want only the lasting one", page.Total)
+    }
+    if n := e.Stats().MapSizes["messages"]; n != 2 {
+        t.Errorf("%d messages stored before the sweep, want 2", n)
+    }
+
+    e.sweepExpiredMessages()
+    if n := e.Stats().MapSizes["messages"]; n != 1 {
+        t.Errorf("%d messages stored after the sweep, want 1", n)
+    }
+    if n := e.Stats().Counts.TotalMessages; n != 1 {
+        t.Errorf("TotalMessages = %d, want 1", n)
+    }
+    if _, err := e.GetMessage(bob.ID, lasting.ID); err != nil {
+        t.Errorf("message without a TTL: %v", err)
+    }
+}
+
+func TestGetUserMessagesOrderAndPages(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+
+    // Bob receives m0..m4 a minute apart, reads m1 and m3, then replies
+    for i := 0; i < 5; i++ {
+        msg, err := e.SendDirectMessage(alice.ID, bob.ID, fmt.Sprintf("m%d", i))
+        if err != nil {
+            t.Fatalf("SendDirectMessage: %v", err)
+        }
+        if i == 1 || i == 3 {
+            if err := e.MarkMessageRead(bob.ID, msg.ID); err != nil {
+                t.Fatalf("MarkMessageRead: %v", err)
+            }
+        }
+        clock.Advance(time.Minute)
+    }
+    if _, err := e.SendDirectMessage(bob.ID, alice.ID, "reply"); err != nil {
+        t.Fatalf("SendDirectMessage: %v", err)
+    }
+    // Messages between others aren't bob's
+    if _, err := e.SendDirectMessage(alice.ID, carol.ID, "private"); err != nil {
+        t.Fatalf("SendDirectMessage: %v", err)
+    }
+
+    messageContents := func(page *MessagePage) string {
+        out := make([]string, len(page.Messages))
+        for i, msg := range page.Messages {
+            out[i] = msg.Content
+        }
+        return fmt.Sprint(out)
+    }
+    tests := []struct {
+        name        string
+        page, limit int
+        unreadFirst bool
+        want        string
+    }{
+        {"newest first", 1, 0, false, "[reply m4 m3 m2 m1 m0]"},
+        {"unread first", 1, 0, true, "[m4 m2 m0 reply m3 m1]"},
+        {"first page", 1, 4, false, "[reply m4 m3 m2]"},
+        {"second page", 2, 4, false, "[m1 m0]"},
+        {"unread second page", 2, 4, true, "[m3 m1]"},
+        {"past the end", 3, 4, false, "[]"},
+    }
+    for _, tt := range tests {
+        page, err := e.GetUserMessages(bob.ID, tt.page, tt.limit, tt.unreadFirst)
+        if err != nil {
+            t.Fatalf("%s: GetUserMessages: %v", tt.name, err)
+        }
+        if got := messageContents(page); got != tt.want {
+            t.Errorf("%s: messages = %s, want %s", tt.name, got, tt.want)
+        }
+        // The reply bob sent is never unread for him
+        if page.Total != 6 || page.Unread != 3 {
+            t.Errorf("%s: total %d, unread %d; want 6, 3", tt.name, page.Total, page.Unread)
+        }
+    }
+
+    if _, err := e.GetUserMessages(bob.ID, -1, 4, false); err == nil {
+        t.Error("GetUserMessages accepted a negative page")
+    }
+}
+
+func TestMarkAllMessagesReadOnlyTouchesReceived(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+
+    var received []string
+    for i := 0; i < 3; i++ {
+        msg, _ := e.SendDirectMessage(alice.ID, bob.ID, fmt.Sprintf("to bob %d", i))
+        received = append(received, msg.ID)
+    }
+    // Already read, so not counted again
+    if err := e.MarkMessageRead(bob.ID, received[0]); err != nil {
+        t.Fatalf("MarkMessageRead: %v", err)
+    }
+    sent, _ := e.SendDirectMessage(bob.ID, alice.ID, "from bob")
+    others, _ := e.SendDirectMessage(alice.ID, carol.ID, "to carol")
+
+    n, err := e.MarkAllMessagesRead(bob.ID)
+    if err != nil {
+        t.Fatalf("MarkAllMessagesRead: %v", err)
+    }
+    if n != 2 {
+        t.Errorf("marked %d messages read, want 2", n)
+    }
+    for _, id := range received {
+        if msg, _ := e.GetMessage(bob.ID, id); !msg.IsRead {
+            t.Errorf("received message %s is still unread", id)
+        }
+    }
+    if msg, _ := e.GetMessage(alice.ID, sent.ID); msg.IsRead {
+        t.Error("bob's sent message was marked read for alice")
+    }
+    if msg, _ := e.GetMessage(carol.ID, others.ID); msg.IsRead {
+        t.Error("carol's message was marked read")
+    }
+
+    if n, err := e.MarkAllMessagesRead(bob.ID); err != nil || n != 0 {
+        t.Errorf("second MarkAllMessagesRead = %d, %v; want 0", n, err)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,168 @@
+// internal/engine/notifications.go
+package engine
+
+import (
+    "errors"
+    "fmt"
+    "regexp"
+    "sort"
+    "sync"
+
+    "reddit-clone/internal/models"
+)
+
+// mentionPattern matches @username mentions in post and comment bodies
+var mentionPattern = regexp.MustCompile(`@([A-Za-z0-9_-]+)`)
+
+// notificationNouns names each notification type in batch summaries
+var notificationNouns = map[string][2]string{
+    models.NotificationReply:   {"reply", "replies"},
+    models.NotificationMention: {"mention", "mentions"},
+}
+
+// notify stores a notification and adds it to the recipient's inbox. With
+// batching on, it is folded into the recipient's open batch of the same
+// type instead, if there is one. A batch stays open for
+// NotificationBatchWindow from its first notification, and each one
+// folded in moves its UpdatedAt forward.
+func (e *RedditEngine) notify(notification *models.Notification) {
+    now := e.clock.Now()
+    window := e.config.NotificationBatchWindow
+    batchKey := notification.UserID + ":" + notification.Type
+
+    e.notificationMtx.Lock()
+    if window > 0 {
+        if batchI, ok := e.notifyBatches.Load(batchKey); ok {
+            batch := batchI.(*models.Notification)
+            if !batch.IsRead && now.Sub(batch.CreatedAt) < window {
+                batch.Count++
+                batch.UpdatedAt = now
+                batch.ActorID = notification.ActorID
+                batch.PostID = notification.PostID
+                batch.CommentID = notification.CommentID
+                batch.Summary = fmt.Sprintf("%d new %s", batch.Count, notificationNouns[batch.Type][1])
+                e.notificationMtx.Unlock()
+                return
+            }
+        }
+    }
+    notification.ID = e.generateID()
+    notification.CreatedAt = now
+    notification.UpdatedAt = now
+    notification.Count = 1
+    if window > 0 {
+        e.notifyBatches.Store(batchKey, notification)
+    }
+    e.notificationMtx.Unlock()
+
+    e.notifications.Store(notification.ID, notification)
+    inboxI, _ := e.userNotifications.LoadOrStore(notification.UserID, &sync.Map{})
+    inboxI.(*sync.Map).Store(notification.ID, true)
+}
+
+// notifyReply tells the parent comment's author about a reply, unless they replied to themselves
+func (e *RedditEngine) notifyReply(comment *models.Comment) string {
+    if comment.ParentID == nil {
+        return ""
+    }
+    parentI, ok := e.comments.Load(*comment.ParentID)
+    if !ok {
+        return ""
+    }
+    parent := parentI.(*models.Comment)
+    if parent.AuthorID == comment.AuthorID {
+        return ""
+    }
+
+    e.notify(&models.Notification{
+        UserID:    parent.AuthorID,
+        Type:      models.NotificationReply,
+        ActorID:   actorID(comment.AuthorID, comment.Anonymous),
+        PostID:    comment.PostID,
+        CommentID: comment.ID,
+    })
+    return parent.AuthorID
+}
+
+// notifyMentions notifies each user mentioned in content once, naming
+// actorID as the mentioner. The author and any user in skip (e.g. already
+// notified of a reply) are left out.
+func (e *RedditEngine) notifyMentions(content, authorID, actorID, postID, commentID string, skip ...string) {
+    notified := make(map[string]bool)
+    notified[authorID] = true
+    for _, userID := range skip {
+        notified[userID] = true
+    }
+
+    for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+        userIDI, ok := e.usernames.Load(match[1])
+        if !ok {
+            continue
+        }
+        userID := userIDI.(string)
+        if notified[userID] {
+            continue
+        }
+        notified[userID] = true
+
+        e.notify(&models.Notification{
+            UserID:    userID,
+            Type:      models.NotificationMention,
+            ActorID:   actorID,
+            PostID:    postID,
+            CommentID: commentID,
+        })
+    }
+}
+
+// GetNotifications returns a user's notifications, most recently updated
+// first, so a batch that just grew comes before older notifications
+func (e *RedditEngine) GetNotifications(userID string) ([]*models.Notification, error) {
+    if _, exists := e.users.Load(userID); !exists {
+        return nil, errors.New("user not found")
+    }
+
+    var notifications []*models.Notification
+    inboxI, ok := e.userNotifications.Load(userID)
+    if !ok {
+        return notifications, nil
+    }
+
+    e.notificationMtx.Lock()
+    inboxI.(*sync.Map).Range(func(key, _ interface{}) bool {
+        if notificationI, ok := e.notifications.Load(key); ok {
+            notification := *notificationI.(*models.Notification)
+            notifications = append(notifications, &notification)
+        }
+        return true
+    })
+    e.notificationMtx.Unlock()
+
+    sort.Slice(notifications, func(i, j int) bool {
+        return notifications[i].UpdatedAt.After(notifications[j].UpdatedAt)
+    })
+    return notifications, nil
+}
+
+// MarkNotificationRead marks one of the user's notifications as read
+func (e *RedditEngine) MarkNotificationRead(userID, notificationID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    notificationI, ok := e.notifications.Load(notificationID)
+    if !ok {
+        return errors.New("notification not found")
+    }
+    notification := notificationI.(*models.Notification)
+    if notification.UserID != userID {
+        return errors.New("notification not found")
+    }
+
+    e.notificationMtx.Lock()
+    notification.IsRead = true
+    e.notificationMtx.Unlock()
+    return nil
+}
\ No newline at end of file
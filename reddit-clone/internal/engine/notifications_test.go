@@ -0,0 +1,197 @@
+// internal/engine/notifications_test.go
+package engine
+
+import (
+    "fmt"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// notificationFixture is a user with a comment others reply to
+type notificationFixture struct {
+    e       *RedditEngine
+    clock   *FakeClock
+    alice   *models.User
+    post    *models.Post
+    comment *models.Comment
+    replies int
+}
+
+func newNotificationFixture(t *testing.T) *notificationFixture {
+    t.Helper()
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+    return &notificationFixture{e: e, clock: clock, alice: alice, post: post, comment: mustComment(t, e, alice.ID, post.ID, nil)}
+}
+
+// reply has a new user reply to alice's comment
+func (f *notificationFixture) reply(t *testing.T) {
+    t.Helper()
+    f.replies++
+    replier := mustRegister(t, f.e, fmt.Sprintf("replier%d", f.replies))
+    mustComment(t, f.e, replier.ID, f.post.ID, &f.comment.ID)
+}
+
+func (f *notificationFixture) notifications(t *testing.T) []*models.Notification {
+    t.Helper()
+    notifications, err := f.e.GetNotifications(f.alice.ID)
+    if err != nil {
+        t.Fatalf("GetNotifications: %v", err)
+    }
+    return notifications
+}
+
+func TestNotificationBurstCoalesces(t *testing.T) {
+    f := newNotificationFixture(t)
+    window := f.e.config.NotificationBatchWindow
+
+    f.reply(t)
+    f.clock.Advance(window / 3)
+    f.reply(t)
+    f.clock.Advance(window / 3)
+    f.reply(t)
+
+    notifications := f.notifications(t)
+    if len(notifications) != 1 {
+        t.Fatalf("got %d notifications for a burst, want 1", len(notifications))
+    }
+    batch := notifications[0]
+    if batch.Count != 3 || batch.Summary != "3 new replies" {
+        t.Errorf("batch Count = %d, Summary = %q; want 3, %q", batch.Count, batch.Summary, "3 new replies")
+    }
+    if !batch.CreatedAt.Equal(testStart) {
+        t.Errorf("batch CreatedAt = %v, want the first reply's %v", batch.CreatedAt, testStart)
+    }
+    if want := testStart.Add(2 * (window / 3)); !batch.UpdatedAt.Equal(want) {
+        t.Errorf("batch UpdatedAt = %v, want the last reply's %v", batch.UpdatedAt, want)
+    }
+}
+
+func TestSpacedNotificationsStaySeparate(t *testing.T) {
+    f := newNotificationFixture(t)
+    window := f.e.config.NotificationBatchWindow
+
+    // Each reply follows the last within the window, but the third comes
+    // after the window that opened with the first
+    f.reply(t)
+    f.clock.Advance(window * 2 / 3)
+    f.reply(t)
+    f.clock.Advance(window * 2 / 3)
+    f.reply(t)
+
+    notifications := f.notifications(t)
+    if len(notifications) != 2 {
+        t.Fatalf("got %d notifications, want 2", len(notifications))
+    }
+    if notifications[0].Count != 1 || notifications[1].Count != 2 {
+        t.Errorf("Counts = %d, %d; want the new entry 1 and the first batch 2", notifications[0].Count, notifications[1].Count)
+    }
+}
+
+func TestGrowingBatchSortsFirst(t *testing.T) {
+    f := newNotificationFixture(t)
+    window := f.e.config.NotificationBatchWindow
+    bob := mustRegister(t, f.e, "bob")
+
+    f.reply(t)
+    f.clock.Advance(window / 4)
+    if _, err := f.e.CreateComment("Hey @alice", bob.ID, f.post.ID, nil); err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+    f.clock.Advance(window / 4)
+    f.reply(t)
+
+    // The reply batch started before the mention but grew after it
+    notifications := f.notifications(t)
+    if len(notifications) != 2 {
+        t.Fatalf("got %d notifications, want 2", len(notifications))
+    }
+    if notifications[0].Type != models.NotificationReply || notifications[1].Type != models.NotificationMention {
+        t.Errorf("order = %s, %s; want the updated reply batch first", notifications[0].Type, notifications[1].Type)
+    }
+}
+
+func TestRepliesAndMentionsReachTheInbox(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.PostCooldown = 0
+    cfg.CommentCooldown = 0
+    cfg.NotificationBatchWindow = 0
+    e, clock := newTestEngineWithConfig(t, cfg)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+    comment := mustComment(t, e, alice.ID, post.ID, nil)
+
+    inbox := func(user *models.User) []*models.Notification {
+        t.Helper()
+        notifications, err := e.GetNotifications(user.ID)
+        if err != nil {
+            t.Fatalf("GetNotifications: %v", err)
+        }
+        return notifications
+    }
+
+    // Replying to yourself notifies nobody
+    mustComment(t, e, alice.ID, post.ID, &comment.ID)
+    if n := len(inbox(alice)); n != 0 {
+        t.Fatalf("self-reply left %d notifications, want 0", n)
+    }
+
+    // A reply that also mentions the parent's author notifies them once
+    clock.Advance(time.Second)
+    reply, err := e.CreateComment("Agreed, @alice", bob.ID, post.ID, &comment.ID)
+    if err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+    got := inbox(alice)
+    if len(got) != 1 {
+        t.Fatalf("alice has %d notifications after a reply, want 1", len(got))
+    }
+    if n := got[0]; n.Type != models.NotificationReply || n.ActorID != bob.ID || n.PostID != post.ID || n.CommentID != reply.ID {
+        t.Errorf("reply notification = %+v, want a reply from bob on %s", n, reply.ID)
+    }
+
+    // Each mentioned user hears once; the author and unknown names don't
+    clock.Advance(time.Second)
+    mention, err := e.CreateComment("@carol @carol @bob @nobody look", bob.ID, post.ID, nil)
+    if err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+    got = inbox(carol)
+    if len(got) != 1 {
+        t.Fatalf("carol has %d notifications, want 1", len(got))
+    }
+    if n := got[0]; n.Type != models.NotificationMention || n.ActorID != bob.ID || n.CommentID != mention.ID {
+        t.Errorf("mention notification = %+v, want a mention from bob in %s", n, mention.ID)
+    }
+    if n := len(inbox(bob)); n != 0 {
+        t.Errorf("bob has %d notifications for mentioning himself, want 0", n)
+    }
+
+    // Mentions in a post carry no comment, and the newest sorts first
+    clock.Advance(time.Second)
+    mustJoin(t, e, carol.ID, subreddit.ID)
+    if _, err := e.CreatePost("Question for @alice", "Hi @alice", carol.ID, subreddit.ID); err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    got = inbox(alice)
+    if len(got) != 2 || got[0].Type != models.NotificationMention || got[0].ActorID != carol.ID || got[0].CommentID != "" {
+        t.Fatalf("alice's inbox = %+v, want carol's post mention before bob's reply", got)
+    }
+
+    if err := e.MarkNotificationRead(bob.ID, got[0].ID); err == nil {
+        t.Error("bob marked alice's notification read")
+    }
+    if err := e.MarkNotificationRead(alice.ID, got[0].ID); err != nil {
+        t.Fatalf("MarkNotificationRead: %v", err)
+    }
+    if got = inbox(alice); !got[0].IsRead || got[1].IsRead {
+        t.Errorf("read flags = %v, %v; want only the mention read", got[0].IsRead, got[1].IsRead)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,457 @@
+// internal/engine/persistence.go
+package engine
+
+import (
+    "context"
+    "encoding/gob"
+    "errors"
+    "fmt"
+    "io"
+    "log"
+    "os"
+    "path/filepath"
+    "sync"
+    "sync/atomic"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// snapshotVersion is bumped whenever the snapshot layout changes
+const snapshotVersion = 1
+
+var (
+    ErrEngineNotEmpty = errors.New("engine already holds data")
+    ErrMergeConflict  = errors.New("snapshot conflicts with the engine's data")
+)
+
+// snapshot is the on-disk form of the engine's data. Indexes and counters
+// are not saved; they are rebuilt from the records on load.
+type snapshot struct {
+    Version       int
+    SavedAt       time.Time
+    Users         []models.User
+    Subreddits    []snapshotSubreddit
+    Posts         []models.Post
+    Comments      []models.Comment
+    Messages      []models.DirectMessage
+    Votes         []models.Vote
+    Reports       []models.Report
+    Notifications []models.Notification
+    BannedUsers   map[string]time.Time // userID -> time of the ban
+    Webhooks      []models.Webhook
+}
+
+// snapshotSubreddit flattens a subreddit's member and moderator sets,
+// which live in sync.Maps and can't be encoded directly
+type snapshotSubreddit struct {
+    ID          string
+    Name        string
+    Description string
+    Type        string
+    CreatorID   string
+    MaxMembers  int64
+    PostCount   int64
+    CreatedAt   time.Time
+    Flairs      []string
+    BannedWords []string
+    Members     []string
+    Moderators  []string
+
+    ApprovedPosters []string
+    QueuePosts      bool
+
+    // NoCrossposts is stored inverted so snapshots from before the setting
+    // existed restore with crossposts allowed
+    NoCrossposts   bool
+    Archived       bool
+    AllowAnonymous bool
+}
+
+// SaveState writes a snapshot of the engine's data to path. The snapshot
+// is written to a temporary file in the same directory and renamed into
+// place, so a crash mid-write leaves the previous snapshot intact.
+func (e *RedditEngine) SaveState(path string) error {
+    tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+    if err != nil {
+        return err
+    }
+    tmpPath := tmp.Name()
+    defer os.Remove(tmpPath) // no-op once renamed
+
+    if err := e.WriteSnapshot(tmp); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Sync(); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        return err
+    }
+    return os.Rename(tmpPath, path)
+}
+
+// LoadState restores a snapshot written by SaveState into an empty engine
+func (e *RedditEngine) LoadState(path string) error {
+    f, err := os.Open(path)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+    return e.ReadSnapshot(f)
+}
+
+// WriteSnapshot encodes the engine's data to w
+func (e *RedditEngine) WriteSnapshot(w io.Writer) error {
+    return gob.NewEncoder(w).Encode(e.snapshot())
+}
+
+// ReadSnapshot decodes a snapshot from r and restores it into the engine,
+// which must not hold any data yet. Other writes wait until it finishes,
+// and it returns ErrReadOnly in maintenance mode.
+func (e *RedditEngine) ReadSnapshot(r io.Reader) error {
+    done, err := e.beginExclusiveWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    snap, err := decodeSnapshot(r)
+    if err != nil {
+        return err
+    }
+    if !e.isEmpty() {
+        return ErrEngineNotEmpty
+    }
+    e.restore(snap)
+    return nil
+}
+
+// MergeSnapshot decodes a snapshot from r and adds its records to the
+// engine's, which may already hold data. Records whose IDs already exist
+// are skipped, so the engine's copy wins. If a snapshot user's username
+// belongs to a different user here, nothing is merged and
+// ErrMergeConflict is returned. Other writes wait until the merge
+// finishes, and it returns ErrReadOnly in maintenance mode.
+func (e *RedditEngine) MergeSnapshot(r io.Reader) error {
+    done, err := e.beginExclusiveWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    snap, err := decodeSnapshot(r)
+    if err != nil {
+        return err
+    }
+    for _, user := range snap.Users {
+        if ownerI, taken := e.usernames.Load(user.Username); taken && ownerI.(string) != user.ID {
+            return fmt.Errorf("%w: username %q is taken", ErrMergeConflict, user.Username)
+        }
+    }
+    e.restore(e.newRecords(snap))
+    return nil
+}
+
+// decodeSnapshot reads a snapshot and checks its version
+func decodeSnapshot(r io.Reader) (*snapshot, error) {
+    var snap snapshot
+    if err := gob.NewDecoder(r).Decode(&snap); err != nil {
+        return nil, err
+    }
+    if snap.Version != snapshotVersion {
+        return nil, fmt.Errorf("unsupported snapshot version %d", snap.Version)
+    }
+    return &snap, nil
+}
+
+// RunAutosave saves the engine's state to path every interval until the
+// context is cancelled, then saves once more so a graceful shutdown loses
+// nothing. Failed saves are logged and retried on the next tick.
+func (e *RedditEngine) RunAutosave(ctx context.Context, interval time.Duration, path string) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            if err := e.SaveState(path); err != nil {
+                log.Printf("Final autosave to %s failed: %v\n", path, err)
+            }
+            return
+        case <-ticker.C:
+            if err := e.SaveState(path); err != nil {
+                log.Printf("Autosave to %s failed: %v\n", path, err)
+            }
+        }
+    }
+}
+
+// snapshot copies every record out of the stores. Edits and read-state
+// changes are held off while copying so no record is caught half-updated.
+func (e *RedditEngine) snapshot() *snapshot {
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    e.notificationMtx.Lock()
+    defer e.notificationMtx.Unlock()
+
+    snap := &snapshot{Version: snapshotVersion, SavedAt: e.clock.Now()}
+    e.users.Range(func(_, value interface{}) bool {
+        snap.Users = append(snap.Users, *value.(*models.User))
+        return true
+    })
+    e.subreddits.Range(func(_, value interface{}) bool {
+        subreddit := value.(*models.SubReddit)
+        snap.Subreddits = append(snap.Subreddits, snapshotSubreddit{
+            ID:          subreddit.ID,
+            Name:        subreddit.Name,
+            Description: subreddit.Description,
+            Type:        subreddit.Type,
+            CreatorID:   subreddit.CreatorID,
+            MaxMembers:  atomic.LoadInt64(&subreddit.MaxMembers),
+            PostCount:   atomic.LoadInt64(&subreddit.PostCount),
+            CreatedAt:   subreddit.CreatedAt,
+            Flairs:      subreddit.Flairs,
+            BannedWords: subreddit.BannedWords,
+            Members:     syncMapKeys(&subreddit.Members),
+            Moderators:  syncMapKeys(&subreddit.Moderators),
+
+            ApprovedPosters: syncMapKeys(&subreddit.ApprovedPosters),
+            QueuePosts:      subreddit.QueuePosts,
+            NoCrossposts:    !subreddit.AllowCrossposts,
+            Archived:        subreddit.Archived,
+            AllowAnonymous:  subreddit.AllowAnonymous,
+        })
+        return true
+    })
+    // Votes wait while posts, comments and votes are copied, so the saved
+    // counts agree with the saved votes
+    unlock := e.lockAllTargets()
+    e.posts.Range(func(_, value interface{}) bool {
+        snap.Posts = append(snap.Posts, *value.(*models.Post))
+        return true
+    })
+    e.comments.Range(func(_, value interface{}) bool {
+        snap.Comments = append(snap.Comments, *value.(*models.Comment))
+        return true
+    })
+    e.messages.Range(func(_, value interface{}) bool {
+        snap.Messages = append(snap.Messages, *value.(*models.DirectMessage))
+        return true
+    })
+    e.votes.Range(func(_, value interface{}) bool {
+        snap.Votes = append(snap.Votes, *value.(*models.Vote))
+        return true
+    })
+    unlock()
+    e.reports.Range(func(_, value interface{}) bool {
+        snap.Reports = append(snap.Reports, *value.(*models.Report))
+        return true
+    })
+    e.notifications.Range(func(_, value interface{}) bool {
+        snap.Notifications = append(snap.Notifications, *value.(*models.Notification))
+        return true
+    })
+    e.webhooks.Range(func(_, value interface{}) bool {
+        snap.Webhooks = append(snap.Webhooks, *value.(*models.Webhook))
+        return true
+    })
+    snap.BannedUsers = make(map[string]time.Time)
+    e.bannedUsers.Range(func(key, value interface{}) bool {
+        snap.BannedUsers[key.(string)] = value.(time.Time)
+        return true
+    })
+    return snap
+}
+
+// restore stores every record in the snapshot and rebuilds the indexes
+// and running totals from them
+func (e *RedditEngine) restore(snap *snapshot) {
+    for i := range snap.Users {
+        user := &snap.Users[i]
+        e.users.Store(user.ID, user)
+        e.usernames.Store(user.Username, user.ID)
+        e.counters.users.Add(1)
+    }
+    for _, saved := range snap.Subreddits {
+        subreddit := &models.SubReddit{
+            ID:          saved.ID,
+            Name:        saved.Name,
+            Description: saved.Description,
+            Type:        saved.Type,
+            CreatorID:   saved.CreatorID,
+            MaxMembers:  saved.MaxMembers,
+            PostCount:   saved.PostCount,
+            CreatedAt:   saved.CreatedAt,
+            Flairs:      saved.Flairs,
+            BannedWords: saved.BannedWords,
+            QueuePosts:  saved.QueuePosts,
+
+            AllowCrossposts: !saved.NoCrossposts,
+            Archived:        saved.Archived,
+            AllowAnonymous:  saved.AllowAnonymous,
+        }
+        // Members are restored as saved, even if the cap has since been lowered
+        for _, userID := range saved.Members {
+            subreddit.Members.Store(userID, true)
+            subreddit.MemberCount++
+            subsI, _ := e.userSubscriptions.LoadOrStore(userID, &sync.Map{})
+            subsI.(*sync.Map).Store(subreddit.ID, true)
+        }
+        for _, userID := range saved.Moderators {
+            subreddit.Moderators.Store(userID, true)
+        }
+        for _, userID := range saved.ApprovedPosters {
+            subreddit.ApprovedPosters.Store(userID, true)
+        }
+        // Snapshots from before subreddit types only hold public subreddits
+        if subreddit.Type == "" {
+            subreddit.Type = models.SubredditPublic
+        }
+        e.subreddits.Store(subreddit.ID, subreddit)
+        e.counters.subreddits.Add(1)
+        e.indexSubredditName(subreddit)
+        e.createdSubreddits(subreddit.CreatorID).Add(1)
+    }
+    for i := range snap.Posts {
+        post := &snap.Posts[i]
+        e.posts.Store(post.ID, post)
+        switch {
+        case post.ScheduledFor != nil && post.ScheduledFor.After(e.clock.Now()):
+            e.scheduledPosts.Store(post.ID, post)
+        case post.Pending:
+            e.indexPendingPost(post)
+        default:
+            e.indexPost(post)
+        }
+        e.counters.posts.Add(1)
+    }
+    e.indexSlugs(snap.Posts)
+    for i := range snap.Comments {
+        comment := &snap.Comments[i]
+        e.comments.Store(comment.ID, comment)
+        e.indexComment(comment)
+        e.counters.comments.Add(1)
+    }
+    for i := range snap.Messages {
+        message := &snap.Messages[i]
+        e.messages.Store(message.ID, message)
+        e.counters.messages.Add(1)
+    }
+    for i := range snap.Votes {
+        vote := &snap.Votes[i]
+        e.votes.Store(vote.UserID+":"+vote.TargetID, vote)
+        e.indexVote(vote)
+        e.counters.votes.Add(1)
+    }
+    for i := range snap.Reports {
+        report := &snap.Reports[i]
+        // Snapshots taken before reports could be closed hold only pending ones
+        if report.Status == "" {
+            report.Status = models.ReportPending
+        }
+        e.reports.Store(report.ReporterID+":"+report.TargetID, report)
+    }
+    for i := range snap.Notifications {
+        notification := &snap.Notifications[i]
+        if notification.UpdatedAt.IsZero() {
+            // Snapshots from before batching tracked updates
+            notification.UpdatedAt = notification.CreatedAt
+        }
+        e.notifications.Store(notification.ID, notification)
+        inboxI, _ := e.userNotifications.LoadOrStore(notification.UserID, &sync.Map{})
+        inboxI.(*sync.Map).Store(notification.ID, true)
+    }
+    for userID, bannedAt := range snap.BannedUsers {
+        e.bannedUsers.Store(userID, bannedAt)
+    }
+    for i := range snap.Webhooks {
+        webhook := &snap.Webhooks[i]
+        e.webhooks.Store(webhook.ID, webhook)
+        e.indexWebhook(webhook)
+    }
+}
+
+// newRecords returns the part of snap the engine doesn't already hold.
+// A new post whose slug is taken in its subreddit loses the slug, so
+// restore assigns it a fresh one.
+func (e *RedditEngine) newRecords(snap *snapshot) *snapshot {
+    fresh := &snapshot{Version: snap.Version, SavedAt: snap.SavedAt, BannedUsers: make(map[string]time.Time)}
+    for _, user := range snap.Users {
+        if _, exists := e.users.Load(user.ID); !exists {
+            fresh.Users = append(fresh.Users, user)
+        }
+    }
+    for _, subreddit := range snap.Subreddits {
+        if _, exists := e.subreddits.Load(subreddit.ID); !exists {
+            fresh.Subreddits = append(fresh.Subreddits, subreddit)
+        }
+    }
+    for _, post := range snap.Posts {
+        if _, exists := e.posts.Load(post.ID); exists {
+            continue
+        }
+        if idxI, ok := e.subredditSlugs.Load(post.SubRedditID); ok && post.Slug != "" {
+            idx := idxI.(*slugIndex)
+            idx.mtx.Lock()
+            if ownerID, taken := idx.slugs[post.Slug]; taken && ownerID != post.ID {
+                post.Slug = ""
+            }
+            idx.mtx.Unlock()
+        }
+        fresh.Posts = append(fresh.Posts, post)
+    }
+    for _, comment := range snap.Comments {
+        if _, exists := e.comments.Load(comment.ID); !exists {
+            fresh.Comments = append(fresh.Comments, comment)
+        }
+    }
+    for _, message := range snap.Messages {
+        if _, exists := e.messages.Load(message.ID); !exists {
+            fresh.Messages = append(fresh.Messages, message)
+        }
+    }
+    for _, vote := range snap.Votes {
+        if _, exists := e.votes.Load(vote.UserID + ":" + vote.TargetID); !exists {
+            fresh.Votes = append(fresh.Votes, vote)
+        }
+    }
+    for _, report := range snap.Reports {
+        if _, exists := e.reports.Load(report.ReporterID + ":" + report.TargetID); !exists {
+            fresh.Reports = append(fresh.Reports, report)
+        }
+    }
+    for _, notification := range snap.Notifications {
+        if _, exists := e.notifications.Load(notification.ID); !exists {
+            fresh.Notifications = append(fresh.Notifications, notification)
+        }
+    }
+    for userID, bannedAt := range snap.BannedUsers {
+        if _, exists := e.bannedUsers.Load(userID); !exists {
+            fresh.BannedUsers[userID] = bannedAt
+        }
+    }
+    for _, webhook := range snap.Webhooks {
+        if _, exists := e.webhooks.Load(webhook.ID); !exists {
+            fresh.Webhooks = append(fresh.Webhooks, webhook)
+        }
+    }
+    return fresh
+}
+
+// isEmpty reports whether the engine holds no users or subreddits
+func (e *RedditEngine) isEmpty() bool {
+    return e.counters.users.Load() == 0 && e.counters.subreddits.Load() == 0
+}
+
+// syncMapKeys returns the string keys of a sync.Map
+func syncMapKeys(m *sync.Map) []string {
+    var keys []string
+    m.Range(func(key, _ interface{}) bool {
+        keys = append(keys, key.(string))
+        return true
+    })
+    return keys
+}
\ No newline at end of file
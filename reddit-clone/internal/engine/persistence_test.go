@@ -0,0 +1,127 @@
+// internal/engine/persistence_test.go
+package engine
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+// newPopulatedEngine returns an engine holding some of every kind of record
+func newPopulatedEngine(t *testing.T) *RedditEngine {
+    t.Helper()
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    mustJoin(t, e, bob.ID, subreddit.ID)
+    post := mustPost(t, e, bob.ID, subreddit.ID)
+    comment := mustComment(t, e, alice.ID, post.ID, nil)
+    mustComment(t, e, bob.ID, post.ID, &comment.ID)
+    mustVote(t, e, alice.ID, post.ID, VoteUp)
+    mustVote(t, e, bob.ID, comment.ID, VoteDown)
+    if _, err := e.SendDirectMessage(alice.ID, bob.ID, "hi"); err != nil {
+        t.Fatalf("SendDirectMessage: %v", err)
+    }
+    return e
+}
+
+// mustLoad loads the snapshot at path into a new engine
+func mustLoad(t *testing.T, path string) *RedditEngine {
+    t.Helper()
+    loaded, _ := newTestEngine(t)
+    if err := loaded.LoadState(path); err != nil {
+        t.Fatalf("LoadState: %v", err)
+    }
+    return loaded
+}
+
+func TestSaveStateRoundTrip(t *testing.T) {
+    e := newPopulatedEngine(t)
+    dir := t.TempDir()
+    path := filepath.Join(dir, "state.gob")
+    if err := e.SaveState(path); err != nil {
+        t.Fatalf("SaveState: %v", err)
+    }
+    // Only the snapshot is left; the temporary file was renamed into place
+    if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+        t.Errorf("directory holds %d files after saving, want 1", len(entries))
+    }
+
+    loaded := mustLoad(t, path)
+    want, _ := e.GlobalStats()
+    if got, _ := loaded.GlobalStats(); *got != *want {
+        t.Errorf("restored totals %+v, want %+v", *got, *want)
+    }
+
+    // Indexes are rebuilt, so lookups work as before
+    bob, err := loaded.GetUserByUsername("bob")
+    if err != nil {
+        t.Fatalf("GetUserByUsername: %v", err)
+    }
+    subreddits, err := loaded.GetUserSubreddits(bob.ID)
+    if err != nil || len(subreddits) != 1 {
+        t.Fatalf("bob belongs to %d subreddits after loading (%v), want 1", len(subreddits), err)
+    }
+    posts, err := loaded.ListPosts(bob.ID, subreddits[0].ID)
+    if err != nil || len(posts) != 1 {
+        t.Fatalf("subreddit lists %d posts after loading (%v), want 1", len(posts), err)
+    }
+    if up, down := posts[0].Votes(); up != 1 || down != 0 {
+        t.Errorf("post votes %d/%d after loading, want 1/0", up, down)
+    }
+    comments, _ := loaded.GetComments(posts[0].ID)
+    if len(comments) != 2 {
+        t.Errorf("post has %d comments after loading, want 2", len(comments))
+    }
+    if _, err := loaded.AuthenticateUser("alice", "password123"); err != nil {
+        t.Errorf("alice can't log in after loading: %v", err)
+    }
+
+    if err := loaded.LoadState(path); err != ErrEngineNotEmpty {
+        t.Errorf("LoadState into a populated engine: err = %v, want ErrEngineNotEmpty", err)
+    }
+}
+
+func TestRunAutosave(t *testing.T) {
+    e := newPopulatedEngine(t)
+    path := filepath.Join(t.TempDir(), "state.gob")
+
+    // Saves happen on every tick
+    ctx, cancel := context.WithCancel(context.Background())
+    done := make(chan struct{})
+    go func() {
+        e.RunAutosave(ctx, 5*time.Millisecond, path)
+        close(done)
+    }()
+    deadline := time.Now().Add(5 * time.Second)
+    for {
+        if _, err := os.Stat(path); err == nil {
+            break
+        }
+        if time.Now().After(deadline) {
+            t.Fatal("no autosave within 5s")
+        }
+        time.Sleep(time.Millisecond)
+    }
+    cancel()
+    <-done
+    mustLoad(t, path)
+
+    // Shutting down saves at once, without waiting for a tick
+    ctx, cancel = context.WithCancel(context.Background())
+    done = make(chan struct{})
+    go func() {
+        e.RunAutosave(ctx, time.Hour, path)
+        close(done)
+    }()
+    mustRegister(t, e, "carol")
+    cancel()
+    <-done
+    loaded := mustLoad(t, path)
+    if _, err := loaded.GetUserByUsername("carol"); err != nil {
+        t.Errorf("user created before shutdown missing from the final save: %v", err)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,154 @@
+// internal/engine/personalized.go
+package engine
+
+import (
+    "math"
+    "sort"
+    "sync"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// Personalized ranking weights. Activity counts are log-damped so a
+// handful of interactions matters but thousands don't swamp the score.
+const (
+    subredditAffinityWeight = 2.0
+    authorAffinityWeight    = 3.0
+)
+
+// personalizedFeed is a user's cached personalized ranking
+type personalizedFeed struct {
+    posts      []*models.Post
+    computedAt time.Time
+}
+
+// GetPersonalizedFeed returns one page of the user's feed ranked by post
+// score plus boosts for subreddits the user is active in (their posts,
+// comments and upvotes there) and for authors they have upvoted. The
+// ranking is cached per user for Config.PersonalizedFeedTTL. Page is
+// 1-based; a limit of 0 returns the whole feed.
+func (e *RedditEngine) GetPersonalizedFeed(userID string, page, limit int) ([]*models.Post, error) {
+    return e.GetPersonalizedFeedWithOptions(userID, page, limit, PersonalizedFeedOptions{})
+}
+
+// PersonalizedFeedOptions adjusts which ranked posts are paginated
+type PersonalizedFeedOptions struct {
+    // HideOwn leaves out posts the user wrote
+    HideOwn bool
+}
+
+// GetPersonalizedFeedWithOptions is GetPersonalizedFeed with filtering.
+// Filters apply before pagination, so every page but the last is full.
+func (e *RedditEngine) GetPersonalizedFeedWithOptions(userID string, page, limit int, opts PersonalizedFeedOptions) ([]*models.Post, error) {
+    if _, err := e.GetUser(userID); err != nil {
+        return nil, err
+    }
+
+    // A cached ranking may hold posts auto-removed since it was computed
+    ranked := e.listedPosts(e.cachedPersonalizedFeed(userID))
+    if opts.HideOwn {
+        ranked = excludeAuthor(ranked, userID)
+    }
+    if limit <= 0 {
+        return ranked, nil
+    }
+    start := (max(page, 1) - 1) * limit
+    if start >= len(ranked) {
+        return []*models.Post{}, nil
+    }
+    // Copy the page so the caller can't reach the rest of the ranking
+    pagePosts := make([]*models.Post, min(start+limit, len(ranked))-start)
+    copy(pagePosts, ranked[start:])
+    return pagePosts, nil
+}
+
+// cachedPersonalizedFeed returns the user's ranking, recomputing it once the cached copy expires
+func (e *RedditEngine) cachedPersonalizedFeed(userID string) []*models.Post {
+    if cachedI, ok := e.personalizedFeeds.Load(userID); ok {
+        cached := cachedI.(*personalizedFeed)
+        if e.clock.Now().Sub(cached.computedAt) < e.config.PersonalizedFeedTTL {
+            return cached.posts
+        }
+    }
+
+    posts := e.rankPersonalizedFeed(userID)
+    e.personalizedFeeds.Store(userID, &personalizedFeed{posts: posts, computedAt: e.clock.Now()})
+    return posts
+}
+
+// rankPersonalizedFeed scores every post in the user's feed, highest first
+func (e *RedditEngine) rankPersonalizedFeed(userID string) []*models.Post {
+    subredditActivity, authorUpvotes := e.userAffinities(userID)
+
+    posts, _ := e.GetFeed(userID)
+    rank := make(map[string]float64, len(posts))
+    for _, post := range posts {
+        rank[post.ID] = float64(post.Score()) +
+            subredditAffinityWeight*math.Log1p(float64(subredditActivity[post.SubRedditID])) +
+            authorAffinityWeight*math.Log1p(float64(authorUpvotes[post.AuthorID]))
+    }
+
+    sort.SliceStable(posts, func(i, j int) bool {
+        if rank[posts[i].ID] != rank[posts[j].ID] {
+            return rank[posts[i].ID] > rank[posts[j].ID]
+        }
+        return posts[i].CreatedAt.After(posts[j].CreatedAt)
+    })
+    return posts
+}
+
+// userAffinities counts the user's activity per subreddit (posts, comments
+// and upvotes) and their upvotes per author. It reads only the user's own
+// entries in the author and vote indexes.
+func (e *RedditEngine) userAffinities(userID string) (subredditActivity, authorUpvotes map[string]int) {
+    subredditActivity = make(map[string]int)
+    authorUpvotes = make(map[string]int)
+
+    rangeUserIndex(&e.userPosts, userID, func(postID string) {
+        if postI, ok := e.posts.Load(postID); ok {
+            subredditActivity[postI.(*models.Post).SubRedditID]++
+        }
+    })
+    rangeUserIndex(&e.userComments, userID, func(commentID string) {
+        commentI, ok := e.comments.Load(commentID)
+        if !ok {
+            return
+        }
+        if postI, ok := e.posts.Load(commentI.(*models.Comment).PostID); ok {
+            subredditActivity[postI.(*models.Post).SubRedditID]++
+        }
+    })
+    rangeUserIndex(&e.userVotes, userID, func(targetID string) {
+        voteI, ok := e.votes.Load(userID + ":" + targetID)
+        if !ok || !voteI.(*models.Vote).IsUpvote {
+            return
+        }
+        vote := voteI.(*models.Vote)
+        if postI, ok := e.posts.Load(vote.TargetID); ok {
+            post := postI.(*models.Post)
+            subredditActivity[post.SubRedditID]++
+            authorUpvotes[post.AuthorID]++
+        } else if commentI, ok := e.comments.Load(vote.TargetID); ok {
+            comment := commentI.(*models.Comment)
+            if postI, ok := e.posts.Load(comment.PostID); ok {
+                subredditActivity[postI.(*models.Post).SubRedditID]++
+            }
+            authorUpvotes[comment.AuthorID]++
+        }
+    })
+    return subredditActivity, authorUpvotes
+}
+
+// rangeUserIndex calls fn with each ID in userID's entry of a per-user
+// index such as userPosts or userVotes
+func rangeUserIndex(index *sync.Map, userID string, fn func(id string)) {
+    idxI, ok := index.Load(userID)
+    if !ok {
+        return
+    }
+    idxI.(*sync.Map).Range(func(key, _ interface{}) bool {
+        fn(key.(string))
+        return true
+    })
+}
\ No newline at end of file
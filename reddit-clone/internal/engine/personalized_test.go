@@ -0,0 +1,102 @@
+// internal/engine/personalized_test.go
+package engine
+
+import (
+    "testing"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// feedIndex returns where postID falls in posts, or -1
+func feedIndex(posts []*models.Post, postID string) int {
+    for i, post := range posts {
+        if post.ID == postID {
+            return i
+        }
+    }
+    return -1
+}
+
+func TestPersonalizedFeedBoostsActiveSubreddits(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+    active := mustCreateSubreddit(t, e, "active", bob.ID)
+    quiet := mustCreateSubreddit(t, e, "quiet", carol.ID)
+    mustJoin(t, e, alice.ID, active.ID)
+    mustJoin(t, e, alice.ID, quiet.ID)
+
+    // Alice comments in one subreddit only
+    discussion := mustPost(t, e, bob.ID, active.ID)
+    for i := 0; i < 3; i++ {
+        mustComment(t, e, alice.ID, discussion.ID, nil)
+    }
+
+    // Without a boost the newer quiet post would rank first
+    activePost := mustPost(t, e, bob.ID, active.ID)
+    clock.Advance(time.Minute)
+    quietPost := mustPost(t, e, carol.ID, quiet.ID)
+
+    feed, err := e.GetPersonalizedFeed(alice.ID, 1, 0)
+    if err != nil {
+        t.Fatalf("GetPersonalizedFeed: %v", err)
+    }
+    if a, q := feedIndex(feed, activePost.ID), feedIndex(feed, quietPost.ID); a < 0 || q < 0 || a > q {
+        t.Fatalf("active post at %d, quiet post at %d; want the active post first", a, q)
+    }
+}
+
+func TestPersonalizedFeedBoostsUpvotedAuthors(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+    sub := mustCreateSubreddit(t, e, "golang", bob.ID)
+    mustJoin(t, e, alice.ID, sub.ID)
+    mustJoin(t, e, carol.ID, sub.ID)
+
+    // Alice upvotes a comment of Bob's, which moves no post's score
+    comment := mustComment(t, e, bob.ID, mustPost(t, e, carol.ID, sub.ID).ID, nil)
+    mustVote(t, e, alice.ID, comment.ID, VoteUp)
+
+    bobPost := mustPost(t, e, bob.ID, sub.ID)
+    clock.Advance(time.Minute)
+    carolPost := mustPost(t, e, carol.ID, sub.ID)
+
+    feed, err := e.GetPersonalizedFeed(alice.ID, 1, 0)
+    if err != nil {
+        t.Fatalf("GetPersonalizedFeed: %v", err)
+    }
+    if b, c := feedIndex(feed, bobPost.ID), feedIndex(feed, carolPost.ID); b < 0 || c < 0 || b > c {
+        t.Fatalf("Bob's post at %d, Carol's at %d; want Bob's first", b, c)
+    }
+}
+
+func TestPersonalizedFeedPageIsACopy(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    sub := mustCreateSubreddit(t, e, "golang", alice.ID)
+    for i := 0; i < 3; i++ {
+        mustPost(t, e, alice.ID, sub.ID)
+    }
+
+    page, err := e.GetPersonalizedFeed(alice.ID, 1, 2)
+    if err != nil {
+        t.Fatalf("GetPersonalizedFeed: %v", err)
+    }
+    if len(page) != 2 || cap(page) != 2 {
+        t.Fatalf("page has len %d cap %d, want 2 and 2", len(page), cap(page))
+    }
+    first := page[0]
+    page[0] = nil
+
+    again, err := e.GetPersonalizedFeed(alice.ID, 1, 2)
+    if err != nil {
+        t.Fatalf("GetPersonalizedFeed: %v", err)
+    }
+    if again[0] != first {
+        t.Fatal("writing to a returned page changed the cached ranking")
+    }
+}
\ No newline at end of file
@@ -0,0 +1,95 @@
+// internal/engine/popular.go
+package engine
+
+import (
+    "context"
+    "errors"
+    "math"
+    "sort"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// MaxPopularLimit caps how many posts GetPopularPosts returns
+const MaxPopularLimit = 100
+
+// hotEpoch anchors post ages in the hotness score
+var hotEpoch = time.Date(2005, time.December, 8, 7, 46, 43, 0, time.UTC)
+
+// PopularOptions adjusts which posts GetPopularPostsWithOptions considers
+type PopularOptions struct {
+    IncludeNSFW bool
+}
+
+// GetPopularPosts ranks the posts created within timeWindow across every
+// subreddit by hotness, leaving out NSFW posts and private subreddits.
+// The limit is capped at MaxPopularLimit.
+func (e *RedditEngine) GetPopularPosts(timeWindow time.Duration, limit int) ([]*models.Post, error) {
+    return e.GetPopularPostsWithOptions(context.Background(), timeWindow, limit, PopularOptions{})
+}
+
+// GetPopularPostsWithOptions is GetPopularPosts shaped by opts. The scan
+// stops with the context's error once it is done.
+func (e *RedditEngine) GetPopularPostsWithOptions(ctx context.Context, timeWindow time.Duration, limit int, opts PopularOptions) ([]*models.Post, error) {
+    if timeWindow <= 0 {
+        return nil, errors.New("time window must be positive")
+    }
+    if limit <= 0 {
+        return nil, errors.New("limit must be positive")
+    }
+    limit = min(limit, MaxPopularLimit)
+
+    cutoff := e.clock.Now().Add(-timeWindow)
+    var posts []*models.Post
+    scanned := 0
+    e.posts.Range(func(_, value interface{}) bool {
+        // Checking every post would cost more than the check saves
+        scanned++
+        if scanned%1024 == 0 && ctx.Err() != nil {
+            return false
+        }
+        post := value.(*models.Post)
+        if post.CreatedAt.Before(cutoff) || (post.NSFW && !opts.IncludeNSFW) || post.Pending || e.postAutoRemoved(post) || e.isScheduled(post.ID) || e.inPrivateSubreddit(post) {
+            return true
+        }
+        posts = append(posts, post)
+        return true
+    })
+    if err := ctx.Err(); err != nil {
+        return nil, err
+    }
+
+    sort.SliceStable(posts, func(i, j int) bool {
+        hi, hj := hotness(posts[i]), hotness(posts[j])
+        if hi != hj {
+            return hi > hj
+        }
+        return posts[i].CreatedAt.After(posts[j].CreatedAt)
+    })
+    if len(posts) > limit {
+        posts = posts[:limit]
+    }
+    return posts, nil
+}
+
+// inPrivateSubreddit reports whether the post belongs to a private
+// subreddit, whose posts stay out of site-wide listings
+func (e *RedditEngine) inPrivateSubreddit(post *models.Post) bool {
+    subredditI, ok := e.subreddits.Load(post.SubRedditID)
+    return ok && subredditI.(*models.SubReddit).Type == models.SubredditPrivate
+}
+
+// hotness scores a post so that each tenfold increase in net votes is
+// worth about half a day of recency
+func hotness(post *models.Post) float64 {
+    score := post.Score()
+    order := math.Log10(math.Max(math.Abs(float64(score)), 1))
+    sign := 0.0
+    if score > 0 {
+        sign = 1
+    } else if score < 0 {
+        sign = -1
+    }
+    return sign*order + post.CreatedAt.Sub(hotEpoch).Seconds()/45000
+}
\ No newline at end of file
@@ -0,0 +1,99 @@
+// internal/engine/popular_test.go
+package engine
+
+import (
+    "context"
+    "slices"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+func postIDs(posts []*models.Post) []string {
+    ids := make([]string, len(posts))
+    for i, post := range posts {
+        ids[i] = post.ID
+    }
+    return ids
+}
+
+func TestGetPopularPosts(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    voters := []*models.User{mustRegister(t, e, "bob"), mustRegister(t, e, "carol"), mustRegister(t, e, "dave")}
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    upvote := func(post *models.Post) {
+        for _, voter := range voters {
+            mustVote(t, e, voter.ID, post.ID, VoteUp)
+        }
+    }
+
+    // Scores of 3 are worth about six hours of recency
+    old := mustPost(t, e, alice.ID, subreddit.ID)
+    upvote(old)
+    clock.Advance(26 * time.Hour)
+    upvoted := mustPost(t, e, alice.ID, subreddit.ID)
+    upvote(upvoted)
+    clock.Advance(4 * time.Hour)
+    fresh := mustPost(t, e, alice.ID, subreddit.ID)
+    nsfw, err := e.CreatePostWithOptions("NSFW", "Content", alice.ID, subreddit.ID, PostOptions{NSFW: true})
+    if err != nil {
+        t.Fatalf("CreatePostWithOptions: %v", err)
+    }
+    upvote(nsfw)
+    newPrivateSubreddit(t, e, alice.ID)
+
+    tests := []struct {
+        name   string
+        window time.Duration
+        limit  int
+        want   []*models.Post
+    }{
+        {"last day", 24 * time.Hour, 10, []*models.Post{upvoted, fresh}},
+        {"limited", 24 * time.Hour, 1, []*models.Post{upvoted}},
+        {"last two days", 48 * time.Hour, 10, []*models.Post{upvoted, fresh, old}},
+        {"last hour", time.Hour, 10, []*models.Post{fresh}},
+    }
+    for _, tt := range tests {
+        posts, err := e.GetPopularPosts(tt.window, tt.limit)
+        if err != nil {
+            t.Fatalf("%s: GetPopularPosts: %v", tt.name, err)
+        }
+        if got, want := postIDs(posts), postIDs(tt.want); !slices.Equal(got, want) {
+            t.Errorf("%s: popular = %v, want %v", tt.name, got, want)
+        }
+    }
+
+    posts, err := e.GetPopularPostsWithOptions(context.Background(), 24*time.Hour, 10, PopularOptions{IncludeNSFW: true})
+    if err != nil {
+        t.Fatalf("GetPopularPostsWithOptions: %v", err)
+    }
+    if got, want := postIDs(posts), postIDs([]*models.Post{nsfw, upvoted, fresh}); !slices.Equal(got, want) {
+        t.Errorf("popular with NSFW = %v, want %v", got, want)
+    }
+
+    if _, err := e.GetPopularPosts(0, 10); err == nil {
+        t.Error("GetPopularPosts accepted a zero window")
+    }
+    if _, err := e.GetPopularPosts(time.Hour, 0); err == nil {
+        t.Error("GetPopularPosts accepted a zero limit")
+    }
+}
+
+func TestGetPopularPostsCapsLimit(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    for range MaxPopularLimit + 5 {
+        mustPost(t, e, alice.ID, subreddit.ID)
+    }
+
+    posts, err := e.GetPopularPosts(time.Hour, MaxPopularLimit*2)
+    if err != nil {
+        t.Fatalf("GetPopularPosts: %v", err)
+    }
+    if len(posts) != MaxPopularLimit {
+        t.Errorf("got %d posts, want %d", len(posts), MaxPopularLimit)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,154 @@
+// internal/engine/postqueue.go
+package engine
+
+import (
+    "errors"
+    "sort"
+    "sync"
+
+    "reddit-clone/internal/models"
+)
+
+var (
+    ErrPostPending    = errors.New("post is awaiting moderator approval")
+    ErrPostNotPending = errors.New("post is not awaiting approval")
+)
+
+// indexPendingPost adds a queued post to its subreddit's mod queue
+func (e *RedditEngine) indexPendingPost(post *models.Post) {
+    idxI, _ := e.pendingPosts.LoadOrStore(post.SubRedditID, &sync.Map{})
+    idxI.(*sync.Map).Store(post.ID, true)
+}
+
+// pendingPostList returns the queued posts of a subreddit
+func (e *RedditEngine) pendingPostList(subredditID string) []*models.Post {
+    var posts []*models.Post
+    idxI, ok := e.pendingPosts.Load(subredditID)
+    if !ok {
+        return posts
+    }
+    idxI.(*sync.Map).Range(func(key, _ interface{}) bool {
+        if postI, ok := e.posts.Load(key.(string)); ok {
+            posts = append(posts, postI.(*models.Post))
+        }
+        return true
+    })
+    return posts
+}
+
+// CanSeePost reports whether the user may read the post: a post in a
+// private subreddit is only visible to its members, a pending post only to
+// its author and the subreddit's moderators, and a scheduled one only to
+// its author
+func (e *RedditEngine) CanSeePost(userID string, post *models.Post) bool {
+    if _, err := e.ViewSubReddit(userID, post.SubRedditID); errors.Is(err, ErrSubredditPrivate) {
+        return false
+    }
+    if e.isScheduled(post.ID) {
+        return post.AuthorID == userID
+    }
+    if !post.Pending || post.AuthorID == userID {
+        return true
+    }
+    subreddit, err := e.GetSubReddit(post.SubRedditID)
+    return err == nil && isModerator(userID, subreddit)
+}
+
+// checkCanSeeTarget refuses a user acting on a post, or a comment under
+// one, in a private subreddit they don't belong to, so voting or
+// reporting by ID can't reveal the target exists. Unknown targets pass
+// for the caller to report.
+func (e *RedditEngine) checkCanSeeTarget(userID, targetID string) error {
+    postID := targetID
+    if commentI, ok := e.comments.Load(targetID); ok {
+        postID = commentI.(*models.Comment).PostID
+    }
+    postI, ok := e.posts.Load(postID)
+    if !ok {
+        return nil
+    }
+    if _, err := e.ViewSubReddit(userID, postI.(*models.Post).SubRedditID); errors.Is(err, ErrSubredditPrivate) {
+        return err
+    }
+    return nil
+}
+
+// GetPendingPosts returns a subreddit's mod queue, oldest first; only
+// moderators may read it
+func (e *RedditEngine) GetPendingPosts(modID, subredditID string) ([]*models.Post, error) {
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(modID, subreddit) {
+        return nil, ErrNotModerator
+    }
+    posts := e.pendingPostList(subredditID)
+    sort.Slice(posts, func(i, j int) bool {
+        return posts[i].CreatedAt.Before(posts[j].CreatedAt)
+    })
+    return posts, nil
+}
+
+// ApprovePost publishes a queued post as if it had just been created
+func (e *RedditEngine) ApprovePost(modID, postID string) (*models.Post, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    post, err := e.dequeuePost(modID, postID)
+    if err != nil {
+        return nil, err
+    }
+    e.announcePost(post)
+    return post, nil
+}
+
+// RejectPost discards a queued post
+func (e *RedditEngine) RejectPost(modID, postID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    if _, err := e.dequeuePost(modID, postID); err != nil {
+        return err
+    }
+    removed := make(map[string]bool)
+    if err := e.removePostAndComments(postID, removed); err != nil {
+        return err
+    }
+    e.removeTargetRecords(removed)
+    return nil
+}
+
+// dequeuePost takes a pending post off its subreddit's mod queue and clears
+// its Pending flag. The flag is checked under editMtx so a post is only
+// approved or rejected once.
+func (e *RedditEngine) dequeuePost(modID, postID string) (*models.Post, error) {
+    post, err := e.GetPost(postID)
+    if err != nil {
+        return nil, err
+    }
+    subreddit, err := e.GetSubReddit(post.SubRedditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(modID, subreddit) {
+        return nil, ErrNotModerator
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    if !post.Pending {
+        return nil, ErrPostNotPending
+    }
+    post.Pending = false
+    if idxI, ok := e.pendingPosts.Load(post.SubRedditID); ok {
+        idxI.(*sync.Map).Delete(postID)
+    }
+    return post, nil
+}
\ No newline at end of file
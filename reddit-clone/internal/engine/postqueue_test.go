@@ -0,0 +1,104 @@
+// internal/engine/postqueue_test.go
+package engine
+
+import (
+    "testing"
+
+    "reddit-clone/internal/models"
+)
+
+// newQueuedSubreddit creates a restricted subreddit moderated by modID
+// that queues posts from users who aren't approved posters
+func newQueuedSubreddit(t *testing.T, e *RedditEngine, modID string) *models.SubReddit {
+    t.Helper()
+    subreddit, err := e.CreateSubRedditWithOptions("queued", "Reviewed posts", modID, SubredditOptions{Type: models.SubredditRestricted, QueuePosts: true})
+    if err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+    return subreddit
+}
+
+func TestPendingPostHiddenUntilApproved(t *testing.T) {
+    e, _ := newTestEngine(t)
+    mod := mustRegister(t, e, "mod")
+    author := mustRegister(t, e, "author")
+    reader := mustRegister(t, e, "reader")
+    subreddit := newQueuedSubreddit(t, e, mod.ID)
+    mustJoin(t, e, author.ID, subreddit.ID)
+    mustJoin(t, e, reader.ID, subreddit.ID)
+
+    post, err := e.CreatePost("Queued", "Awaiting review", author.ID, subreddit.ID)
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    if !post.Pending {
+        t.Fatal("post from a non-approved user isn't pending")
+    }
+
+    listed := func() bool {
+        t.Helper()
+        posts, err := e.ListPosts(reader.ID, subreddit.ID)
+        if err != nil {
+            t.Fatalf("ListPosts: %v", err)
+        }
+        feed, err := e.GetFeed(reader.ID)
+        if err != nil {
+            t.Fatalf("GetFeed: %v", err)
+        }
+        return containsPost(posts, post.ID) || containsPost(feed, post.ID)
+    }
+    if listed() {
+        t.Error("pending post is listed")
+    }
+    if e.CanSeePost(reader.ID, post) {
+        t.Error("a reader can see the pending post")
+    }
+    if !e.CanSeePost(author.ID, post) || !e.CanSeePost(mod.ID, post) {
+        t.Error("the author and moderator should see the pending post")
+    }
+    queue, err := e.GetPendingPosts(mod.ID, subreddit.ID)
+    if err != nil || len(queue) != 1 || queue[0].ID != post.ID {
+        t.Fatalf("GetPendingPosts = %v, %v; want the post", queue, err)
+    }
+    if _, err := e.GetPendingPosts(reader.ID, subreddit.ID); err != ErrNotModerator {
+        t.Errorf("GetPendingPosts by a reader: err = %v, want ErrNotModerator", err)
+    }
+    if _, err := e.ApprovePost(author.ID, post.ID); err != ErrNotModerator {
+        t.Errorf("ApprovePost by the author: err = %v, want ErrNotModerator", err)
+    }
+
+    if _, err := e.ApprovePost(mod.ID, post.ID); err != nil {
+        t.Fatalf("ApprovePost: %v", err)
+    }
+    if !listed() || !e.CanSeePost(reader.ID, post) {
+        t.Error("approved post isn't visible to readers")
+    }
+    if queue, _ := e.GetPendingPosts(mod.ID, subreddit.ID); len(queue) != 0 {
+        t.Errorf("queue holds %d posts after approval, want 0", len(queue))
+    }
+    if _, err := e.ApprovePost(mod.ID, post.ID); err != ErrPostNotPending {
+        t.Errorf("second ApprovePost: err = %v, want ErrPostNotPending", err)
+    }
+}
+
+func TestRejectedPostIsDiscarded(t *testing.T) {
+    e, _ := newTestEngine(t)
+    mod := mustRegister(t, e, "mod")
+    author := mustRegister(t, e, "author")
+    subreddit := newQueuedSubreddit(t, e, mod.ID)
+    mustJoin(t, e, author.ID, subreddit.ID)
+    post, err := e.CreatePost("Queued", "Awaiting review", author.ID, subreddit.ID)
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+
+    if err := e.RejectPost(mod.ID, post.ID); err != nil {
+        t.Fatalf("RejectPost: %v", err)
+    }
+    if _, err := e.GetPost(post.ID); err == nil {
+        t.Error("rejected post is still stored")
+    }
+    if queue, _ := e.GetPendingPosts(mod.ID, subreddit.ID); len(queue) != 0 {
+        t.Errorf("queue holds %d posts after rejection, want 0", len(queue))
+    }
+}
\ No newline at end of file
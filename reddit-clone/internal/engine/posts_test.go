@@ -0,0 +1,36 @@
+// internal/engine/posts_test.go
+package engine
+
+import (
+    "fmt"
+    "testing"
+)
+
+func TestGetPostsBatch(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    first := mustPost(t, e, alice.ID, subreddit.ID)
+    second := mustPost(t, e, alice.ID, subreddit.ID)
+
+    // Posts come back in request order, unknown IDs skipped
+    posts, err := e.GetPosts([]string{second.ID, "missing", first.ID})
+    if err != nil {
+        t.Fatalf("GetPosts: %v", err)
+    }
+    if len(posts) != 2 || posts[0].ID != second.ID || posts[1].ID != first.ID {
+        t.Errorf("GetPosts returned %d posts, want second then first", len(posts))
+    }
+
+    ids := make([]string, MaxPostBatchSize)
+    for i := range ids {
+        ids[i] = fmt.Sprintf("missing%d", i)
+    }
+    ids[0] = first.ID
+    if posts, err := e.GetPosts(ids); err != nil || len(posts) != 1 {
+        t.Errorf("GetPosts of a full batch = %d posts, %v; want 1, nil", len(posts), err)
+    }
+    if _, err := e.GetPosts(append(ids, second.ID)); err == nil {
+        t.Errorf("GetPosts of %d IDs succeeded, want an error", MaxPostBatchSize+1)
+    }
+}
\ No newline at end of file
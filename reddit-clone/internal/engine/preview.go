@@ -0,0 +1,31 @@
+// internal/engine/preview.go
+package engine
+
+import (
+    "errors"
+    "sync/atomic"
+
+    "reddit-clone/internal/models"
+)
+
+// GetPostPreview returns a post's comment count and its highest-scored
+// top-level comment, for rendering feed entries without the full tree.
+// Ties go to the older comment; topComment is nil if there are no
+// top-level comments.
+func (e *RedditEngine) GetPostPreview(postID string) (commentCount int64, topComment *models.Comment, err error) {
+    postI, ok := e.posts.Load(postID)
+    if !ok {
+        return 0, nil, errors.New("post not found")
+    }
+
+    for _, comment := range e.postCommentList(postID) {
+        if comment.ParentID != nil {
+            continue
+        }
+        if topComment == nil || comment.Score() > topComment.Score() ||
+            (comment.Score() == topComment.Score() && comment.CreatedAt.Before(topComment.CreatedAt)) {
+            topComment = comment
+        }
+    }
+    return atomic.LoadInt64(&postI.(*models.Post).CommentCount), topComment, nil
+}
\ No newline at end of file
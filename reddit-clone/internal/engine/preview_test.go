@@ -0,0 +1,43 @@
+// internal/engine/preview_test.go
+package engine
+
+import (
+    "testing"
+    "time"
+)
+
+func TestGetPostPreview(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+
+    count, top, err := e.GetPostPreview(post.ID)
+    if err != nil || count != 0 || top != nil {
+        t.Fatalf("preview without comments = %d, %v, %v; want 0, nil, nil", count, top, err)
+    }
+
+    older := mustComment(t, e, alice.ID, post.ID, nil)
+    clock.Advance(time.Second)
+    newer := mustComment(t, e, bob.ID, post.ID, nil)
+    reply := mustComment(t, e, carol.ID, post.ID, &newer.ID)
+
+    // On a tie the older comment wins
+    if count, top, _ := e.GetPostPreview(post.ID); count != 3 || top == nil || top.ID != older.ID {
+        t.Errorf("preview = %d comments, top %v; want 3, the older comment", count, top)
+    }
+
+    // Replies count toward the total but are never the top comment
+    mustVote(t, e, bob.ID, reply.ID, VoteUp)
+    mustVote(t, e, carol.ID, reply.ID, VoteUp)
+    mustVote(t, e, carol.ID, newer.ID, VoteUp)
+    if _, top, _ := e.GetPostPreview(post.ID); top == nil || top.ID != newer.ID {
+        t.Errorf("top comment = %v, want the upvoted top-level comment", top)
+    }
+
+    if _, _, err := e.GetPostPreview("missing"); err == nil {
+        t.Error("preview of an unknown post succeeded")
+    }
+}
\ No newline at end of file
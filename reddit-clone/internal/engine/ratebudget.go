@@ -0,0 +1,97 @@
+// internal/engine/ratebudget.go
+package engine
+
+import (
+    "errors"
+    "sync"
+    "time"
+)
+
+var ErrRateBudgetExceeded = errors.New("request budget exhausted")
+
+// operatorBudgetKey is where operator calls, which act for no user, are
+// counted; no generated user ID contains a colon
+const operatorBudgetKey = "operator:"
+
+// RateBudget caps how many requests each user may make per
+// Config.RateBudgetWindow, whichever transport they arrive on. The REST
+// middleware and the gRPC interceptor both charge the engine's budget,
+// so a user can't double their allowance by splitting traffic.
+type RateBudget struct {
+    e     *RedditEngine
+    users sync.Map // map[userID or operatorBudgetKey]*budgetWindow
+}
+
+// budgetWindow is one user's usage in their current window
+type budgetWindow struct {
+    mtx   sync.Mutex
+    start time.Time
+    used  int
+}
+
+// RateBudget returns the per-user request budget shared by every transport
+func (e *RedditEngine) RateBudget() *RateBudget {
+    return e.rateBudget
+}
+
+// Charge counts one request by userID. Once the user has made
+// Config.RateBudgetSize requests in the window that began with their first
+// one, it returns ErrRateBudgetExceeded and how long until the window
+// resets. A size of zero disables the budget. IDs that name no registered
+// user aren't charged, so made-up IDs can't fill the budget map; the
+// request they carry fails its own lookup instead.
+func (b *RateBudget) Charge(userID string) (time.Duration, error) {
+    if userID == "" {
+        return 0, nil
+    }
+    if _, err := b.e.GetUser(userID); err != nil {
+        return 0, nil
+    }
+    return b.charge(userID)
+}
+
+// ChargeOperator counts one operator request, such as a snapshot export.
+// Operators share a single budget; callers must already have
+// authenticated one.
+func (b *RateBudget) ChargeOperator() (time.Duration, error) {
+    return b.charge(operatorBudgetKey)
+}
+
+func (b *RateBudget) charge(key string) (time.Duration, error) {
+    size, window := b.e.config.RateBudgetSize, b.e.config.RateBudgetWindow
+    if size <= 0 || window <= 0 {
+        return 0, nil
+    }
+
+    now := b.e.clock.Now()
+    usageI, _ := b.users.LoadOrStore(key, &budgetWindow{start: now})
+    usage := usageI.(*budgetWindow)
+    usage.mtx.Lock()
+    defer usage.mtx.Unlock()
+    if now.Sub(usage.start) >= window {
+        usage.start = now
+        usage.used = 0
+    }
+    if usage.used >= size {
+        return usage.start.Add(window).Sub(now), ErrRateBudgetExceeded
+    }
+    usage.used++
+    return 0, nil
+}
+
+// sweep forgets users whose window has run out, since their next request
+// starts a fresh one anyway
+func (b *RateBudget) sweep() {
+    window := b.e.config.RateBudgetWindow
+    now := b.e.clock.Now()
+    b.users.Range(func(key, value interface{}) bool {
+        usage := value.(*budgetWindow)
+        usage.mtx.Lock()
+        expired := now.Sub(usage.start) >= window
+        usage.mtx.Unlock()
+        if expired {
+            b.users.CompareAndDelete(key, value)
+        }
+        return true
+    })
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+// internal/engine/ratebudget_test.go
+package engine
+
+import (
+    "errors"
+    "testing"
+    "time"
+)
+
+func TestRateBudgetResetsEachWindow(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.RateBudgetSize = 2
+    cfg.RateBudgetWindow = time.Minute
+    e, clock := newTestEngineWithConfig(t, cfg)
+    alice := mustRegister(t, e, "alice")
+
+    for i := 0; i < 2; i++ {
+        if _, err := e.RateBudget().Charge(alice.ID); err != nil {
+            t.Fatalf("charge %d: %v", i, err)
+        }
+    }
+    clock.Advance(20 * time.Second)
+    wait, err := e.RateBudget().Charge(alice.ID)
+    if !errors.Is(err, ErrRateBudgetExceeded) {
+        t.Fatalf("third charge: err = %v, want ErrRateBudgetExceeded", err)
+    }
+    if wait != 40*time.Second {
+        t.Errorf("wait = %v, want 40s", wait)
+    }
+
+    clock.Advance(40 * time.Second)
+    if _, err := e.RateBudget().Charge(alice.ID); err != nil {
+        t.Errorf("charge in the next window: %v", err)
+    }
+}
+
+func TestRateBudgetSkipsUnknownUsers(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.RateBudgetSize = 1
+    e, _ := newTestEngineWithConfig(t, cfg)
+
+    for i := 0; i < 3; i++ {
+        if _, err := e.RateBudget().Charge("made-up"); err != nil {
+            t.Fatalf("charge %d for an unknown user: %v", i, err)
+        }
+    }
+    if n := e.Stats().MapSizes["rate_budgets"]; n != 0 {
+        t.Errorf("rate_budgets has %d entries, want 0", n)
+    }
+
+    // Operators share one budget of their own
+    if _, err := e.RateBudget().ChargeOperator(); err != nil {
+        t.Fatalf("first operator charge: %v", err)
+    }
+    if _, err := e.RateBudget().ChargeOperator(); !errors.Is(err, ErrRateBudgetExceeded) {
+        t.Errorf("second operator charge: err = %v, want ErrRateBudgetExceeded", err)
+    }
+}
\ No newline at end of file
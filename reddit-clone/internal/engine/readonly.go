@@ -0,0 +1,48 @@
+// internal/engine/readonly.go
+package engine
+
+import "errors"
+
+var ErrReadOnly = errors.New("service in maintenance: writes are disabled")
+
+// SetReadOnly turns maintenance mode on or off. While it is on every
+// mutating method returns ErrReadOnly and reads keep working. Turning it
+// on waits for writes already in progress, so a snapshot taken afterwards
+// sees a consistent state.
+func (e *RedditEngine) SetReadOnly(readOnly bool) {
+    e.maintenanceMtx.Lock()
+    defer e.maintenanceMtx.Unlock()
+    e.readOnly = readOnly
+}
+
+// IsReadOnly reports whether the engine is in maintenance mode
+func (e *RedditEngine) IsReadOnly() bool {
+    e.maintenanceMtx.RLock()
+    defer e.maintenanceMtx.RUnlock()
+    return e.readOnly
+}
+
+// beginWrite admits a mutating call, returning ErrReadOnly in maintenance
+// mode. The caller must call done once its write is finished. Mutating
+// methods must not call each other while admitted, or a pending
+// SetReadOnly can deadlock them.
+func (e *RedditEngine) beginWrite() (done func(), err error) {
+    e.maintenanceMtx.RLock()
+    if e.readOnly {
+        e.maintenanceMtx.RUnlock()
+        return nil, ErrReadOnly
+    }
+    return e.maintenanceMtx.RUnlock, nil
+}
+
+// beginExclusiveWrite is beginWrite for a rare operation that must not
+// overlap any other write, such as a merge that replaces posts other
+// writers update. It waits for writes in progress and holds off new ones.
+func (e *RedditEngine) beginExclusiveWrite() (done func(), err error) {
+    e.maintenanceMtx.Lock()
+    if e.readOnly {
+        e.maintenanceMtx.Unlock()
+        return nil, ErrReadOnly
+    }
+    return e.maintenanceMtx.Unlock, nil
+}
\ No newline at end of file
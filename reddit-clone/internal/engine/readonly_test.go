@@ -0,0 +1,72 @@
+// internal/engine/readonly_test.go
+package engine
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestReadOnlyRefusesWritesAndServesReads(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+    mustComment(t, e, alice.ID, post.ID, nil)
+
+    e.SetReadOnly(true)
+    if !e.IsReadOnly() {
+        t.Fatal("IsReadOnly = false after SetReadOnly(true)")
+    }
+
+    writes := map[string]func() error{
+        "RegisterAccount": func() error {
+            _, err := e.RegisterAccount("bob", "password123")
+            return err
+        },
+        "CreateSubReddit": func() error {
+            _, err := e.CreateSubReddit("rust", "Another", alice.ID)
+            return err
+        },
+        "CreatePost": func() error {
+            _, err := e.CreatePost("Title", "Content", alice.ID, subreddit.ID)
+            return err
+        },
+        "CreateComment": func() error {
+            _, err := e.CreateComment("Reply", alice.ID, post.ID, nil)
+            return err
+        },
+        "Vote": func() error {
+            return e.Vote(alice.ID, post.ID, true)
+        },
+        "EditPost": func() error {
+            _, err := e.EditPost(alice.ID, post.ID, "Edited", "Edited", AnyVersion)
+            return err
+        },
+        "LeaveSubReddit": func() error {
+            return e.LeaveSubReddit(alice.ID, subreddit.ID)
+        },
+    }
+    for name, write := range writes {
+        if err := write(); !errors.Is(err, ErrReadOnly) {
+            t.Errorf("%s in maintenance: err = %v, want ErrReadOnly", name, err)
+        }
+    }
+
+    if _, err := e.GetPost(post.ID); err != nil {
+        t.Errorf("GetPost in maintenance: %v", err)
+    }
+    if comments, err := e.GetComments(post.ID); err != nil || len(comments) != 1 {
+        t.Errorf("GetComments in maintenance = %d comments, %v; want 1", len(comments), err)
+    }
+    if feed, err := e.GetFeed(alice.ID); err != nil || len(feed) != 1 {
+        t.Errorf("GetFeed in maintenance = %d posts, %v; want 1", len(feed), err)
+    }
+    if stats := e.Stats(); stats.Counts.TotalUsers != 1 || stats.Counts.TotalPosts != 1 {
+        t.Errorf("counts changed in maintenance: %+v", stats.Counts)
+    }
+
+    e.SetReadOnly(false)
+    if _, err := e.CreatePost("Title", "Content", alice.ID, subreddit.ID); err != nil {
+        t.Errorf("CreatePost after maintenance: %v", err)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,157 @@
+// internal/engine/reports.go
+package engine
+
+import (
+    "errors"
+    "sort"
+
+    "reddit-clone/internal/models"
+)
+
+var (
+    ErrNotModerator     = errors.New("user is not a moderator of this subreddit")
+    ErrAlreadyReported  = errors.New("target already reported by this user")
+    ErrReportNotFound   = errors.New("report not found")
+    ErrReportNotPending = errors.New("report has already been closed")
+)
+
+// isModerator reports whether the user moderates the subreddit
+func isModerator(userID string, subreddit *models.SubReddit) bool {
+    _, ok := subreddit.Moderators.Load(userID)
+    return ok
+}
+
+// Report flags a post or comment for the moderators of its subreddit
+func (e *RedditEngine) Report(userID, targetID, reason string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    if _, exists := e.users.Load(userID); !exists {
+        return errors.New("user not found")
+    }
+    if reason == "" {
+        return errors.New("report reason is required")
+    }
+
+    report := &models.Report{
+        ReporterID: userID,
+        TargetID:   targetID,
+        Reason:     reason,
+        CreatedAt:  e.clock.Now(),
+        Status:     models.ReportPending,
+    }
+
+    // Resolve the target and the subreddit it belongs to
+    if postI, isPost := e.posts.Load(targetID); isPost {
+        report.TargetType = "post"
+        report.SubRedditID = postI.(*models.Post).SubRedditID
+    } else if commentI, isComment := e.comments.Load(targetID); isComment {
+        postI, ok := e.posts.Load(commentI.(*models.Comment).PostID)
+        if !ok {
+            return errors.New("post not found")
+        }
+        report.TargetType = "comment"
+        report.SubRedditID = postI.(*models.Post).SubRedditID
+    } else {
+        return errors.New("target not found")
+    }
+    if err := e.checkCanSeeTarget(userID, targetID); err != nil {
+        return err
+    }
+
+    report.ID = e.generateID()
+    if _, loaded := e.reports.LoadOrStore(userID+":"+targetID, report); loaded {
+        return ErrAlreadyReported
+    }
+    return nil
+}
+
+// GetReports returns the pending reports for a subreddit, oldest first;
+// only its moderators may read them
+func (e *RedditEngine) GetReports(modUserID, subredditID string) ([]*models.Report, error) {
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(modUserID, subreddit) {
+        return nil, ErrNotModerator
+    }
+
+    var reports []*models.Report
+    e.reports.Range(func(_, value interface{}) bool {
+        report := value.(*models.Report)
+        if report.SubRedditID == subredditID && report.Status == models.ReportPending {
+            reports = append(reports, report)
+        }
+        return true
+    })
+    sort.Slice(reports, func(i, j int) bool {
+        return reports[i].CreatedAt.Before(reports[j].CreatedAt)
+    })
+    return reports, nil
+}
+
+// ResolveReport closes a pending report after a moderator of its
+// subreddit has acted on the reported content
+func (e *RedditEngine) ResolveReport(modID, reportID string) (*models.Report, error) {
+    return e.closeReport(modID, reportID, models.ReportResolved)
+}
+
+// DismissReport closes a pending report a moderator of its subreddit
+// found no fault with
+func (e *RedditEngine) DismissReport(modID, reportID string) (*models.Report, error) {
+    return e.closeReport(modID, reportID, models.ReportDismissed)
+}
+
+// closeReport takes a report out of the mod queue. The closed report stays
+// on record, so its reporter still can't report the same target again.
+// Reports are replaced rather than changed in place, since GetReports
+// reads them without a lock.
+func (e *RedditEngine) closeReport(modID, reportID, status string) (*models.Report, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    key, report := e.findReport(reportID)
+    if report == nil {
+        return nil, ErrReportNotFound
+    }
+    subreddit, err := e.GetSubReddit(report.SubRedditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(modID, subreddit) {
+        return nil, ErrNotModerator
+    }
+    if report.Status != models.ReportPending {
+        return nil, ErrReportNotPending
+    }
+
+    closed := *report
+    closedAt := e.clock.Now()
+    closed.Status = status
+    closed.ClosedBy = modID
+    closed.ClosedAt = &closedAt
+    // Another moderator may have closed it first
+    if !e.reports.CompareAndSwap(key, report, &closed) {
+        return nil, ErrReportNotPending
+    }
+    return &closed, nil
+}
+
+// findReport looks a report up by ID, returning its key in e.reports
+func (e *RedditEngine) findReport(reportID string) (key interface{}, report *models.Report) {
+    e.reports.Range(func(k, value interface{}) bool {
+        if candidate := value.(*models.Report); candidate.ID == reportID {
+            key, report = k, candidate
+            return false
+        }
+        return true
+    })
+    return key, report
+}
\ No newline at end of file
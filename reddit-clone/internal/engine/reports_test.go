@@ -0,0 +1,98 @@
+// internal/engine/reports_test.go
+package engine
+
+import (
+    "errors"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+func TestReportsReachOnlyModerators(t *testing.T) {
+    e, clock := newTestEngine(t)
+    mod := mustRegister(t, e, "moderator")
+    reporter := mustRegister(t, e, "reporter")
+    subreddit := mustCreateSubreddit(t, e, "golang", mod.ID)
+    mustJoin(t, e, reporter.ID, subreddit.ID)
+    post := mustPost(t, e, reporter.ID, subreddit.ID)
+    comment := mustComment(t, e, reporter.ID, post.ID, nil)
+
+    if err := e.Report(reporter.ID, post.ID, "spam"); err != nil {
+        t.Fatalf("Report post: %v", err)
+    }
+    clock.Advance(time.Minute)
+    if err := e.Report(reporter.ID, comment.ID, "rude"); err != nil {
+        t.Fatalf("Report comment: %v", err)
+    }
+    if err := e.Report(reporter.ID, post.ID, "spam again"); !errors.Is(err, ErrAlreadyReported) {
+        t.Errorf("second report err = %v, want ErrAlreadyReported", err)
+    }
+    if err := e.Report(reporter.ID, "missing", "spam"); err == nil {
+        t.Error("report of an unknown target was accepted")
+    }
+
+    if _, err := e.GetReports(reporter.ID, subreddit.ID); !errors.Is(err, ErrNotModerator) {
+        t.Errorf("non-moderator GetReports err = %v, want ErrNotModerator", err)
+    }
+    reports, err := e.GetReports(mod.ID, subreddit.ID)
+    if err != nil {
+        t.Fatalf("GetReports: %v", err)
+    }
+    if len(reports) != 2 {
+        t.Fatalf("got %d reports, want 2", len(reports))
+    }
+    if reports[0].TargetType != "post" || reports[1].TargetType != "comment" {
+        t.Errorf("report targets = %s, %s; want post then comment", reports[0].TargetType, reports[1].TargetType)
+    }
+    for _, report := range reports {
+        if report.Status != models.ReportPending || report.ReporterID != reporter.ID {
+            t.Errorf("report = %+v, want a pending report by the reporter", *report)
+        }
+    }
+}
+
+func TestClosingReportsEmptiesTheQueue(t *testing.T) {
+    e, _ := newTestEngine(t)
+    mod := mustRegister(t, e, "moderator")
+    reporter := mustRegister(t, e, "reporter")
+    subreddit := mustCreateSubreddit(t, e, "golang", mod.ID)
+    mustJoin(t, e, reporter.ID, subreddit.ID)
+    first := mustPost(t, e, reporter.ID, subreddit.ID)
+    second := mustPost(t, e, reporter.ID, subreddit.ID)
+    for _, target := range []string{first.ID, second.ID} {
+        if err := e.Report(reporter.ID, target, "spam"); err != nil {
+            t.Fatalf("Report: %v", err)
+        }
+    }
+    reports, _ := e.GetReports(mod.ID, subreddit.ID)
+
+    if _, err := e.ResolveReport(reporter.ID, reports[0].ID); !errors.Is(err, ErrNotModerator) {
+        t.Errorf("non-moderator ResolveReport err = %v, want ErrNotModerator", err)
+    }
+    resolved, err := e.ResolveReport(mod.ID, reports[0].ID)
+    if err != nil {
+        t.Fatalf("ResolveReport: %v", err)
+    }
+    if resolved.Status != models.ReportResolved || resolved.ClosedBy != mod.ID || resolved.ClosedAt == nil {
+        t.Errorf("resolved report = %+v", *resolved)
+    }
+    if _, err := e.DismissReport(mod.ID, reports[0].ID); !errors.Is(err, ErrReportNotPending) {
+        t.Errorf("closing twice err = %v, want ErrReportNotPending", err)
+    }
+    dismissed, err := e.DismissReport(mod.ID, reports[1].ID)
+    if err != nil || dismissed.Status != models.ReportDismissed {
+        t.Fatalf("DismissReport = %v, %v", dismissed, err)
+    }
+    if _, err := e.DismissReport(mod.ID, "missing"); !errors.Is(err, ErrReportNotFound) {
+        t.Errorf("unknown report err = %v, want ErrReportNotFound", err)
+    }
+
+    if pending, _ := e.GetReports(mod.ID, subreddit.ID); len(pending) != 0 {
+        t.Errorf("%d reports still pending, want 0", len(pending))
+    }
+    // A closed report still stops the same user reporting the target again
+    if err := e.Report(reporter.ID, first.ID, "spam"); !errors.Is(err, ErrAlreadyReported) {
+        t.Errorf("re-report err = %v, want ErrAlreadyReported", err)
+    }
+}
\ No newline at end of file
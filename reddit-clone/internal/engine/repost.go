@@ -0,0 +1,33 @@
+// internal/engine/repost.go
+package engine
+
+import "reddit-clone/internal/models"
+
+// RepostPost shares a post the user can see into one of their subreddits
+// under their own name. The repost points at the original post, never at
+// another repost, so feeds can collapse an original and all its reposts.
+// It is checked like any new post by the user.
+func (e *RedditEngine) RepostPost(userID, postID, subredditID string) (*models.Post, error) {
+    original, err := e.GetPost(postID)
+    if err != nil {
+        return nil, err
+    }
+    if err := e.checkCanSeeTarget(userID, postID); err != nil {
+        return nil, err
+    }
+    if e.isScheduled(postID) {
+        return nil, ErrPostScheduled
+    }
+    if original.Pending {
+        return nil, ErrPostPending
+    }
+    return e.CreatePostWithOptions(original.Title, original.Content, userID, subredditID, PostOptions{repostOf: original})
+}
+
+// originalPostID is the post a repost of post should point at
+func originalPostID(post *models.Post) string {
+    if post.OriginalID != "" {
+        return post.OriginalID
+    }
+    return post.ID
+}
\ No newline at end of file
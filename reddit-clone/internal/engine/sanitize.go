@@ -0,0 +1,30 @@
+// internal/engine/sanitize.go
+package engine
+
+import "reddit-clone/pkg/sanitize"
+
+// storedContent returns post or comment content as it should be stored:
+// sanitized, unless Config.SanitizeOnRender leaves that to RenderContent
+func (e *RedditEngine) storedContent(content string) string {
+    if e.config.SanitizeOnRender {
+        return content
+    }
+    return sanitize.Markdown(content)
+}
+
+// RenderTitle returns a post title ready to display. Titles are stored as
+// written, since slugs and duplicate detection are derived from them, so
+// they are sanitized here whatever Config.SanitizeOnRender says.
+func (e *RedditEngine) RenderTitle(title string) string {
+    return sanitize.Markdown(title)
+}
+
+// RenderContent returns stored post or comment content ready to display.
+// With Config.SanitizeOnRender it sanitizes the raw content; otherwise the
+// content was sanitized before it was stored and is returned unchanged.
+func (e *RedditEngine) RenderContent(content string) string {
+    if e.config.SanitizeOnRender {
+        return sanitize.Markdown(content)
+    }
+    return content
+}
\ No newline at end of file
@@ -0,0 +1,116 @@
+// internal/engine/scheduled.go
+package engine
+
+import (
+    "errors"
+    "sort"
+
+    "reddit-clone/internal/models"
+)
+
+var (
+    ErrPostScheduled    = errors.New("post is scheduled and not yet published")
+    ErrPostNotScheduled = errors.New("post is not scheduled")
+)
+
+// isScheduled reports whether the post is waiting for its publication time
+func (e *RedditEngine) isScheduled(postID string) bool {
+    _, ok := e.scheduledPosts.Load(postID)
+    return ok
+}
+
+// scheduledPostList returns the scheduled posts matching keep
+func (e *RedditEngine) scheduledPostList(keep func(*models.Post) bool) []*models.Post {
+    var posts []*models.Post
+    e.scheduledPosts.Range(func(_, value interface{}) bool {
+        if post := value.(*models.Post); keep(post) {
+            posts = append(posts, post)
+        }
+        return true
+    })
+    return posts
+}
+
+// GetScheduledPosts returns the user's posts still waiting to be
+// published, soonest first
+func (e *RedditEngine) GetScheduledPosts(userID string) ([]*models.Post, error) {
+    if _, err := e.GetUser(userID); err != nil {
+        return nil, err
+    }
+
+    posts := e.scheduledPostList(func(post *models.Post) bool {
+        return post.AuthorID == userID
+    })
+    sort.Slice(posts, func(i, j int) bool {
+        return posts[i].ScheduledFor.Before(*posts[j].ScheduledFor)
+    })
+    return posts, nil
+}
+
+// CancelScheduledPost deletes one of the user's scheduled posts before it
+// is published
+func (e *RedditEngine) CancelScheduledPost(userID, postID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    postI, ok := e.scheduledPosts.Load(postID)
+    if !ok {
+        if _, err := e.GetPost(postID); err != nil {
+            return err
+        }
+        return ErrPostNotScheduled
+    }
+    if postI.(*models.Post).AuthorID != userID {
+        return ErrNotAuthor
+    }
+    // Only one of a concurrent cancel and publish gets past here
+    if _, ok := e.scheduledPosts.LoadAndDelete(postID); !ok {
+        return ErrPostNotScheduled
+    }
+
+    removed := make(map[string]bool)
+    if err := e.removePostAndComments(postID, removed); err != nil {
+        return err
+    }
+    e.removeTargetRecords(removed)
+    return nil
+}
+
+// publishScheduledPosts publishes every scheduled post whose time has
+// come. A post whose subreddit was deleted meanwhile is dropped. It skips
+// the run in maintenance mode, leaving the posts for the next one.
+func (e *RedditEngine) publishScheduledPosts() {
+    done, err := e.beginWrite()
+    if err != nil {
+        return
+    }
+    defer done()
+
+    now := e.clock.Now()
+    due := e.scheduledPostList(func(post *models.Post) bool {
+        return !post.ScheduledFor.After(now)
+    })
+    sort.Slice(due, func(i, j int) bool {
+        return due[i].ScheduledFor.Before(*due[j].ScheduledFor)
+    })
+    for _, post := range due {
+        if _, ok := e.scheduledPosts.LoadAndDelete(post.ID); !ok {
+            continue
+        }
+        if _, err := e.GetSubReddit(post.SubRedditID); err != nil {
+            removed := make(map[string]bool)
+            if e.removePostAndComments(post.ID, removed) == nil {
+                e.removeTargetRecords(removed)
+            }
+            continue
+        }
+        if post.Pending {
+            e.indexPendingPost(post)
+            continue
+        }
+        e.announcePost(post)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,74 @@
+// internal/engine/scheduled_test.go
+package engine
+
+import (
+    "testing"
+    "time"
+)
+
+func TestScheduledPostPublishesWhenDue(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    mustJoin(t, e, bob.ID, subreddit.ID)
+
+    at := testStart.Add(time.Hour)
+    post, err := e.CreatePostWithOptions("Later", "Not yet", alice.ID, subreddit.ID, PostOptions{ScheduledFor: &at})
+    if err != nil {
+        t.Fatalf("CreatePostWithOptions: %v", err)
+    }
+
+    visible := func() bool {
+        t.Helper()
+        feed, err := e.GetFeed(bob.ID)
+        if err != nil {
+            t.Fatalf("GetFeed: %v", err)
+        }
+        listed, err := e.ListPosts(bob.ID, subreddit.ID)
+        if err != nil {
+            t.Fatalf("ListPosts: %v", err)
+        }
+        popular, err := e.GetPopularPosts(24*time.Hour, 10)
+        if err != nil {
+            t.Fatalf("GetPopularPosts: %v", err)
+        }
+        inFeed, inList, inPopular := containsPost(feed, post.ID), containsPost(listed, post.ID), containsPost(popular, post.ID)
+        if inFeed != inList || inList != inPopular {
+            t.Fatalf("post listed in feed=%v listing=%v popular=%v, want all or none", inFeed, inList, inPopular)
+        }
+        return inFeed
+    }
+
+    if visible() {
+        t.Fatal("scheduled post is listed before its time")
+    }
+    if _, err := e.CreateComment("Early", bob.ID, post.ID, nil); err != ErrPostScheduled {
+        t.Errorf("commenting on a scheduled post: err = %v, want ErrPostScheduled", err)
+    }
+    if err := e.Vote(bob.ID, post.ID, true); err != ErrPostScheduled {
+        t.Errorf("voting on a scheduled post: err = %v, want ErrPostScheduled", err)
+    }
+    if scheduled, _ := e.GetScheduledPosts(alice.ID); len(scheduled) != 1 || scheduled[0].ID != post.ID {
+        t.Errorf("GetScheduledPosts = %v, want the post", scheduled)
+    }
+
+    // A sweep before the time leaves it scheduled
+    clock.Advance(59 * time.Minute)
+    e.publishScheduledPosts()
+    if visible() {
+        t.Fatal("scheduled post published a minute early")
+    }
+
+    clock.Advance(time.Minute)
+    e.publishScheduledPosts()
+    if !visible() {
+        t.Fatal("scheduled post isn't listed once due")
+    }
+    if scheduled, _ := e.GetScheduledPosts(alice.ID); len(scheduled) != 0 {
+        t.Errorf("GetScheduledPosts = %v after publishing, want none", scheduled)
+    }
+    if _, err := e.CreateComment("Now", bob.ID, post.ID, nil); err != nil {
+        t.Errorf("commenting on the published post: %v", err)
+    }
+}
\ No newline at end of file
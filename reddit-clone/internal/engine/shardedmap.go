@@ -0,0 +1,66 @@
+// internal/engine/shardedmap.go
+package engine
+
+import "sync"
+
+// shardedMap spreads its entries over several sync.Maps chosen by key hash,
+// so concurrent inserts and deletes contend on one shard's lock instead of
+// a single map's. It offers the subset of the sync.Map API the engine uses.
+type shardedMap struct {
+    shards []sync.Map
+}
+
+// newShardedMap creates a map with n shards; n below 1 means one shard
+func newShardedMap(n int) *shardedMap {
+    if n < 1 {
+        n = 1
+    }
+    return &shardedMap{shards: make([]sync.Map, n)}
+}
+
+// shard returns the map owning key. The FNV-1a hash is inlined so the
+// lookup doesn't allocate a hash.Hash32.
+func (m *shardedMap) shard(key string) *sync.Map {
+    if len(m.shards) == 1 {
+        return &m.shards[0]
+    }
+    h := uint32(2166136261)
+    for i := 0; i < len(key); i++ {
+        h ^= uint32(key[i])
+        h *= 16777619
+    }
+    return &m.shards[h%uint32(len(m.shards))]
+}
+
+// Load returns the value stored for key
+func (m *shardedMap) Load(key string) (interface{}, bool) {
+    return m.shard(key).Load(key)
+}
+
+// Store sets the value for key
+func (m *shardedMap) Store(key string, value interface{}) {
+    m.shard(key).Store(key, value)
+}
+
+// LoadAndDelete deletes key and returns its previous value, if any
+func (m *shardedMap) LoadAndDelete(key string) (interface{}, bool) {
+    return m.shard(key).LoadAndDelete(key)
+}
+
+// Range calls f for every entry, one shard after another, until f returns
+// false. Like sync.Map.Range it doesn't see a consistent snapshot.
+func (m *shardedMap) Range(f func(key, value interface{}) bool) {
+    for i := range m.shards {
+        stopped := false
+        m.shards[i].Range(func(key, value interface{}) bool {
+            if !f(key, value) {
+                stopped = true
+                return false
+            }
+            return true
+        })
+        if stopped {
+            return
+        }
+    }
+}
\ No newline at end of file
@@ -0,0 +1,75 @@
+// internal/engine/shardedmap_test.go
+package engine
+
+import (
+    "fmt"
+    "runtime"
+    "strconv"
+    "sync/atomic"
+    "testing"
+)
+
+// shardBenchGoroutines is how many goroutines BenchmarkShardedMap runs,
+// the same as the harness's post store benchmark
+const shardBenchGoroutines = 64
+
+func TestShardedMap(t *testing.T) {
+    m := newShardedMap(8)
+    for i := 0; i < 100; i++ {
+        m.Store(strconv.Itoa(i), i)
+    }
+
+    if v, ok := m.Load("42"); !ok || v.(int) != 42 {
+        t.Errorf("Load(42) = %v, %v; want 42, true", v, ok)
+    }
+    if v, ok := m.LoadAndDelete("42"); !ok || v.(int) != 42 {
+        t.Errorf("LoadAndDelete(42) = %v, %v; want 42, true", v, ok)
+    }
+    if _, ok := m.Load("42"); ok {
+        t.Error("Load found a deleted key")
+    }
+
+    seen := 0
+    m.Range(func(_, _ interface{}) bool {
+        seen++
+        return true
+    })
+    if seen != 99 {
+        t.Errorf("Range visited %d entries, want 99", seen)
+    }
+
+    seen = 0
+    m.Range(func(_, _ interface{}) bool {
+        seen++
+        return seen < 10
+    })
+    if seen != 10 {
+        t.Errorf("Range visited %d entries after being stopped at 10", seen)
+    }
+}
+
+// BenchmarkShardedMap compares the post store at DefaultPostShards with
+// one shard, which is a single sync.Map, under shardBenchGoroutines
+// goroutines each storing new keys and reading them back
+func BenchmarkShardedMap(b *testing.B) {
+    procs := runtime.GOMAXPROCS(0)
+    parallelism := (shardBenchGoroutines + procs - 1) / procs
+    for _, shards := range []int{1, DefaultPostShards} {
+        b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+            m := newShardedMap(shards)
+            var seq atomic.Int64
+            b.SetParallelism(parallelism)
+            b.ReportAllocs()
+            b.RunParallel(func(pb *testing.PB) {
+                for pb.Next() {
+                    key := strconv.FormatInt(seq.Add(1), 36)
+                    m.Store(key, key)
+                    if _, ok := m.Load(key); !ok {
+                        b.Error("stored key not found")
+                        return
+                    }
+                }
+            })
+        })
+    }
+}
\ No newline at end of file
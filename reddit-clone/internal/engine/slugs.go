@@ -0,0 +1,130 @@
+// internal/engine/slugs.go
+package engine
+
+import (
+    "errors"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "unicode"
+
+    "reddit-clone/internal/models"
+)
+
+// maxSlugLength caps the title-derived part of a slug, before any collision suffix
+const maxSlugLength = 60
+
+// fallbackSlug is used when a title has no letters or digits at all
+const fallbackSlug = "post"
+
+// slugIndex maps a subreddit's post slugs to post IDs. Slugs are never
+// released, so a permalink can't be reused by a later post.
+type slugIndex struct {
+    mtx   sync.Mutex
+    slugs map[string]string // map[slug]postID
+    next  map[string]int    // map[base slug]first suffix worth trying
+}
+
+// slugify lowercases a title and joins its runs of letters and digits with
+// hyphens, dropping punctuation, symbols and emoji
+func slugify(title string) string {
+    var b strings.Builder
+    pendingHyphen := false
+    for _, r := range strings.ToLower(title) {
+        if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+            pendingHyphen = b.Len() > 0
+            continue
+        }
+        if pendingHyphen {
+            if b.Len()+1 >= maxSlugLength {
+                break
+            }
+            b.WriteByte('-')
+            pendingHyphen = false
+        }
+        if b.Len()+len(string(r)) > maxSlugLength {
+            break
+        }
+        b.WriteRune(r)
+    }
+    if b.Len() == 0 {
+        return fallbackSlug
+    }
+    return b.String()
+}
+
+// assignSlug gives the post a slug unique within its subreddit, appending
+// -2, -3, ... when the title's slug is already taken
+func (e *RedditEngine) assignSlug(post *models.Post) {
+    idx := e.subredditSlugIndex(post.SubRedditID)
+    base := slugify(post.Title)
+
+    idx.mtx.Lock()
+    defer idx.mtx.Unlock()
+    // Start past the suffixes already handed out, so many posts with one
+    // title, such as reposts, don't rescan them all
+    slug := base
+    n := max(idx.next[base], 2)
+    for ; ; n++ {
+        if _, taken := idx.slugs[slug]; !taken {
+            break
+        }
+        slug = base + "-" + strconv.Itoa(n)
+    }
+    idx.next[base] = n
+    idx.slugs[slug] = post.ID
+    post.Slug = slug
+}
+
+// reserveSlug records an already assigned slug, as when restoring a snapshot
+func (e *RedditEngine) reserveSlug(post *models.Post) {
+    idx := e.subredditSlugIndex(post.SubRedditID)
+    idx.mtx.Lock()
+    idx.slugs[post.Slug] = post.ID
+    idx.mtx.Unlock()
+}
+
+// indexSlugs reserves the slugs of restored posts and assigns slugs, oldest
+// first, to posts saved before slugs existed
+func (e *RedditEngine) indexSlugs(posts []models.Post) {
+    var unslugged []*models.Post
+    for i := range posts {
+        if posts[i].Slug == "" {
+            unslugged = append(unslugged, &posts[i])
+            continue
+        }
+        e.reserveSlug(&posts[i])
+    }
+    sort.Slice(unslugged, func(i, j int) bool {
+        return unslugged[i].CreatedAt.Before(unslugged[j].CreatedAt)
+    })
+    for _, post := range unslugged {
+        e.assignSlug(post)
+    }
+}
+
+func (e *RedditEngine) subredditSlugIndex(subredditID string) *slugIndex {
+    idxI, _ := e.subredditSlugs.LoadOrStore(subredditID, &slugIndex{slugs: make(map[string]string), next: make(map[string]int)})
+    return idxI.(*slugIndex)
+}
+
+// GetPostBySlug resolves a post permalink within a subreddit
+func (e *RedditEngine) GetPostBySlug(subredditID, slug string) (*models.Post, error) {
+    if _, ok := e.subreddits.Load(subredditID); !ok {
+        return nil, ErrSubredditNotFound
+    }
+
+    idxI, ok := e.subredditSlugs.Load(subredditID)
+    if !ok {
+        return nil, errors.New("post not found")
+    }
+    idx := idxI.(*slugIndex)
+    idx.mtx.Lock()
+    postID, ok := idx.slugs[slug]
+    idx.mtx.Unlock()
+    if !ok {
+        return nil, errors.New("post not found")
+    }
+    return e.GetPost(postID)
+}
\ No newline at end of file
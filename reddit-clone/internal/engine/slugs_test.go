@@ -0,0 +1,95 @@
+// internal/engine/slugs_test.go
+package engine
+
+import (
+    "strings"
+    "testing"
+
+    "reddit-clone/internal/models"
+)
+
+func TestSlugify(t *testing.T) {
+    tests := []struct {
+        title string
+        want  string
+    }{
+        {"Hello World", "hello-world"},
+        {"  Go 1.23 is out!  ", "go-1-23-is-out"},
+        {"What's new -- in   Go?", "what-s-new-in-go"},
+        {"Café au lait", "café-au-lait"},
+        {"🚀 Launch day 🚀", "launch-day"},
+        {"?!...", fallbackSlug},
+        {"🎉🎉", fallbackSlug},
+        {"", fallbackSlug},
+    }
+    for _, tt := range tests {
+        if got := slugify(tt.title); got != tt.want {
+            t.Errorf("slugify(%q) = %q, want %q", tt.title, got, tt.want)
+        }
+    }
+
+    // Long titles are cut at a word boundary, without a trailing hyphen
+    long := slugify(strings.Repeat("abcdefghi ", 20))
+    if len(long) > maxSlugLength || strings.HasSuffix(long, "-") {
+        t.Errorf("slugify(long title) = %q, want at most %d bytes and no trailing hyphen", long, maxSlugLength)
+    }
+}
+
+func TestAssignSlugDedupesWithinSubreddit(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    golang := mustCreateSubreddit(t, e, "golang", alice.ID)
+    rust := mustCreateSubreddit(t, e, "rust", alice.ID)
+
+    var slugs []string
+    for range 3 {
+        post, err := e.CreatePost("Release notes", "Test content", alice.ID, golang.ID)
+        if err != nil {
+            t.Fatalf("CreatePost: %v", err)
+        }
+        slugs = append(slugs, post.Slug)
+    }
+    if want := []string{"release-notes", "release-notes-2", "release-notes-3"}; strings.Join(slugs, ",") != strings.Join(want, ",") {
+        t.Errorf("slugs = %v, want %v", slugs, want)
+    }
+
+    // A title whose own slug looks like a suffixed one still gets a fresh slug
+    post, err := e.CreatePost("Release notes 2", "Test content", alice.ID, golang.ID)
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    if post.Slug != "release-notes-2-2" {
+        t.Errorf("slug = %q, want %q", post.Slug, "release-notes-2-2")
+    }
+
+    // Slugs are only unique per subreddit
+    other, err := e.CreatePost("Release notes", "Test content", alice.ID, rust.ID)
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    if other.Slug != "release-notes" {
+        t.Errorf("slug in another subreddit = %q, want %q", other.Slug, "release-notes")
+    }
+}
+
+func TestGetPostBySlug(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    first, _ := e.CreatePost("Release notes", "Test content", alice.ID, subreddit.ID)
+    second, _ := e.CreatePost("Release notes", "Test content", alice.ID, subreddit.ID)
+
+    for _, want := range []*models.Post{first, second} {
+        got, err := e.GetPostBySlug(subreddit.ID, want.Slug)
+        if err != nil || got.ID != want.ID {
+            t.Errorf("GetPostBySlug(%q) = %v, %v; want post %s", want.Slug, got, err, want.ID)
+        }
+    }
+
+    if _, err := e.GetPostBySlug(subreddit.ID, "missing"); err == nil {
+        t.Error("GetPostBySlug with an unknown slug succeeded")
+    }
+    if _, err := e.GetPostBySlug("missing", first.Slug); err != ErrSubredditNotFound {
+        t.Errorf("GetPostBySlug in an unknown subreddit = %v, want ErrSubredditNotFound", err)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,36 @@
+// internal/engine/stats.go
+package engine
+
+import (
+    "reddit-clone/internal/models"
+)
+
+// GetSubredditStats computes live statistics for a single subreddit
+func (e *RedditEngine) GetSubredditStats(subredditID string) (*models.SubredditMetrics, error) {
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return nil, err
+    }
+
+    stats := &models.SubredditMetrics{Name: subreddit.Name}
+
+    subreddit.Members.Range(func(_, _ interface{}) bool {
+        stats.MemberCount++
+        return true
+    })
+
+    // Posts come from the subreddit index and their comments from each
+    // post's comment index
+    for _, post := range e.subredditPostList(subredditID) {
+        stats.PostCount++
+        upvotes, downvotes := post.Votes()
+        stats.VoteCount += upvotes + downvotes
+        for _, comment := range e.postCommentList(post.ID) {
+            stats.CommentCount++
+            upvotes, downvotes := comment.Votes()
+            stats.VoteCount += upvotes + downvotes
+        }
+    }
+
+    return stats, nil
+}
\ No newline at end of file
@@ -0,0 +1,110 @@
+// internal/engine/stats_test.go
+package engine
+
+import (
+    "errors"
+    "testing"
+
+    "reddit-clone/internal/models"
+)
+
+func TestGetSubredditStats(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    mustJoin(t, e, bob.ID, subreddit.ID)
+
+    first := mustPost(t, e, alice.ID, subreddit.ID)
+    second := mustPost(t, e, bob.ID, subreddit.ID)
+    comment := mustComment(t, e, bob.ID, first.ID, nil)
+    mustComment(t, e, alice.ID, first.ID, &comment.ID)
+    mustComment(t, e, alice.ID, second.ID, nil)
+    mustVote(t, e, bob.ID, first.ID, VoteUp)
+    mustVote(t, e, alice.ID, second.ID, VoteDown)
+    mustVote(t, e, alice.ID, comment.ID, VoteUp)
+
+    // Content elsewhere must not be counted
+    other := mustCreateSubreddit(t, e, "rust", bob.ID)
+    otherPost := mustPost(t, e, bob.ID, other.ID)
+    mustComment(t, e, bob.ID, otherPost.ID, nil)
+    mustVote(t, e, alice.ID, otherPost.ID, VoteUp)
+
+    stats, err := e.GetSubredditStats(subreddit.ID)
+    if err != nil {
+        t.Fatalf("GetSubredditStats: %v", err)
+    }
+    if stats.Name != "golang" || stats.MemberCount != 2 || stats.PostCount != 2 || stats.CommentCount != 3 || stats.VoteCount != 3 {
+        t.Errorf("stats = %+v, want golang with 2 members, 2 posts, 3 comments and 3 votes", *stats)
+    }
+}
+
+func TestGetSubredditStatsUnknownSubreddit(t *testing.T) {
+    e, _ := newTestEngine(t)
+    if _, err := e.GetSubredditStats("missing"); !errors.Is(err, ErrSubredditNotFound) {
+        t.Errorf("err = %v, want ErrSubredditNotFound", err)
+    }
+}
+
+func TestGlobalStatsMatchTheStores(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    golang := mustCreateSubreddit(t, e, "golang", alice.ID)
+    rust := mustCreateSubreddit(t, e, "rust", bob.ID)
+    mustJoin(t, e, bob.ID, golang.ID)
+
+    kept := mustPost(t, e, alice.ID, golang.ID)
+    removed := mustPost(t, e, bob.ID, golang.ID)
+    comment := mustComment(t, e, bob.ID, kept.ID, nil)
+    mustComment(t, e, alice.ID, kept.ID, &comment.ID)
+    mustComment(t, e, alice.ID, removed.ID, nil)
+    mustVote(t, e, bob.ID, kept.ID, VoteUp)
+    mustVote(t, e, bob.ID, kept.ID, VoteDown) // Changing a vote adds none
+    mustVote(t, e, alice.ID, comment.ID, VoteUp)
+    mustVote(t, e, alice.ID, removed.ID, VoteUp)
+    mustVote(t, e, bob.ID, comment.ID, VoteUp)
+    mustVote(t, e, bob.ID, comment.ID, VoteNone)
+    if _, err := e.SendDirectMessage(alice.ID, bob.ID, "hi"); err != nil {
+        t.Fatalf("SendDirectMessage: %v", err)
+    }
+    rustPost := mustPost(t, e, bob.ID, rust.ID)
+    mustComment(t, e, bob.ID, rustPost.ID, nil)
+
+    check := func(want models.GlobalStats) {
+        t.Helper()
+        got, err := e.GlobalStats()
+        if err != nil {
+            t.Fatalf("GlobalStats: %v", err)
+        }
+        if *got != want {
+            t.Errorf("GlobalStats = %+v, want %+v", *got, want)
+        }
+        // The running totals never drift from what a full scan finds
+        sizes := e.Stats().MapSizes
+        scanned := models.GlobalStats{
+            TotalUsers:      int64(sizes["users"]),
+            TotalSubreddits: int64(sizes["subreddits"]),
+            TotalPosts:      int64(sizes["posts"]),
+            TotalComments:   int64(sizes["comments"]),
+            TotalVotes:      int64(sizes["votes"]),
+            TotalMessages:   int64(sizes["messages"]),
+        }
+        if *got != scanned {
+            t.Errorf("GlobalStats = %+v, but the stores hold %+v", *got, scanned)
+        }
+    }
+
+    check(models.GlobalStats{TotalUsers: 2, TotalSubreddits: 2, TotalPosts: 3, TotalComments: 4, TotalVotes: 3, TotalMessages: 1})
+
+    // Removing a post takes its comments and votes with it
+    if err := e.RemovePost(removed.ID); err != nil {
+        t.Fatalf("RemovePost: %v", err)
+    }
+    check(models.GlobalStats{TotalUsers: 2, TotalSubreddits: 2, TotalPosts: 2, TotalComments: 3, TotalVotes: 2, TotalMessages: 1})
+
+    if err := e.DeleteSubReddit(bob.ID, rust.ID); err != nil {
+        t.Fatalf("DeleteSubReddit: %v", err)
+    }
+    check(models.GlobalStats{TotalUsers: 2, TotalSubreddits: 1, TotalPosts: 1, TotalComments: 2, TotalVotes: 2, TotalMessages: 1})
+}
\ No newline at end of file
@@ -0,0 +1,112 @@
+// internal/engine/subreddittypes.go
+package engine
+
+import (
+    "errors"
+
+    "reddit-clone/internal/models"
+)
+
+var (
+    ErrSubredditPrivate     = errors.New("subreddit is private")
+    ErrNotApprovedPoster    = errors.New("only approved users can post in this subreddit")
+    ErrInvalidSubredditType = errors.New("subreddit type must be public, restricted or private")
+)
+
+// checkSubredditType returns ErrInvalidSubredditType for an unknown type
+func checkSubredditType(subredditType string) error {
+    switch subredditType {
+    case models.SubredditPublic, models.SubredditRestricted, models.SubredditPrivate:
+        return nil
+    }
+    return ErrInvalidSubredditType
+}
+
+// isApprovedPoster reports whether the user may post in a restricted
+// subreddit or join a private one
+func isApprovedPoster(userID string, subreddit *models.SubReddit) bool {
+    if isModerator(userID, subreddit) {
+        return true
+    }
+    _, approved := subreddit.ApprovedPosters.Load(userID)
+    return approved
+}
+
+// checkCanPost returns ErrNotApprovedPoster if the subreddit is restricted
+// and the user isn't approved
+func checkCanPost(userID string, subreddit *models.SubReddit) error {
+    if subreddit.Type == models.SubredditRestricted && !isApprovedPoster(userID, subreddit) {
+        return ErrNotApprovedPoster
+    }
+    return nil
+}
+
+// checkCanJoin returns ErrSubredditPrivate if the subreddit is private and
+// the user is neither a member already nor approved
+func checkCanJoin(userID string, subreddit *models.SubReddit) error {
+    if subreddit.Type != models.SubredditPrivate || isApprovedPoster(userID, subreddit) {
+        return nil
+    }
+    if _, isMember := subreddit.Members.Load(userID); isMember {
+        return nil
+    }
+    return ErrSubredditPrivate
+}
+
+// ViewSubReddit retrieves a subreddit as seen by userID: a private
+// subreddit is only visible to its members
+func (e *RedditEngine) ViewSubReddit(userID, subredditID string) (*models.SubReddit, error) {
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return nil, err
+    }
+    if subreddit.Type == models.SubredditPrivate {
+        if _, isMember := subreddit.Members.Load(userID); !isMember {
+            return nil, ErrSubredditPrivate
+        }
+    }
+    return subreddit, nil
+}
+
+// ApprovePoster lets a user post in a restricted subreddit, or join a
+// private one; only moderators may approve
+func (e *RedditEngine) ApprovePoster(modID, subredditID, userID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return err
+    }
+    if !isModerator(modID, subreddit) {
+        return ErrNotModerator
+    }
+    if _, exists := e.users.Load(userID); !exists {
+        return ErrUserNotFound
+    }
+    subreddit.ApprovedPosters.Store(userID, true)
+    return nil
+}
+
+// RemoveApprovedPoster withdraws a user's approval. Members of a private
+// subreddit stay members; only future joins are affected.
+func (e *RedditEngine) RemoveApprovedPoster(modID, subredditID, userID string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return err
+    }
+    if !isModerator(modID, subreddit) {
+        return ErrNotModerator
+    }
+    subreddit.ApprovedPosters.Delete(userID)
+    return nil
+}
\ No newline at end of file
@@ -0,0 +1,52 @@
+// internal/engine/targetlocks.go
+package engine
+
+import (
+    "hash/fnv"
+
+    "reddit-clone/internal/models"
+)
+
+// targetLockStripes is how many locks votes are spread across; every vote
+// on one post or comment uses the same one
+const targetLockStripes = 64
+
+// lockTarget serializes changes to one post or comment's votes and
+// returns the unlock function. Targets hash onto a fixed set of locks, so
+// votes on different targets rarely contend and there is nothing to start
+// or stop. Readers don't take the lock; the counts themselves are updated
+// atomically.
+func (e *RedditEngine) lockTarget(targetID string) func() {
+    h := fnv.New32a()
+    h.Write([]byte(targetID))
+    mtx := &e.targetLocks[h.Sum32()%targetLockStripes]
+    mtx.Lock()
+    return mtx.Unlock
+}
+
+// lockAllTargets holds off every vote so posts and comments can be copied
+// whole, and returns the unlock function
+func (e *RedditEngine) lockAllTargets() func() {
+    for i := range e.targetLocks {
+        e.targetLocks[i].Lock()
+    }
+    return func() {
+        for i := range e.targetLocks {
+            e.targetLocks[i].Unlock()
+        }
+    }
+}
+
+// copyPost returns a copy of a post other goroutines may be voting on
+func (e *RedditEngine) copyPost(post *models.Post) models.Post {
+    unlock := e.lockTarget(post.ID)
+    defer unlock()
+    return *post
+}
+
+// copyComment returns a copy of a comment other goroutines may be voting on
+func (e *RedditEngine) copyComment(comment *models.Comment) models.Comment {
+    unlock := e.lockTarget(comment.ID)
+    defer unlock()
+    return *comment
+}
\ No newline at end of file
@@ -0,0 +1,62 @@
+// internal/engine/userdirectory.go
+package engine
+
+import (
+    "errors"
+    "sort"
+    "strings"
+
+    "reddit-clone/internal/models"
+)
+
+// ListUsers returns one page of the users whose username contains query,
+// ignoring case, sorted by username, and the number of matches across all
+// pages. An empty query matches everyone. The returned users are copies
+// with the password hash and verification token cleared. Page is 1-based;
+// a limit of 0 returns them all.
+func (e *RedditEngine) ListUsers(query string, page, limit int) ([]*models.User, int, error) {
+    if page < 0 || limit < 0 {
+        return nil, 0, errors.New("page and limit cannot be negative")
+    }
+
+    // The username index holds each user's current name, so matching
+    // needs no user lookups
+    query = strings.ToLower(query)
+    var userIDs []string
+    names := make(map[string]string)
+    e.usernames.Range(func(key, value interface{}) bool {
+        username, userID := key.(string), value.(string)
+        if strings.Contains(strings.ToLower(username), query) {
+            userIDs = append(userIDs, userID)
+            names[userID] = strings.ToLower(username)
+        }
+        return true
+    })
+    sort.Slice(userIDs, func(i, j int) bool {
+        if names[userIDs[i]] != names[userIDs[j]] {
+            return names[userIDs[i]] < names[userIDs[j]]
+        }
+        return userIDs[i] < userIDs[j]
+    })
+    total := len(userIDs)
+    if limit > 0 {
+        start := min((max(page, 1)-1)*limit, len(userIDs))
+        userIDs = userIDs[start:min(start+limit, len(userIDs))]
+    }
+
+    // Renames and email verification change users under editMtx
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+    users := make([]*models.User, 0, len(userIDs))
+    for _, userID := range userIDs {
+        userI, ok := e.users.Load(userID)
+        if !ok {
+            continue
+        }
+        user := *userI.(*models.User)
+        user.Password = ""
+        user.VerificationToken = ""
+        users = append(users, &user)
+    }
+    return users, total, nil
+}
\ No newline at end of file
@@ -0,0 +1,59 @@
+// internal/engine/usernames.go
+package engine
+
+import (
+    "errors"
+
+    "reddit-clone/internal/models"
+)
+
+var ErrRenamingTooFast = errors.New("username was changed too recently")
+
+// ChangeUsername renames a user. The new name is reserved in the username
+// index before the old one is released, so two users can never end up
+// with the same name. Renames are limited to one per
+// Config.UsernameChangeCooldown and the last Config.MaxUsernameHistory
+// names are kept. Content refers to users by ID, so nothing else changes.
+func (e *RedditEngine) ChangeUsername(userID, newUsername string) error {
+    done, err := e.beginWrite()
+    if err != nil {
+        return err
+    }
+    defer done()
+
+    user, err := e.GetUser(userID)
+    if err != nil {
+        return err
+    }
+    if newUsername == "" {
+        return errors.New("username cannot be empty")
+    }
+
+    e.editMtx.Lock()
+    defer e.editMtx.Unlock()
+
+    oldUsername := user.Username
+    if newUsername == oldUsername {
+        return nil
+    }
+    if cooldown := e.config.UsernameChangeCooldown; cooldown > 0 && len(user.UsernameHistory) > 0 {
+        lastChange := user.UsernameHistory[len(user.UsernameHistory)-1].ChangedAt
+        if e.clock.Now().Sub(lastChange) < cooldown {
+            return ErrRenamingTooFast
+        }
+    }
+    if _, taken := e.usernames.LoadOrStore(newUsername, userID); taken {
+        return ErrUsernameTaken
+    }
+    e.usernames.Delete(oldUsername)
+
+    user.Username = newUsername
+    user.UsernameHistory = append(user.UsernameHistory, models.UsernameChange{
+        PreviousUsername: oldUsername,
+        ChangedAt:        e.clock.Now(),
+    })
+    if limit := e.config.MaxUsernameHistory; limit > 0 && len(user.UsernameHistory) > limit {
+        user.UsernameHistory = append([]models.UsernameChange(nil), user.UsernameHistory[len(user.UsernameHistory)-limit:]...)
+    }
+    return nil
+}
\ No newline at end of file
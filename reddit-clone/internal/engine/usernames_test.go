@@ -0,0 +1,87 @@
+// internal/engine/usernames_test.go
+package engine
+
+import (
+    "fmt"
+    "testing"
+)
+
+func TestChangeUsername(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+
+    if err := e.ChangeUsername(alice.ID, "bob"); err != ErrUsernameTaken {
+        t.Fatalf("rename to a taken name = %v, want ErrUsernameTaken", err)
+    }
+    if err := e.ChangeUsername(alice.ID, "alicia"); err != nil {
+        t.Fatalf("ChangeUsername: %v", err)
+    }
+
+    // The index follows the rename, and the old name is free again
+    if got, err := e.GetUserByUsername("alicia"); err != nil || got.ID != alice.ID {
+        t.Errorf("lookup of the new name = %v, %v; want alice", got, err)
+    }
+    if _, err := e.GetUserByUsername("alice"); err == nil {
+        t.Error("the old name still resolves")
+    }
+    if id, err := e.AuthenticateUser("alicia", "password123"); err != nil || id != alice.ID {
+        t.Errorf("login with the new name = %q, %v", id, err)
+    }
+    if got, _ := e.GetUserByUsername("bob"); got.ID != bob.ID {
+        t.Error("the refused rename disturbed bob's entry")
+    }
+
+    user, _ := e.GetUser(alice.ID)
+    if len(user.UsernameHistory) != 1 || user.UsernameHistory[0].PreviousUsername != "alice" || !user.UsernameHistory[0].ChangedAt.Equal(clock.Now()) {
+        t.Errorf("history = %+v, want one change from alice", user.UsernameHistory)
+    }
+
+    // Renaming to the current name is a no-op and doesn't start a cooldown
+    if err := e.ChangeUsername(bob.ID, "bob"); err != nil {
+        t.Errorf("rename to the same name: %v", err)
+    }
+    if err := e.ChangeUsername(bob.ID, "robert"); err != nil {
+        t.Errorf("bob's first rename: %v", err)
+    }
+
+    if err := e.ChangeUsername(bob.ID, "alice"); err != ErrRenamingTooFast {
+        t.Errorf("second rename inside the cooldown = %v, want ErrRenamingTooFast", err)
+    }
+    // After the cooldown, another user may take the freed name
+    clock.Advance(DefaultUsernameChangeCooldown)
+    if err := e.ChangeUsername(bob.ID, "alice"); err != nil {
+        t.Errorf("rename to a freed name after the cooldown: %v", err)
+    }
+    if err := e.ChangeUsername(alice.ID, ""); err == nil {
+        t.Error("rename to an empty name succeeded")
+    }
+}
+
+func TestUsernameHistoryIsCapped(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.UsernameChangeCooldown = 0
+    cfg.MaxUsernameHistory = 3
+    e, _ := newTestEngineWithConfig(t, cfg)
+    alice := mustRegister(t, e, "alice")
+
+    for i := 1; i <= 5; i++ {
+        if err := e.ChangeUsername(alice.ID, fmt.Sprintf("alice%d", i)); err != nil {
+            t.Fatalf("rename %d: %v", i, err)
+        }
+    }
+    user, _ := e.GetUser(alice.ID)
+    var previous []string
+    for _, change := range user.UsernameHistory {
+        previous = append(previous, change.PreviousUsername)
+    }
+    if want := "[alice2 alice3 alice4]"; fmt.Sprint(previous) != want {
+        t.Errorf("history = %v, want %s", previous, want)
+    }
+    // Every name but the current one is free
+    for _, name := range []string{"alice", "alice1", "alice2", "alice3", "alice4"} {
+        if _, err := e.GetUserByUsername(name); err == nil {
+            t.Errorf("old name %s still resolves", name)
+        }
+    }
+}
\ No newline at end of file
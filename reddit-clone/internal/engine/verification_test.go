@@ -0,0 +1,70 @@
+// internal/engine/verification_test.go
+package engine
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestVerifyEmail(t *testing.T) {
+    e, _ := newTestEngine(t)
+    user, token, err := e.RegisterAccountWithEmail("alice", "password123", "alice@example.com")
+    if err != nil {
+        t.Fatalf("RegisterAccountWithEmail: %v", err)
+    }
+    if token == "" || user.Verified || user.Email != "alice@example.com" {
+        t.Fatalf("new account: token %q, verified %v, email %q", token, user.Verified, user.Email)
+    }
+    // Only a hash of the token is kept
+    if user.VerificationToken == token {
+        t.Error("the user record stores the token itself")
+    }
+
+    if err := e.VerifyEmail(user.ID, "wrong"); err != ErrInvalidVerificationToken {
+        t.Errorf("VerifyEmail with a wrong token = %v, want ErrInvalidVerificationToken", err)
+    }
+    if err := e.VerifyEmail(user.ID, token); err != nil {
+        t.Fatalf("VerifyEmail: %v", err)
+    }
+    if got, _ := e.GetUser(user.ID); !got.Verified {
+        t.Error("account not verified")
+    }
+    // Verifying again is harmless
+    if err := e.VerifyEmail(user.ID, token); err != nil {
+        t.Errorf("second VerifyEmail: %v", err)
+    }
+
+    // Accounts without an email get no token and have nothing to verify
+    plain, token, err := e.RegisterAccountWithEmail("bob", "password123", "")
+    if err != nil || token != "" {
+        t.Fatalf("RegisterAccountWithEmail without email = %q, %v; want no token", token, err)
+    }
+    if err := e.VerifyEmail(plain.ID, ""); err != ErrInvalidVerificationToken {
+        t.Errorf("VerifyEmail without an email = %v, want ErrInvalidVerificationToken", err)
+    }
+}
+
+func TestRequireVerifiedEmailToPost(t *testing.T) {
+    cfg := NewDefaultConfig()
+    cfg.PostCooldown = 0
+    cfg.RequireVerifiedEmail = true
+    e, _ := newTestEngineWithConfig(t, cfg)
+    user, token, err := e.RegisterAccountWithEmail("alice", "password123", "alice@example.com")
+    if err != nil {
+        t.Fatalf("RegisterAccountWithEmail: %v", err)
+    }
+    subreddit := mustCreateSubreddit(t, e, "golang", user.ID)
+
+    if _, err := e.CreatePost("Hello", "Content", user.ID, subreddit.ID); !errors.Is(err, ErrEmailNotVerified) {
+        t.Fatalf("unverified post = %v, want ErrEmailNotVerified", err)
+    }
+    if err := e.VerifyEmail(user.ID, token); err != nil {
+        t.Fatalf("VerifyEmail: %v", err)
+    }
+    mustPost(t, e, user.ID, subreddit.ID)
+
+    // Without the requirement, unverified accounts post as before
+    open, _ := newTestEngine(t)
+    bob, _, _ := open.RegisterAccountWithEmail("bob", "password123", "bob@example.com")
+    mustPost(t, open, bob.ID, mustCreateSubreddit(t, open, "golang", bob.ID).ID)
+}
\ No newline at end of file
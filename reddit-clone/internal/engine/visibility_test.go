@@ -0,0 +1,94 @@
+// internal/engine/visibility_test.go
+package engine
+
+import (
+    "errors"
+    "testing"
+
+    "reddit-clone/internal/models"
+)
+
+// newPrivateSubreddit creates a private subreddit moderated by creatorID
+// with a post of theirs in it
+func newPrivateSubreddit(t *testing.T, e *RedditEngine, creatorID string) (*models.SubReddit, *models.Post) {
+    t.Helper()
+    subreddit, err := e.CreateSubRedditWithOptions("secret", "Members only", creatorID, SubredditOptions{Type: models.SubredditPrivate})
+    if err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+    return subreddit, mustPost(t, e, creatorID, subreddit.ID)
+}
+
+func TestCanSeePostInPrivateSubreddit(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit, post := newPrivateSubreddit(t, e, alice.ID)
+
+    if !e.CanSeePost(alice.ID, post) {
+        t.Error("member can't see a post in their private subreddit")
+    }
+    if e.CanSeePost(bob.ID, post) {
+        t.Error("non-member can see a post in a private subreddit")
+    }
+    if e.CanSeePost("", post) {
+        t.Error("anonymous caller can see a post in a private subreddit")
+    }
+
+    if err := e.ApprovePoster(alice.ID, subreddit.ID, bob.ID); err != nil {
+        t.Fatalf("ApprovePoster: %v", err)
+    }
+    mustJoin(t, e, bob.ID, subreddit.ID)
+    if !e.CanSeePost(bob.ID, post) {
+        t.Error("new member can't see a post in the private subreddit")
+    }
+}
+
+func TestCanSeePostInPublicSubreddit(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, alice.ID, subreddit.ID)
+
+    if !e.CanSeePost(bob.ID, post) || !e.CanSeePost("", post) {
+        t.Error("a post in a public subreddit isn't visible to everyone")
+    }
+}
+
+func TestCrosspostTargetsHidePrivatePosts(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    _, post := newPrivateSubreddit(t, e, alice.ID)
+
+    if _, err := e.GetCrosspostTargets(bob.ID, post.ID); err == nil {
+        t.Error("non-member got crosspost targets for a private post")
+    }
+}
+
+func TestNonMembersCantVoteOrReportPrivateContent(t *testing.T) {
+    e, _ := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    _, post := newPrivateSubreddit(t, e, alice.ID)
+    comment := mustComment(t, e, alice.ID, post.ID, nil)
+
+    for _, targetID := range []string{post.ID, comment.ID} {
+        if _, err := e.SetVote(bob.ID, targetID, VoteUp); !errors.Is(err, ErrSubredditPrivate) {
+            t.Errorf("SetVote(%s): err = %v, want ErrSubredditPrivate", targetID, err)
+        }
+        if err := e.Report(bob.ID, targetID, "spam"); !errors.Is(err, ErrSubredditPrivate) {
+            t.Errorf("Report(%s): err = %v, want ErrSubredditPrivate", targetID, err)
+        }
+    }
+    if post.Upvotes != 0 || comment.Upvotes != 0 {
+        t.Errorf("refused votes were counted: post %d, comment %d", post.Upvotes, comment.Upvotes)
+    }
+
+    // Members still can
+    mustVote(t, e, alice.ID, comment.ID, VoteUp)
+    if err := e.Report(alice.ID, post.ID, "spam"); err != nil {
+        t.Errorf("member Report: %v", err)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,81 @@
+// internal/engine/votehistory.go
+package engine
+
+import (
+    "errors"
+    "sort"
+    "sync"
+
+    "reddit-clone/internal/models"
+)
+
+// voteSummaryLength is how many characters of a comment a vote summary keeps
+const voteSummaryLength = 80
+
+// indexVote records a vote in its voter's vote index
+func (e *RedditEngine) indexVote(vote *models.Vote) {
+    idxI, _ := e.userVotes.LoadOrStore(vote.UserID, &sync.Map{})
+    idxI.(*sync.Map).Store(vote.TargetID, true)
+}
+
+// unindexVote drops a vote from its voter's vote index
+func (e *RedditEngine) unindexVote(userID, targetID string) {
+    if idxI, ok := e.userVotes.Load(userID); ok {
+        idxI.(*sync.Map).Delete(targetID)
+    }
+}
+
+// GetVoteHistory returns one page of the votes a user has cast, newest
+// first, and the number of votes across all pages. Retracted votes and
+// votes on deleted content are not included. Page is 1-based; a limit of
+// 0 returns them all.
+func (e *RedditEngine) GetVoteHistory(userID string, page, limit int) ([]*models.Vote, int, error) {
+    if page < 0 || limit < 0 {
+        return nil, 0, errors.New("page and limit cannot be negative")
+    }
+    if _, err := e.GetUser(userID); err != nil {
+        return nil, 0, err
+    }
+
+    votes := []*models.Vote{}
+    if idxI, ok := e.userVotes.Load(userID); ok {
+        idxI.(*sync.Map).Range(func(key, _ interface{}) bool {
+            if voteI, ok := e.votes.Load(userID + ":" + key.(string)); ok {
+                vote := *voteI.(*models.Vote)
+                votes = append(votes, &vote)
+            }
+            return true
+        })
+    }
+    total := len(votes)
+
+    sort.Slice(votes, func(i, j int) bool {
+        if !votes[i].CreatedAt.Equal(votes[j].CreatedAt) {
+            return votes[i].CreatedAt.After(votes[j].CreatedAt)
+        }
+        return votes[i].TargetID < votes[j].TargetID
+    })
+    if limit > 0 {
+        start := min((max(page, 1)-1)*limit, len(votes))
+        votes = votes[start:min(start+limit, len(votes))]
+    }
+    return votes, total, nil
+}
+
+// VoteTargetSummary describes a vote's target in a line: the title of a
+// post, or the start of a comment. kind is "post" or "comment", and both
+// are empty if the target no longer exists.
+func (e *RedditEngine) VoteTargetSummary(targetID string) (kind, summary string) {
+    if postI, ok := e.posts.Load(targetID); ok {
+        return "post", e.RenderTitle(postI.(*models.Post).Title)
+    }
+    comment, err := e.GetComment(targetID)
+    if err != nil {
+        return "", ""
+    }
+    summary = comment.Content
+    if runes := []rune(summary); len(runes) > voteSummaryLength {
+        summary = string(runes[:voteSummaryLength]) + "..."
+    }
+    return "comment", summary
+}
\ No newline at end of file
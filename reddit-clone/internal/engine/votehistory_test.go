@@ -0,0 +1,88 @@
+// internal/engine/votehistory_test.go
+package engine
+
+import (
+    "fmt"
+    "strings"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+func TestGetVoteHistory(t *testing.T) {
+    e, clock := newTestEngine(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    posts := []*models.Post{mustPost(t, e, alice.ID, subreddit.ID), mustPost(t, e, alice.ID, subreddit.ID), mustPost(t, e, alice.ID, subreddit.ID)}
+    long := strings.Repeat("é", voteSummaryLength+5)
+    comment, err := e.CreateComment(long, alice.ID, posts[0].ID, nil)
+    if err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+    vote := func(targetID string, direction int) {
+        t.Helper()
+        clock.Advance(time.Minute)
+        mustVote(t, e, bob.ID, targetID, direction)
+    }
+
+    vote(posts[0].ID, VoteUp)
+    vote(posts[1].ID, VoteDown)
+    vote(comment.ID, VoteUp)
+    vote(posts[2].ID, VoteUp)
+    // Retracted votes drop out; a flipped vote keeps its place
+    vote(posts[2].ID, VoteNone)
+    vote(posts[0].ID, VoteDown)
+    // Alice's votes aren't bob's
+    mustVote(t, e, alice.ID, posts[1].ID, VoteUp)
+
+    describe := func(votes []*models.Vote) string {
+        out := make([]string, len(votes))
+        for i, v := range votes {
+            kind, _ := e.VoteTargetSummary(v.TargetID)
+            out[i] = fmt.Sprintf("%s:%t", kind, v.IsUpvote)
+            if v.UserID != bob.ID {
+                t.Errorf("history holds a vote by %s", v.UserID)
+            }
+        }
+        return fmt.Sprint(out)
+    }
+    tests := []struct {
+        page, limit int
+        want        string
+    }{
+        {1, 0, "[comment:true post:false post:false]"},
+        {1, 2, "[comment:true post:false]"},
+        {2, 2, "[post:false]"},
+        {3, 2, "[]"},
+    }
+    for _, tt := range tests {
+        votes, total, err := e.GetVoteHistory(bob.ID, tt.page, tt.limit)
+        if err != nil {
+            t.Fatalf("GetVoteHistory: %v", err)
+        }
+        if got := describe(votes); got != tt.want || total != 3 {
+            t.Errorf("page %d, limit %d: %s of %d, want %s of 3", tt.page, tt.limit, got, total, tt.want)
+        }
+    }
+    votes, _, _ := e.GetVoteHistory(bob.ID, 1, 0)
+    if votes[1].TargetID != posts[1].ID || votes[2].TargetID != posts[0].ID {
+        t.Errorf("history targets = %s, %s; want the second post then the first", votes[1].TargetID, votes[2].TargetID)
+    }
+
+    kind, summary := e.VoteTargetSummary(posts[0].ID)
+    if kind != "post" || summary != posts[0].Title {
+        t.Errorf("post summary = %s %q, want post %q", kind, summary, posts[0].Title)
+    }
+    if _, summary := e.VoteTargetSummary(comment.ID); summary != strings.Repeat("é", voteSummaryLength)+"..." {
+        t.Errorf("comment summary = %q, want the first %d characters and an ellipsis", summary, voteSummaryLength)
+    }
+    if kind, summary := e.VoteTargetSummary("missing"); kind != "" || summary != "" {
+        t.Errorf("summary of a missing target = %q %q, want empty", kind, summary)
+    }
+
+    if _, _, err := e.GetVoteHistory("missing", 1, 10); err == nil {
+        t.Error("GetVoteHistory for an unknown user succeeded")
+    }
+}
\ No newline at end of file
@@ -0,0 +1,176 @@
+// internal/engine/votes_test.go
+package engine
+
+import (
+    "context"
+    "fmt"
+    "runtime"
+    "sync"
+    "sync/atomic"
+    "testing"
+
+    "reddit-clone/internal/models"
+)
+
+// Votes and reposts contend the way the simulator makes them: voteBenchUsers
+// users voting on voteBenchPosts posts, every repostEvery-th operation a
+// repost
+const (
+    voteBenchUsers = 64
+    voteBenchPosts = 8
+    repostEvery    = 10
+)
+
+// newVoteFixture returns an engine with users all joined to one subreddit
+// and posts in it
+func newVoteFixture(t testing.TB, users, posts int) (*RedditEngine, *models.SubReddit, []*models.User, []*models.Post) {
+    t.Helper()
+    e, _ := newTestEngine(t)
+    voters := make([]*models.User, users)
+    for i := range voters {
+        voters[i] = mustRegister(t, e, fmt.Sprintf("voter%d", i))
+    }
+    subreddit := mustCreateSubreddit(t, e, "votes", voters[0].ID)
+    for _, voter := range voters[1:] {
+        mustJoin(t, e, voter.ID, subreddit.ID)
+    }
+    targets := make([]*models.Post, posts)
+    for i := range targets {
+        targets[i] = mustPost(t, e, voters[i%users].ID, subreddit.ID)
+    }
+    return e, subreddit, voters, targets
+}
+
+// checkVoteCounts fails unless every post's counts match the votes cast on it
+func checkVoteCounts(t testing.TB, e *RedditEngine, posts []*models.Post) {
+    t.Helper()
+    for _, post := range posts {
+        var up, down int64
+        e.votes.Range(func(_, value interface{}) bool {
+            vote := value.(*models.Vote)
+            if vote.TargetID == post.ID {
+                if vote.IsUpvote {
+                    up++
+                } else {
+                    down++
+                }
+            }
+            return true
+        })
+        if gotUp, gotDown := post.Votes(); gotUp != up || gotDown != down {
+            t.Errorf("post %s counts %d/%d, votes %d/%d", post.ID, gotUp, gotDown, up, down)
+        }
+    }
+}
+
+func TestConcurrentVotesKeepExactCounts(t *testing.T) {
+    e, _, voters, posts := newVoteFixture(t, 32, 4)
+
+    var wg sync.WaitGroup
+    for _, voter := range voters {
+        wg.Add(1)
+        go func(userID string) {
+            defer wg.Done()
+            for i := 0; i < 200; i++ {
+                post := posts[i%len(posts)]
+                if _, err := e.ToggleVote(userID, post.ID, i%3 != 0); err != nil {
+                    t.Errorf("ToggleVote: %v", err)
+                    return
+                }
+                // Readers don't take the vote lock
+                post.Score()
+            }
+        }(voter.ID)
+    }
+    wg.Wait()
+    checkVoteCounts(t, e, posts)
+}
+
+func TestRepostPointsAtTheOriginal(t *testing.T) {
+    e, subreddit, users, posts := newVoteFixture(t, 3, 1)
+    original := posts[0]
+
+    repost, err := e.RepostPost(users[1].ID, original.ID, subreddit.ID)
+    if err != nil {
+        t.Fatalf("RepostPost: %v", err)
+    }
+    if !repost.IsRepost || repost.OriginalID != original.ID || repost.AuthorID != users[1].ID {
+        t.Errorf("repost = %+v, want a repost of %s by %s", repost, original.ID, users[1].ID)
+    }
+    if repost.Title != original.Title || repost.Content != original.Content {
+        t.Errorf("repost text %q/%q, want the original's %q/%q", repost.Title, repost.Content, original.Title, original.Content)
+    }
+
+    // A repost of the repost still points at the original
+    again, err := e.RepostPost(users[2].ID, repost.ID, subreddit.ID)
+    if err != nil {
+        t.Fatalf("RepostPost of a repost: %v", err)
+    }
+    if again.OriginalID != original.ID {
+        t.Errorf("repost of a repost points at %s, want %s", again.OriginalID, original.ID)
+    }
+
+    feed, err := e.GetFeedWithOptions(context.Background(), users[0].ID, FeedOptions{DedupeReposts: true})
+    if err != nil {
+        t.Fatalf("GetFeedWithOptions: %v", err)
+    }
+    if len(feed) != 1 {
+        t.Errorf("deduplicated feed has %d posts, want the original and its reposts as one", len(feed))
+    }
+}
+
+func TestGetUserVotesReportsDirections(t *testing.T) {
+    e, _, users, posts := newVoteFixture(t, 2, 3)
+    voter := users[1]
+    comment := mustComment(t, e, users[0].ID, posts[0].ID, nil)
+
+    mustVote(t, e, voter.ID, posts[0].ID, VoteUp)
+    mustVote(t, e, voter.ID, comment.ID, VoteDown)
+    mustVote(t, e, voter.ID, posts[1].ID, VoteUp)
+    mustVote(t, e, voter.ID, posts[1].ID, VoteNone)
+    mustVote(t, e, users[0].ID, posts[2].ID, VoteUp)
+
+    votes, err := e.GetUserVotes(voter.ID, []string{posts[0].ID, comment.ID, posts[1].ID, posts[2].ID, "missing"})
+    if err != nil {
+        t.Fatalf("GetUserVotes: %v", err)
+    }
+    want := map[string]bool{posts[0].ID: true, comment.ID: false}
+    if len(votes) != len(want) {
+        t.Fatalf("got %d votes %v, want %v", len(votes), votes, want)
+    }
+    for targetID, isUpvote := range want {
+        if got, ok := votes[targetID]; !ok || got != isUpvote {
+            t.Errorf("vote on %s = %v (present %v), want %v", targetID, got, ok, isUpvote)
+        }
+    }
+}
+
+// BenchmarkVotesAndReposts runs voteBenchUsers goroutines voting on a few
+// hot posts and now and then reposting one, then checks no vote was lost
+func BenchmarkVotesAndReposts(b *testing.B) {
+    e, subreddit, voters, posts := newVoteFixture(b, voteBenchUsers, voteBenchPosts)
+    procs := runtime.GOMAXPROCS(0)
+    var workers, ops atomic.Int64
+    b.SetParallelism((voteBenchUsers + procs - 1) / procs)
+    b.ReportAllocs()
+    b.ResetTimer()
+    b.RunParallel(func(pb *testing.PB) {
+        voter := voters[int(workers.Add(1)-1)%len(voters)]
+        for pb.Next() {
+            i := ops.Add(1)
+            post := posts[i%voteBenchPosts]
+            var err error
+            if i%repostEvery == 0 {
+                _, err = e.RepostPost(voter.ID, post.ID, subreddit.ID)
+            } else {
+                _, err = e.ToggleVote(voter.ID, post.ID, i%3 != 0)
+            }
+            if err != nil {
+                b.Error(err)
+                return
+            }
+        }
+    })
+    b.StopTimer()
+    checkVoteCounts(b, e, posts)
+}
\ No newline at end of file
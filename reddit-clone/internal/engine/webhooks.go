@@ -0,0 +1,70 @@
+// internal/engine/webhooks.go
+package engine
+
+import (
+    "errors"
+    "net/url"
+    "sort"
+    "sync"
+
+    "reddit-clone/internal/models"
+)
+
+// AddWebhook registers an outbound webhook URL for a subreddit's new posts
+// and comments; only moderators may add one
+func (e *RedditEngine) AddWebhook(userID, subredditID, rawURL string) (*models.Webhook, error) {
+    done, err := e.beginWrite()
+    if err != nil {
+        return nil, err
+    }
+    defer done()
+
+    subreddit, err := e.GetSubReddit(subredditID)
+    if err != nil {
+        return nil, err
+    }
+    if !isModerator(userID, subreddit) {
+        return nil, ErrNotModerator
+    }
+
+    parsed, err := url.Parse(rawURL)
+    if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+        return nil, errors.New("webhook url must be an absolute http or https url")
+    }
+
+    webhook := &models.Webhook{
+        ID:          e.generateID(),
+        SubRedditID: subredditID,
+        URL:         parsed.String(),
+        CreatorID:   userID,
+        CreatedAt:   e.clock.Now(),
+    }
+    e.webhooks.Store(webhook.ID, webhook)
+    e.indexWebhook(webhook)
+    return webhook, nil
+}
+
+// GetWebhooks returns a subreddit's webhooks, oldest first
+func (e *RedditEngine) GetWebhooks(subredditID string) []*models.Webhook {
+    var webhooks []*models.Webhook
+    idxI, ok := e.subredditWebhooks.Load(subredditID)
+    if !ok {
+        return webhooks
+    }
+    idxI.(*sync.Map).Range(func(key, _ interface{}) bool {
+        if webhookI, ok := e.webhooks.Load(key); ok {
+            webhooks = append(webhooks, webhookI.(*models.Webhook))
+        }
+        return true
+    })
+    sort.Slice(webhooks, func(i, j int) bool {
+        return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+    })
+    return webhooks
+}
+
+// indexWebhook records a webhook under its subreddit in the webhook index
+func (e *RedditEngine) indexWebhook(webhook *models.Webhook) {
+    idxI, _ := e.subredditWebhooks.LoadOrStore(webhook.SubRedditID, &sync.Map{})
+    idxI.(*sync.Map).Store(webhook.ID, true)
+}
\ No newline at end of file
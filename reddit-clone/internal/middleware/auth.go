@@ -7,29 +7,46 @@ import (
     "strings"
 )
 
+// contextKey is private so no other package can collide with our values
+type contextKey string
+
+const userIDKey contextKey = "userID"
+
+// UserIDFromContext returns the authenticated user ID set by AuthMiddleware
+func UserIDFromContext(ctx context.Context) (string, bool) {
+    userID, ok := ctx.Value(userIDKey).(string)
+    return userID, ok && userID != ""
+}
+
 func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         // Get token from Authorization header
         authHeader := r.Header.Get("Authorization")
         if authHeader == "" {
-            http.Error(w, "Authorization header required", http.StatusUnauthorized)
+            writeError(w, http.StatusUnauthorized, "Authorization header required")
             return
         }
 
-        // Expected format: "Bearer <token>"
-        parts := strings.Split(authHeader, " ")
-        if len(parts) != 2 || parts[0] != "Bearer" {
-            http.Error(w, "Invalid authorization format", http.StatusUnauthorized)
+        userID, ok := bearerUserID(r)
+        if !ok {
+            writeError(w, http.StatusUnauthorized, "Invalid authorization format")
             return
         }
 
-        token := parts[1]
-        // In a real implementation, validate the token here
-        // For now, we'll just use the token as the user ID
-        userID := token
-
         // Add user ID to request context
-        ctx := context.WithValue(r.Context(), "userID", userID)
+        ctx := context.WithValue(r.Context(), userIDKey, userID)
         next.ServeHTTP(w, r.WithContext(ctx))
     }
+}
+
+// bearerUserID returns the user ID carried by an Authorization header in
+// the expected "Bearer <token>" format
+func bearerUserID(r *http.Request) (string, bool) {
+    parts := strings.Split(r.Header.Get("Authorization"), " ")
+    if len(parts) != 2 || parts[0] != "Bearer" {
+        return "", false
+    }
+    // In a real implementation, validate the token here
+    // For now, we'll just use the token as the user ID
+    return parts[1], true
 }
\ No newline at end of file
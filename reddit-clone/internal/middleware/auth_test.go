@@ -0,0 +1,30 @@
+// internal/middleware/auth_test.go
+package middleware
+
+import (
+    "context"
+    "testing"
+)
+
+func TestUserIDFromContext(t *testing.T) {
+    tests := []struct {
+        name   string
+        ctx    context.Context
+        want   string
+        wantOK bool
+    }{
+        {"missing", context.Background(), "", false},
+        {"wrong type", context.WithValue(context.Background(), userIDKey, 42), "", false},
+        {"empty", context.WithValue(context.Background(), userIDKey, ""), "", false},
+        {"other key", context.WithValue(context.Background(), "userID", "user1"), "", false},
+        {"set", context.WithValue(context.Background(), userIDKey, "user1"), "user1", true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, ok := UserIDFromContext(tt.ctx)
+            if got != tt.want || ok != tt.wantOK {
+                t.Errorf("UserIDFromContext = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+            }
+        })
+    }
+}
\ No newline at end of file
@@ -0,0 +1,85 @@
+// internal/middleware/clientip.go
+package middleware
+
+import (
+    "fmt"
+    "net"
+    "net/http"
+    "strings"
+)
+
+// TrustedProxies lists the networks whose forwarding headers are believed
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses CIDRs such as 10.0.0.0/8; a bare address
+// trusts just that host. Blank entries are skipped.
+func ParseTrustedProxies(cidrs []string) (TrustedProxies, error) {
+    var proxies TrustedProxies
+    for _, cidr := range cidrs {
+        cidr = strings.TrimSpace(cidr)
+        if cidr == "" {
+            continue
+        }
+        if !strings.Contains(cidr, "/") {
+            ip := net.ParseIP(cidr)
+            if ip == nil {
+                return nil, fmt.Errorf("invalid trusted proxy %q", cidr)
+            }
+            bits := 8 * net.IPv4len
+            if ip.To4() == nil {
+                bits = 8 * net.IPv6len
+            }
+            proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+            continue
+        }
+        _, network, err := net.ParseCIDR(cidr)
+        if err != nil {
+            return nil, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+        }
+        proxies = append(proxies, network)
+    }
+    return proxies, nil
+}
+
+// Trusts reports whether ip belongs to a trusted proxy
+func (t TrustedProxies) Trusts(ip net.IP) bool {
+    for _, network := range t {
+        if network.Contains(ip) {
+            return true
+        }
+    }
+    return false
+}
+
+// ClientIP returns the address of the client that made r. X-Forwarded-For
+// and X-Real-IP are only consulted when the connection comes from a
+// trusted proxy, so other clients can't spoof them. X-Forwarded-For is
+// read right to left, skipping trusted hops, because each proxy appends
+// the address it received the request from and only the rightmost
+// entries were written by proxies we trust.
+func (t TrustedProxies) ClientIP(r *http.Request) string {
+    host, _, err := net.SplitHostPort(r.RemoteAddr)
+    if err != nil {
+        host = r.RemoteAddr
+    }
+    peer := net.ParseIP(host)
+    if peer == nil || !t.Trusts(peer) {
+        return host
+    }
+
+    hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+    for i := len(hops) - 1; i >= 0; i-- {
+        ip := net.ParseIP(strings.TrimSpace(hops[i]))
+        if ip == nil {
+            // A malformed hop can't be trusted or attributed
+            break
+        }
+        if !t.Trusts(ip) {
+            return ip.String()
+        }
+    }
+    if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+        return ip.String()
+    }
+    return host
+}
\ No newline at end of file
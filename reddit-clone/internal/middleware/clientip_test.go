@@ -0,0 +1,106 @@
+// internal/middleware/clientip_test.go
+package middleware
+
+import (
+    "net/http/httptest"
+    "testing"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+    proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", " 192.168.1.5 ", "", "::1"})
+    if err != nil {
+        t.Fatalf("ParseTrustedProxies: %v", err)
+    }
+    if len(proxies) != 3 {
+        t.Fatalf("parsed %d networks, want 3", len(proxies))
+    }
+    if _, err := ParseTrustedProxies([]string{"not-an-ip"}); err == nil {
+        t.Error("ParseTrustedProxies accepted a bad address")
+    }
+    if _, err := ParseTrustedProxies([]string{"10.0.0.0/99"}); err == nil {
+        t.Error("ParseTrustedProxies accepted a bad CIDR")
+    }
+}
+
+func TestClientIP(t *testing.T) {
+    proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8"})
+    if err != nil {
+        t.Fatalf("ParseTrustedProxies: %v", err)
+    }
+
+    tests := []struct {
+        name       string
+        remoteAddr string
+        forwarded  []string
+        realIP     string
+        want       string
+    }{
+        {
+            name:       "direct client",
+            remoteAddr: "203.0.113.7:4000",
+            want:       "203.0.113.7",
+        },
+        {
+            name:       "untrusted peer spoofing X-Forwarded-For",
+            remoteAddr: "203.0.113.7:4000",
+            forwarded:  []string{"198.51.100.1"},
+            want:       "203.0.113.7",
+        },
+        {
+            name:       "untrusted peer spoofing X-Real-IP",
+            remoteAddr: "203.0.113.7:4000",
+            realIP:     "198.51.100.1",
+            want:       "203.0.113.7",
+        },
+        {
+            name:       "trusted proxy",
+            remoteAddr: "10.0.0.2:4000",
+            forwarded:  []string{"203.0.113.7"},
+            want:       "203.0.113.7",
+        },
+        {
+            name:       "client-supplied hop left of the real one is ignored",
+            remoteAddr: "10.0.0.2:4000",
+            forwarded:  []string{"198.51.100.1, 203.0.113.7"},
+            want:       "203.0.113.7",
+        },
+        {
+            name:       "trusted hops are skipped",
+            remoteAddr: "10.0.0.2:4000",
+            forwarded:  []string{"203.0.113.7, 10.0.0.9", "10.0.0.3"},
+            want:       "203.0.113.7",
+        },
+        {
+            name:       "malformed hop stops the walk",
+            remoteAddr: "10.0.0.2:4000",
+            forwarded:  []string{"203.0.113.7, garbage"},
+            want:       "10.0.0.2",
+        },
+        {
+            name:       "trusted proxy with X-Real-IP",
+            remoteAddr: "10.0.0.2:4000",
+            realIP:     "203.0.113.7",
+            want:       "203.0.113.7",
+        },
+        {
+            name:       "trusted proxy without headers",
+            remoteAddr: "10.0.0.2:4000",
+            want:       "10.0.0.2",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            r := httptest.NewRequest("GET", "/", nil)
+            r.RemoteAddr = tt.remoteAddr
+            for _, value := range tt.forwarded {
+                r.Header.Add("X-Forwarded-For", value)
+            }
+            if tt.realIP != "" {
+                r.Header.Set("X-Real-IP", tt.realIP)
+            }
+            if got := proxies.ClientIP(r); got != tt.want {
+                t.Errorf("ClientIP = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
\ No newline at end of file
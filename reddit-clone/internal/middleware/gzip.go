@@ -0,0 +1,142 @@
+// internal/middleware/gzip.go
+package middleware
+
+import (
+    "bytes"
+    "compress/gzip"
+    "net/http"
+    "strconv"
+    "strings"
+)
+
+// DefaultGzipMinSize is the smallest response body worth compressing
+const DefaultGzipMinSize = 1024
+
+// GzipMiddleware compresses JSON responses of at least minSize bytes for
+// clients that accept gzip. Smaller or non-JSON responses, and WebSocket
+// upgrades, pass through unchanged.
+func GzipMiddleware(minSize int) func(http.Handler) http.Handler {
+    return func(next http.Handler) http.Handler {
+        return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            w.Header().Add("Vary", "Accept-Encoding")
+            if !acceptsGzip(r.Header.Get("Accept-Encoding")) || isWebSocketUpgrade(r) {
+                next.ServeHTTP(w, r)
+                return
+            }
+
+            gw := &gzipResponseWriter{ResponseWriter: w, minSize: minSize}
+            next.ServeHTTP(gw, r)
+            gw.finish()
+        })
+    }
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(header string) bool {
+    for _, part := range strings.Split(header, ",") {
+        coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+        if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+            continue
+        }
+        // "gzip;q=0" explicitly refuses it
+        if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+            if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+                return false
+            }
+        }
+        return true
+    }
+    return false
+}
+
+// gzipResponseWriter holds back the status and body until it knows whether
+// the response is large enough to compress. It only talks to the wrapped
+// writer through the ResponseWriter interface, so it composes with other
+// wrapping middleware on either side.
+type gzipResponseWriter struct {
+    http.ResponseWriter
+    minSize int
+
+    status  int
+    buf     bytes.Buffer
+    decided bool
+    gz      *gzip.Writer
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+    if w.status == 0 {
+        w.status = status
+    }
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+    if w.decided {
+        if w.gz != nil {
+            return w.gz.Write(p)
+        }
+        return w.ResponseWriter.Write(p)
+    }
+
+    w.buf.Write(p)
+    if w.buf.Len() >= w.minSize {
+        if err := w.decide(true); err != nil {
+            return 0, err
+        }
+    }
+    return len(p), nil
+}
+
+// decide commits the status and headers, starts compressing if the response
+// qualifies, and flushes the buffered body
+func (w *gzipResponseWriter) decide(largeEnough bool) error {
+    w.decided = true
+    if w.status == 0 {
+        w.status = http.StatusOK
+    }
+
+    header := w.Header()
+    if largeEnough && w.compressible() {
+        header.Set("Content-Encoding", "gzip")
+        header.Del("Content-Length")
+        w.gz = gzip.NewWriter(w.ResponseWriter)
+    }
+    w.ResponseWriter.WriteHeader(w.status)
+
+    if w.buf.Len() == 0 {
+        return nil
+    }
+    var err error
+    if w.gz != nil {
+        _, err = w.gz.Write(w.buf.Bytes())
+    } else {
+        _, err = w.ResponseWriter.Write(w.buf.Bytes())
+    }
+    w.buf.Reset()
+    return err
+}
+
+// compressible reports whether the handler produced a JSON body that isn't
+// already encoded
+func (w *gzipResponseWriter) compressible() bool {
+    header := w.Header()
+    if header.Get("Content-Encoding") != "" {
+        return false
+    }
+    return strings.HasPrefix(header.Get("Content-Type"), "application/json")
+}
+
+// finish writes out a response that stayed under the threshold and
+// terminates the gzip stream
+func (w *gzipResponseWriter) finish() {
+    if !w.decided {
+        w.decide(false)
+    }
+    if w.gz != nil {
+        w.gz.Close()
+    }
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+    return w.ResponseWriter
+}
\ No newline at end of file
@@ -0,0 +1,109 @@
+// internal/middleware/gzip_test.go
+package middleware
+
+import (
+    "compress/gzip"
+    "encoding/json"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+// jsonHandler answers with a JSON list of n repeated words and a 201
+func jsonHandler(n int) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        words := make([]string, n)
+        for i := range words {
+            words[i] = "subreddit"
+        }
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(words)
+    })
+}
+
+func serveGzip(t *testing.T, h http.Handler, header http.Header) *httptest.ResponseRecorder {
+    t.Helper()
+    req := httptest.NewRequest("GET", "/", nil)
+    for k, v := range header {
+        req.Header[k] = v
+    }
+    rec := httptest.NewRecorder()
+    GzipMiddleware(DefaultGzipMinSize)(h).ServeHTTP(rec, req)
+    return rec
+}
+
+func decodeWords(t *testing.T, r io.Reader) []string {
+    t.Helper()
+    var words []string
+    if err := json.NewDecoder(r).Decode(&words); err != nil {
+        t.Fatalf("decoding body: %v", err)
+    }
+    return words
+}
+
+func TestGzipMiddlewareCompressesLargeJSON(t *testing.T) {
+    rec := serveGzip(t, jsonHandler(500), http.Header{"Accept-Encoding": {"deflate, gzip"}})
+
+    if rec.Code != http.StatusCreated {
+        t.Errorf("status = %d, want 201", rec.Code)
+    }
+    if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+        t.Fatalf("Content-Encoding = %q, want gzip", got)
+    }
+    if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+        t.Errorf("Vary = %q, want Accept-Encoding", got)
+    }
+    zr, err := gzip.NewReader(rec.Body)
+    if err != nil {
+        t.Fatalf("gzip.NewReader: %v", err)
+    }
+    if words := decodeWords(t, zr); len(words) != 500 || words[0] != "subreddit" {
+        t.Errorf("decompressed %d words, want 500", len(words))
+    }
+}
+
+func TestGzipMiddlewarePassesThrough(t *testing.T) {
+    tests := []struct {
+        name   string
+        words  int
+        header http.Header
+    }{
+        {"no Accept-Encoding", 500, nil},
+        {"gzip refused", 500, http.Header{"Accept-Encoding": {"gzip;q=0"}}},
+        {"below threshold", 3, http.Header{"Accept-Encoding": {"gzip"}}},
+        {"WebSocket upgrade", 500, http.Header{"Accept-Encoding": {"gzip"}, "Upgrade": {"websocket"}}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            rec := serveGzip(t, jsonHandler(tt.words), tt.header)
+            if rec.Code != http.StatusCreated {
+                t.Errorf("status = %d, want 201", rec.Code)
+            }
+            if got := rec.Header().Get("Content-Encoding"); got != "" {
+                t.Errorf("Content-Encoding = %q, want none", got)
+            }
+            if words := decodeWords(t, rec.Body); len(words) != tt.words {
+                t.Errorf("got %d words, want %d", len(words), tt.words)
+            }
+        })
+    }
+}
+
+func TestGzipMiddlewareSkipsNonJSON(t *testing.T) {
+    text := strings.Repeat("plain text ", 500)
+    h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "text/plain")
+        io.WriteString(w, text)
+    })
+
+    rec := serveGzip(t, h, http.Header{"Accept-Encoding": {"gzip"}})
+    if got := rec.Header().Get("Content-Encoding"); got != "" {
+        t.Errorf("Content-Encoding = %q, want none", got)
+    }
+    if rec.Body.String() != text {
+        t.Error("text body was altered")
+    }
+}
\ No newline at end of file
@@ -0,0 +1,82 @@
+// internal/middleware/negotiate.go
+package middleware
+
+import (
+    "context"
+    "mime"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "reddit-clone/pkg/msgpack"
+)
+
+// JSONContentType is the media type responses use unless the client asks
+// for MessagePack
+const JSONContentType = "application/json"
+
+const contentTypeKey contextKey = "contentType"
+
+// ResponseContentType returns the media type NegotiateMiddleware chose for
+// the response, JSON when it didn't run or the client didn't ask otherwise
+func ResponseContentType(ctx context.Context) string {
+    if contentType, ok := ctx.Value(contentTypeKey).(string); ok {
+        return contentType
+    }
+    return JSONContentType
+}
+
+// NegotiateMiddleware picks the response encoding from the Accept header.
+// MessagePack is used when the client lists it, under its own name, ahead
+// of or with a higher quality than JSON; wildcards only ever select JSON.
+func NegotiateMiddleware(next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Add("Vary", "Accept")
+        if prefersMsgpack(r.Header.Get("Accept")) {
+            r = r.WithContext(context.WithValue(r.Context(), contentTypeKey, msgpack.ContentType))
+        }
+        next.ServeHTTP(w, r)
+    })
+}
+
+// IsMsgpack reports whether a Content-Type or Accept media type names
+// MessagePack, under its registered or its older x- name
+func IsMsgpack(contentType string) bool {
+    mediaType, _, err := mime.ParseMediaType(contentType)
+    return err == nil && (mediaType == msgpack.ContentType || mediaType == "application/x-msgpack")
+}
+
+// prefersMsgpack weighs MessagePack against JSON in an Accept header. The
+// higher quality wins and a tie goes to whichever is listed first.
+func prefersMsgpack(accept string) bool {
+    msgpackQ, jsonQ := 0.0, 0.0
+    msgpackAt, jsonAt := -1, -1
+    for i, part := range strings.Split(accept, ",") {
+        mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+        if err != nil {
+            continue
+        }
+        q := 1.0
+        if qStr, ok := params["q"]; ok {
+            if v, err := strconv.ParseFloat(qStr, 64); err == nil {
+                q = v
+            }
+        }
+
+        switch {
+        case IsMsgpack(mediaType):
+            if msgpackAt < 0 {
+                msgpackQ, msgpackAt = q, i
+            }
+        case mediaType == JSONContentType, mediaType == "application/*", mediaType == "*/*":
+            // An explicit JSON entry overrides a wildcard listed before it
+            if jsonAt < 0 || mediaType == JSONContentType {
+                jsonQ, jsonAt = q, i
+            }
+        }
+    }
+    if msgpackAt < 0 || msgpackQ <= 0 {
+        return false
+    }
+    return jsonAt < 0 || msgpackQ > jsonQ || (msgpackQ == jsonQ && msgpackAt < jsonAt)
+}
\ No newline at end of file
@@ -0,0 +1,35 @@
+// internal/middleware/ratebudget.go
+package middleware
+
+import (
+    "math"
+    "net/http"
+    "strconv"
+    "time"
+)
+
+// RateCharger counts a request against a user's budget, returning an
+// error and how long to wait once the budget is spent. It must ignore IDs
+// that name no user, since the token here hasn't been checked yet.
+type RateCharger interface {
+    Charge(userID string) (time.Duration, error)
+}
+
+// RateBudgetMiddleware charges each request that carries a bearer token
+// to its user, answering 429 with Retry-After once their budget is spent.
+// Requests without a token, or whose token names no user, pass through
+// uncharged for AuthMiddleware and the handler to reject.
+func RateBudgetMiddleware(budget RateCharger) func(http.Handler) http.Handler {
+    return func(next http.Handler) http.Handler {
+        return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            if userID, ok := bearerUserID(r); ok {
+                if wait, err := budget.Charge(userID); err != nil {
+                    w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+                    writeError(w, http.StatusTooManyRequests, err.Error())
+                    return
+                }
+            }
+            next.ServeHTTP(w, r)
+        })
+    }
+}
\ No newline at end of file
@@ -0,0 +1,40 @@
+// internal/middleware/recover.go
+package middleware
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+    "runtime/debug"
+
+    "reddit-clone/api/v1"
+)
+
+// RecoverMiddleware turns a panicking handler into a 500 JSON error
+// instead of letting it tear down the connection
+func RecoverMiddleware(next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        defer func() {
+            rec := recover()
+            if rec == nil {
+                return
+            }
+            // ErrAbortHandler is the sanctioned way to abort a response
+            if rec == http.ErrAbortHandler {
+                panic(rec)
+            }
+
+            log.Printf("panic serving %s %s: %v\n%s", r.Method, r.URL.Path, rec, debug.Stack())
+            writeError(w, http.StatusInternalServerError, "Internal server error")
+        }()
+
+        next.ServeHTTP(w, r)
+    })
+}
+
+// writeError writes a JSON error body carrying the generic code for status
+func writeError(w http.ResponseWriter, status int, message string) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(status)
+    json.NewEncoder(w).Encode(api.ErrorResponse{Error: message, Code: api.CodeForStatus(status)})
+}
\ No newline at end of file
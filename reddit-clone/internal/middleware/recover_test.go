@@ -0,0 +1,48 @@
+// internal/middleware/recover_test.go
+package middleware
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "reddit-clone/api/v1"
+)
+
+func TestRecoverMiddlewareAnswersPanicWith500(t *testing.T) {
+    panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        panic("boom")
+    })
+    // The timeout runs the handler on its own goroutine and re-raises the
+    // panic, so recovery installed outside it must still catch it
+    handler := RecoverMiddleware(TimeoutMiddleware(time.Second)(panicking))
+
+    rec := httptest.NewRecorder()
+    handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+    if rec.Code != http.StatusInternalServerError {
+        t.Fatalf("status = %d, want 500", rec.Code)
+    }
+    var body api.ErrorResponse
+    if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+        t.Fatalf("decoding body: %v", err)
+    }
+    if body.Code != api.CodeForStatus(http.StatusInternalServerError) {
+        t.Errorf("code = %q, want %q", body.Code, api.CodeForStatus(http.StatusInternalServerError))
+    }
+}
+
+func TestRecoverMiddlewareRepanicsAbortHandler(t *testing.T) {
+    handler := RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        panic(http.ErrAbortHandler)
+    }))
+
+    defer func() {
+        if rec := recover(); rec != http.ErrAbortHandler {
+            t.Fatalf("recovered %v, want http.ErrAbortHandler", rec)
+        }
+    }()
+    handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
\ No newline at end of file
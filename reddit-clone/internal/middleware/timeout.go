@@ -0,0 +1,34 @@
+// internal/middleware/timeout.go
+package middleware
+
+import (
+    "encoding/json"
+    "net/http"
+    "time"
+
+    "reddit-clone/api/v1"
+)
+
+// TimeoutMiddleware answers 503 with a JSON error when a handler runs
+// longer than d, and cancels the request context so engine scans that
+// honor it stop early. A d of 0 or less disables the limit. WebSocket
+// upgrades are exempt: the connection outlives any request timeout.
+func TimeoutMiddleware(d time.Duration) func(http.Handler) http.Handler {
+    body, _ := json.Marshal(api.ErrorResponse{Error: "Request timed out", Code: api.CodeTimeout})
+    return func(next http.Handler) http.Handler {
+        if d <= 0 {
+            return next
+        }
+        timeout := http.TimeoutHandler(next, d, string(body))
+        return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            if isWebSocketUpgrade(r) {
+                next.ServeHTTP(w, r)
+                return
+            }
+            // The timeout reply keeps this; a handler that finishes in time
+            // replaces it with its own headers
+            w.Header().Set("Content-Type", "application/json")
+            timeout.ServeHTTP(w, r)
+        })
+    }
+}
\ No newline at end of file
@@ -0,0 +1,90 @@
+// internal/middleware/timeout_test.go
+package middleware
+
+import (
+    "encoding/json"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "reddit-clone/api/v1"
+)
+
+// slowHandler waits for delay or for the request to be cancelled, then
+// reports on done whether it was cancelled
+func slowHandler(delay time.Duration, done chan<- bool) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        select {
+        case <-time.After(delay):
+            w.Header().Set("Content-Type", "text/plain")
+            io.WriteString(w, "finished")
+            done <- false
+        case <-r.Context().Done():
+            done <- true
+        }
+    })
+}
+
+func TestTimeoutMiddlewareAnswersSlowHandlerWith503(t *testing.T) {
+    done := make(chan bool, 1)
+    handler := TimeoutMiddleware(20 * time.Millisecond)(slowHandler(time.Minute, done))
+
+    rec := httptest.NewRecorder()
+    handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+    if rec.Code != http.StatusServiceUnavailable {
+        t.Fatalf("status = %d, want 503", rec.Code)
+    }
+    if got := rec.Header().Get("Content-Type"); got != "application/json" {
+        t.Errorf("Content-Type = %q, want application/json", got)
+    }
+    var body api.ErrorResponse
+    if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+        t.Fatalf("decoding body: %v", err)
+    }
+    if body.Code != api.CodeTimeout {
+        t.Errorf("code = %q, want %q", body.Code, api.CodeTimeout)
+    }
+    select {
+    case cancelled := <-done:
+        if !cancelled {
+            t.Error("handler finished instead of seeing its context cancelled")
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("handler never saw its context cancelled")
+    }
+}
+
+func TestTimeoutMiddlewarePassesThrough(t *testing.T) {
+    tests := []struct {
+        name    string
+        timeout time.Duration
+        delay   time.Duration
+        upgrade bool
+    }{
+        {"fast handler", time.Minute, 0, false},
+        {"disabled", 0, 50 * time.Millisecond, false},
+        {"WebSocket upgrade", 10 * time.Millisecond, 50 * time.Millisecond, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            done := make(chan bool, 1)
+            handler := TimeoutMiddleware(tt.timeout)(slowHandler(tt.delay, done))
+            req := httptest.NewRequest("GET", "/", nil)
+            if tt.upgrade {
+                req.Header.Set("Upgrade", "websocket")
+            }
+
+            rec := httptest.NewRecorder()
+            handler.ServeHTTP(rec, req)
+            if rec.Code != http.StatusOK || rec.Body.String() != "finished" {
+                t.Errorf("got %d %q, want 200 %q", rec.Code, rec.Body, "finished")
+            }
+            if got := rec.Header().Get("Content-Type"); got != "text/plain" {
+                t.Errorf("Content-Type = %q, want the handler's text/plain", got)
+            }
+        })
+    }
+}
\ No newline at end of file
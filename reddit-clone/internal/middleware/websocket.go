@@ -0,0 +1,14 @@
+// internal/middleware/websocket.go
+package middleware
+
+import (
+    "net/http"
+    "strings"
+)
+
+// isWebSocketUpgrade reports whether the request asks to switch to the
+// WebSocket protocol. Such a request hijacks the connection, so wrappers
+// that buffer or time out the response must stay out of its way.
+func isWebSocketUpgrade(r *http.Request) bool {
+    return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
\ No newline at end of file
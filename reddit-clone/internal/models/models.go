@@ -4,6 +4,7 @@ package models
 import (
     "time"
     "sync"
+    "sync/atomic"
 )
 
 // User represents a Reddit user
@@ -14,56 +15,167 @@ type User struct {
     Karma     int64     `json:"karma"`
     IsOnline  bool      `json:"is_online"`
     CreatedAt time.Time `json:"created_at"`
+    Email     string    `json:"email,omitempty"`
+    Verified  bool      `json:"verified"`
+
+    // VerificationToken is the SHA-256 of the pending email verification
+    // token, cleared once the email is verified
+    VerificationToken string `json:"-"`
+
+    // UsernameHistory lists the user's recent renames, oldest first
+    UsernameHistory []UsernameChange `json:"username_history,omitempty"`
+
+    // FeedPreference is the feed sort used when a request names none;
+    // empty means newest first
+    FeedPreference string `json:"feed_preference,omitempty"`
 }
 
+// UsernameChange records a username a user has given up
+type UsernameChange struct {
+    PreviousUsername string    `json:"previous_username"`
+    ChangedAt        time.Time `json:"changed_at"`
+}
+
+// Subreddit types. Public subreddits are open to everyone; anyone can read
+// a restricted one but only approved users post there; a private one is
+// readable by its members only.
+const (
+    SubredditPublic     = "public"
+    SubredditRestricted = "restricted"
+    SubredditPrivate    = "private"
+)
+
 // SubReddit represents a subreddit
 type SubReddit struct {
     ID          string    `json:"id"`
     Name        string    `json:"name"`
     Description string    `json:"description"`
+    Type        string    `json:"type"` // SubredditPublic, SubredditRestricted or SubredditPrivate
     CreatorID   string    `json:"creator_id"`
     MemberCount int64     `json:"member_count"`
+    MaxMembers  int64     `json:"max_members"` // 0 means unlimited
     PostCount   int64     `json:"post_count"`
     CreatedAt   time.Time `json:"created_at"`
-    Members     sync.Map  `json:"-"` // map[userID]bool
+    Flairs      []string  `json:"flairs,omitempty"` // Flairs posts may carry, managed by moderators
+    BannedWords []string  `json:"-"`                // Words and phrases posts and comments may not contain
+    Members     sync.Map  `json:"-"`                // map[userID]bool
+    Moderators  sync.Map  `json:"-"`                // map[userID]bool
+
+    // ApprovedPosters may post in a restricted subreddit or join a
+    // private one; moderators need no approval
+    ApprovedPosters sync.Map `json:"-"` // map[userID]bool
+
+    // QueuePosts holds posts from non-approved users of a restricted
+    // subreddit for moderator review instead of rejecting them
+    QueuePosts bool `json:"queue_posts"`
+
+    // AllowCrossposts lists the subreddit as a crosspost target for its
+    // members' posts elsewhere
+    AllowCrossposts bool `json:"allow_crossposts"`
+
+    // Archived makes the subreddit read-only: no posts, comments, joins
+    // or votes
+    Archived bool `json:"archived"`
+
+    // AllowAnonymous lets posts and comments hide their author from
+    // everyone but the moderators
+    AllowAnonymous bool `json:"allow_anonymous"`
 }
 
 // Post represents a post in a subreddit
 type Post struct {
-    ID           string    `json:"id"`
-    Title        string    `json:"title"`
-    Content      string    `json:"content"`
-    AuthorID     string    `json:"author_id"`
-    SubRedditID  string    `json:"subreddit_id"`
-    IsRepost     bool      `json:"is_repost"`
-    OriginalID   string    `json:"original_id,omitempty"`
-    Upvotes      int64     `json:"upvotes"`
-    Downvotes    int64     `json:"downvotes"`
-    CommentCount int64     `json:"comment_count"`
-    CreatedAt    time.Time `json:"created_at"`
+    ID            string       `json:"id"`
+    Title         string       `json:"title"`
+    Slug          string       `json:"slug"` // URL-friendly title, unique within the subreddit
+    Content       string       `json:"content"`
+    AuthorID      string       `json:"author_id"`
+    SubRedditID   string       `json:"subreddit_id"`
+    IsRepost      bool         `json:"is_repost"`
+    OriginalID    string       `json:"original_id,omitempty"`
+    Upvotes       int64        `json:"upvotes"`
+    Downvotes     int64        `json:"downvotes"`
+    CommentCount  int64        `json:"comment_count"`
+    CreatedAt     time.Time    `json:"created_at"`
+    Edited        bool         `json:"edited"`
+    EditHistory   []EditRecord `json:"edit_history,omitempty"` // Most recent edits, oldest first
+    Version       int64        `json:"version"`                // Edits so far; guards against concurrent edits
+    Distinguished bool         `json:"distinguished"`          // Marked as an official moderator post
+    Flair         string       `json:"flair,omitempty"`        // One of the subreddit's flairs
+    NSFW          bool         `json:"nsfw"`                   // Left out of popular listings by default
+    Pending       bool         `json:"pending,omitempty"`      // Awaiting moderator approval; hidden until approved
+    ContestMode   bool         `json:"contest_mode"`           // Comments shown shuffled and without votes
+    AutoRemoved   bool         `json:"auto_removed,omitempty"` // Voted below the removal threshold; hidden until a moderator restores it
+    Anonymous     bool         `json:"anonymous,omitempty"`    // Author shown only to moderators
+    Pinned        bool         `json:"pinned,omitempty"`       // Listed above every other post in its subreddit
+    Locked        bool         `json:"locked,omitempty"`       // Only moderators comment, and comments can't be edited
+
+    // ScheduledFor is when a scheduled post is published, and its
+    // CreatedAt; the post is hidden from everyone but its author until then
+    ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
+}
+
+// Votes returns the post's upvote and downvote counts. The engine changes
+// them atomically while the post is shared, so read them through here.
+func (p *Post) Votes() (upvotes, downvotes int64) {
+    return atomic.LoadInt64(&p.Upvotes), atomic.LoadInt64(&p.Downvotes)
+}
+
+// Score is the post's net vote count, used for ranking
+func (p *Post) Score() int64 {
+    upvotes, downvotes := p.Votes()
+    return upvotes - downvotes
 }
 
 // Comment represents a comment on a post or another comment
 type Comment struct {
-    ID        string    `json:"id"`
-    Content   string    `json:"content"`
-    AuthorID  string    `json:"author_id"`
-    PostID    string    `json:"post_id"`
-    ParentID  *string   `json:"parent_id"` // nil if top-level comment
-    Depth     int       `json:"depth"`     // Comment hierarchy level
-    Upvotes   int64     `json:"upvotes"`
-    Downvotes int64     `json:"downvotes"`
-    CreatedAt time.Time `json:"created_at"`
+    ID            string       `json:"id"`
+    Content       string       `json:"content"`
+    AuthorID      string       `json:"author_id"`
+    PostID        string       `json:"post_id"`
+    ParentID      *string      `json:"parent_id"` // nil if top-level comment
+    Depth         int          `json:"depth"`     // Comment hierarchy level
+    Upvotes       int64        `json:"upvotes"`
+    Downvotes     int64        `json:"downvotes"`
+    CreatedAt     time.Time    `json:"created_at"`
+    Edited        bool         `json:"edited"`
+    EditHistory   []EditRecord `json:"edit_history,omitempty"` // Most recent edits, oldest first
+    Version       int64        `json:"version"`                // Edits so far; guards against concurrent edits
+    Distinguished bool         `json:"distinguished"`          // Marked as an official moderator comment
+    AutoRemoved   bool         `json:"auto_removed,omitempty"` // Voted below the removal threshold; hidden until a moderator restores it
+    Anonymous     bool         `json:"anonymous,omitempty"`    // Author shown only to moderators
+    UpdatedAt     *time.Time   `json:"updated_at,omitempty"`   // nil until the author edits the comment
+}
+
+// Votes returns the comment's upvote and downvote counts, read atomically
+// like Post.Votes
+func (c *Comment) Votes() (upvotes, downvotes int64) {
+    return atomic.LoadInt64(&c.Upvotes), atomic.LoadInt64(&c.Downvotes)
+}
+
+// Score is the comment's net vote count, used for ranking
+func (c *Comment) Score() int64 {
+    upvotes, downvotes := c.Votes()
+    return upvotes - downvotes
+}
+
+// EditRecord captures the content of a post or comment before an edit
+type EditRecord struct {
+    PreviousTitle   string    `json:"previous_title,omitempty"` // Posts only
+    PreviousContent string    `json:"previous_content"`
+    EditedAt        time.Time `json:"edited_at"`
 }
 
 // DirectMessage represents a private message between users
 type DirectMessage struct {
-    ID        string    `json:"id"`
-    FromID    string    `json:"from_id"`
-    ToID      string    `json:"to_id"`
-    Content   string    `json:"content"`
-    IsRead    bool      `json:"is_read"`
-    CreatedAt time.Time `json:"created_at"`
+    ID        string     `json:"id"`
+    FromID    string     `json:"from_id"`
+    ToID      string     `json:"to_id"`
+    Content   string     `json:"content"`
+    IsRead    bool       `json:"is_read"`
+    CreatedAt time.Time  `json:"created_at"`
+    ExpiresAt *time.Time `json:"expires_at,omitempty"` // nil if the message never expires
+    Edited    bool       `json:"edited"`
+    UpdatedAt *time.Time `json:"updated_at,omitempty"` // nil until the sender edits the message
 }
 
 // Vote represents a user's vote on a post or comment
@@ -75,6 +187,64 @@ type Vote struct {
     CreatedAt time.Time `json:"created_at"`
 }
 
+// Report states. A report waits in the mod queue until a moderator
+// resolves it, having acted on the content, or dismisses it.
+const (
+    ReportPending   = "pending"
+    ReportResolved  = "resolved"
+    ReportDismissed = "dismissed"
+)
+
+// Report represents a user's report of a post or comment to moderators
+type Report struct {
+    ID          string    `json:"id"`
+    ReporterID  string    `json:"reporter_id"`
+    TargetID    string    `json:"target_id"`   // Post or Comment ID
+    TargetType  string    `json:"target_type"` // "post" or "comment"
+    SubRedditID string    `json:"subreddit_id"`
+    Reason      string    `json:"reason"`
+    CreatedAt   time.Time `json:"created_at"`
+    Status      string    `json:"status"` // ReportPending, ReportResolved or ReportDismissed
+
+    // The moderator who closed the report, and when
+    ClosedBy string     `json:"closed_by,omitempty"`
+    ClosedAt *time.Time `json:"closed_at,omitempty"`
+}
+
+// Webhook is an outbound URL notified of a subreddit's new posts and comments
+type Webhook struct {
+    ID          string    `json:"id"`
+    SubRedditID string    `json:"subreddit_id"`
+    URL         string    `json:"url"`
+    CreatorID   string    `json:"creator_id"`
+    CreatedAt   time.Time `json:"created_at"`
+}
+
+// Notification types
+const (
+    NotificationReply   = "reply"
+    NotificationMention = "mention"
+)
+
+// Notification tells a user about a reply to their comment or a mention of them
+type Notification struct {
+    ID        string    `json:"id"`
+    UserID    string    `json:"user_id"`  // Recipient
+    Type      string    `json:"type"`     // NotificationReply or NotificationMention
+    ActorID   string    `json:"actor_id"` // User who replied or mentioned
+    PostID    string    `json:"post_id"`
+    CommentID string    `json:"comment_id,omitempty"` // Empty for mentions in a post
+    IsRead    bool      `json:"is_read"`
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"` // When the latest notification was batched into this one
+
+    // Count is how many notifications of this type were batched into this
+    // one; ActorID, PostID and CommentID are the latest's. Summary reads
+    // like "3 new replies" once there is more than one.
+    Count   int    `json:"count,omitempty"`
+    Summary string `json:"summary,omitempty"`
+}
+
 // Metrics represents performance and usage metrics
 type Metrics struct {
     TotalUsers        int64
@@ -88,6 +258,16 @@ type Metrics struct {
     SubredditStats   map[string]*SubredditMetrics
 }
 
+// GlobalStats holds site-wide content totals
+type GlobalStats struct {
+    TotalUsers      int64 `json:"total_users"`
+    TotalSubreddits int64 `json:"total_subreddits"`
+    TotalPosts      int64 `json:"total_posts"`
+    TotalComments   int64 `json:"total_comments"`
+    TotalVotes      int64 `json:"total_votes"`
+    TotalMessages   int64 `json:"total_messages"`
+}
+
 // SubredditMetrics represents metrics for a specific subreddit
 type SubredditMetrics struct {
     Name         string
@@ -29,8 +29,11 @@ const (
 	RedditService_CreateComment_FullMethodName   = "/reddit.RedditService/CreateComment"
 	RedditService_Vote_FullMethodName            = "/reddit.RedditService/Vote"
 	RedditService_GetFeed_FullMethodName         = "/reddit.RedditService/GetFeed"
+	RedditService_StreamFeed_FullMethodName      = "/reddit.RedditService/StreamFeed"
 	RedditService_SendMessage_FullMethodName     = "/reddit.RedditService/SendMessage"
 	RedditService_GetUserMessages_FullMethodName = "/reddit.RedditService/GetUserMessages"
+	RedditService_ExportSnapshot_FullMethodName  = "/reddit.RedditService/ExportSnapshot"
+	RedditService_ImportSnapshot_FullMethodName  = "/reddit.RedditService/ImportSnapshot"
 )
 
 // RedditServiceClient is the client API for RedditService service.
@@ -45,8 +48,12 @@ type RedditServiceClient interface {
 	CreateComment(ctx context.Context, in *CommentRequest, opts ...grpc.CallOption) (*CommentResponse, error)
 	Vote(ctx context.Context, in *VoteRequest, opts ...grpc.CallOption) (*StatusResponse, error)
 	GetFeed(ctx context.Context, in *FeedRequest, opts ...grpc.CallOption) (*FeedResponse, error)
+	StreamFeed(ctx context.Context, in *FeedRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PostResponse], error)
 	SendMessage(ctx context.Context, in *MessageRequest, opts ...grpc.CallOption) (*MessageResponse, error)
 	GetUserMessages(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*MessagesResponse, error)
+	// Admin only: the x-admin-key metadata must match the server's admin key
+	ExportSnapshot(ctx context.Context, in *ExportSnapshotRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SnapshotChunk], error)
+	ImportSnapshot(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[SnapshotChunk, ImportSnapshotResponse], error)
 }
 
 type redditServiceClient struct {
@@ -137,6 +144,25 @@ func (c *redditServiceClient) GetFeed(ctx context.Context, in *FeedRequest, opts
 	return out, nil
 }
 
+func (c *redditServiceClient) StreamFeed(ctx context.Context, in *FeedRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PostResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RedditService_ServiceDesc.Streams[0], RedditService_StreamFeed_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FeedRequest, PostResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RedditService_StreamFeedClient = grpc.ServerStreamingClient[PostResponse]
+
 func (c *redditServiceClient) SendMessage(ctx context.Context, in *MessageRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(MessageResponse)
@@ -157,6 +183,38 @@ func (c *redditServiceClient) GetUserMessages(ctx context.Context, in *UserReque
 	return out, nil
 }
 
+func (c *redditServiceClient) ExportSnapshot(ctx context.Context, in *ExportSnapshotRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SnapshotChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RedditService_ServiceDesc.Streams[1], RedditService_ExportSnapshot_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportSnapshotRequest, SnapshotChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RedditService_ExportSnapshotClient = grpc.ServerStreamingClient[SnapshotChunk]
+
+func (c *redditServiceClient) ImportSnapshot(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[SnapshotChunk, ImportSnapshotResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RedditService_ServiceDesc.Streams[2], RedditService_ImportSnapshot_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SnapshotChunk, ImportSnapshotResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RedditService_ImportSnapshotClient = grpc.ClientStreamingClient[SnapshotChunk, ImportSnapshotResponse]
+
 // RedditServiceServer is the server API for RedditService service.
 // All implementations must embed UnimplementedRedditServiceServer
 // for forward compatibility.
@@ -169,8 +227,12 @@ type RedditServiceServer interface {
 	CreateComment(context.Context, *CommentRequest) (*CommentResponse, error)
 	Vote(context.Context, *VoteRequest) (*StatusResponse, error)
 	GetFeed(context.Context, *FeedRequest) (*FeedResponse, error)
+	StreamFeed(*FeedRequest, grpc.ServerStreamingServer[PostResponse]) error
 	SendMessage(context.Context, *MessageRequest) (*MessageResponse, error)
 	GetUserMessages(context.Context, *UserRequest) (*MessagesResponse, error)
+	// Admin only: the x-admin-key metadata must match the server's admin key
+	ExportSnapshot(*ExportSnapshotRequest, grpc.ServerStreamingServer[SnapshotChunk]) error
+	ImportSnapshot(grpc.ClientStreamingServer[SnapshotChunk, ImportSnapshotResponse]) error
 	mustEmbedUnimplementedRedditServiceServer()
 }
 
@@ -205,12 +267,21 @@ func (UnimplementedRedditServiceServer) Vote(context.Context, *VoteRequest) (*St
 func (UnimplementedRedditServiceServer) GetFeed(context.Context, *FeedRequest) (*FeedResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetFeed not implemented")
 }
+func (UnimplementedRedditServiceServer) StreamFeed(*FeedRequest, grpc.ServerStreamingServer[PostResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamFeed not implemented")
+}
 func (UnimplementedRedditServiceServer) SendMessage(context.Context, *MessageRequest) (*MessageResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendMessage not implemented")
 }
 func (UnimplementedRedditServiceServer) GetUserMessages(context.Context, *UserRequest) (*MessagesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserMessages not implemented")
 }
+func (UnimplementedRedditServiceServer) ExportSnapshot(*ExportSnapshotRequest, grpc.ServerStreamingServer[SnapshotChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportSnapshot not implemented")
+}
+func (UnimplementedRedditServiceServer) ImportSnapshot(grpc.ClientStreamingServer[SnapshotChunk, ImportSnapshotResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportSnapshot not implemented")
+}
 func (UnimplementedRedditServiceServer) mustEmbedUnimplementedRedditServiceServer() {}
 func (UnimplementedRedditServiceServer) testEmbeddedByValue()                       {}
 
@@ -376,6 +447,17 @@ func _RedditService_GetFeed_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RedditService_StreamFeed_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FeedRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RedditServiceServer).StreamFeed(m, &grpc.GenericServerStream[FeedRequest, PostResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RedditService_StreamFeedServer = grpc.ServerStreamingServer[PostResponse]
+
 func _RedditService_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(MessageRequest)
 	if err := dec(in); err != nil {
@@ -412,6 +494,24 @@ func _RedditService_GetUserMessages_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RedditService_ExportSnapshot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportSnapshotRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RedditServiceServer).ExportSnapshot(m, &grpc.GenericServerStream[ExportSnapshotRequest, SnapshotChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RedditService_ExportSnapshotServer = grpc.ServerStreamingServer[SnapshotChunk]
+
+func _RedditService_ImportSnapshot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RedditServiceServer).ImportSnapshot(&grpc.GenericServerStream[SnapshotChunk, ImportSnapshotResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RedditService_ImportSnapshotServer = grpc.ClientStreamingServer[SnapshotChunk, ImportSnapshotResponse]
+
 // RedditService_ServiceDesc is the grpc.ServiceDesc for RedditService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -460,6 +560,22 @@ var RedditService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _RedditService_GetUserMessages_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamFeed",
+			Handler:       _RedditService_StreamFeed_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportSnapshot",
+			Handler:       _RedditService_ExportSnapshot_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportSnapshot",
+			Handler:       _RedditService_ImportSnapshot_Handler,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "internal/proto/reddit.proto",
 }
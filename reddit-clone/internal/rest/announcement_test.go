@@ -0,0 +1,81 @@
+// internal/rest/announcement_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+)
+
+func TestAnnouncementIsPinnedAtTheTop(t *testing.T) {
+    s, e := newTestServer(t)
+    admin := mustRegister(t, e, "admin")
+    alice := mustRegister(t, e, "alice")
+    golang := mustCreateSubreddit(t, e, "golang", alice.ID)
+    rust := mustCreateSubreddit(t, e, "rust", alice.ID)
+    quiet := mustCreateSubreddit(t, e, "quiet", alice.ID)
+    old := mustCreateSubreddit(t, e, "old", alice.ID)
+    mustPost(t, e, "Before", "Content", alice.ID, golang.ID)
+    if err := e.ArchiveSubreddit(alice.ID, old.ID, true); err != nil {
+        t.Fatalf("ArchiveSubreddit: %v", err)
+    }
+
+    // An unknown target fails the broadcast before anything is posted
+    if _, err := e.BroadcastAnnouncementTo(admin.ID, "Oops", "Content", []string{golang.ID, "no-such-subreddit"}); err == nil {
+        t.Fatal("broadcast to an unknown subreddit succeeded")
+    }
+
+    posts, err := e.BroadcastAnnouncementTo(admin.ID, "Maintenance", "Down at noon", []string{golang.ID, rust.ID, old.ID})
+    if err != nil {
+        t.Fatalf("BroadcastAnnouncementTo: %v", err)
+    }
+    if len(posts) != 2 {
+        t.Fatalf("announced in %d subreddits, want 2 with the archived one skipped", len(posts))
+    }
+    // Newer posts still list below the announcement
+    mustPost(t, e, "After", "Content", alice.ID, golang.ID)
+    mustPost(t, e, "After", "Content", alice.ID, rust.ID)
+
+    listing := func(subredditID string) []api.PostResponse {
+        t.Helper()
+        rec := serve(t, s, "GET", "/api/v1/posts?subreddit_id="+subredditID, alice.ID, nil)
+        wantStatus(t, rec, http.StatusOK)
+        var resp api.PostListResponse
+        decodeBody(t, rec, &resp)
+        return resp.Posts
+    }
+    for _, subreddit := range []string{golang.ID, rust.ID} {
+        got := listing(subreddit)
+        if len(got) == 0 {
+            t.Errorf("subreddit %s has no posts", subreddit)
+            continue
+        }
+        top := got[0]
+        if top.Title != "Maintenance" || top.AuthorID != admin.ID || !top.Pinned || !top.Distinguished {
+            t.Errorf("top of %s = %+v, want the pinned, distinguished announcement", subreddit, top)
+        }
+        for _, p := range got[1:] {
+            if p.Title == "Maintenance" || p.Title == "Oops" {
+                t.Errorf("%s lists %q more than once", subreddit, p.Title)
+            }
+        }
+    }
+    for _, subreddit := range []string{quiet.ID, old.ID} {
+        if got := listing(subreddit); len(got) != 0 {
+            t.Errorf("untargeted or archived subreddit %s lists %+v", subreddit, got)
+        }
+    }
+
+    // With no subset every live subreddit gets it
+    posts, err = e.BroadcastAnnouncement(admin.ID, "Welcome", "Hello all")
+    if err != nil {
+        t.Fatalf("BroadcastAnnouncement: %v", err)
+    }
+    if len(posts) != 3 {
+        t.Errorf("announced in %d subreddits, want the 3 that aren't archived", len(posts))
+    }
+    if got := listing(quiet.ID); len(got) != 1 || got[0].Title != "Welcome" || !got[0].Pinned {
+        t.Errorf("quiet listing = %+v, want the pinned announcement", got)
+    }
+}
\ No newline at end of file
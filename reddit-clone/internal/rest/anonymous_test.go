@@ -0,0 +1,73 @@
+// internal/rest/anonymous_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+    "reddit-clone/internal/engine"
+)
+
+func TestAnonymousAuthorMaskedExceptForMods(t *testing.T) {
+    s, e := newTestServer(t)
+    mod := mustRegister(t, e, "mod")
+    author := mustRegister(t, e, "author")
+    reader := mustRegister(t, e, "reader")
+    subreddit, err := e.CreateSubRedditWithOptions("confessions", "Anonymous allowed", mod.ID, engine.SubredditOptions{AllowAnonymous: true})
+    if err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+    join := func(userID string) {
+        if _, err := e.JoinSubReddit(userID, subreddit.ID); err != nil {
+            t.Fatalf("JoinSubReddit: %v", err)
+        }
+    }
+    join(author.ID)
+    join(reader.ID)
+
+    post, err := e.CreatePostWithOptions("Secret", "Shh", author.ID, subreddit.ID, engine.PostOptions{Anonymous: true})
+    if err != nil {
+        t.Fatalf("CreatePostWithOptions: %v", err)
+    }
+    if _, err := e.CreateCommentWithOptions("Me too", author.ID, post.ID, nil, engine.CommentOptions{Anonymous: true}); err != nil {
+        t.Fatalf("CreateCommentWithOptions: %v", err)
+    }
+
+    authors := map[string]string{
+        reader.ID: engine.AnonymousAuthor,
+        mod.ID:    author.ID,
+        author.ID: author.ID,
+    }
+    for viewerID, want := range authors {
+        rec := serve(t, s, "GET", "/api/v1/posts/"+post.ID, viewerID, nil)
+        wantStatus(t, rec, http.StatusOK)
+        var got api.PostResponse
+        decodeBody(t, rec, &got)
+        if got.AuthorID != want || !got.Anonymous {
+            t.Errorf("post seen by %s: author = %q, anonymous = %v; want %q, true", viewerID, got.AuthorID, got.Anonymous, want)
+        }
+
+        rec = serve(t, s, "GET", "/api/v1/posts/"+post.ID+"/comments", viewerID, nil)
+        wantStatus(t, rec, http.StatusOK)
+        var comments api.CommentListResponse
+        decodeBody(t, rec, &comments)
+        if len(comments.Comments) != 1 || comments.Comments[0].AuthorID != want {
+            t.Errorf("comments seen by %s = %+v, want one by %q", viewerID, comments.Comments, want)
+        }
+    }
+}
+
+func TestAnonymousPostRefusedWhereNotAllowed(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+
+    rec := serve(t, s, "POST", "/api/v1/posts", alice.ID, api.PostRequest{Title: "Secret", Content: "Shh", SubredditID: subreddit.ID, Anonymous: true})
+    wantStatus(t, rec, http.StatusForbidden)
+    var errResp api.ErrorResponse
+    decodeBody(t, rec, &errResp)
+    if errResp.Code != api.CodeNoAnonymous {
+        t.Errorf("error code = %q, want %q", errResp.Code, api.CodeNoAnonymous)
+    }
+}
\ No newline at end of file
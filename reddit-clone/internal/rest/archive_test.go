@@ -0,0 +1,35 @@
+// internal/rest/archive_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+)
+
+func TestEditPostInArchivedSubreddit(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, "Hello", "World", alice.ID, subreddit.ID)
+    if err := e.ArchiveSubreddit(alice.ID, subreddit.ID, true); err != nil {
+        t.Fatalf("ArchiveSubreddit: %v", err)
+    }
+
+    rec := serve(t, s, "PUT", "/api/v1/posts/"+post.ID, alice.ID, api.EditPostRequest{Title: "Edited", Content: "Edited"})
+    wantStatus(t, rec, http.StatusForbidden)
+    var errResp api.ErrorResponse
+    decodeBody(t, rec, &errResp)
+    if errResp.Code != api.CodeArchived {
+        t.Errorf("error code = %q, want %q", errResp.Code, api.CodeArchived)
+    }
+
+    rec = serve(t, s, "GET", "/api/v1/posts/"+post.ID, alice.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+    var got api.PostResponse
+    decodeBody(t, rec, &got)
+    if got.Title != "Hello" {
+        t.Errorf("title = %q after a refused edit, want %q", got.Title, "Hello")
+    }
+}
\ No newline at end of file
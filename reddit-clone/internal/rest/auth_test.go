@@ -0,0 +1,39 @@
+// internal/rest/auth_test.go
+package rest
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+// TestHandlersRefuseUnauthenticatedContext calls handlers without
+// AuthMiddleware, as a routing mistake would, and checks they answer 401
+// rather than acting as nobody
+func TestHandlersRefuseUnauthenticatedContext(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+
+    handlers := map[string]http.HandlerFunc{
+        "GetMe":           s.handleGetMe,
+        "GetMySubreddits": s.handleGetMySubreddits,
+        "GetMessages":     s.handleGetMessages,
+        "GetVoteHistory":  s.handleGetVoteHistory,
+    }
+    contexts := map[string]context.Context{
+        "missing": context.Background(),
+        // A string key that merely looks like the middleware's
+        "foreign key": context.WithValue(context.Background(), "userID", alice.ID),
+    }
+    for name, handler := range handlers {
+        for ctxName, ctx := range contexts {
+            t.Run(name+"/"+ctxName, func(t *testing.T) {
+                req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+                rec := httptest.NewRecorder()
+                handler(rec, req)
+                wantStatus(t, rec, http.StatusUnauthorized)
+            })
+        }
+    }
+}
\ No newline at end of file
@@ -0,0 +1,53 @@
+// internal/rest/comments_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+)
+
+func TestGetCommentsDepth(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, "Post", "Content", alice.ID, subreddit.ID)
+
+    // A chain of four comments, each replying to the last
+    var parentID *string
+    var chain []string
+    for i := 0; i < 4; i++ {
+        comment, err := e.CreateComment("Comment", alice.ID, post.ID, parentID)
+        if err != nil {
+            t.Fatalf("CreateComment: %v", err)
+        }
+        chain = append(chain, comment.ID)
+        parentID = &comment.ID
+    }
+
+    rec := serve(t, s, "GET", "/api/v1/posts/"+post.ID+"/comments?depth=2", alice.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+    var resp api.CommentListResponse
+    decodeBody(t, rec, &resp)
+    if len(resp.Comments) != 2 || resp.Comments[0].ID != chain[0] || resp.Comments[1].ID != chain[1] {
+        t.Fatalf("depth=2 returned %d comments, want the first two of the chain", len(resp.Comments))
+    }
+    if last := resp.Comments[1]; !last.HasMoreReplies || last.RemainingReplies != 2 {
+        t.Errorf("deepest comment: has_more_replies %v, remaining_replies %d; want true, 2", last.HasMoreReplies, last.RemainingReplies)
+    }
+    if resp.Comments[0].HasMoreReplies {
+        t.Error("a comment whose replies were returned is flagged as having more")
+    }
+
+    rec = serve(t, s, "GET", "/api/v1/posts/"+post.ID+"/comments", alice.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+    resp = api.CommentListResponse{}
+    decodeBody(t, rec, &resp)
+    if len(resp.Comments) != 4 {
+        t.Errorf("without depth got %d comments, want all 4", len(resp.Comments))
+    }
+
+    rec = serve(t, s, "GET", "/api/v1/posts/"+post.ID+"/comments?depth=-1", alice.ID, nil)
+    wantStatus(t, rec, http.StatusBadRequest)
+}
\ No newline at end of file
@@ -0,0 +1,64 @@
+// internal/rest/distinguish_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+)
+
+func TestOnlyModeratorsDistinguish(t *testing.T) {
+    s, e := newTestServer(t)
+    mod := mustRegister(t, e, "mod")
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", mod.ID)
+    if _, err := e.JoinSubReddit(alice.ID, subreddit.ID); err != nil {
+        t.Fatalf("JoinSubReddit: %v", err)
+    }
+    post := mustPost(t, e, "Post", "Content", alice.ID, subreddit.ID)
+
+    postReq := api.PostRequest{Title: "Official", Content: "From the mods", SubredditID: subreddit.ID, Distinguished: true}
+    rec := serve(t, s, "POST", "/api/v1/posts", alice.ID, postReq)
+    wantStatus(t, rec, http.StatusForbidden)
+    var errResp api.ErrorResponse
+    decodeBody(t, rec, &errResp)
+    if errResp.Code != api.CodeNotModerator {
+        t.Errorf("error code = %q, want %q", errResp.Code, api.CodeNotModerator)
+    }
+    if posts, _ := e.ListPosts(alice.ID, subreddit.ID); len(posts) != 1 {
+        t.Errorf("subreddit has %d posts after a refused one, want 1", len(posts))
+    }
+
+    rec = serve(t, s, "POST", "/api/v1/posts", mod.ID, postReq)
+    wantStatus(t, rec, http.StatusCreated)
+    var created api.PostResponse
+    decodeBody(t, rec, &created)
+    if !created.Distinguished {
+        t.Error("moderator's post isn't distinguished")
+    }
+
+    commentReq := api.CommentRequest{Content: "Official reply", Distinguished: true}
+    rec = serve(t, s, "POST", "/api/v1/posts/"+post.ID+"/comments", alice.ID, commentReq)
+    wantStatus(t, rec, http.StatusForbidden)
+    decodeBody(t, rec, &errResp)
+    if errResp.Code != api.CodeNotModerator {
+        t.Errorf("error code = %q, want %q", errResp.Code, api.CodeNotModerator)
+    }
+
+    rec = serve(t, s, "POST", "/api/v1/posts/"+post.ID+"/comments", mod.ID, commentReq)
+    wantStatus(t, rec, http.StatusCreated)
+    var comment api.CommentResponse
+    decodeBody(t, rec, &comment)
+    if !comment.Distinguished {
+        t.Error("moderator's comment isn't distinguished")
+    }
+
+    // Without the flag anyone may post as usual
+    rec = serve(t, s, "POST", "/api/v1/posts/"+post.ID+"/comments", alice.ID, api.CommentRequest{Content: "Plain reply"})
+    wantStatus(t, rec, http.StatusCreated)
+    decodeBody(t, rec, &comment)
+    if comment.Distinguished {
+        t.Error("plain comment is distinguished")
+    }
+}
\ No newline at end of file
@@ -0,0 +1,69 @@
+// internal/rest/edits_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+)
+
+func TestStaleEditAnswers409(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, "Hello", "World", alice.ID, subreddit.ID)
+    path := "/api/v1/posts/" + post.ID
+
+    version := int64(0)
+    rec := serve(t, s, "PUT", path, alice.ID, api.EditPostRequest{Title: "First", Content: "First edit", Version: &version})
+    wantStatus(t, rec, http.StatusOK)
+    var edited api.PostResponse
+    decodeBody(t, rec, &edited)
+    if edited.Version != 1 {
+        t.Errorf("version after one edit = %d, want 1", edited.Version)
+    }
+
+    rec = serve(t, s, "PUT", path, alice.ID, api.EditPostRequest{Title: "Second", Content: "Stale edit", Version: &version})
+    wantStatus(t, rec, http.StatusConflict)
+    var errResp api.ErrorResponse
+    decodeBody(t, rec, &errResp)
+    if errResp.Code != api.CodeVersionConflict {
+        t.Errorf("error code = %q, want %q", errResp.Code, api.CodeVersionConflict)
+    }
+
+    // Leaving the version out overwrites regardless
+    rec = serve(t, s, "PUT", path, alice.ID, api.EditPostRequest{Title: "Forced", Content: "No version"})
+    wantStatus(t, rec, http.StatusOK)
+}
+
+func TestEditCommentOnLockedPostIsForbidden(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, "Hello", "World", alice.ID, subreddit.ID)
+    comment, err := e.CreateComment("First", alice.ID, post.ID, nil)
+    if err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+    path := "/api/v1/comments/" + comment.ID
+
+    rec := serve(t, s, "PUT", path, alice.ID, api.EditCommentRequest{Content: "Edited"})
+    wantStatus(t, rec, http.StatusOK)
+    var edited api.CommentResponse
+    decodeBody(t, rec, &edited)
+    if edited.Content != "Edited" || !edited.Edited || !edited.CreatedAt.Equal(comment.CreatedAt) {
+        t.Errorf("edited comment = %+v, want new content, edited, original CreatedAt", edited)
+    }
+
+    if _, err := e.ToggleLockPost(alice.ID, post.ID); err != nil {
+        t.Fatalf("ToggleLockPost: %v", err)
+    }
+    rec = serve(t, s, "PUT", path, alice.ID, api.EditCommentRequest{Content: "While locked"})
+    wantStatus(t, rec, http.StatusForbidden)
+    var errResp api.ErrorResponse
+    decodeBody(t, rec, &errResp)
+    if errResp.Code != api.CodePostLocked {
+        t.Errorf("error code = %q, want %q", errResp.Code, api.CodePostLocked)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,65 @@
+// internal/rest/errors.go
+package rest
+
+import (
+    "errors"
+
+    "reddit-clone/api/v1"
+    "reddit-clone/internal/engine"
+)
+
+// maintenanceRetryAfter is the Retry-After, in seconds, sent while the
+// engine is in maintenance mode
+const maintenanceRetryAfter = 30
+
+// appErrorCodes maps engine errors to the codes clients see
+var appErrorCodes = []struct {
+    err  error
+    code string
+}{
+    {engine.ErrUsernameTaken, api.CodeUserExists},
+    {engine.ErrUserBanned, api.CodeUserBanned},
+    {engine.ErrNotMember, api.CodeNotMember},
+    {engine.ErrNotModerator, api.CodeNotModerator},
+    {engine.ErrNotAuthor, api.CodeNotAuthor},
+    {engine.ErrNotCreator, api.CodeNotCreator},
+    {engine.ErrNotSender, api.CodeNotSender},
+    {engine.ErrSubredditFull, api.CodeSubredditFull},
+    {engine.ErrSubredditLimit, api.CodeSubredditLimit},
+    {engine.ErrDuplicatePost, api.CodeDuplicatePost},
+    {engine.ErrAlreadyReported, api.CodeAlreadyReported},
+    {engine.ErrReportNotPending, api.CodeReportNotPending},
+    {engine.ErrInvalidFlair, api.CodeInvalidFlair},
+    {engine.ErrMessageWindowExpired, api.CodeEditWindowExpired},
+    {engine.ErrEmailNotVerified, api.CodeEmailNotVerified},
+    {engine.ErrInvalidVerificationToken, api.CodeInvalidToken},
+    {engine.ErrReadOnly, api.CodeMaintenance},
+    {engine.ErrSubredditPrivate, api.CodeSubredditPrivate},
+    {engine.ErrNotApprovedPoster, api.CodeNotApproved},
+    {engine.ErrPostPending, api.CodePostPending},
+    {engine.ErrPostNotPending, api.CodePostNotPending},
+    {engine.ErrBannedWord, api.CodeBannedWord},
+    {engine.ErrVersionConflict, api.CodeVersionConflict},
+    {engine.ErrAccountTooNew, api.CodeAccountTooNew},
+    {engine.ErrSubredditArchived, api.CodeArchived},
+    {engine.ErrNotAutoRemoved, api.CodeNotAutoRemoved},
+    {engine.ErrAnonymousNotAllowed, api.CodeNoAnonymous},
+    {engine.ErrPostScheduled, api.CodePostScheduled},
+    {engine.ErrPostNotScheduled, api.CodePostNotScheduled},
+    {engine.ErrPostLocked, api.CodePostLocked},
+    {engine.ErrPostingTooFast, api.CodeRateLimited},
+    {engine.ErrRenamingTooFast, api.CodeRateLimited},
+    {engine.ErrSubredditNotFound, api.CodeNotFound},
+    {engine.ErrUserNotFound, api.CodeNotFound},
+}
+
+// errorCode returns the code for err, or the generic code for status when
+// err is not one of the engine's typed errors
+func errorCode(status int, err error) string {
+    for _, mapping := range appErrorCodes {
+        if errors.Is(err, mapping.err) {
+            return mapping.code
+        }
+    }
+    return api.CodeForStatus(status)
+}
\ No newline at end of file
@@ -0,0 +1,69 @@
+// internal/rest/errors_test.go
+package rest
+
+import (
+    "errors"
+    "fmt"
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+    "reddit-clone/internal/engine"
+)
+
+func TestErrorCode(t *testing.T) {
+    tests := []struct {
+        name   string
+        status int
+        err    error
+        want   string
+    }{
+        {"typed error", http.StatusConflict, engine.ErrUsernameTaken, api.CodeUserExists},
+        {"wrapped error", http.StatusBadRequest, fmt.Errorf("%w: %q", engine.ErrInvalidFlair, "Meme"), api.CodeInvalidFlair},
+        {"shared code", http.StatusTooManyRequests, engine.ErrRenamingTooFast, api.CodeRateLimited},
+        {"untyped 404", http.StatusNotFound, errors.New("post not found"), api.CodeNotFound},
+        {"untyped 400", http.StatusBadRequest, errors.New("content cannot be empty"), api.CodeBadRequest},
+        {"untyped 500", http.StatusInternalServerError, errors.New("boom"), api.CodeInternal},
+        {"nil", http.StatusUnauthorized, nil, api.CodeUnauthorized},
+    }
+    for _, tt := range tests {
+        if got := errorCode(tt.status, tt.err); got != tt.want {
+            t.Errorf("%s: errorCode(%d, %v) = %q, want %q", tt.name, tt.status, tt.err, got, tt.want)
+        }
+    }
+}
+
+func TestResponsesCarryErrorCodes(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+
+    tests := []struct {
+        name   string
+        method string
+        path   string
+        userID string
+        body   interface{}
+        status int
+        code   string
+    }{
+        {"taken username", "POST", "/api/v1/users/register", "", api.RegisterRequest{Username: "alice", Password: "password123"}, http.StatusBadRequest, api.CodeUserExists},
+        {"not a member", "POST", "/api/v1/posts", bob.ID, api.PostRequest{Title: "Hi", Content: "Content", SubredditID: subreddit.ID}, http.StatusBadRequest, api.CodeNotMember},
+        {"unknown subreddit", "GET", "/api/v1/subreddits/missing", alice.ID, nil, http.StatusNotFound, api.CodeNotFound},
+        {"no credentials", "GET", "/api/v1/feed", "", nil, http.StatusUnauthorized, api.CodeUnauthorized},
+        {"not the creator", "DELETE", "/api/v1/subreddits/" + subreddit.ID, bob.ID, nil, http.StatusForbidden, api.CodeNotCreator},
+    }
+    for _, tt := range tests {
+        rec := serve(t, s, tt.method, tt.path, tt.userID, tt.body)
+        if rec.Code != tt.status {
+            t.Errorf("%s: status = %d, want %d; body: %s", tt.name, rec.Code, tt.status, rec.Body)
+            continue
+        }
+        var body api.ErrorResponse
+        decodeBody(t, rec, &body)
+        if body.Code != tt.code || body.Error == "" {
+            t.Errorf("%s: code = %q, error %q; want %q with a message", tt.name, body.Code, body.Error, tt.code)
+        }
+    }
+}
\ No newline at end of file
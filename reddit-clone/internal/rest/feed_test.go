@@ -0,0 +1,178 @@
+// internal/rest/feed_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+    "reddit-clone/internal/engine"
+)
+
+func TestFeedPreview(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, "Post", "Content", alice.ID, subreddit.ID)
+    quiet := mustPost(t, e, "Quiet", "No comments", alice.ID, subreddit.ID)
+    first, err := e.CreateComment("First", alice.ID, post.ID, nil)
+    if err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+    best, err := e.CreateComment("Best", bob.ID, post.ID, nil)
+    if err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+    if _, err := e.CreateComment("Reply", alice.ID, post.ID, &first.ID); err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+    if _, err := e.SetVote(alice.ID, best.ID, engine.VoteUp); err != nil {
+        t.Fatalf("SetVote: %v", err)
+    }
+
+    feed := func(query string) map[string]api.PostResponse {
+        t.Helper()
+        rec := serve(t, s, "GET", "/api/v1/feed"+query, alice.ID, nil)
+        wantStatus(t, rec, http.StatusOK)
+        var posts []api.PostResponse
+        decodeBody(t, rec, &posts)
+        byID := make(map[string]api.PostResponse, len(posts))
+        for _, p := range posts {
+            byID[p.ID] = p
+        }
+        return byID
+    }
+
+    if got := feed(""); got[post.ID].TopComment != nil {
+        t.Errorf("feed without preview has a top comment")
+    }
+
+    got := feed("?preview=true")
+    entry := got[post.ID]
+    if entry.CommentCount != 3 {
+        t.Errorf("comment_count = %d, want 3", entry.CommentCount)
+    }
+    if entry.TopComment == nil || entry.TopComment.ID != best.ID || entry.TopComment.Score != 1 {
+        t.Errorf("top_comment = %+v, want the upvoted comment", entry.TopComment)
+    }
+    if quietEntry, ok := got[quiet.ID]; !ok || quietEntry.TopComment != nil || quietEntry.CommentCount != 0 {
+        t.Errorf("post without comments = %+v, want no top comment and a zero count", quietEntry)
+    }
+}
+
+func TestFeedHideOwn(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", bob.ID)
+    if _, err := e.JoinSubReddit(alice.ID, subreddit.ID); err != nil {
+        t.Fatalf("JoinSubReddit: %v", err)
+    }
+    own := []string{
+        mustPost(t, e, "Mine", "Content", alice.ID, subreddit.ID).ID,
+        mustPost(t, e, "Also mine", "Content", alice.ID, subreddit.ID).ID,
+    }
+    others := []string{
+        mustPost(t, e, "Bob's", "Content", bob.ID, subreddit.ID).ID,
+        mustPost(t, e, "Bob's too", "Content", bob.ID, subreddit.ID).ID,
+    }
+
+    feed := func(query string) map[string]bool {
+        t.Helper()
+        rec := serve(t, s, "GET", "/api/v1/feed"+query, alice.ID, nil)
+        wantStatus(t, rec, http.StatusOK)
+        var posts []api.PostResponse
+        decodeBody(t, rec, &posts)
+        ids := make(map[string]bool, len(posts))
+        for _, p := range posts {
+            ids[p.ID] = true
+        }
+        return ids
+    }
+
+    tests := []struct {
+        query string
+        want  []string
+    }{
+        {"", append(append([]string{}, own...), others...)},
+        {"?hide_own=false", append(append([]string{}, own...), others...)},
+        {"?hide_own=true", others},
+        // Hidden posts don't take up room on a personalized page
+        {"?sort=personalized&hide_own=true&limit=2", others},
+    }
+    for _, tt := range tests {
+        got := feed(tt.query)
+        if len(got) != len(tt.want) {
+            t.Errorf("feed%s has %d posts, want %d", tt.query, len(got), len(tt.want))
+        }
+        for _, id := range tt.want {
+            if !got[id] {
+                t.Errorf("feed%s is missing post %s", tt.query, id)
+            }
+        }
+    }
+}
+
+func TestFeedUsesStoredSortPreference(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", bob.ID)
+    if _, err := e.JoinSubReddit(alice.ID, subreddit.ID); err != nil {
+        t.Fatalf("JoinSubReddit: %v", err)
+    }
+    liked := mustPost(t, e, "Liked", "Content", bob.ID, subreddit.ID)
+    if _, err := e.SetVote(alice.ID, liked.ID, engine.VoteUp); err != nil {
+        t.Fatalf("SetVote: %v", err)
+    }
+    mustPost(t, e, "Other", "Content", bob.ID, subreddit.ID)
+
+    // The personalized feed pages and ranks the upvoted post first; the
+    // new feed ignores limit, so the post count tells the sorts apart
+    feed := func(query string) []api.PostResponse {
+        t.Helper()
+        rec := serve(t, s, "GET", "/api/v1/feed"+query, alice.ID, nil)
+        wantStatus(t, rec, http.StatusOK)
+        var posts []api.PostResponse
+        decodeBody(t, rec, &posts)
+        return posts
+    }
+    wantNew := func(query string) {
+        t.Helper()
+        if posts := feed(query); len(posts) != 2 {
+            t.Errorf("feed%s has %d posts, want the whole new feed", query, len(posts))
+        }
+    }
+    wantPersonalized := func(query string) {
+        t.Helper()
+        if posts := feed(query); len(posts) != 1 || posts[0].ID != liked.ID {
+            t.Errorf("feed%s = %+v, want the first personalized page", query, posts)
+        }
+    }
+    setPreference := func(sort string, status int) {
+        t.Helper()
+        rec := serve(t, s, "PUT", "/api/v1/users/me/preferences", alice.ID, api.PreferencesRequest{FeedSort: sort})
+        wantStatus(t, rec, status)
+    }
+
+    wantNew("?limit=1")
+
+    setPreference(engine.FeedSortPersonalized, http.StatusOK)
+    if preference, err := e.FeedPreference(alice.ID); err != nil || preference != engine.FeedSortPersonalized {
+        t.Fatalf("stored preference = %q (%v), want personalized", preference, err)
+    }
+    wantPersonalized("?limit=1")
+    // An explicit sort still wins
+    wantNew("?sort=new&limit=1")
+
+    // Unknown sorts are refused and leave the preference alone
+    setPreference("top", http.StatusBadRequest)
+    wantPersonalized("?limit=1")
+
+    // An empty sort clears it
+    setPreference("", http.StatusOK)
+    wantNew("?limit=1")
+
+    wantStatus(t, serve(t, s, "PUT", "/api/v1/users/me/preferences", "", api.PreferencesRequest{FeedSort: engine.FeedSortNew}), http.StatusUnauthorized)
+}
\ No newline at end of file
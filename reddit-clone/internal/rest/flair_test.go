@@ -0,0 +1,55 @@
+// internal/rest/flair_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+)
+
+func TestPostFlairs(t *testing.T) {
+    s, e := newTestServer(t)
+    mod := mustRegister(t, e, "mod")
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", mod.ID)
+    flairsPath := "/api/v1/subreddits/" + subreddit.ID + "/flairs"
+
+    rec := serve(t, s, "PUT", flairsPath, alice.ID, api.FlairsRequest{Flairs: []string{"News"}})
+    wantStatus(t, rec, http.StatusForbidden)
+
+    rec = serve(t, s, "PUT", flairsPath, mod.ID, api.FlairsRequest{Flairs: []string{"News", "Question"}})
+    wantStatus(t, rec, http.StatusOK)
+    rec = serve(t, s, "GET", flairsPath, alice.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+    var flairs api.FlairsResponse
+    decodeBody(t, rec, &flairs)
+    if len(flairs.Flairs) != 2 || flairs.Flairs[0] != "News" {
+        t.Errorf("flairs = %v, want [News Question]", flairs.Flairs)
+    }
+
+    rec = serve(t, s, "POST", "/api/v1/posts", mod.ID, api.PostRequest{Title: "Bad", Content: "Content", SubredditID: subreddit.ID, Flair: "Meme"})
+    wantStatus(t, rec, http.StatusBadRequest)
+    var errResp api.ErrorResponse
+    decodeBody(t, rec, &errResp)
+    if errResp.Code != api.CodeInvalidFlair {
+        t.Errorf("error code = %q, want %q", errResp.Code, api.CodeInvalidFlair)
+    }
+
+    rec = serve(t, s, "POST", "/api/v1/posts", mod.ID, api.PostRequest{Title: "Go 1.23", Content: "Content", SubredditID: subreddit.ID, Flair: "News"})
+    wantStatus(t, rec, http.StatusCreated)
+    var created api.PostResponse
+    decodeBody(t, rec, &created)
+    if created.Flair != "News" {
+        t.Errorf("created flair = %q, want News", created.Flair)
+    }
+    mustPost(t, e, "Plain", "Content", mod.ID, subreddit.ID)
+
+    rec = serve(t, s, "GET", "/api/v1/posts?subreddit_id="+subreddit.ID+"&flair=News", alice.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+    var list api.PostListResponse
+    decodeBody(t, rec, &list)
+    if len(list.Posts) != 1 || list.Posts[0].ID != created.ID {
+        t.Errorf("flair=News listed %d posts, want only %s", len(list.Posts), created.ID)
+    }
+}
\ No newline at end of file
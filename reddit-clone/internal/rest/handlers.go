@@ -2,256 +2,1514 @@
 package rest
 
 import (
-    "encoding/json"
+    "errors"
     "net/http"
+    "strconv"
+    "time"
     "github.com/gorilla/mux"
     
     "reddit-clone/api/v1"
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/models"
 )
 
 // User handlers
 func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
     var req api.RegisterRequest
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+    if !s.decodeRequest(w, r, &req) {
         return
     }
 
-    user, err := s.engine.RegisterAccount(req.Username, req.Password)
+    user, token, err := s.engine.RegisterAccountWithEmail(req.Username, req.Password, req.Email)
     if err != nil {
-        respondWithError(w, http.StatusBadRequest, err.Error())
+        respondWithAppError(w, r, http.StatusBadRequest, err)
         return
     }
 
-    resp := api.UserResponse{
-        ID:        user.ID,
-        Username:  user.Username,
-        Karma:     user.Karma,
-        CreatedAt: user.CreatedAt,
+    resp := newUserResponse(user)
+    resp.VerificationToken = token
+    respond(w, r, http.StatusCreated, resp)
+}
+
+func (s *Server) handleGetMe(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    user, err := s.engine.GetUser(userID)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "User not found")
+        return
+    }
+
+    respond(w, r, http.StatusOK, newUserResponse(user))
+}
+
+func (s *Server) handleChangeUsername(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    var req api.ChangeUsernameRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
+
+    err := s.engine.ChangeUsername(userID, req.Username)
+    if errors.Is(err, engine.ErrUsernameTaken) {
+        respondWithAppError(w, r, http.StatusConflict, err)
+        return
+    }
+    if errors.Is(err, engine.ErrRenamingTooFast) {
+        respondWithAppError(w, r, http.StatusTooManyRequests, err)
+        return
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "User not found")
+        return
+    }
+
+    s.handleGetMe(w, r)
+}
+
+func (s *Server) handleSetPreferences(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    var req api.PreferencesRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
+
+    err := s.engine.SetFeedPreference(userID, req.FeedSort)
+    if errors.Is(err, engine.ErrInvalidFeedSort) {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "User not found")
+        return
+    }
+
+    respond(w, r, http.StatusOK, api.PreferencesResponse{FeedSort: req.FeedSort})
+}
+
+func (s *Server) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    var req api.VerifyEmailRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
+
+    err := s.engine.VerifyEmail(userID, req.Token)
+    if errors.Is(err, engine.ErrInvalidVerificationToken) {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "User not found")
+        return
     }
-    respondWithJSON(w, http.StatusCreated, resp)
+
+    s.handleGetMe(w, r)
 }
 
 // Subreddit handlers
 func (s *Server) handleCreateSubreddit(w http.ResponseWriter, r *http.Request) {
     var req api.SubredditRequest
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+    if !s.decodeRequest(w, r, &req) {
         return
     }
 
     // Get user ID from context (after implementing auth middleware)
-    userID := r.Context().Value("userID").(string)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
 
-    subreddit, err := s.engine.CreateSubReddit(req.Name, req.Description, userID)
+    opts := engine.SubredditOptions{MaxMembers: req.MaxMembers, Type: req.Type, QueuePosts: req.QueuePosts, AllowAnonymous: req.AllowAnonymous}
+    if req.AllowCrossposts != nil {
+        opts.NoCrossposts = !*req.AllowCrossposts
+    }
+    subreddit, err := s.engine.CreateSubRedditWithOptions(req.Name, req.Description, userID, opts)
+    if errors.Is(err, engine.ErrUserBanned) || errors.Is(err, engine.ErrSubredditLimit) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
     if err != nil {
-        respondWithError(w, http.StatusBadRequest, err.Error())
+        respondWithAppError(w, r, http.StatusBadRequest, err)
         return
     }
 
-    resp := api.SubredditResponse{
-        ID:          subreddit.ID,
-        Name:        subreddit.Name,
-        Description: subreddit.Description,
-        MemberCount: subreddit.MemberCount,
-        CreatedAt:   subreddit.CreatedAt,
-    }
-    respondWithJSON(w, http.StatusCreated, resp)
+    respond(w, r, http.StatusCreated, newSubredditResponse(subreddit))
 }
 
-func (s *Server) handleJoinSubreddit(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleUpdateSubreddit(w http.ResponseWriter, r *http.Request) {
     vars := mux.Vars(r)
     subredditID := vars["id"]
-    userID := r.Context().Value("userID").(string)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    var req api.UpdateSubredditRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
 
-    err := s.engine.JoinSubReddit(userID, subredditID)
+    update := engine.SubredditUpdate{
+        Description: req.Description,
+        MaxMembers:  req.MaxMembers,
+        Type:        req.Type,
+        QueuePosts:  req.QueuePosts,
+
+        AllowCrossposts: req.AllowCrossposts,
+        AllowAnonymous:  req.AllowAnonymous,
+    }
+    subreddit, err := s.engine.UpdateSubReddit(userID, subredditID, update)
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
     if err != nil {
-        respondWithError(w, http.StatusBadRequest, err.Error())
+        respondWithAppError(w, r, http.StatusBadRequest, err)
         return
     }
 
-    respondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+    respond(w, r, http.StatusOK, newSubredditResponse(subreddit))
 }
 
-func (s *Server) handleLeaveSubreddit(w http.ResponseWriter, r *http.Request) {
+// handleAddWebhook registers an outbound webhook for a subreddit; moderators only
+func (s *Server) handleAddWebhook(w http.ResponseWriter, r *http.Request) {
     vars := mux.Vars(r)
     subredditID := vars["id"]
-    userID := r.Context().Value("userID").(string)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
 
-    err := s.engine.LeaveSubReddit(userID, subredditID)
+    var req api.WebhookRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
+
+    webhook, err := s.engine.AddWebhook(userID, subredditID, req.URL)
+    if errors.Is(err, engine.ErrSubredditNotFound) {
+        respondWithAppError(w, r, http.StatusNotFound, err)
+        return
+    }
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
     if err != nil {
-        respondWithError(w, http.StatusBadRequest, err.Error())
+        respondWithAppError(w, r, http.StatusBadRequest, err)
         return
     }
 
-    respondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+    respond(w, r, http.StatusCreated, api.WebhookResponse{
+        ID:          webhook.ID,
+        SubredditID: webhook.SubRedditID,
+        URL:         webhook.URL,
+        CreatedAt:   webhook.CreatedAt,
+    })
 }
 
-// Post handlers
-func (s *Server) handleCreatePost(w http.ResponseWriter, r *http.Request) {
-    var req api.PostRequest
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+// handleApprovePoster lets a user post in a restricted subreddit or join a
+// private one; moderators only
+func (s *Server) handleApprovePoster(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    if !s.changeApproval(w, r, s.engine.ApprovePoster(userID, vars["id"], vars["userId"])) {
+        return
+    }
+    respond(w, r, http.StatusOK, map[string]string{"status": "success"})
+}
+
+// handleRemoveApprovedPoster withdraws a user's approval; moderators only
+func (s *Server) handleRemoveApprovedPoster(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    if !s.changeApproval(w, r, s.engine.RemoveApprovedPoster(userID, vars["id"], vars["userId"])) {
         return
     }
+    respond(w, r, http.StatusOK, map[string]string{"status": "success"})
+}
+
+// changeApproval maps an approval change error to a response, reporting
+// whether the change succeeded
+func (s *Server) changeApproval(w http.ResponseWriter, r *http.Request, err error) bool {
+    if errors.Is(err, engine.ErrSubredditNotFound) || errors.Is(err, engine.ErrUserNotFound) {
+        respondWithAppError(w, r, http.StatusNotFound, err)
+        return false
+    }
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return false
+    }
+    if err != nil {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return false
+    }
+    return true
+}
 
-    userID := r.Context().Value("userID").(string)
+// handleGetPendingPosts serves a subreddit's queue of posts awaiting
+// approval; moderators only
+func (s *Server) handleGetPendingPosts(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
 
-    post, err := s.engine.CreatePost(req.Title, req.Content, userID, req.SubredditID)
+    posts, err := s.engine.GetPendingPosts(userID, vars["id"])
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
     if err != nil {
-        respondWithError(w, http.StatusBadRequest, err.Error())
+        respondWithError(w, r, http.StatusNotFound, "Subreddit not found")
         return
     }
 
-    resp := api.PostResponse{
-        ID:          post.ID,
-        Title:       post.Title,
-        Content:     post.Content,
-        AuthorID:    post.AuthorID,
-        SubredditID: post.SubRedditID,
-        Upvotes:     post.Upvotes,
-        Downvotes:   post.Downvotes,
-        CreatedAt:   post.CreatedAt,
+    resp := api.PostListResponse{
+        Posts:    make([]api.PostResponse, len(posts)),
+        Total:    len(posts),
+        PageInfo: api.NewPageInfo(1, 0, len(posts)),
     }
-    respondWithJSON(w, http.StatusCreated, resp)
+    for i, post := range posts {
+        resp.Posts[i] = s.newPostResponse(r, post)
+    }
+    respond(w, r, http.StatusOK, resp)
 }
 
-func (s *Server) handleGetPost(w http.ResponseWriter, r *http.Request) {
+// handleGetCrosspostTargets lists the subreddits the caller could
+// crosspost a post to
+func (s *Server) handleGetCrosspostTargets(w http.ResponseWriter, r *http.Request) {
     vars := mux.Vars(r)
-    postID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
 
-    // Add method to engine to get single post
-    post, err := s.engine.GetPost(postID)
+    subreddits, err := s.engine.GetCrosspostTargets(userID, vars["id"])
     if err != nil {
-        respondWithError(w, http.StatusNotFound, "Post not found")
+        respondWithError(w, r, http.StatusNotFound, "Post not found")
         return
     }
 
-    resp := api.PostResponse{
-        ID:          post.ID,
-        Title:       post.Title,
-        Content:     post.Content,
-        AuthorID:    post.AuthorID,
-        SubredditID: post.SubRedditID,
-        Upvotes:     post.Upvotes,
-        Downvotes:   post.Downvotes,
-        CreatedAt:   post.CreatedAt,
+    resp := api.SubredditListResponse{
+        Subreddits: make([]api.SubredditResponse, len(subreddits)),
+        Total:      len(subreddits),
+        PageInfo:   api.NewPageInfo(1, 0, len(subreddits)),
+    }
+    for i, subreddit := range subreddits {
+        resp.Subreddits[i] = newSubredditResponse(subreddit)
     }
-    respondWithJSON(w, http.StatusOK, resp)
+    respond(w, r, http.StatusOK, resp)
 }
 
-func (s *Server) handleVote(w http.ResponseWriter, r *http.Request) {
+// handleApprovePost publishes a queued post; moderators only
+func (s *Server) handleApprovePost(w http.ResponseWriter, r *http.Request) {
     vars := mux.Vars(r)
-    targetID := vars["id"]
-    userID := r.Context().Value("userID").(string)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
 
-    var req api.VoteRequest
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+    post, err := s.engine.ApprovePost(userID, vars["id"])
+    if !s.reviewPost(w, r, err) {
         return
     }
+    respond(w, r, http.StatusOK, s.newPostResponse(r, post))
+}
 
-    err := s.engine.Vote(userID, targetID, req.IsUpvote)
-    if err != nil {
-        respondWithError(w, http.StatusBadRequest, err.Error())
+// handleRejectPost discards a queued post; moderators only
+func (s *Server) handleRejectPost(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
         return
     }
 
-    respondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+    if !s.reviewPost(w, r, s.engine.RejectPost(userID, vars["id"])) {
+        return
+    }
+    respond(w, r, http.StatusOK, map[string]string{"status": "success"})
 }
 
-// Feed handler
-func (s *Server) handleGetFeed(w http.ResponseWriter, r *http.Request) {
-    userID := r.Context().Value("userID").(string)
+// handleRestorePost brings an auto-removed post back; moderators only
+func (s *Server) handleRestorePost(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
 
-    posts, err := s.engine.GetFeed(userID)
-    if err != nil {
-        respondWithError(w, http.StatusInternalServerError, err.Error())
+    post, err := s.engine.RestorePost(userID, vars["id"])
+    if !restoreSucceeded(w, r, err, "Post not found") {
         return
     }
+    respond(w, r, http.StatusOK, s.newPostResponse(r, post))
+}
 
-    var resp []api.PostResponse
-    for _, post := range posts {
-        resp = append(resp, api.PostResponse{
-            ID:          post.ID,
-            Title:       post.Title,
-            Content:     post.Content,
-            AuthorID:    post.AuthorID,
-            SubredditID: post.SubRedditID,
-            Upvotes:     post.Upvotes,
-            Downvotes:   post.Downvotes,
-            CreatedAt:   post.CreatedAt,
-        })
+// handleRestoreComment brings an auto-removed comment back; moderators only
+func (s *Server) handleRestoreComment(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    comment, err := s.engine.RestoreComment(userID, vars["id"])
+    if !restoreSucceeded(w, r, err, "Comment not found") {
+        return
     }
-    respondWithJSON(w, http.StatusOK, resp)
+    respond(w, r, http.StatusOK, s.newCommentResponse(r, comment))
 }
 
-// Message handlers
-func (s *Server) handleGetMessages(w http.ResponseWriter, r *http.Request) {
-    userID := r.Context().Value("userID").(string)
+// restoreSucceeded maps a restore error to a response, reporting whether
+// the restore succeeded
+func restoreSucceeded(w http.ResponseWriter, r *http.Request, err error, notFound string) bool {
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return false
+    }
+    if errors.Is(err, engine.ErrNotAutoRemoved) {
+        respondWithAppError(w, r, http.StatusConflict, err)
+        return false
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return false
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, notFound)
+        return false
+    }
+    return true
+}
+
+// reviewPost maps an approve or reject error to a response, reporting
+// whether the review succeeded
+func (s *Server) reviewPost(w http.ResponseWriter, r *http.Request, err error) bool {
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return false
+    }
+    if errors.Is(err, engine.ErrPostNotPending) {
+        respondWithAppError(w, r, http.StatusConflict, err)
+        return false
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return false
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Post not found")
+        return false
+    }
+    return true
+}
+
+func (s *Server) handleGetFlairs(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    subredditID := vars["id"]
 
-    messages, err := s.engine.GetUserMessages(userID)
+    if !s.canViewSubreddit(w, r, subredditID) {
+        return
+    }
+    flairs, err := s.engine.GetSubredditFlairs(subredditID)
     if err != nil {
-        respondWithError(w, http.StatusInternalServerError, err.Error())
+        respondWithAppError(w, r, http.StatusNotFound, err)
         return
     }
 
-    respondWithJSON(w, http.StatusOK, messages)
+    respond(w, r, http.StatusOK, api.FlairsResponse{SubredditID: subredditID, Flairs: flairs})
 }
 
-func (s *Server) handleSendMessage(w http.ResponseWriter, r *http.Request) {
-    userID := r.Context().Value("userID").(string)
+// handleSetFlairs replaces a subreddit's allowed flairs; moderators only
+func (s *Server) handleSetFlairs(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    subredditID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    var req api.FlairsRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
 
-    var req struct {
-        ToID    string `json:"to_id"`
-        Content string `json:"content"`
+    flairs, err := s.engine.SetSubredditFlairs(userID, subredditID, req.Flairs)
+    if errors.Is(err, engine.ErrSubredditNotFound) {
+        respondWithAppError(w, r, http.StatusNotFound, err)
+        return
+    }
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if err != nil {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
     }
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+
+    respond(w, r, http.StatusOK, api.FlairsResponse{SubredditID: subredditID, Flairs: flairs})
+}
+
+// handleGetBannedWords returns a subreddit's banned word list; moderators only
+func (s *Server) handleGetBannedWords(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    subredditID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
         return
     }
 
-    message, err := s.engine.SendDirectMessage(userID, req.ToID, req.Content)
+    words, err := s.engine.GetBannedWords(userID, subredditID)
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
     if err != nil {
-        respondWithError(w, http.StatusBadRequest, err.Error())
+        respondWithAppError(w, r, http.StatusNotFound, err)
         return
     }
 
-    respondWithJSON(w, http.StatusCreated, message)
+    respond(w, r, http.StatusOK, api.BannedWordsResponse{SubredditID: subredditID, Words: words})
 }
 
-// Add this to internal/rest/handlers.go
-func (s *Server) handleCreateComment(w http.ResponseWriter, r *http.Request) {
+// handleSetBannedWords replaces a subreddit's banned word list; moderators only
+func (s *Server) handleSetBannedWords(w http.ResponseWriter, r *http.Request) {
     vars := mux.Vars(r)
-    postID := vars["id"]
-    userID := r.Context().Value("userID").(string)
+    subredditID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
 
-    var req api.CommentRequest
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+    var req api.BannedWordsRequest
+    if !s.decodeRequest(w, r, &req) {
         return
     }
 
-    comment, err := s.engine.CreateComment(
-        req.Content,
-        userID,
-        postID,
-        req.ParentID,
-    )
+    words, err := s.engine.SetBannedWords(userID, subredditID, req.Words)
+    if errors.Is(err, engine.ErrSubredditNotFound) {
+        respondWithAppError(w, r, http.StatusNotFound, err)
+        return
+    }
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if err != nil {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+
+    respond(w, r, http.StatusOK, api.BannedWordsResponse{SubredditID: subredditID, Words: words})
+}
+
+// handleArchiveSubreddit archives or unarchives a subreddit; moderators only
+func (s *Server) handleArchiveSubreddit(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    subredditID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    var req api.ArchiveRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
+
+    err := s.engine.ArchiveSubreddit(userID, subredditID, req.Archived)
+    if errors.Is(err, engine.ErrSubredditNotFound) {
+        respondWithAppError(w, r, http.StatusNotFound, err)
+        return
+    }
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if err != nil {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+
+    subreddit, err := s.engine.GetSubReddit(subredditID)
+    if err != nil {
+        respondWithAppError(w, r, http.StatusNotFound, err)
+        return
+    }
+    respond(w, r, http.StatusOK, newSubredditResponse(subreddit))
+}
+
+// handleDeleteSubreddit deletes a subreddit and everything in it; creator only
+func (s *Server) handleDeleteSubreddit(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    subredditID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    err := s.engine.DeleteSubReddit(userID, subredditID)
+    if errors.Is(err, engine.ErrSubredditNotFound) {
+        respondWithAppError(w, r, http.StatusNotFound, err)
+        return
+    }
+    if errors.Is(err, engine.ErrNotCreator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
     if err != nil {
-        respondWithError(w, http.StatusBadRequest, err.Error())
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+
+    respond(w, r, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func (s *Server) handleJoinSubreddit(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    subredditID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
         return
     }
 
-    resp := api.CommentResponse{
-        ID:        comment.ID,
-        Content:   comment.Content,
-        AuthorID:  comment.AuthorID,
-        PostID:    comment.PostID,
-        ParentID:  comment.ParentID,
-        Upvotes:   comment.Upvotes,
-        Downvotes: comment.Downvotes,
-        CreatedAt: comment.CreatedAt,
+    joined, err := s.engine.JoinSubReddit(userID, subredditID)
+    if errors.Is(err, engine.ErrSubredditNotFound) || errors.Is(err, engine.ErrUserNotFound) {
+        respondWithAppError(w, r, http.StatusNotFound, err)
+        return
+    }
+    if errors.Is(err, engine.ErrUserBanned) || errors.Is(err, engine.ErrSubredditPrivate) || errors.Is(err, engine.ErrSubredditArchived) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
     }
-    respondWithJSON(w, http.StatusCreated, resp)
+    if errors.Is(err, engine.ErrSubredditFull) {
+        respondWithAppError(w, r, http.StatusConflict, err)
+        return
+    }
+    if err != nil {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+
+    respond(w, r, http.StatusOK, api.JoinResponse{
+        Status:      "success",
+        SubredditID: subredditID,
+        Joined:      joined,
+    })
+}
+
+func (s *Server) handleLeaveSubreddit(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    subredditID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    err := s.engine.LeaveSubReddit(userID, subredditID)
+    if err != nil {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+
+    respond(w, r, http.StatusOK, map[string]string{"status": "success"})
+}
+
+// Post handlers
+func (s *Server) handleCreatePost(w http.ResponseWriter, r *http.Request) {
+    var req api.PostRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
+
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    opts := engine.PostOptions{
+        Distinguished: req.Distinguished,
+        Flair:         req.Flair,
+        NSFW:          req.NSFW,
+        Anonymous:     req.Anonymous,
+        ScheduledFor:  req.ScheduledFor,
+    }
+    post, err := s.engine.CreatePostWithOptions(req.Title, req.Content, userID, req.SubredditID, opts)
+    if errors.Is(err, engine.ErrPostingTooFast) {
+        respondWithAppError(w, r, http.StatusTooManyRequests, err)
+        return
+    }
+    if errors.Is(err, engine.ErrDuplicatePost) {
+        respondWithAppError(w, r, http.StatusConflict, err)
+        return
+    }
+    if errors.Is(err, engine.ErrUserBanned) || errors.Is(err, engine.ErrNotModerator) || errors.Is(err, engine.ErrEmailNotVerified) || errors.Is(err, engine.ErrNotApprovedPoster) || errors.Is(err, engine.ErrAccountTooNew) || errors.Is(err, engine.ErrSubredditArchived) || errors.Is(err, engine.ErrAnonymousNotAllowed) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if err != nil {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+
+    // A queued or scheduled post is accepted but not yet published
+    status := http.StatusCreated
+    if post.Pending || post.ScheduledFor != nil {
+        status = http.StatusAccepted
+    }
+    respond(w, r, status, s.newPostResponse(r, post))
+}
+
+func (s *Server) handleGetPost(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    postID := vars["id"]
+
+    // Add method to engine to get single post
+    post, err := s.engine.GetPost(postID)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Post not found")
+        return
+    }
+    if !s.canViewPost(w, r, post) {
+        return
+    }
+
+    respond(w, r, http.StatusOK, s.newPostResponse(r, post))
+}
+
+// handleGetPostBySlug resolves a post permalink within a subreddit
+func (s *Server) handleGetPostBySlug(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    if !s.canViewSubreddit(w, r, vars["id"]) {
+        return
+    }
+
+    post, err := s.engine.GetPostBySlug(vars["id"], vars["slug"])
+    if errors.Is(err, engine.ErrSubredditNotFound) {
+        respondWithError(w, r, http.StatusNotFound, "Subreddit not found")
+        return
+    }
+    if userID, _ := userIDFromContext(r); err != nil || !s.engine.CanSeePost(userID, post) {
+        respondWithError(w, r, http.StatusNotFound, "Post not found")
+        return
+    }
+
+    respond(w, r, http.StatusOK, s.newPostResponse(r, post))
+}
+
+func (s *Server) handleGetPostsBatch(w http.ResponseWriter, r *http.Request) {
+    var postIDs []string
+    if !s.decodeRequest(w, r, &postIDs) {
+        return
+    }
+
+    posts, err := s.engine.GetPosts(postIDs)
+    if err != nil {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+
+    // Posts the caller can't see, such as pending posts they can't review or
+    // posts in private subreddits they aren't in, are left out like unknown IDs
+    userID, _ := userIDFromContext(r)
+    resp := make([]api.PostResponse, 0, len(posts))
+    for _, post := range posts {
+        if s.engine.CanSeePost(userID, post) {
+            resp = append(resp, s.newPostResponse(r, post))
+        }
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) handleVote(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    targetID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    s.castVote(w, r, userID, targetID)
+}
+
+// castVote applies a post or comment vote request and responds with the
+// target's updated counts
+func (s *Server) castVote(w http.ResponseWriter, r *http.Request, userID, targetID string) {
+    var req api.VoteRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
+
+    var result engine.VoteResult
+    var err error
+    if r.Method == http.MethodPut {
+        direction := engine.VoteDown
+        if req.IsUpvote {
+            direction = engine.VoteUp
+        }
+        if req.Direction != nil {
+            direction = *req.Direction
+        }
+        result, err = s.engine.SetVote(userID, targetID, direction)
+    } else {
+        result, err = s.engine.ToggleVote(userID, targetID, req.IsUpvote)
+    }
+    if errors.Is(err, engine.ErrUserBanned) || errors.Is(err, engine.ErrSubredditArchived) || errors.Is(err, engine.ErrSubredditPrivate) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if err != nil {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+
+    upvotes, downvotes := s.engine.DisplayVotes(targetID, result.Upvotes, result.Downvotes)
+    respond(w, r, http.StatusOK, api.VoteResponse{
+        Upvotes:   upvotes,
+        Downvotes: downvotes,
+        Score:     upvotes - downvotes,
+        UserVote:  result.UserVote,
+    })
+}
+
+// Report handlers
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    targetID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    var req api.ReportRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
+
+    err := s.engine.Report(userID, targetID, req.Reason)
+    if errors.Is(err, engine.ErrAlreadyReported) {
+        respondWithAppError(w, r, http.StatusConflict, err)
+        return
+    }
+    if errors.Is(err, engine.ErrSubredditPrivate) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if err != nil {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+
+    respond(w, r, http.StatusCreated, map[string]string{"status": "success"})
+}
+
+func (s *Server) handleGetReports(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    subredditID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    reports, err := s.engine.GetReports(userID, subredditID)
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Subreddit not found")
+        return
+    }
+
+    var resp []api.ReportResponse
+    for _, report := range reports {
+        resp = append(resp, newReportResponse(report))
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+// handleResolveReport closes a report a moderator has acted on
+func (s *Server) handleResolveReport(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    report, err := s.engine.ResolveReport(userID, mux.Vars(r)["id"])
+    if !closedReport(w, r, err) {
+        return
+    }
+    respond(w, r, http.StatusOK, newReportResponse(report))
+}
+
+// handleDismissReport closes a report a moderator found no fault with
+func (s *Server) handleDismissReport(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    report, err := s.engine.DismissReport(userID, mux.Vars(r)["id"])
+    if !closedReport(w, r, err) {
+        return
+    }
+    respond(w, r, http.StatusOK, newReportResponse(report))
+}
+
+// closedReport maps a resolve or dismiss error to a response, reporting
+// whether the report was closed
+func closedReport(w http.ResponseWriter, r *http.Request, err error) bool {
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return false
+    }
+    if errors.Is(err, engine.ErrReportNotPending) {
+        respondWithAppError(w, r, http.StatusConflict, err)
+        return false
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return false
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Report not found")
+        return false
+    }
+    return true
+}
+
+func newReportResponse(report *models.Report) api.ReportResponse {
+    return api.ReportResponse{
+        ID:          report.ID,
+        ReporterID:  report.ReporterID,
+        TargetID:    report.TargetID,
+        TargetType:  report.TargetType,
+        SubredditID: report.SubRedditID,
+        Reason:      report.Reason,
+        CreatedAt:   report.CreatedAt,
+        Status:      report.Status,
+        ClosedBy:    report.ClosedBy,
+        ClosedAt:    report.ClosedAt,
+    }
+}
+
+// Feed handler
+func (s *Server) handleGetFeed(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    query := r.URL.Query()
+    // Own posts are shown unless the user opts out
+    hideOwn := query.Get("hide_own") == "true"
+
+    // Without an explicit sort the user's stored preference applies
+    feedSort := query.Get("sort")
+    if feedSort == "" {
+        preference, err := s.engine.FeedPreference(userID)
+        if err != nil {
+            respondWithError(w, r, http.StatusNotFound, "User not found")
+            return
+        }
+        feedSort = preference
+    }
+
+    var posts []*models.Post
+    var err error
+    switch feedSort {
+    case "", engine.FeedSortNew:
+        opts := engine.FeedOptions{
+            DedupeReposts: query.Get("dedupe") == "true",
+            Flair:         query.Get("flair"),
+            HideOwn:       hideOwn,
+        }
+        posts, err = s.engine.GetFeedWithOptions(r.Context(), userID, opts)
+    case engine.FeedSortPersonalized:
+        if query.Get("flair") != "" {
+            respondWithError(w, r, http.StatusBadRequest, "flair filtering is not supported with sort=personalized")
+            return
+        }
+        // Personalized ranking is paginated; without a limit the whole feed is returned
+        page, ok := positiveQueryInt(w, r, "page", 1)
+        if !ok {
+            return
+        }
+        limit, ok := positiveQueryInt(w, r, "limit", 0)
+        if !ok {
+            return
+        }
+        opts := engine.PersonalizedFeedOptions{HideOwn: hideOwn}
+        posts, err = s.engine.GetPersonalizedFeedWithOptions(userID, page, limit, opts)
+    default:
+        respondWithError(w, r, http.StatusBadRequest, "Invalid sort")
+        return
+    }
+    if err != nil {
+        respondWithAppError(w, r, http.StatusInternalServerError, err)
+        return
+    }
+
+    // Look up the user's own votes so the client can render vote state
+    postIDs := make([]string, len(posts))
+    for i, post := range posts {
+        postIDs[i] = post.ID
+    }
+    votes, err := s.engine.GetUserVotes(userID, postIDs)
+    if err != nil {
+        respondWithAppError(w, r, http.StatusInternalServerError, err)
+        return
+    }
+
+    // Previews add each post's top comment, which costs a comment scan per post
+    preview := r.URL.Query().Get("preview") == "true"
+
+    var resp []api.PostResponse
+    for _, post := range posts {
+        postResp := s.newPostResponse(r, post)
+        postResp.UserVote = userVoteValue(votes, post.ID)
+        if preview {
+            commentCount, topComment, err := s.engine.GetPostPreview(post.ID)
+            if err != nil {
+                respondWithAppError(w, r, http.StatusInternalServerError, err)
+                return
+            }
+            postResp.CommentCount = commentCount
+            if topComment != nil {
+                commentResp := s.newCommentResponse(r, topComment)
+                postResp.TopComment = &commentResp
+            }
+        }
+        resp = append(resp, postResp)
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+// positiveQueryInt reads an optional positive integer query parameter,
+// responding with 400 and returning false when it is malformed
+func positiveQueryInt(w http.ResponseWriter, r *http.Request, name string, def int) (int, bool) {
+    str := r.URL.Query().Get(name)
+    if str == "" {
+        return def, true
+    }
+    n, err := strconv.Atoi(str)
+    if err != nil || n <= 0 {
+        respondWithError(w, r, http.StatusBadRequest, "Invalid "+name)
+        return 0, false
+    }
+    return n, true
+}
+
+// pageBounds returns the slice bounds of one page of n items
+func pageBounds(page, limit, n int) (start, end int) {
+    if limit <= 0 {
+        return 0, n
+    }
+    start = min((max(page, 1)-1)*limit, n)
+    return start, min(start+limit, n)
+}
+
+// userVoteValue converts a vote lookup into the 1/0/-1 user_vote value
+func userVoteValue(votes map[string]bool, targetID string) int {
+    isUpvote, voted := votes[targetID]
+    if !voted {
+        return 0
+    }
+    if isUpvote {
+        return 1
+    }
+    return -1
+}
+
+// Message handlers
+func (s *Server) handleGetMessages(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    // Messages are paginated; without a limit every message is returned
+    query := r.URL.Query()
+    page, ok := positiveQueryInt(w, r, "page", 1)
+    if !ok {
+        return
+    }
+    limit, ok := positiveQueryInt(w, r, "limit", 0)
+    if !ok {
+        return
+    }
+
+    result, err := s.engine.GetUserMessages(userID, page, limit, query.Get("unread_first") == "true")
+    if err != nil {
+        respondWithAppError(w, r, http.StatusInternalServerError, err)
+        return
+    }
+
+    resp := api.MessageListResponse{
+        Messages: make([]api.MessageResponse, 0, len(result.Messages)),
+        Total:    result.Total,
+        Unread:   result.Unread,
+        PageInfo: api.NewPageInfo(page, limit, result.Total),
+    }
+    for _, msg := range result.Messages {
+        resp.Messages = append(resp.Messages, newMessageResponse(msg))
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) handleGetNotifications(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    notifications, err := s.engine.GetNotifications(userID)
+    if err != nil {
+        respondWithAppError(w, r, http.StatusNotFound, err)
+        return
+    }
+
+    respond(w, r, http.StatusOK, notifications)
+}
+
+func (s *Server) handleMarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    notificationID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    if err := s.engine.MarkNotificationRead(userID, notificationID); err != nil {
+        respondWithAppError(w, r, http.StatusNotFound, err)
+        return
+    }
+
+    respond(w, r, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func (s *Server) handleSendMessage(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    var req api.MessageRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
+
+    ttl := time.Duration(req.TTLSeconds) * time.Second
+    message, err := s.engine.SendDirectMessageWithTTL(userID, req.ToID, req.Content, ttl)
+    if errors.Is(err, engine.ErrUserBanned) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if err != nil {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+
+    respond(w, r, http.StatusCreated, message)
+}
+
+// Add this to internal/rest/handlers.go
+func (s *Server) handleCreateComment(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    postID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    var req api.CommentRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
+
+    comment, err := s.engine.CreateCommentWithOptions(
+        req.Content,
+        userID,
+        postID,
+        req.ParentID,
+        engine.CommentOptions{Distinguished: req.Distinguished, Anonymous: req.Anonymous},
+    )
+    if errors.Is(err, engine.ErrPostingTooFast) {
+        respondWithAppError(w, r, http.StatusTooManyRequests, err)
+        return
+    }
+    if errors.Is(err, engine.ErrUserBanned) || errors.Is(err, engine.ErrNotModerator) || errors.Is(err, engine.ErrSubredditPrivate) || errors.Is(err, engine.ErrAccountTooNew) || errors.Is(err, engine.ErrSubredditArchived) || errors.Is(err, engine.ErrAnonymousNotAllowed) || errors.Is(err, engine.ErrPostLocked) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if err != nil {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+
+    respond(w, r, http.StatusCreated, s.newCommentResponse(r, comment))
+}
+
+func (s *Server) handleGetCommentContext(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    commentID := vars["id"]
+
+    if !s.canViewComment(w, r, commentID) {
+        return
+    }
+    chain, err := s.engine.GetCommentContext(commentID)
+    if err != nil {
+        respondWithAppError(w, r, http.StatusNotFound, err)
+        return
+    }
+
+    resp := make([]api.CommentResponse, len(chain))
+    for i, comment := range chain {
+        resp[i] = s.newCommentResponse(r, comment)
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+// handleGetReplies serves one page of a comment's direct replies, for
+// loading more of a thread on demand
+func (s *Server) handleGetReplies(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    commentID := vars["id"]
+    query := r.URL.Query()
+
+    page, ok := positiveQueryInt(w, r, "page", 1)
+    if !ok {
+        return
+    }
+    limit, ok := positiveQueryInt(w, r, "limit", defaultCommentPageLimit)
+    if !ok {
+        return
+    }
+
+    if !s.canViewComment(w, r, commentID) {
+        return
+    }
+    viewerID, _ := userIDFromContext(r)
+    opts := engine.CommentTreeOptions{
+        Sort:     query.Get("sort"),
+        Page:     page,
+        Limit:    limit,
+        ViewerID: viewerID,
+    }
+    replies, total, err := s.engine.GetRepliesWithOptions(commentID, opts)
+    if err != nil {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+
+    resp := api.CommentListResponse{
+        Comments: make([]api.CommentResponse, len(replies)),
+        Total:    total,
+        PageInfo: api.NewPageInfo(page, limit, total),
+    }
+    for i, reply := range replies {
+        resp.Comments[i] = s.newCommentResponse(r, reply)
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) handleEditPost(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    postID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    var req api.EditPostRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
+
+    post, err := s.engine.EditPost(userID, postID, req.Title, req.Content, expectedVersion(req.Version))
+    if errors.Is(err, engine.ErrNotAuthor) || errors.Is(err, engine.ErrSubredditArchived) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if errors.Is(err, engine.ErrVersionConflict) {
+        respondWithAppError(w, r, http.StatusConflict, err)
+        return
+    }
+    if errors.Is(err, engine.ErrBannedWord) {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Post not found")
+        return
+    }
+
+    respond(w, r, http.StatusOK, s.newPostResponse(r, post))
+}
+
+// expectedVersion turns an edit request's optional version into the
+// engine's expected version; leaving it out skips the check
+func expectedVersion(version *int64) int64 {
+    if version == nil {
+        return engine.AnyVersion
+    }
+    return *version
+}
+
+func (s *Server) handleEditComment(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    commentID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    var req api.EditCommentRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
+
+    comment, err := s.engine.EditComment(userID, commentID, req.Content, expectedVersion(req.Version))
+    if errors.Is(err, engine.ErrNotAuthor) || errors.Is(err, engine.ErrPostLocked) || errors.Is(err, engine.ErrSubredditArchived) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if errors.Is(err, engine.ErrVersionConflict) {
+        respondWithAppError(w, r, http.StatusConflict, err)
+        return
+    }
+    if errors.Is(err, engine.ErrBannedWord) {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Comment not found")
+        return
+    }
+
+    respond(w, r, http.StatusOK, s.newCommentResponse(r, comment))
+}
+
+func (s *Server) handleGetPostHistory(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    postID := vars["id"]
+
+    post, err := s.engine.GetPost(postID)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Post not found")
+        return
+    }
+    if !s.canViewPost(w, r, post) {
+        return
+    }
+    history, err := s.engine.GetPostHistory(postID)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Post not found")
+        return
+    }
+
+    respond(w, r, http.StatusOK, s.newEditHistoryResponse(history))
+}
+
+func (s *Server) handleGetCommentHistory(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    commentID := vars["id"]
+
+    if !s.canViewComment(w, r, commentID) {
+        return
+    }
+    history, err := s.engine.GetCommentHistory(commentID)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Comment not found")
+        return
+    }
+
+    respond(w, r, http.StatusOK, s.newEditHistoryResponse(history))
+}
+
+func (s *Server) handleDistinguishPost(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    postID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    post, err := s.engine.ToggleDistinguishPost(userID, postID)
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Post not found")
+        return
+    }
+
+    respond(w, r, http.StatusOK, s.newPostResponse(r, post))
+}
+
+// handleToggleContestMode flips contest mode on a post; its author and
+// the subreddit's moderators only
+func (s *Server) handleToggleContestMode(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    post, err := s.engine.ToggleContestMode(userID, vars["id"])
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Post not found")
+        return
+    }
+
+    respond(w, r, http.StatusOK, s.newPostResponse(r, post))
+}
+
+// handleToggleLockPost locks or unlocks a post; moderators only
+func (s *Server) handleToggleLockPost(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    post, err := s.engine.ToggleLockPost(userID, vars["id"])
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Post not found")
+        return
+    }
+
+    respond(w, r, http.StatusOK, s.newPostResponse(r, post))
+}
+
+func (s *Server) handleDistinguishComment(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    commentID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    comment, err := s.engine.ToggleDistinguishComment(userID, commentID)
+    if errors.Is(err, engine.ErrNotModerator) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Comment not found")
+        return
+    }
+
+    respond(w, r, http.StatusOK, s.newCommentResponse(r, comment))
 }
\ No newline at end of file
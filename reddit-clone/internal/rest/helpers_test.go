@@ -0,0 +1,89 @@
+// internal/rest/helpers_test.go
+package rest
+
+import (
+    "bytes"
+    "encoding/json"
+    "net/http/httptest"
+    "testing"
+
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/models"
+)
+
+// newTestServer returns a server with default options over an engine with
+// flood control and duplicate detection off
+func newTestServer(t *testing.T) (*Server, *engine.RedditEngine) {
+    t.Helper()
+    cfg := engine.NewDefaultConfig()
+    cfg.PostCooldown = 0
+    cfg.CommentCooldown = 0
+    cfg.DuplicatePostWindow = 0
+    e := engine.NewRedditEngineWithConfig(cfg)
+    return NewServer(e), e
+}
+
+// serve sends a request through the server's router, authenticated as
+// userID unless it is empty, with body encoded as JSON unless it is nil
+func serve(t *testing.T, s *Server, method, path, userID string, body interface{}) *httptest.ResponseRecorder {
+    t.Helper()
+    var buf bytes.Buffer
+    if body != nil {
+        if err := json.NewEncoder(&buf).Encode(body); err != nil {
+            t.Fatalf("encoding request: %v", err)
+        }
+    }
+    req := httptest.NewRequest(method, path, &buf)
+    if body != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+    if userID != "" {
+        req.Header.Set("Authorization", "Bearer "+userID)
+    }
+    rec := httptest.NewRecorder()
+    s.router.ServeHTTP(rec, req)
+    return rec
+}
+
+// decodeBody decodes a JSON response into v, failing the test on error
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+    t.Helper()
+    if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+        t.Fatalf("decoding response: %v", err)
+    }
+}
+
+func mustRegister(t *testing.T, e *engine.RedditEngine, username string) *models.User {
+    t.Helper()
+    user, err := e.RegisterAccount(username, "password123")
+    if err != nil {
+        t.Fatalf("RegisterAccount(%q): %v", username, err)
+    }
+    return user
+}
+
+func mustCreateSubreddit(t *testing.T, e *engine.RedditEngine, name, creatorID string) *models.SubReddit {
+    t.Helper()
+    subreddit, err := e.CreateSubReddit(name, "Test subreddit", creatorID)
+    if err != nil {
+        t.Fatalf("CreateSubReddit(%q): %v", name, err)
+    }
+    return subreddit
+}
+
+func mustPost(t *testing.T, e *engine.RedditEngine, title, content, authorID, subredditID string) *models.Post {
+    t.Helper()
+    post, err := e.CreatePost(title, content, authorID, subredditID)
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    return post
+}
+
+// wantStatus fails the test unless rec has the given status
+func wantStatus(t *testing.T, rec *httptest.ResponseRecorder, status int) {
+    t.Helper()
+    if rec.Code != status {
+        t.Fatalf("status = %d, want %d; body %s", rec.Code, status, rec.Body)
+    }
+}
\ No newline at end of file
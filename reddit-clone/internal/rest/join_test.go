@@ -0,0 +1,84 @@
+// internal/rest/join_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+    "reddit-clone/internal/engine"
+)
+
+func TestJoinFullSubredditIsConflict(t *testing.T) {
+    s, e := newTestServer(t)
+    creator := mustRegister(t, e, "creator")
+    alice := mustRegister(t, e, "alice")
+    subreddit, err := e.CreateSubRedditWithOptions("tiny", "Just the creator", creator.ID, engine.SubredditOptions{MaxMembers: 1})
+    if err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+
+    rec := serve(t, s, "POST", "/api/v1/subreddits/"+subreddit.ID+"/join", alice.ID, nil)
+    wantStatus(t, rec, http.StatusConflict)
+    var errResp api.ErrorResponse
+    decodeBody(t, rec, &errResp)
+    if errResp.Code != api.CodeSubredditFull {
+        t.Errorf("error code = %q, want %q", errResp.Code, api.CodeSubredditFull)
+    }
+}
+
+func TestJoinReportsWhetherItJoined(t *testing.T) {
+    s, e := newTestServer(t)
+    creator := mustRegister(t, e, "creator")
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", creator.ID)
+
+    join := func(method string) api.JoinResponse {
+        t.Helper()
+        rec := serve(t, s, method, "/api/v1/subreddits/"+subreddit.ID+"/join", alice.ID, nil)
+        wantStatus(t, rec, http.StatusOK)
+        var resp api.JoinResponse
+        decodeBody(t, rec, &resp)
+        if resp.SubredditID != subreddit.ID {
+            t.Errorf("subreddit_id = %q, want %q", resp.SubredditID, subreddit.ID)
+        }
+        return resp
+    }
+
+    if resp := join("POST"); !resp.Joined {
+        t.Error("first join reported as a no-op")
+    }
+    // Repeating the join, by POST or PUT, changes nothing
+    for _, method := range []string{"POST", "PUT"} {
+        if resp := join(method); resp.Joined {
+            t.Errorf("repeat %s join reported as a new join", method)
+        }
+    }
+    if got, _ := e.GetSubReddit(subreddit.ID); got.MemberCount != 2 {
+        t.Errorf("MemberCount = %d after repeat joins, want 2", got.MemberCount)
+    }
+}
+
+func TestJoinErrors(t *testing.T) {
+    s, e := newTestServer(t)
+    creator := mustRegister(t, e, "creator")
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", creator.ID)
+
+    rec := serve(t, s, "PUT", "/api/v1/subreddits/missing/join", alice.ID, nil)
+    wantStatus(t, rec, http.StatusNotFound)
+
+    if err := e.BanUser(alice.ID); err != nil {
+        t.Fatalf("BanUser: %v", err)
+    }
+    rec = serve(t, s, "PUT", "/api/v1/subreddits/"+subreddit.ID+"/join", alice.ID, nil)
+    wantStatus(t, rec, http.StatusForbidden)
+    var errResp api.ErrorResponse
+    decodeBody(t, rec, &errResp)
+    if errResp.Code != api.CodeUserBanned {
+        t.Errorf("error code = %q, want %q", errResp.Code, api.CodeUserBanned)
+    }
+    if got, _ := e.GetSubReddit(subreddit.ID); got.MemberCount != 1 {
+        t.Errorf("MemberCount = %d after a refused join, want 1", got.MemberCount)
+    }
+}
\ No newline at end of file
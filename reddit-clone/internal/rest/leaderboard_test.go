@@ -0,0 +1,37 @@
+// internal/rest/leaderboard_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+)
+
+func TestLeaderboardWindow(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    path := "/api/v1/subreddits/" + subreddit.ID + "/leaderboard"
+
+    for _, window := range []string{"1h", "24h", "year"} {
+        rec := serve(t, s, http.MethodGet, path+"?window="+window, alice.ID, nil)
+        wantStatus(t, rec, http.StatusBadRequest)
+    }
+
+    rec := serve(t, s, http.MethodGet, path+"?window=week", alice.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+    var resp api.LeaderboardResponse
+    decodeBody(t, rec, &resp)
+    if resp.Window != "week" {
+        t.Errorf("window = %q, want week", resp.Window)
+    }
+
+    rec = serve(t, s, http.MethodGet, path, alice.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+    resp = api.LeaderboardResponse{}
+    decodeBody(t, rec, &resp)
+    if resp.Window != "" {
+        t.Errorf("default window = %q, want empty (all time)", resp.Window)
+    }
+}
\ No newline at end of file
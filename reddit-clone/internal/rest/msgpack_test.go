@@ -0,0 +1,87 @@
+// internal/rest/msgpack_test.go
+package rest
+
+import (
+    "bytes"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "reddit-clone/api/v1"
+    "reddit-clone/pkg/msgpack"
+)
+
+// serveMsgpack sends body encoded as MessagePack, asking for a MessagePack
+// response
+func serveMsgpack(t *testing.T, s *Server, method, path, userID string, body []byte) *httptest.ResponseRecorder {
+    t.Helper()
+    req := httptest.NewRequest(method, path, bytes.NewReader(body))
+    req.Header.Set("Content-Type", msgpack.ContentType)
+    req.Header.Set("Accept", msgpack.ContentType)
+    if userID != "" {
+        req.Header.Set("Authorization", "Bearer "+userID)
+    }
+    rec := httptest.NewRecorder()
+    s.router.ServeHTTP(rec, req)
+    return rec
+}
+
+func TestMsgpackRequestAndResponse(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+
+    body, err := msgpack.Marshal(api.SubredditRequest{Name: "golang", Description: "Gophers"})
+    if err != nil {
+        t.Fatalf("Marshal: %v", err)
+    }
+    rec := serveMsgpack(t, s, http.MethodPost, "/api/v1/subreddits", alice.ID, body)
+    wantStatus(t, rec, http.StatusCreated)
+    if ct := rec.Header().Get("Content-Type"); ct != msgpack.ContentType {
+        t.Fatalf("Content-Type = %q, want %q", ct, msgpack.ContentType)
+    }
+
+    var resp api.SubredditResponse
+    if err := msgpack.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+        t.Fatalf("decoding response: %v", err)
+    }
+    if resp.Name != "golang" || resp.Description != "Gophers" || resp.CreatorID != alice.ID {
+        t.Errorf("response = %+v, want golang/Gophers by alice", resp)
+    }
+    if resp.CreatedAt.IsZero() {
+        t.Error("created_at did not survive the round trip")
+    }
+}
+
+func TestMsgpackMalformedBodies(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+
+    deep := append([]byte{0x81, 0xa4, 'n', 'a', 'm', 'e'}, bytes.Repeat([]byte{0x91}, 1<<19)...)
+    bodies := map[string][]byte{
+        "truncated":     {0x82, 0xa4, 'n', 'a', 'm'},
+        "invalid byte":  {0xc1},
+        "trailing data": {0x80, 0x00},
+        "deep nesting":  append(deep, 0xc0),
+    }
+    for name, body := range bodies {
+        t.Run(name, func(t *testing.T) {
+            rec := serveMsgpack(t, s, http.MethodPost, "/api/v1/subreddits", alice.ID, body)
+            wantStatus(t, rec, http.StatusBadRequest)
+        })
+    }
+}
+
+func TestRequestBodyLimit(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+
+    huge := bytes.Repeat([]byte{0x91}, maxRequestBody+1)
+    rec := serveMsgpack(t, s, http.MethodPost, "/api/v1/subreddits", alice.ID, huge)
+    wantStatus(t, rec, http.StatusRequestEntityTooLarge)
+
+    rec = serve(t, s, http.MethodPost, "/api/v1/subreddits", alice.ID, api.SubredditRequest{
+        Name:        "golang",
+        Description: string(bytes.Repeat([]byte("x"), maxRequestBody)),
+    })
+    wantStatus(t, rec, http.StatusRequestEntityTooLarge)
+}
\ No newline at end of file
@@ -0,0 +1,56 @@
+// internal/rest/notfound_test.go
+package rest
+
+import (
+    "net/http"
+    "strings"
+    "testing"
+
+    "reddit-clone/api/v1"
+    "reddit-clone/pkg/msgpack"
+)
+
+func TestUnmatchedRoutesAnswerWithErrorBody(t *testing.T) {
+    s, _ := newTestServer(t)
+
+    tests := []struct {
+        method, path string
+        status       int
+        message      string
+    }{
+        {"GET", "/api/v1/nowhere", http.StatusNotFound, "Not found: /api/v1/nowhere"},
+        {"DELETE", "/api/v1/users/login", http.StatusMethodNotAllowed, "Method DELETE not allowed on /api/v1/users/login"},
+    }
+    for _, tt := range tests {
+        rec := serve(t, s, tt.method, tt.path, "", nil)
+        wantStatus(t, rec, tt.status)
+        if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+            t.Errorf("%s %s: Content-Type = %q, want JSON", tt.method, tt.path, ct)
+        }
+        if rec.Header().Get("Access-Control-Allow-Origin") == "" {
+            t.Errorf("%s %s: no CORS header", tt.method, tt.path)
+        }
+        var errResp api.ErrorResponse
+        decodeBody(t, rec, &errResp)
+        if errResp.Error != tt.message || errResp.Code != api.CodeForStatus(tt.status) {
+            t.Errorf("%s %s: body = %+v, want %q with code %q", tt.method, tt.path, errResp, tt.message, api.CodeForStatus(tt.status))
+        }
+    }
+}
+
+func TestUnmatchedRouteNegotiatesMsgpack(t *testing.T) {
+    s, _ := newTestServer(t)
+
+    rec := serveMsgpack(t, s, "GET", "/api/v1/nowhere", "", nil)
+    wantStatus(t, rec, http.StatusNotFound)
+    if ct := rec.Header().Get("Content-Type"); ct != msgpack.ContentType {
+        t.Fatalf("Content-Type = %q, want %q", ct, msgpack.ContentType)
+    }
+    var errResp api.ErrorResponse
+    if err := msgpack.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+        t.Fatalf("Unmarshal: %v", err)
+    }
+    if errResp.Code != api.CodeForStatus(http.StatusNotFound) {
+        t.Errorf("code = %q, want %q", errResp.Code, api.CodeForStatus(http.StatusNotFound))
+    }
+}
\ No newline at end of file
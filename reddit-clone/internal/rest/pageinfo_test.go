@@ -0,0 +1,41 @@
+// internal/rest/pageinfo_test.go
+package rest
+
+import (
+    "fmt"
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+)
+
+func TestListPostsPageInfo(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    for i := 0; i < 5; i++ {
+        mustPost(t, e, fmt.Sprintf("Post %d", i), "Content", alice.ID, subreddit.ID)
+    }
+
+    tests := []struct {
+        page    int
+        posts   int
+        hasNext bool
+        hasPrev bool
+    }{
+        {1, 2, true, false},
+        {2, 2, true, true},
+        {3, 1, false, true},
+    }
+    for _, tt := range tests {
+        path := fmt.Sprintf("/api/v1/posts?subreddit_id=%s&page=%d&limit=2", subreddit.ID, tt.page)
+        rec := serve(t, s, "GET", path, alice.ID, nil)
+        wantStatus(t, rec, http.StatusOK)
+        var resp api.PostListResponse
+        decodeBody(t, rec, &resp)
+        info := resp.PageInfo
+        if len(resp.Posts) != tt.posts || info.Page != tt.page || info.Limit != 2 || info.Total != 5 || info.HasNext != tt.hasNext || info.HasPrev != tt.hasPrev {
+            t.Errorf("page %d: %d posts, page_info %+v", tt.page, len(resp.Posts), info)
+        }
+    }
+}
\ No newline at end of file
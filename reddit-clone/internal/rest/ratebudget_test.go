@@ -0,0 +1,43 @@
+// internal/rest/ratebudget_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/internal/engine"
+)
+
+func TestRateBudgetChargesRegisteredUsers(t *testing.T) {
+    cfg := engine.NewDefaultConfig()
+    cfg.RateBudgetSize = 2
+    e := engine.NewRedditEngineWithConfig(cfg)
+    s := NewServer(e)
+    alice := mustRegister(t, e, "alice")
+
+    for i := 0; i < 2; i++ {
+        wantStatus(t, serve(t, s, http.MethodGet, "/api/v1/subreddits", alice.ID, nil), http.StatusOK)
+    }
+    rec := serve(t, s, http.MethodGet, "/api/v1/subreddits", alice.ID, nil)
+    wantStatus(t, rec, http.StatusTooManyRequests)
+    if rec.Header().Get("Retry-After") == "" {
+        t.Error("429 without Retry-After")
+    }
+}
+
+func TestRateBudgetIgnoresMadeUpTokens(t *testing.T) {
+    cfg := engine.NewDefaultConfig()
+    cfg.RateBudgetSize = 1
+    e := engine.NewRedditEngineWithConfig(cfg)
+    s := NewServer(e)
+
+    for _, token := range []string{"made-up-1", "made-up-2", "made-up-2", "made-up-3"} {
+        rec := serve(t, s, http.MethodGet, "/api/v1/subreddits", token, nil)
+        if rec.Code == http.StatusTooManyRequests {
+            t.Fatalf("token %q was charged: %s", token, rec.Body)
+        }
+    }
+    if n := e.Stats().MapSizes["rate_budgets"]; n != 0 {
+        t.Errorf("rate_budgets has %d entries after made-up tokens, want 0", n)
+    }
+}
\ No newline at end of file
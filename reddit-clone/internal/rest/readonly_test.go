@@ -0,0 +1,49 @@
+// internal/rest/readonly_test.go
+package rest
+
+import (
+    "net/http"
+    "strconv"
+    "testing"
+
+    "reddit-clone/api/v1"
+)
+
+func TestMaintenanceModeAnswers503(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, "Hello", "World", alice.ID, subreddit.ID)
+    e.SetReadOnly(true)
+
+    writes := []struct {
+        method, path string
+        body         interface{}
+    }{
+        {"POST", "/api/v1/users/register", api.RegisterRequest{Username: "bob", Password: "password123"}},
+        {"POST", "/api/v1/posts", api.PostRequest{Title: "Title", Content: "Content", SubredditID: subreddit.ID}},
+        {"POST", "/api/v1/posts/" + post.ID + "/comments", api.CommentRequest{Content: "Reply", PostID: post.ID}},
+        {"POST", "/api/v1/posts/" + post.ID + "/vote", api.VoteRequest{IsUpvote: true}},
+        {"PUT", "/api/v1/posts/" + post.ID, api.EditPostRequest{Title: "Edited", Content: "Edited"}},
+    }
+    for _, write := range writes {
+        rec := serve(t, s, write.method, write.path, alice.ID, write.body)
+        if rec.Code != http.StatusServiceUnavailable {
+            t.Errorf("%s %s: status = %d, want 503; body %s", write.method, write.path, rec.Code, rec.Body)
+            continue
+        }
+        if got := rec.Header().Get("Retry-After"); got != strconv.Itoa(maintenanceRetryAfter) {
+            t.Errorf("%s %s: Retry-After = %q, want %d", write.method, write.path, got, maintenanceRetryAfter)
+        }
+        var errResp api.ErrorResponse
+        decodeBody(t, rec, &errResp)
+        if errResp.Code != api.CodeMaintenance {
+            t.Errorf("%s %s: error code = %q, want %q", write.method, write.path, errResp.Code, api.CodeMaintenance)
+        }
+    }
+
+    rec := serve(t, s, "GET", "/api/v1/posts/"+post.ID, alice.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+    rec = serve(t, s, "GET", "/api/v1/posts/"+post.ID+"/comments", alice.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+}
\ No newline at end of file
@@ -0,0 +1,50 @@
+// internal/rest/sanitize_test.go
+package rest
+
+import (
+    "net/http"
+    "strings"
+    "testing"
+
+    "reddit-clone/api/v1"
+)
+
+func TestGetPostSanitizesTitleAndContent(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    sub := mustCreateSubreddit(t, e, "golang", alice.ID)
+    title := `<img src=x onerror=alert(1)>`
+    post := mustPost(t, e, title, "> [a]: &#106;avascript:alert(1)\n\n[x](\njavascript:alert(1))", alice.ID, sub.ID)
+
+    rec := serve(t, s, "GET", "/api/v1/posts/"+post.ID, alice.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+    var resp api.PostResponse
+    decodeBody(t, rec, &resp)
+
+    if strings.Contains(resp.Title, "<") {
+        t.Errorf("title %q contains a tag", resp.Title)
+    }
+    if resp.RawTitle != title {
+        t.Errorf("raw title = %q, want %q", resp.RawTitle, title)
+    }
+    for _, want := range []string{"[a]: #&#106;avascript:", "\n#javascript:"} {
+        if !strings.Contains(resp.Content, want) {
+            t.Errorf("content %q doesn't contain %q", resp.Content, want)
+        }
+    }
+}
+
+func TestGetPostLeavesPlainTitleAlone(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    sub := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, "Go 1.23 is out", "Release notes", alice.ID, sub.ID)
+
+    rec := serve(t, s, "GET", "/api/v1/posts/"+post.ID, alice.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+    var resp api.PostResponse
+    decodeBody(t, rec, &resp)
+    if resp.Title != post.Title || resp.RawTitle != "" {
+        t.Errorf("title = %q, raw title = %q; want %q and none", resp.Title, resp.RawTitle, post.Title)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,88 @@
+// internal/rest/score_test.go
+package rest
+
+import (
+    "encoding/json"
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+    "reddit-clone/internal/engine"
+)
+
+func TestResponsesCarryScore(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    carol := mustRegister(t, e, "carol")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    vote := func(user, targetID string, direction int) {
+        t.Helper()
+        if _, err := e.SetVote(user, targetID, direction); err != nil {
+            t.Fatalf("SetVote: %v", err)
+        }
+    }
+
+    posts := []struct {
+        votes    []int // By alice, bob and carol
+        up, down int64
+    }{
+        {[]int{engine.VoteUp, engine.VoteUp, engine.VoteDown}, 2, 1},
+        {[]int{engine.VoteDown, engine.VoteDown, engine.VoteDown}, 0, 3},
+        {[]int{engine.VoteUp, engine.VoteDown, engine.VoteNone}, 1, 1},
+    }
+    voters := []string{alice.ID, bob.ID, carol.ID}
+    for i, tt := range posts {
+        post := mustPost(t, e, "Post", "Content", alice.ID, subreddit.ID)
+        for j, direction := range tt.votes {
+            vote(voters[j], post.ID, direction)
+        }
+
+        rec := serve(t, s, "GET", "/api/v1/posts/"+post.ID, alice.ID, nil)
+        wantStatus(t, rec, http.StatusOK)
+        var raw map[string]json.RawMessage
+        if err := json.Unmarshal(rec.Body.Bytes(), &raw); err != nil {
+            t.Fatalf("decoding response: %v", err)
+        }
+        if _, ok := raw["score"]; !ok {
+            t.Errorf("post %d response has no score field: %s", i, rec.Body)
+        }
+        var resp api.PostResponse
+        decodeBody(t, rec, &resp)
+        if resp.Upvotes != tt.up || resp.Downvotes != tt.down || resp.Score != tt.up-tt.down {
+            t.Errorf("post %d = %d/%d score %d, want %d/%d score %d", i, resp.Upvotes, resp.Downvotes, resp.Score, tt.up, tt.down, tt.up-tt.down)
+        }
+    }
+
+    // The top sort ranks comments by the score they display
+    post := mustPost(t, e, "Discussion", "Content", alice.ID, subreddit.ID)
+    var comments []string
+    for _, content := range []string{"low", "high", "middle"} {
+        comment, err := e.CreateComment(content, alice.ID, post.ID, nil)
+        if err != nil {
+            t.Fatalf("CreateComment: %v", err)
+        }
+        comments = append(comments, comment.ID)
+    }
+    vote(bob.ID, comments[0], engine.VoteDown)
+    vote(bob.ID, comments[1], engine.VoteUp)
+    vote(carol.ID, comments[1], engine.VoteUp)
+
+    rec := serve(t, s, "GET", "/api/v1/posts/"+post.ID+"/comments?sort=top", alice.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+    var list api.CommentListResponse
+    decodeBody(t, rec, &list)
+    wantOrder := []struct {
+        content string
+        score   int64
+    }{{"high", 2}, {"middle", 0}, {"low", -1}}
+    if len(list.Comments) != len(wantOrder) {
+        t.Fatalf("got %d comments, want %d", len(list.Comments), len(wantOrder))
+    }
+    for i, want := range wantOrder {
+        got := list.Comments[i]
+        if got.Content != want.content || got.Score != want.score || got.Score != got.Upvotes-got.Downvotes {
+            t.Errorf("comment %d = %q score %d (%d/%d), want %q score %d", i, got.Content, got.Score, got.Upvotes, got.Downvotes, want.content, want.score)
+        }
+    }
+}
\ No newline at end of file
@@ -3,24 +3,98 @@ package rest
 
 import (
     "encoding/json"
+    "errors"
+    "io"
     "log"
     "net/http"
+    "sort"
+    "strconv"
+    "sync/atomic"
+    "time"
     "github.com/gorilla/mux"
     
     "reddit-clone/api/v1"
     "reddit-clone/internal/engine"
     "reddit-clone/internal/middleware"
+    "reddit-clone/internal/models"
+    "reddit-clone/pkg/config"
+    "reddit-clone/pkg/msgpack"
 )
 
+// defaultAutocompleteLimit is how many suggestions autocomplete returns by default
+const defaultAutocompleteLimit = 10
+
+// defaultPopularWindow and defaultPopularLimit shape /posts/popular when
+// the query leaves them out; maxPopularWindow bounds the window
+const (
+    defaultPopularWindow = 24 * time.Hour
+    defaultPopularLimit  = 50
+    maxPopularWindow     = 30 * 24 * time.Hour
+)
+
+// defaultDigestPeriod is how far back the digest looks when since is left out
+const defaultDigestPeriod = 7 * 24 * time.Hour
+
+// defaultCommentPageLimit is how many top-level comments a page holds by default
+const defaultCommentPageLimit = 50
+
+// defaultTopContributors is how many authors top-contributors ranks by default
+const defaultTopContributors = 10
+
+// defaultLeaderboardLimit is how many members a leaderboard ranks by default
+const defaultLeaderboardLimit = 10
+
+// defaultActivityDays is how many days an activity heatmap covers by
+// default; maxActivityDays bounds it
+const (
+    defaultActivityDays = 30
+    maxActivityDays     = 365
+)
+
+// maxRequestBody is the largest request body, in bytes, decodeRequest
+// reads; it comfortably holds the longest post the default limits allow
+const maxRequestBody = 1 << 20
+
+// defaultVoteHistoryLimit is how many votes a page of vote history holds by default
+const defaultVoteHistoryLimit = 25
+
 type Server struct {
     engine *engine.RedditEngine
     router *mux.Router
+    admin  http.Handler // Served under /admin/, outside the router's middleware
+    opts   ServerOptions
+}
+
+// ServerOptions tunes the REST server
+type ServerOptions struct {
+    Gzip           bool          // Compress large JSON responses for clients that accept gzip
+    GzipMinSize    int           // Smallest body, in bytes, that is compressed
+    RequestTimeout time.Duration // Longest a handler may run before a 503; 0 disables
+    Limits         api.Limits    // Longest titles, content, comments and messages accepted; 0 is unlimited
+    Msgpack        bool          // Speak MessagePack to clients that ask for it in Accept or Content-Type
+}
+
+// DefaultServerOptions returns the options NewServer uses
+func DefaultServerOptions() ServerOptions {
+    return ServerOptions{
+        Gzip:           true,
+        GzipMinSize:    middleware.DefaultGzipMinSize,
+        RequestTimeout: config.DefaultRequestTimeout,
+        Limits:         api.DefaultLimits(),
+        Msgpack:        true,
+    }
 }
 
 func NewServer(engine *engine.RedditEngine) *Server {
+    return NewServerWithOptions(engine, DefaultServerOptions())
+}
+
+// NewServerWithOptions creates a REST server with the given options
+func NewServerWithOptions(engine *engine.RedditEngine, opts ServerOptions) *Server {
     server := &Server{
         engine: engine,
         router: mux.NewRouter(),
+        opts:   opts,
     }
     server.setupRoutes()
     return server
@@ -30,85 +104,426 @@ func (s *Server) setupRoutes() {
     // Public routes
     s.router.HandleFunc("/api/v1/users/register", s.handleRegister).Methods("POST")
     s.router.HandleFunc("/api/v1/users/login", s.handleLogin).Methods("POST")
+    s.router.HandleFunc("/api/v1/limits", s.handleGetLimits).Methods("GET")
 
     // Protected routes
     // Subreddit routes
     s.router.HandleFunc("/api/v1/subreddits", middleware.AuthMiddleware(s.handleCreateSubreddit)).Methods("POST")
+    s.router.HandleFunc("/api/v1/subreddits/autocomplete", middleware.AuthMiddleware(s.handleAutocompleteSubreddits)).Methods("GET")
     s.router.HandleFunc("/api/v1/subreddits/{id}", middleware.AuthMiddleware(s.handleGetSubreddit)).Methods("GET")
+    s.router.HandleFunc("/api/v1/subreddits/{id}", middleware.AuthMiddleware(s.handleUpdateSubreddit)).Methods("PUT")
+    s.router.HandleFunc("/api/v1/subreddits/{id}", middleware.AuthMiddleware(s.handleDeleteSubreddit)).Methods("DELETE")
     s.router.HandleFunc("/api/v1/subreddits", middleware.AuthMiddleware(s.handleListSubreddits)).Methods("GET")
-    s.router.HandleFunc("/api/v1/subreddits/{id}/join", middleware.AuthMiddleware(s.handleJoinSubreddit)).Methods("POST")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/join", middleware.AuthMiddleware(s.handleJoinSubreddit)).Methods("POST", "PUT")
     s.router.HandleFunc("/api/v1/subreddits/{id}/leave", middleware.AuthMiddleware(s.handleLeaveSubreddit)).Methods("POST")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/stats", middleware.AuthMiddleware(s.handleGetSubredditStats)).Methods("GET")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/top-contributors", middleware.AuthMiddleware(s.handleGetTopContributors)).Methods("GET")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/leaderboard", middleware.AuthMiddleware(s.handleGetLeaderboard)).Methods("GET")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/activity", middleware.AuthMiddleware(s.handleGetSubredditActivity)).Methods("GET")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/reports", middleware.AuthMiddleware(s.handleGetReports)).Methods("GET")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/flairs", middleware.AuthMiddleware(s.handleGetFlairs)).Methods("GET")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/flairs", middleware.AuthMiddleware(s.handleSetFlairs)).Methods("PUT")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/banned-words", middleware.AuthMiddleware(s.handleGetBannedWords)).Methods("GET")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/banned-words", middleware.AuthMiddleware(s.handleSetBannedWords)).Methods("PUT")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/archive", middleware.AuthMiddleware(s.handleArchiveSubreddit)).Methods("PUT")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/webhooks", middleware.AuthMiddleware(s.handleAddWebhook)).Methods("POST")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/approved/{userId}", middleware.AuthMiddleware(s.handleApprovePoster)).Methods("PUT")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/approved/{userId}", middleware.AuthMiddleware(s.handleRemoveApprovedPoster)).Methods("DELETE")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/pending", middleware.AuthMiddleware(s.handleGetPendingPosts)).Methods("GET")
+    s.router.HandleFunc("/api/v1/subreddits/{id}/posts/{slug}", middleware.AuthMiddleware(s.handleGetPostBySlug)).Methods("GET")
 
     // Post routes
     s.router.HandleFunc("/api/v1/posts", middleware.AuthMiddleware(s.handleCreatePost)).Methods("POST")
+    s.router.HandleFunc("/api/v1/posts/batch", middleware.AuthMiddleware(s.handleGetPostsBatch)).Methods("POST")
+    s.router.HandleFunc("/api/v1/posts/popular", middleware.AuthMiddleware(s.handleGetPopularPosts)).Methods("GET")
     s.router.HandleFunc("/api/v1/posts/{id}", middleware.AuthMiddleware(s.handleGetPost)).Methods("GET")
+    s.router.HandleFunc("/api/v1/posts/{id}", middleware.AuthMiddleware(s.handleEditPost)).Methods("PUT")
+    s.router.HandleFunc("/api/v1/posts/{id}/history", middleware.AuthMiddleware(s.handleGetPostHistory)).Methods("GET")
     s.router.HandleFunc("/api/v1/posts", middleware.AuthMiddleware(s.handleListPosts)).Methods("GET")
-    s.router.HandleFunc("/api/v1/posts/{id}/vote", middleware.AuthMiddleware(s.handleVote)).Methods("POST")
+    s.router.HandleFunc("/api/v1/posts/{id}/vote", middleware.AuthMiddleware(s.handleVote)).Methods("POST", "PUT")
+    s.router.HandleFunc("/api/v1/posts/{id}/report", middleware.AuthMiddleware(s.handleReport)).Methods("POST")
+    s.router.HandleFunc("/api/v1/posts/{id}/distinguish", middleware.AuthMiddleware(s.handleDistinguishPost)).Methods("POST")
+    s.router.HandleFunc("/api/v1/posts/{id}/contest-mode", middleware.AuthMiddleware(s.handleToggleContestMode)).Methods("POST")
+    s.router.HandleFunc("/api/v1/posts/{id}/lock", middleware.AuthMiddleware(s.handleToggleLockPost)).Methods("POST")
+    s.router.HandleFunc("/api/v1/posts/{id}/approve", middleware.AuthMiddleware(s.handleApprovePost)).Methods("POST")
+    s.router.HandleFunc("/api/v1/posts/{id}/reject", middleware.AuthMiddleware(s.handleRejectPost)).Methods("POST")
+    s.router.HandleFunc("/api/v1/posts/{id}/restore", middleware.AuthMiddleware(s.handleRestorePost)).Methods("POST")
+    s.router.HandleFunc("/api/v1/posts/{id}/crosspost-targets", middleware.AuthMiddleware(s.handleGetCrosspostTargets)).Methods("GET")
 
     // Comment routes
     s.router.HandleFunc("/api/v1/posts/{id}/comments", middleware.AuthMiddleware(s.handleCreateComment)).Methods("POST")
     s.router.HandleFunc("/api/v1/posts/{id}/comments", middleware.AuthMiddleware(s.handleGetComments)).Methods("GET")
-    s.router.HandleFunc("/api/v1/comments/{id}/vote", middleware.AuthMiddleware(s.handleVoteComment)).Methods("POST")
+    s.router.HandleFunc("/api/v1/comments/{id}", middleware.AuthMiddleware(s.handleEditComment)).Methods("PUT")
+    s.router.HandleFunc("/api/v1/comments/{id}/context", middleware.AuthMiddleware(s.handleGetCommentContext)).Methods("GET")
+    s.router.HandleFunc("/api/v1/comments/{id}/replies", middleware.AuthMiddleware(s.handleGetReplies)).Methods("GET")
+    s.router.HandleFunc("/api/v1/comments/{id}/history", middleware.AuthMiddleware(s.handleGetCommentHistory)).Methods("GET")
+    s.router.HandleFunc("/api/v1/comments/{id}/vote", middleware.AuthMiddleware(s.handleVoteComment)).Methods("POST", "PUT")
+    s.router.HandleFunc("/api/v1/comments/{id}/report", middleware.AuthMiddleware(s.handleReport)).Methods("POST")
+    s.router.HandleFunc("/api/v1/reports/{id}/resolve", middleware.AuthMiddleware(s.handleResolveReport)).Methods("POST")
+    s.router.HandleFunc("/api/v1/reports/{id}/dismiss", middleware.AuthMiddleware(s.handleDismissReport)).Methods("POST")
+    s.router.HandleFunc("/api/v1/comments/{id}/restore", middleware.AuthMiddleware(s.handleRestoreComment)).Methods("POST")
+    s.router.HandleFunc("/api/v1/comments/{id}/distinguish", middleware.AuthMiddleware(s.handleDistinguishComment)).Methods("POST")
 
     // Feed routes
     s.router.HandleFunc("/api/v1/feed", middleware.AuthMiddleware(s.handleGetFeed)).Methods("GET")
+    s.router.HandleFunc("/api/v1/digest", middleware.AuthMiddleware(s.handleGetDigest)).Methods("GET")
+    s.router.HandleFunc("/api/v1/ws", middleware.AuthMiddleware(s.handleFeedSocket)).Methods("GET")
 
     // Message routes
     s.router.HandleFunc("/api/v1/messages", middleware.AuthMiddleware(s.handleSendMessage)).Methods("POST")
     s.router.HandleFunc("/api/v1/messages", middleware.AuthMiddleware(s.handleGetMessages)).Methods("GET")
+    s.router.HandleFunc("/api/v1/messages/read-all", middleware.AuthMiddleware(s.handleMarkAllMessagesRead)).Methods("POST")
     s.router.HandleFunc("/api/v1/messages/{id}", middleware.AuthMiddleware(s.handleGetMessage)).Methods("GET")
+    s.router.HandleFunc("/api/v1/messages/{id}", middleware.AuthMiddleware(s.handleEditMessage)).Methods("PUT")
+    s.router.HandleFunc("/api/v1/messages/{id}", middleware.AuthMiddleware(s.handleDeleteMessage)).Methods("DELETE")
+    s.router.HandleFunc("/api/v1/messages/{id}/read", middleware.AuthMiddleware(s.handleMarkMessageRead)).Methods("POST")
+
+    // Stats routes
+    s.router.HandleFunc("/api/v1/stats", middleware.AuthMiddleware(s.handleGetGlobalStats)).Methods("GET")
+
+    // Notification routes
+    s.router.HandleFunc("/api/v1/notifications", middleware.AuthMiddleware(s.handleGetNotifications)).Methods("GET")
+    s.router.HandleFunc("/api/v1/notifications/{id}/read", middleware.AuthMiddleware(s.handleMarkNotificationRead)).Methods("POST")
 
     // User routes
+    s.router.HandleFunc("/api/v1/users/me", middleware.AuthMiddleware(s.handleGetMe)).Methods("GET")
+    s.router.HandleFunc("/api/v1/users/me/subreddits", middleware.AuthMiddleware(s.handleGetMySubreddits)).Methods("GET")
+    s.router.HandleFunc("/api/v1/users/me/votes", middleware.AuthMiddleware(s.handleGetVoteHistory)).Methods("GET")
+    s.router.HandleFunc("/api/v1/users/me/export", middleware.AuthMiddleware(s.handleExportUserData)).Methods("GET")
+    s.router.HandleFunc("/api/v1/users/me/scheduled", middleware.AuthMiddleware(s.handleGetScheduledPosts)).Methods("GET")
+    s.router.HandleFunc("/api/v1/users/me/scheduled/{id}", middleware.AuthMiddleware(s.handleCancelScheduledPost)).Methods("DELETE")
+    s.router.HandleFunc("/api/v1/users/me/leave-all", middleware.AuthMiddleware(s.handleLeaveAllSubreddits)).Methods("POST")
+    s.router.HandleFunc("/api/v1/users/me/verify-email", middleware.AuthMiddleware(s.handleVerifyEmail)).Methods("POST")
+    s.router.HandleFunc("/api/v1/users/me/username", middleware.AuthMiddleware(s.handleChangeUsername)).Methods("PUT")
+    s.router.HandleFunc("/api/v1/users/me/preferences", middleware.AuthMiddleware(s.handleSetPreferences)).Methods("PUT")
     s.router.HandleFunc("/api/v1/users/{id}/public-key", middleware.AuthMiddleware(s.handleGetPublicKey)).Methods("GET") // For bonus feature
 
+    // Recovery is outermost, so a panic in any later middleware or handler
+    // becomes a 500. The timeout handler re-raises a handler's panic on
+    // this goroutine, where it is caught.
+    s.router.Use(middleware.RecoverMiddleware)
+
+    // Compression wraps everything after recovery
+    if s.opts.Gzip {
+        s.router.Use(middleware.GzipMiddleware(s.opts.GzipMinSize))
+    }
+
+    s.router.Use(middleware.TimeoutMiddleware(s.opts.RequestTimeout))
+
     // Add CORS middleware
     s.router.Use(middleware.CORSMiddleware)
+
+    // Charge each authenticated request to the budget gRPC calls share
+    s.router.Use(middleware.RateBudgetMiddleware(s.engine.RateBudget()))
+
+    // Choose between JSON and MessagePack responses from the Accept header
+    if s.opts.Msgpack {
+        s.router.Use(middleware.NegotiateMiddleware)
+    }
+
+    // Unmatched requests skip the router's middleware, so they get CORS
+    // headers here and the same error body as a failed handler
+    notFound := http.Handler(http.HandlerFunc(handleNotFound))
+    methodNotAllowed := http.Handler(http.HandlerFunc(handleMethodNotAllowed))
+    if s.opts.Msgpack {
+        notFound = middleware.NegotiateMiddleware(notFound)
+        methodNotAllowed = middleware.NegotiateMiddleware(methodNotAllowed)
+    }
+    s.router.NotFoundHandler = middleware.CORSMiddleware(notFound)
+    s.router.MethodNotAllowedHandler = middleware.CORSMiddleware(methodNotAllowed)
+}
+
+// handleNotFound answers requests for paths no route matches
+func handleNotFound(w http.ResponseWriter, r *http.Request) {
+    respondWithError(w, r, http.StatusNotFound, "Not found: "+r.URL.Path)
+}
+
+// handleMethodNotAllowed answers requests whose path matches a route
+// registered for other methods
+func handleMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+    respondWithError(w, r, http.StatusMethodNotAllowed, "Method "+r.Method+" not allowed on "+r.URL.Path)
+}
+
+// MountAdmin serves the admin API under /admin/. It bypasses the public
+// router so none of its middleware, CORS included, applies.
+func (s *Server) MountAdmin(handler http.Handler) {
+    s.admin = handler
 }
 
 func (s *Server) Start(port string) error {
     log.Printf("Starting REST server on port %s\n", port)
-    return http.ListenAndServe(port, s.router)
+    if s.admin == nil {
+        return http.ListenAndServe(port, s.router)
+    }
+    root := http.NewServeMux()
+    root.Handle("/admin/", s.admin)
+    root.Handle("/", s.router)
+    return http.ListenAndServe(port, root)
+}
+
+// userIDFromContext returns the caller's user ID, if the request was authenticated
+func userIDFromContext(r *http.Request) (string, bool) {
+    return middleware.UserIDFromContext(r.Context())
+}
+
+// decodeRequest decodes the body into v and, if v is an api.Validator,
+// validates it against the server's limits. The body is JSON unless its
+// Content-Type is MessagePack and the server accepts it. Bodies over
+// maxRequestBody are refused. On failure it writes a 400, 413, 415 or 422
+// response and returns false.
+func (s *Server) decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+    r.Body = http.MaxBytesReader(w, r.Body, maxRequestBody)
+    var err error
+    if middleware.IsMsgpack(r.Header.Get("Content-Type")) {
+        if !s.opts.Msgpack {
+            respondWithError(w, r, http.StatusUnsupportedMediaType, "MessagePack request bodies are not accepted")
+            return false
+        }
+        var body []byte
+        if body, err = io.ReadAll(r.Body); err == nil {
+            err = msgpack.Unmarshal(body, v)
+        }
+    } else {
+        err = json.NewDecoder(r.Body).Decode(v)
+    }
+    var tooLarge *http.MaxBytesError
+    if errors.As(err, &tooLarge) {
+        respondWithError(w, r, http.StatusRequestEntityTooLarge, "Request body too large")
+        return false
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusBadRequest, "Invalid request payload")
+        return false
+    }
+
+    switch validator := v.(type) {
+    case api.LimitsValidator:
+        err = validator.ValidateLimits(s.opts.Limits)
+    case api.Validator:
+        err = validator.Validate()
+    }
+    if err != nil {
+        resp := api.ErrorResponse{Error: "Validation failed", Code: api.CodeValidationFailed}
+        var validationErr *api.ValidationError
+        if errors.As(err, &validationErr) {
+            resp.Details = validationErr.Fields
+        }
+        respond(w, r, http.StatusUnprocessableEntity, resp)
+        return false
+    }
+    return true
 }
 
 // Helper methods for responses
-func respondWithError(w http.ResponseWriter, code int, message string) {
-    respondWithJSON(w, code, api.ErrorResponse{Error: message})
+func respondWithError(w http.ResponseWriter, r *http.Request, code int, message string) {
+    respond(w, r, code, api.ErrorResponse{Error: message, Code: api.CodeForStatus(code)})
 }
 
-func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
-    response, err := json.Marshal(payload)
+// respondWithAppError reports err with the error code for its engine error,
+// falling back to the generic code for the status. Maintenance mode is
+// always a 503 with Retry-After, whatever status the handler chose.
+func respondWithAppError(w http.ResponseWriter, r *http.Request, code int, err error) {
+    if errors.Is(err, engine.ErrReadOnly) {
+        code = http.StatusServiceUnavailable
+        w.Header().Set("Retry-After", strconv.Itoa(maintenanceRetryAfter))
+    }
+    respond(w, r, code, api.ErrorResponse{Error: err.Error(), Code: errorCode(code, err)})
+}
+
+// respond writes payload as JSON, or as MessagePack when NegotiateMiddleware
+// found the client prefers it
+func respond(w http.ResponseWriter, r *http.Request, code int, payload interface{}) {
+    contentType := middleware.ResponseContentType(r.Context())
+    var response []byte
+    var err error
+    if contentType == msgpack.ContentType {
+        response, err = msgpack.Marshal(payload)
+    } else {
+        response, err = json.Marshal(payload)
+    }
     if err != nil {
         w.WriteHeader(http.StatusInternalServerError)
         w.Write([]byte("Internal Server Error"))
         return
     }
 
-    w.Header().Set("Content-Type", "application/json")
+    w.Header().Set("Content-Type", contentType)
     w.WriteHeader(code)
     w.Write(response)
 }
 
-// Login handler (new)
-func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
-    var req struct {
-        Username string `json:"username"`
-        Password string `json:"password"`
+// Helper methods for converting models to API responses
+func newUserResponse(user *models.User) api.UserResponse {
+    return api.UserResponse{
+        ID:        user.ID,
+        Username:  user.Username,
+        Karma:     user.Karma,
+        CreatedAt: user.CreatedAt,
+        Email:     user.Email,
+        Verified:  user.Verified,
     }
+}
+
+func newSubredditResponse(subreddit *models.SubReddit) api.SubredditResponse {
+    return api.SubredditResponse{
+        ID:          subreddit.ID,
+        Name:        subreddit.Name,
+        Description: subreddit.Description,
+        Type:        subreddit.Type,
+        QueuePosts:  subreddit.QueuePosts,
+        MemberCount: atomic.LoadInt64(&subreddit.MemberCount),
+        MaxMembers:  atomic.LoadInt64(&subreddit.MaxMembers),
+        CreatorID:   subreddit.CreatorID,
+        CreatedAt:   subreddit.CreatedAt,
+
+        AllowCrossposts: subreddit.AllowCrossposts,
+        Archived:        subreddit.Archived,
+        AllowAnonymous:  subreddit.AllowAnonymous,
+    }
+}
 
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+// newPostResponse converts a post for the requesting user, who sees an
+// anonymous author only if they wrote the post or moderate its subreddit
+func (s *Server) newPostResponse(r *http.Request, post *models.Post) api.PostResponse {
+    viewerID, _ := userIDFromContext(r)
+    upvotes, downvotes := post.Votes()
+    upvotes, downvotes = s.engine.DisplayVotes(post.ID, upvotes, downvotes)
+    title, rawTitle := s.renderTitle(post.Title)
+    content, raw := s.renderContent(post.Content)
+    return api.PostResponse{
+        ID:            post.ID,
+        Title:         title,
+        RawTitle:      rawTitle,
+        Slug:          post.Slug,
+        Content:       content,
+        RawContent:    raw,
+        AuthorID:      s.engine.PostAuthorFor(viewerID, post),
+        SubredditID:   post.SubRedditID,
+        Upvotes:       upvotes,
+        Downvotes:     downvotes,
+        Score:         upvotes - downvotes,
+        CommentCount:  atomic.LoadInt64(&post.CommentCount),
+        CreatedAt:     post.CreatedAt,
+        Edited:        post.Edited,
+        Version:       post.Version,
+        Distinguished: post.Distinguished,
+        Flair:         post.Flair,
+        NSFW:          post.NSFW,
+        Pending:       post.Pending,
+        ContestMode:   post.ContestMode,
+        AutoRemoved:   post.AutoRemoved,
+        Anonymous:     post.Anonymous,
+        Pinned:        post.Pinned,
+        Locked:        post.Locked,
+        ScheduledFor:  post.ScheduledFor,
+    }
+}
+
+func (s *Server) newCommentResponse(r *http.Request, comment *models.Comment) api.CommentResponse {
+    viewerID, _ := userIDFromContext(r)
+    upvotes, downvotes := comment.Votes()
+    upvotes, downvotes = s.engine.DisplayVotes(comment.ID, upvotes, downvotes)
+    content, raw := s.renderContent(comment.Content)
+    return api.CommentResponse{
+        ID:            comment.ID,
+        Content:       content,
+        RawContent:    raw,
+        AuthorID:      s.engine.CommentAuthorFor(viewerID, comment),
+        PostID:        comment.PostID,
+        ParentID:      comment.ParentID,
+        Depth:         int32(comment.Depth),
+        Upvotes:       upvotes,
+        Downvotes:     downvotes,
+        Score:         upvotes - downvotes,
+        CreatedAt:     comment.CreatedAt,
+        Edited:        comment.Edited,
+        Version:       comment.Version,
+        Distinguished: comment.Distinguished,
+        AutoRemoved:   comment.AutoRemoved,
+        Anonymous:     comment.Anonymous,
+        UpdatedAt:     comment.UpdatedAt,
+    }
+}
+
+// newVoteHistoryResponse describes a vote along with what it was cast on
+func (s *Server) newVoteHistoryResponse(vote *models.Vote) api.VoteHistoryResponse {
+    kind, summary := s.engine.VoteTargetSummary(vote.TargetID)
+    direction := engine.VoteDown
+    if vote.IsUpvote {
+        direction = engine.VoteUp
+    }
+    return api.VoteHistoryResponse{
+        TargetID:   vote.TargetID,
+        TargetType: kind,
+        Summary:    summary,
+        Direction:  direction,
+        CreatedAt:  vote.CreatedAt,
+    }
+}
+
+func newMessageResponse(msg *models.DirectMessage) api.MessageResponse {
+    return api.MessageResponse{
+        ID:        msg.ID,
+        FromID:    msg.FromID,
+        ToID:      msg.ToID,
+        Content:   msg.Content,
+        IsRead:    msg.IsRead,
+        CreatedAt: msg.CreatedAt,
+        ExpiresAt: msg.ExpiresAt,
+        Edited:    msg.Edited,
+        UpdatedAt: msg.UpdatedAt,
+    }
+}
+
+// renderContent returns stored content ready to display, and the raw
+// content too when rendering changed it, so its author can edit the original
+func (s *Server) renderContent(stored string) (content, raw string) {
+    content = s.engine.RenderContent(stored)
+    if content != stored {
+        raw = stored
+    }
+    return content, raw
+}
+
+// renderTitle is renderContent for a post title
+func (s *Server) renderTitle(stored string) (title, raw string) {
+    title = s.engine.RenderTitle(stored)
+    if title != stored {
+        raw = stored
+    }
+    return title, raw
+}
+
+func (s *Server) newEditHistoryResponse(history []models.EditRecord) []api.EditRecordResponse {
+    resp := make([]api.EditRecordResponse, len(history))
+    for i, record := range history {
+        resp[i] = api.EditRecordResponse{
+            PreviousTitle:   s.engine.RenderTitle(record.PreviousTitle),
+            PreviousContent: s.engine.RenderContent(record.PreviousContent),
+            EditedAt:        record.EditedAt,
+        }
+    }
+    return resp
+}
+
+// Login handler (new)
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+    var req api.LoginRequest
+    if !s.decodeRequest(w, r, &req) {
         return
     }
 
     token, err := s.engine.AuthenticateUser(req.Username, req.Password)
+    if errors.Is(err, engine.ErrUserBanned) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
     if err != nil {
-        respondWithError(w, http.StatusUnauthorized, "Invalid credentials")
+        respondWithError(w, r, http.StatusUnauthorized, "Invalid credentials")
         return
     }
 
-    respondWithJSON(w, http.StatusOK, map[string]string{
-        "token": token,
-    })
+    respond(w, r, http.StatusOK, api.LoginResponse{Token: token})
 }
 
 // Additional handler for getting a subreddit
@@ -116,80 +531,670 @@ func (s *Server) handleGetSubreddit(w http.ResponseWriter, r *http.Request) {
     vars := mux.Vars(r)
     subredditID := vars["id"]
 
-    subreddit, err := s.engine.GetSubReddit(subredditID)
+    userID, _ := userIDFromContext(r)
+
+    subreddit, err := s.engine.ViewSubReddit(userID, subredditID)
+    if errors.Is(err, engine.ErrSubredditPrivate) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
     if err != nil {
-        respondWithError(w, http.StatusNotFound, "Subreddit not found")
+        respondWithError(w, r, http.StatusNotFound, "Subreddit not found")
         return
     }
 
-    resp := api.SubredditResponse{
-        ID:          subreddit.ID,
-        Name:        subreddit.Name,
-        Description: subreddit.Description,
-        MemberCount: subreddit.MemberCount,
-        CreatedAt:   subreddit.CreatedAt,
+    respond(w, r, http.StatusOK, newSubredditResponse(subreddit))
+}
+
+// canViewSubreddit answers 403 or 404 and returns false if the caller may
+// not see the subreddit's posts
+func (s *Server) canViewSubreddit(w http.ResponseWriter, r *http.Request, subredditID string) bool {
+    userID, _ := userIDFromContext(r)
+    _, err := s.engine.ViewSubReddit(userID, subredditID)
+    if errors.Is(err, engine.ErrSubredditPrivate) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return false
     }
-    respondWithJSON(w, http.StatusOK, resp)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Subreddit not found")
+        return false
+    }
+    return true
+}
+
+// canViewPost is canViewSubreddit for the post's subreddit, followed by a
+// 404 for a pending or scheduled post the user may not see
+func (s *Server) canViewPost(w http.ResponseWriter, r *http.Request, post *models.Post) bool {
+    if !s.canViewSubreddit(w, r, post.SubRedditID) {
+        return false
+    }
+    if userID, _ := userIDFromContext(r); !s.engine.CanSeePost(userID, post) {
+        respondWithError(w, r, http.StatusNotFound, "Post not found")
+        return false
+    }
+    return true
+}
+
+// canViewComment looks up a comment and applies canViewPost to its post,
+// writing a 404 if either is missing
+func (s *Server) canViewComment(w http.ResponseWriter, r *http.Request, commentID string) bool {
+    comment, err := s.engine.GetComment(commentID)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Comment not found")
+        return false
+    }
+    post, err := s.engine.GetPost(comment.PostID)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Comment not found")
+        return false
+    }
+    return s.canViewPost(w, r, post)
+}
+
+// Handler for live subreddit statistics
+func (s *Server) handleGetSubredditStats(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    subredditID := vars["id"]
+    if !s.canViewSubreddit(w, r, subredditID) {
+        return
+    }
+
+    stats, err := s.engine.GetSubredditStats(subredditID)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Subreddit not found")
+        return
+    }
+
+    resp := api.SubredditStatsResponse{
+        SubredditID:  subredditID,
+        Name:         stats.Name,
+        MemberCount:  stats.MemberCount,
+        PostCount:    stats.PostCount,
+        CommentCount: stats.CommentCount,
+        VoteCount:    stats.VoteCount,
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+// Handler for ranking a subreddit's most active authors
+func (s *Server) handleGetTopContributors(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    subredditID := vars["id"]
+    limit, ok := positiveQueryInt(w, r, "limit", defaultTopContributors)
+    if !ok {
+        return
+    }
+    if !s.canViewSubreddit(w, r, subredditID) {
+        return
+    }
+
+    contributors, err := s.engine.GetTopContributors(subredditID, limit)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Subreddit not found")
+        return
+    }
+
+    resp := api.TopContributorsResponse{
+        SubredditID:  subredditID,
+        Contributors: make([]api.ContributorResponse, len(contributors)),
+    }
+    for i, stat := range contributors {
+        resp.Contributors[i] = api.ContributorResponse{
+            UserID:       stat.UserID,
+            Username:     stat.Username,
+            PostCount:    stat.PostCount,
+            CommentCount: stat.CommentCount,
+            Karma:        stat.Karma,
+        }
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+// handleGetLeaderboard ranks a subreddit's members by karma; window limits
+// it to recent votes (day, week or month) and is all time when absent
+func (s *Server) handleGetLeaderboard(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    subredditID := vars["id"]
+
+    window := r.URL.Query().Get("window")
+    if window == "" {
+        window = engine.LeaderboardAll
+    }
+    limit, ok := positiveQueryInt(w, r, "limit", defaultLeaderboardLimit)
+    if !ok {
+        return
+    }
+    if !s.canViewSubreddit(w, r, subredditID) {
+        return
+    }
+
+    entries, err := s.engine.GetSubredditLeaderboard(subredditID, window, limit)
+    if errors.Is(err, engine.ErrInvalidLeaderboardWindow) {
+        respondWithAppError(w, r, http.StatusBadRequest, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Subreddit not found")
+        return
+    }
+
+    resp := api.LeaderboardResponse{
+        SubredditID: subredditID,
+        Entries:     make([]api.LeaderboardEntryResponse, len(entries)),
+    }
+    if window != engine.LeaderboardAll {
+        resp.Window = window
+    }
+    for i, entry := range entries {
+        resp.Entries[i] = api.LeaderboardEntryResponse{
+            UserID:   entry.UserID,
+            Username: entry.Username,
+            Karma:    entry.Karma,
+        }
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+// handleGetSubredditActivity returns a subreddit's per-day post, comment
+// and vote counts over the last days days, oldest first
+func (s *Server) handleGetSubredditActivity(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    subredditID := vars["id"]
+    days, ok := positiveQueryInt(w, r, "days", defaultActivityDays)
+    if !ok {
+        return
+    }
+    if days > maxActivityDays {
+        respondWithError(w, r, http.StatusBadRequest, "Invalid days")
+        return
+    }
+    if !s.canViewSubreddit(w, r, subredditID) {
+        return
+    }
+
+    buckets, err := s.engine.GetSubredditActivity(subredditID, days)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Subreddit not found")
+        return
+    }
+
+    resp := api.SubredditActivityResponse{
+        SubredditID: subredditID,
+        Days:        make([]api.ActivityDayResponse, len(buckets)),
+    }
+    for i, bucket := range buckets {
+        resp.Days[i] = api.ActivityDayResponse{
+            Date:     bucket.Day.Format("2006-01-02"),
+            Posts:    bucket.Posts,
+            Comments: bucket.Comments,
+            Votes:    bucket.Votes,
+        }
+    }
+    respond(w, r, http.StatusOK, resp)
 }
 
 // Handler for listing subreddits
 func (s *Server) handleListSubreddits(w http.ResponseWriter, r *http.Request) {
+    // Subreddits are paginated by name; without a limit every subreddit is returned
+    page, ok := positiveQueryInt(w, r, "page", 1)
+    if !ok {
+        return
+    }
+    limit, ok := positiveQueryInt(w, r, "limit", 0)
+    if !ok {
+        return
+    }
+
     subreddits, err := s.engine.ListSubreddits()
     if err != nil {
-        respondWithError(w, http.StatusInternalServerError, "Failed to list subreddits")
+        respondWithError(w, r, http.StatusInternalServerError, "Failed to list subreddits")
+        return
+    }
+    sort.Slice(subreddits, func(i, j int) bool {
+        return subreddits[i].Name < subreddits[j].Name
+    })
+
+    start, end := pageBounds(page, limit, len(subreddits))
+    resp := api.SubredditListResponse{
+        Subreddits: make([]api.SubredditResponse, 0, end-start),
+        Total:      len(subreddits),
+        PageInfo:   api.NewPageInfo(page, limit, len(subreddits)),
+    }
+    for _, sr := range subreddits[start:end] {
+        resp.Subreddits = append(resp.Subreddits, newSubredditResponse(sr))
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+// Handler for site-wide statistics
+func (s *Server) handleGetGlobalStats(w http.ResponseWriter, r *http.Request) {
+    stats, err := s.engine.GlobalStats()
+    if err != nil {
+        respondWithError(w, r, http.StatusInternalServerError, "Failed to get stats")
+        return
+    }
+
+    respond(w, r, http.StatusOK, api.GlobalStatsResponse{
+        TotalUsers:      stats.TotalUsers,
+        TotalSubreddits: stats.TotalSubreddits,
+        TotalPosts:      stats.TotalPosts,
+        TotalComments:   stats.TotalComments,
+        TotalVotes:      stats.TotalVotes,
+        TotalMessages:   stats.TotalMessages,
+    })
+}
+
+// Handler for the limits requests are validated against
+func (s *Server) handleGetLimits(w http.ResponseWriter, r *http.Request) {
+    cfg := s.engine.Config()
+    respond(w, r, http.StatusOK, api.LimitsResponse{
+        MinUsernameLength:      api.MinUsernameLength,
+        MaxUsernameLength:      api.MaxUsernameLength,
+        MinPasswordLength:      api.MinPasswordLength,
+        MinSubredditNameLength: api.MinSubredditNameLength,
+        MaxSubredditNameLength: api.MaxSubredditNameLength,
+        MaxDescriptionLength:   api.MaxDescriptionLength,
+        MaxTitleLength:         s.opts.Limits.MaxTitleLength,
+        MaxPostContentLength:   s.opts.Limits.MaxPostContentLength,
+        MaxCommentLength:       s.opts.Limits.MaxCommentLength,
+        MaxMessageLength:       s.opts.Limits.MaxMessageLength,
+        MaxReportReasonLength:  api.MaxReportReasonLength,
+        MaxCommentDepth:        cfg.MaxCommentDepth,
+        MaxSubredditsPerUser:   cfg.MaxSubredditsPerUser,
+    })
+}
+
+// Handler for subreddit name autocomplete
+func (s *Server) handleAutocompleteSubreddits(w http.ResponseWriter, r *http.Request) {
+    query := r.URL.Query().Get("q")
+    if query == "" {
+        respondWithError(w, r, http.StatusBadRequest, "Query parameter q is required")
+        return
+    }
+
+    limit := defaultAutocompleteLimit
+    if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+        n, err := strconv.Atoi(limitStr)
+        if err != nil || n <= 0 {
+            respondWithError(w, r, http.StatusBadRequest, "Invalid limit")
+            return
+        }
+        limit = n
+    }
+
+    subreddits, err := s.engine.SearchSubredditsByPrefix(query, limit)
+    if err != nil {
+        respondWithError(w, r, http.StatusInternalServerError, "Failed to search subreddits")
+        return
+    }
+
+    resp := make([]api.SubredditResponse, 0, len(subreddits))
+    for _, sr := range subreddits {
+        resp = append(resp, newSubredditResponse(sr))
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+// Handler for listing the authenticated user's subreddits
+func (s *Server) handleGetMySubreddits(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    subreddits, err := s.engine.GetUserSubreddits(userID)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "User not found")
         return
     }
 
     var resp []api.SubredditResponse
     for _, sr := range subreddits {
-        resp = append(resp, api.SubredditResponse{
-            ID:          sr.ID,
-            Name:        sr.Name,
-            Description: sr.Description,
-            MemberCount: sr.MemberCount,
-            CreatedAt:   sr.CreatedAt,
-        })
+        resp = append(resp, newSubredditResponse(sr))
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+// handleGetVoteHistory serves one page of the votes the user has cast,
+// newest first
+func (s *Server) handleGetVoteHistory(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+    page, ok := positiveQueryInt(w, r, "page", 1)
+    if !ok {
+        return
+    }
+    limit, ok := positiveQueryInt(w, r, "limit", defaultVoteHistoryLimit)
+    if !ok {
+        return
+    }
+
+    votes, total, err := s.engine.GetVoteHistory(userID, page, limit)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "User not found")
+        return
+    }
+
+    resp := api.VoteHistoryListResponse{
+        Votes:    make([]api.VoteHistoryResponse, len(votes)),
+        Total:    total,
+        PageInfo: api.NewPageInfo(page, limit, total),
+    }
+    for i, vote := range votes {
+        resp.Votes[i] = s.newVoteHistoryResponse(vote)
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+// handleExportUserData serves everything stored about the user as a JSON
+// file download
+func (s *Server) handleExportUserData(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    export, err := s.engine.ExportUserData(userID)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "User not found")
+        return
+    }
+
+    resp := api.UserDataExport{
+        ExportedAt:    export.ExportedAt,
+        User:          newUserResponse(export.User),
+        Posts:         make([]api.PostResponse, len(export.Posts)),
+        Comments:      make([]api.CommentResponse, len(export.Comments)),
+        Votes:         make([]api.VoteHistoryResponse, len(export.Votes)),
+        Messages:      make([]api.MessageResponse, len(export.Messages)),
+        Subscriptions: make([]api.SubredditResponse, len(export.Subscriptions)),
+    }
+    for i, post := range export.Posts {
+        resp.Posts[i] = s.newPostResponse(r, post)
+    }
+    for i, comment := range export.Comments {
+        resp.Comments[i] = s.newCommentResponse(r, comment)
+    }
+    for i, vote := range export.Votes {
+        resp.Votes[i] = s.newVoteHistoryResponse(vote)
+    }
+    for i, msg := range export.Messages {
+        resp.Messages[i] = newMessageResponse(msg)
+    }
+    for i, subreddit := range export.Subscriptions {
+        resp.Subscriptions[i] = newSubredditResponse(subreddit)
+    }
+
+    extension := ".json"
+    if middleware.ResponseContentType(r.Context()) == msgpack.ContentType {
+        extension = ".msgpack"
     }
-    respondWithJSON(w, http.StatusOK, resp)
+    w.Header().Set("Content-Disposition", `attachment; filename="reddit-export-`+export.User.Username+extension+`"`)
+    respond(w, r, http.StatusOK, resp)
+}
+
+// handleGetScheduledPosts lists the user's posts waiting to be published,
+// soonest first
+func (s *Server) handleGetScheduledPosts(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    posts, err := s.engine.GetScheduledPosts(userID)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "User not found")
+        return
+    }
+
+    resp := api.PostListResponse{
+        Posts:    make([]api.PostResponse, len(posts)),
+        Total:    len(posts),
+        PageInfo: api.NewPageInfo(1, 0, len(posts)),
+    }
+    for i, post := range posts {
+        resp.Posts[i] = s.newPostResponse(r, post)
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+// handleCancelScheduledPost deletes one of the user's scheduled posts
+// before it is published
+func (s *Server) handleCancelScheduledPost(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    err := s.engine.CancelScheduledPost(userID, vars["id"])
+    if errors.Is(err, engine.ErrNotAuthor) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if errors.Is(err, engine.ErrPostNotScheduled) {
+        respondWithAppError(w, r, http.StatusConflict, err)
+        return
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Post not found")
+        return
+    }
+
+    respond(w, r, http.StatusOK, map[string]string{"status": "success"})
+}
+
+// Handler for leaving every subreddit; responds with the subreddits the
+// user still belongs to because they created them
+func (s *Server) handleLeaveAllSubreddits(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    err := s.engine.LeaveAllSubreddits(userID)
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "User not found")
+        return
+    }
+
+    s.handleGetMySubreddits(w, r)
 }
 
 // Handler for listing posts
 func (s *Server) handleListPosts(w http.ResponseWriter, r *http.Request) {
-    subredditID := r.URL.Query().Get("subreddit_id")
-    posts, err := s.engine.ListPosts(subredditID)
+    // Posts are paginated newest first, pinned ones ahead of the rest;
+    // without a limit every post is returned
+    query := r.URL.Query()
+    page, ok := positiveQueryInt(w, r, "page", 1)
+    if !ok {
+        return
+    }
+    limit, ok := positiveQueryInt(w, r, "limit", 0)
+    if !ok {
+        return
+    }
+
+    subredditID := query.Get("subreddit_id")
+    userID, _ := userIDFromContext(r)
+    posts, err := s.engine.ListPosts(userID, subredditID)
+    if errors.Is(err, engine.ErrSubredditPrivate) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return
+    }
+    if errors.Is(err, engine.ErrSubredditNotFound) {
+        respondWithError(w, r, http.StatusNotFound, "Subreddit not found")
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusInternalServerError, "Failed to list posts")
+        return
+    }
+    if flair := query.Get("flair"); flair != "" {
+        posts = engine.FilterByFlair(posts, flair)
+    }
+    sort.Slice(posts, func(i, j int) bool {
+        if posts[i].Pinned != posts[j].Pinned {
+            return posts[i].Pinned
+        }
+        return posts[i].CreatedAt.After(posts[j].CreatedAt)
+    })
+
+    start, end := pageBounds(page, limit, len(posts))
+    resp := api.PostListResponse{
+        Posts:    make([]api.PostResponse, 0, end-start),
+        Total:    len(posts),
+        PageInfo: api.NewPageInfo(page, limit, len(posts)),
+    }
+    for _, post := range posts[start:end] {
+        resp.Posts = append(resp.Posts, s.newPostResponse(r, post))
+    }
+    respond(w, r, http.StatusOK, resp)
+}
+
+// handleGetPopularPosts serves the hottest recent posts across all subreddits
+func (s *Server) handleGetPopularPosts(w http.ResponseWriter, r *http.Request) {
+    query := r.URL.Query()
+
+    window := defaultPopularWindow
+    if windowStr := query.Get("window"); windowStr != "" {
+        d, err := time.ParseDuration(windowStr)
+        if err != nil || d <= 0 || d > maxPopularWindow {
+            respondWithError(w, r, http.StatusBadRequest, "Invalid window")
+            return
+        }
+        window = d
+    }
+    limit, ok := positiveQueryInt(w, r, "limit", defaultPopularLimit)
+    if !ok {
+        return
+    }
+
+    opts := engine.PopularOptions{IncludeNSFW: query.Get("include_nsfw") == "true"}
+    posts, err := s.engine.GetPopularPostsWithOptions(r.Context(), window, limit, opts)
     if err != nil {
-        respondWithError(w, http.StatusInternalServerError, "Failed to list posts")
+        respondWithAppError(w, r, http.StatusBadRequest, err)
         return
     }
 
-    var resp []api.PostResponse
-    for _, post := range posts {
-        resp = append(resp, api.PostResponse{
-            ID:          post.ID,
-            Title:       post.Title,
-            Content:     post.Content,
-            AuthorID:    post.AuthorID,
-            SubredditID: post.SubRedditID,
-            Upvotes:     post.Upvotes,
-            Downvotes:   post.Downvotes,
-            CreatedAt:   post.CreatedAt,
-        })
+    resp := make([]api.PostResponse, len(posts))
+    for i, post := range posts {
+        resp[i] = s.newPostResponse(r, post)
     }
-    respondWithJSON(w, http.StatusOK, resp)
+    respond(w, r, http.StatusOK, resp)
+}
+
+// handleGetDigest serves the best posts and comments from the user's
+// subreddits since an RFC 3339 time, by default the last week
+func (s *Server) handleGetDigest(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    now := time.Now()
+    since := now.Add(-defaultDigestPeriod)
+    if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+        t, err := time.Parse(time.RFC3339, sinceStr)
+        if err != nil || t.After(now) {
+            respondWithError(w, r, http.StatusBadRequest, "Invalid since")
+            return
+        }
+        since = t
+    }
+
+    digest, err := s.engine.GetDigest(userID, since)
+    if err != nil {
+        respondWithAppError(w, r, http.StatusNotFound, err)
+        return
+    }
+
+    resp := api.DigestResponse{
+        Since:    digest.Since,
+        Posts:    make([]api.PostResponse, len(digest.Posts)),
+        Comments: make([]api.CommentResponse, len(digest.Comments)),
+    }
+    for i, post := range digest.Posts {
+        resp.Posts[i] = s.newPostResponse(r, post)
+    }
+    for i, comment := range digest.Comments {
+        resp.Comments[i] = s.newCommentResponse(r, comment)
+    }
+    respond(w, r, http.StatusOK, resp)
 }
 
 // Handler for getting comments
 func (s *Server) handleGetComments(w http.ResponseWriter, r *http.Request) {
     vars := mux.Vars(r)
     postID := vars["id"]
+    query := r.URL.Query()
+
+    page, ok := positiveQueryInt(w, r, "page", 1)
+    if !ok {
+        return
+    }
+    limit, ok := positiveQueryInt(w, r, "limit", defaultCommentPageLimit)
+    if !ok {
+        return
+    }
+    // depth=1 returns top-level comments only; without it the whole tree is returned
+    depth, ok := positiveQueryInt(w, r, "depth", 0)
+    if !ok {
+        return
+    }
+    viewerID, _ := userIDFromContext(r)
+    opts := engine.CommentTreeOptions{
+        Sort:     query.Get("sort"),
+        Page:     page,
+        Limit:    limit,
+        Levels:   depth,
+        ViewerID: viewerID,
+    }
 
-    comments, err := s.engine.GetComments(postID)
+    post, err := s.engine.GetPost(postID)
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Post not found")
+        return
+    }
+    if !s.canViewPost(w, r, post) {
+        return
+    }
+    comments, total, err := s.engine.GetCommentTree(postID, opts)
     if err != nil {
-        respondWithError(w, http.StatusInternalServerError, "Failed to get comments")
+        respondWithAppError(w, r, http.StatusBadRequest, err)
         return
     }
 
-    respondWithJSON(w, http.StatusOK, comments)
+    resp := api.CommentListResponse{
+        Comments: make([]api.CommentResponse, len(comments)),
+        Total:    total,
+        PageInfo: api.NewPageInfo(page, limit, total),
+    }
+    for i, node := range comments {
+        resp.Comments[i] = s.newCommentResponse(r, node.Comment)
+        resp.Comments[i].HasMoreReplies = node.HasMoreReplies
+        resp.Comments[i].RemainingReplies = node.RemainingReplies
+        resp.Comments[i].Collapsed = node.Collapsed
+        resp.Comments[i].InCollapsed = node.InCollapsed
+    }
+    respond(w, r, http.StatusOK, resp)
 }
 
 // Handler for getting public key (bonus feature)
@@ -199,11 +1204,11 @@ func (s *Server) handleGetPublicKey(w http.ResponseWriter, r *http.Request) {
 
     publicKey, err := s.engine.GetUserPublicKey(userID)
     if err != nil {
-        respondWithError(w, http.StatusNotFound, "Public key not found")
+        respondWithError(w, r, http.StatusNotFound, "Public key not found")
         return
     }
 
-    respondWithJSON(w, http.StatusOK, map[string]string{
+    respond(w, r, http.StatusOK, map[string]string{
         "public_key": publicKey,
     })
 }
@@ -212,34 +1217,133 @@ func (s *Server) handleGetPublicKey(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleVoteComment(w http.ResponseWriter, r *http.Request) {
     vars := mux.Vars(r)
     commentID := vars["id"]
-    userID := r.Context().Value("userID").(string)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    s.castVote(w, r, userID, commentID)
+}
 
-    var req api.VoteRequest
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+// Handler for getting a single message
+func (s *Server) handleGetMessage(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    messageID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
         return
     }
 
-    err := s.engine.Vote(userID, commentID, req.IsUpvote)
+    message, err := s.engine.GetMessage(userID, messageID)
     if err != nil {
-        respondWithError(w, http.StatusBadRequest, err.Error())
+        respondWithError(w, r, http.StatusNotFound, "Message not found")
         return
     }
 
-    respondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+    respond(w, r, http.StatusOK, message)
 }
 
-// Handler for getting a single message
-func (s *Server) handleGetMessage(w http.ResponseWriter, r *http.Request) {
+// Handler for editing a message the user sent
+func (s *Server) handleEditMessage(w http.ResponseWriter, r *http.Request) {
     vars := mux.Vars(r)
     messageID := vars["id"]
-    userID := r.Context().Value("userID").(string)
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
 
-    message, err := s.engine.GetMessage(userID, messageID)
+    var req api.EditMessageRequest
+    if !s.decodeRequest(w, r, &req) {
+        return
+    }
+
+    if !s.changeMessage(w, r, s.engine.EditMessage(userID, messageID, req.Content)) {
+        return
+    }
+    s.handleGetMessage(w, r)
+}
+
+// Handler for unsending a message the user sent
+func (s *Server) handleDeleteMessage(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    messageID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    if !s.changeMessage(w, r, s.engine.DeleteMessage(userID, messageID)) {
+        return
+    }
+    respond(w, r, http.StatusOK, map[string]string{"status": "success"})
+}
+
+// changeMessage maps an edit or delete error to a response, reporting
+// whether the change succeeded
+func (s *Server) changeMessage(w http.ResponseWriter, r *http.Request, err error) bool {
+    if errors.Is(err, engine.ErrNotSender) {
+        respondWithAppError(w, r, http.StatusForbidden, err)
+        return false
+    }
+    if errors.Is(err, engine.ErrMessageWindowExpired) {
+        respondWithAppError(w, r, http.StatusConflict, err)
+        return false
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return false
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Message not found")
+        return false
+    }
+    return true
+}
+
+// Handler for marking a received message read
+func (s *Server) handleMarkMessageRead(w http.ResponseWriter, r *http.Request) {
+    vars := mux.Vars(r)
+    messageID := vars["id"]
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    err := s.engine.MarkMessageRead(userID, messageID)
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return
+    }
+    if err != nil {
+        respondWithError(w, r, http.StatusNotFound, "Message not found")
+        return
+    }
+
+    respond(w, r, http.StatusOK, map[string]string{"status": "success"})
+}
+
+// Handler for marking every received message as read
+func (s *Server) handleMarkAllMessagesRead(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+
+    updated, err := s.engine.MarkAllMessagesRead(userID)
+    if errors.Is(err, engine.ErrReadOnly) {
+        respondWithAppError(w, r, http.StatusServiceUnavailable, err)
+        return
+    }
     if err != nil {
-        respondWithError(w, http.StatusNotFound, "Message not found")
+        respondWithAppError(w, r, http.StatusInternalServerError, err)
         return
     }
 
-    respondWithJSON(w, http.StatusOK, message)
+    respond(w, r, http.StatusOK, api.MarkAllReadResponse{Updated: updated})
 }
\ No newline at end of file
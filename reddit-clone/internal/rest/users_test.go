@@ -0,0 +1,118 @@
+// internal/rest/users_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+)
+
+func TestLoginRoundTrip(t *testing.T) {
+    s, e := newTestServer(t)
+
+    rec := serve(t, s, "POST", "/api/v1/users/register", "", api.RegisterRequest{Username: "alice", Password: "password123"})
+    wantStatus(t, rec, http.StatusCreated)
+    var registered api.UserResponse
+    decodeBody(t, rec, &registered)
+
+    rec = serve(t, s, "POST", "/api/v1/users/login", "", api.LoginRequest{Username: "alice", Password: "password123"})
+    wantStatus(t, rec, http.StatusOK)
+    var login api.LoginResponse
+    decodeBody(t, rec, &login)
+    if login.Token == "" {
+        t.Fatal("login returned no token")
+    }
+
+    // The token authenticates later requests as the registered user
+    rec = serve(t, s, "GET", "/api/v1/users/me", login.Token, nil)
+    wantStatus(t, rec, http.StatusOK)
+    var me api.UserResponse
+    decodeBody(t, rec, &me)
+    if me.ID != registered.ID || me.Username != "alice" {
+        t.Errorf("me = %+v, want alice (%s)", me, registered.ID)
+    }
+
+    for _, req := range []api.LoginRequest{
+        {Username: "alice", Password: "wrong-password"},
+        {Username: "nobody", Password: "password123"},
+    } {
+        rec = serve(t, s, "POST", "/api/v1/users/login", "", req)
+        wantStatus(t, rec, http.StatusUnauthorized)
+    }
+
+    if err := e.BanUser(registered.ID); err != nil {
+        t.Fatalf("BanUser: %v", err)
+    }
+    rec = serve(t, s, "POST", "/api/v1/users/login", "", api.LoginRequest{Username: "alice", Password: "password123"})
+    wantStatus(t, rec, http.StatusForbidden)
+}
+
+func TestGetMe(t *testing.T) {
+    s, e := newTestServer(t)
+    user, _, err := e.RegisterAccountWithEmail("carol", "password123", "carol@example.com")
+    if err != nil {
+        t.Fatalf("RegisterAccountWithEmail: %v", err)
+    }
+
+    rec := serve(t, s, "GET", "/api/v1/users/me", user.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+    var me api.UserResponse
+    decodeBody(t, rec, &me)
+    if me.ID != user.ID || me.Username != "carol" || me.Email != "carol@example.com" || me.Verified {
+        t.Errorf("me = %+v, want unverified carol <carol@example.com>", me)
+    }
+    if !me.CreatedAt.Equal(user.CreatedAt) {
+        t.Errorf("created_at = %v, want %v", me.CreatedAt, user.CreatedAt)
+    }
+
+    rec = serve(t, s, "GET", "/api/v1/users/me", "", nil)
+    wantStatus(t, rec, http.StatusUnauthorized)
+
+    // A well-formed token for a user that does not exist
+    rec = serve(t, s, "GET", "/api/v1/users/me", "ghost", nil)
+    wantStatus(t, rec, http.StatusNotFound)
+}
+
+func TestGetMySubredditsFollowsMembership(t *testing.T) {
+    s, e := newTestServer(t)
+    creator := mustRegister(t, e, "creator")
+    bob := mustRegister(t, e, "bob")
+    golang := mustCreateSubreddit(t, e, "golang", creator.ID)
+    rust := mustCreateSubreddit(t, e, "rust", creator.ID)
+    mustCreateSubreddit(t, e, "haskell", creator.ID)
+
+    mySubreddits := func() map[string]bool {
+        t.Helper()
+        rec := serve(t, s, "GET", "/api/v1/users/me/subreddits", bob.ID, nil)
+        wantStatus(t, rec, http.StatusOK)
+        var resp []api.SubredditResponse
+        decodeBody(t, rec, &resp)
+        ids := make(map[string]bool)
+        for _, sr := range resp {
+            ids[sr.ID] = true
+        }
+        return ids
+    }
+
+    if got := mySubreddits(); len(got) != 0 {
+        t.Errorf("before joining got %v, want none", got)
+    }
+
+    for _, sr := range []string{golang.ID, rust.ID} {
+        rec := serve(t, s, "POST", "/api/v1/subreddits/"+sr+"/join", bob.ID, nil)
+        wantStatus(t, rec, http.StatusOK)
+    }
+    if got := mySubreddits(); len(got) != 2 || !got[golang.ID] || !got[rust.ID] {
+        t.Errorf("after joining got %v, want golang and rust", got)
+    }
+
+    rec := serve(t, s, "POST", "/api/v1/subreddits/"+rust.ID+"/leave", bob.ID, nil)
+    wantStatus(t, rec, http.StatusOK)
+    if got := mySubreddits(); len(got) != 1 || !got[golang.ID] {
+        t.Errorf("after leaving rust got %v, want golang", got)
+    }
+
+    rec = serve(t, s, "GET", "/api/v1/users/me/subreddits", "ghost", nil)
+    wantStatus(t, rec, http.StatusNotFound)
+}
\ No newline at end of file
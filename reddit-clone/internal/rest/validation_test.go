@@ -0,0 +1,113 @@
+// internal/rest/validation_test.go
+package rest
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "reddit-clone/api/v1"
+    "reddit-clone/internal/engine"
+)
+
+// wantValidationFailure checks rec is a 422 naming exactly the given fields
+func wantValidationFailure(t *testing.T, rec *httptest.ResponseRecorder, fields ...string) {
+    t.Helper()
+    wantStatus(t, rec, http.StatusUnprocessableEntity)
+    var resp api.ErrorResponse
+    decodeBody(t, rec, &resp)
+    if resp.Code != api.CodeValidationFailed {
+        t.Errorf("error code = %q, want %q", resp.Code, api.CodeValidationFailed)
+    }
+    got := make(map[string]bool)
+    for _, detail := range resp.Details {
+        if detail.Message == "" {
+            t.Errorf("field %s has no message", detail.Field)
+        }
+        got[detail.Field] = true
+    }
+    if len(got) != len(fields) {
+        t.Errorf("details name fields %v, want %v", got, fields)
+    }
+    for _, field := range fields {
+        if !got[field] {
+            t.Errorf("details don't name %s: %+v", field, resp.Details)
+        }
+    }
+}
+
+func TestInvalidRequestsAreUnprocessable(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+
+    rec := serve(t, s, "POST", "/api/v1/users/register", "", api.RegisterRequest{Username: "a!", Password: "short", Email: "nope"})
+    wantValidationFailure(t, rec, "username", "password", "email")
+    if _, err := e.GetUserByUsername("a!"); err == nil {
+        t.Error("invalid registration created a user")
+    }
+
+    rec = serve(t, s, "POST", "/api/v1/subreddits", alice.ID, api.SubredditRequest{Name: "ab", MaxMembers: -1, Type: "secret"})
+    wantValidationFailure(t, rec, "name", "max_members", "type")
+
+    rec = serve(t, s, "POST", "/api/v1/posts", alice.ID, api.PostRequest{Title: " ", SubredditID: ""})
+    wantValidationFailure(t, rec, "title", "subreddit_id")
+
+    // A body that isn't JSON is a bad request, not a validation failure
+    req := httptest.NewRequest("POST", "/api/v1/users/register", strings.NewReader("{"))
+    req.Header.Set("Content-Type", "application/json")
+    rec = httptest.NewRecorder()
+    s.router.ServeHTTP(rec, req)
+    wantStatus(t, rec, http.StatusBadRequest)
+}
+
+func TestValidationUsesConfiguredLimits(t *testing.T) {
+    _, e := newTestServer(t)
+    opts := DefaultServerOptions()
+    opts.Limits.MaxTitleLength = 10
+    s := NewServerWithOptions(e, opts)
+    alice := mustRegister(t, e, "alice")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+
+    rec := serve(t, s, "POST", "/api/v1/posts", alice.ID, api.PostRequest{Title: "eleven char", SubredditID: subreddit.ID})
+    wantValidationFailure(t, rec, "title")
+
+    // Lengths count characters, not bytes
+    rec = serve(t, s, "POST", "/api/v1/posts", alice.ID, api.PostRequest{Title: "éééééééééé", SubredditID: subreddit.ID})
+    wantStatus(t, rec, http.StatusCreated)
+}
+
+func TestGetLimitsReflectsConfiguration(t *testing.T) {
+    cfg := engine.NewDefaultConfig()
+    cfg.MaxCommentDepth = 4
+    cfg.MaxSubredditsPerUser = 7
+    e := engine.NewRedditEngineWithConfig(cfg)
+    opts := DefaultServerOptions()
+    opts.Limits.MaxTitleLength = 10
+    opts.Limits.MaxCommentLength = 20
+    s := NewServerWithOptions(e, opts)
+
+    rec := serve(t, s, "GET", "/api/v1/limits", "", nil)
+    wantStatus(t, rec, http.StatusOK)
+    var resp api.LimitsResponse
+    decodeBody(t, rec, &resp)
+
+    want := api.LimitsResponse{
+        MinUsernameLength:      api.MinUsernameLength,
+        MaxUsernameLength:      api.MaxUsernameLength,
+        MinPasswordLength:      api.MinPasswordLength,
+        MinSubredditNameLength: api.MinSubredditNameLength,
+        MaxSubredditNameLength: api.MaxSubredditNameLength,
+        MaxDescriptionLength:   api.MaxDescriptionLength,
+        MaxTitleLength:         10,
+        MaxPostContentLength:   api.MaxPostContentLength,
+        MaxCommentLength:       20,
+        MaxMessageLength:       api.MaxMessageLength,
+        MaxReportReasonLength:  api.MaxReportReasonLength,
+        MaxCommentDepth:        4,
+        MaxSubredditsPerUser:   7,
+    }
+    if resp != want {
+        t.Errorf("limits = %+v, want %+v", resp, want)
+    }
+}
\ No newline at end of file
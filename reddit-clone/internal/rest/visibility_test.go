@@ -0,0 +1,95 @@
+// internal/rest/visibility_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/models"
+)
+
+func TestPrivateSubredditReadsNeedMembership(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit, err := e.CreateSubRedditWithOptions("secret", "Members only", alice.ID, engine.SubredditOptions{Type: models.SubredditPrivate})
+    if err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+    post := mustPost(t, e, "Secret plans", "Members only", alice.ID, subreddit.ID)
+    comment, err := e.CreateComment("Secret reply", alice.ID, post.ID, nil)
+    if err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+
+    paths := []string{
+        "/api/v1/posts/" + post.ID,
+        "/api/v1/posts/" + post.ID + "/comments",
+        "/api/v1/posts/" + post.ID + "/history",
+        "/api/v1/comments/" + comment.ID + "/context",
+        "/api/v1/comments/" + comment.ID + "/replies",
+        "/api/v1/comments/" + comment.ID + "/history",
+        "/api/v1/subreddits/" + subreddit.ID + "/stats",
+        "/api/v1/subreddits/" + subreddit.ID + "/top-contributors",
+        "/api/v1/subreddits/" + subreddit.ID + "/leaderboard",
+        "/api/v1/subreddits/" + subreddit.ID + "/activity",
+        "/api/v1/subreddits/" + subreddit.ID + "/flairs",
+    }
+    for _, path := range paths {
+        t.Run(path, func(t *testing.T) {
+            wantStatus(t, serve(t, s, "GET", path, bob.ID, nil), http.StatusForbidden)
+            wantStatus(t, serve(t, s, "GET", path, alice.ID, nil), http.StatusOK)
+        })
+    }
+}
+
+func TestPostsBatchOmitsPrivatePosts(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    private, err := e.CreateSubRedditWithOptions("secret", "Members only", alice.ID, engine.SubredditOptions{Type: models.SubredditPrivate})
+    if err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+    public := mustCreateSubreddit(t, e, "golang", alice.ID)
+    hidden := mustPost(t, e, "Secret plans", "Members only", alice.ID, private.ID)
+    shown := mustPost(t, e, "Public news", "For everyone", alice.ID, public.ID)
+
+    rec := serve(t, s, "POST", "/api/v1/posts/batch", bob.ID, []string{hidden.ID, shown.ID})
+    wantStatus(t, rec, http.StatusOK)
+    var posts []api.PostResponse
+    decodeBody(t, rec, &posts)
+    if len(posts) != 1 || posts[0].ID != shown.ID {
+        t.Fatalf("batch returned %d posts, want only %s", len(posts), shown.ID)
+    }
+}
+
+func TestNonMembersCantVoteOrReportPrivateContent(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit, err := e.CreateSubRedditWithOptions("secret", "Members only", alice.ID, engine.SubredditOptions{Type: models.SubredditPrivate})
+    if err != nil {
+        t.Fatalf("CreateSubRedditWithOptions: %v", err)
+    }
+    post := mustPost(t, e, "Secret plans", "Members only", alice.ID, subreddit.ID)
+    comment, err := e.CreateComment("Secret reply", alice.ID, post.ID, nil)
+    if err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+
+    for _, path := range []string{"/api/v1/posts/" + post.ID, "/api/v1/comments/" + comment.ID} {
+        rec := serve(t, s, "POST", path+"/vote", bob.ID, api.VoteRequest{IsUpvote: true})
+        wantStatus(t, rec, http.StatusForbidden)
+        var resp api.VoteResponse
+        decodeBody(t, rec, &resp)
+        if resp.Upvotes != 0 || resp.Score != 0 {
+            t.Errorf("%s/vote leaked counts to a non-member: %+v", path, resp)
+        }
+        wantStatus(t, serve(t, s, "POST", path+"/report", bob.ID, api.ReportRequest{Reason: "spam"}), http.StatusForbidden)
+    }
+
+    wantStatus(t, serve(t, s, "POST", "/api/v1/posts/"+post.ID+"/vote", alice.ID, api.VoteRequest{IsUpvote: true}), http.StatusOK)
+}
\ No newline at end of file
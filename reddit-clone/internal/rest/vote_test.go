@@ -0,0 +1,55 @@
+// internal/rest/vote_test.go
+package rest
+
+import (
+    "net/http"
+    "testing"
+
+    "reddit-clone/api/v1"
+)
+
+func TestVoteReturnsUpdatedCounts(t *testing.T) {
+    s, e := newTestServer(t)
+    alice := mustRegister(t, e, "alice")
+    bob := mustRegister(t, e, "bob")
+    subreddit := mustCreateSubreddit(t, e, "golang", alice.ID)
+    post := mustPost(t, e, "Post", "Content", alice.ID, subreddit.ID)
+    comment, err := e.CreateComment("Comment", alice.ID, post.ID, nil)
+    if err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+    up, down, retract := 1, -1, 0
+
+    for _, path := range []string{"/api/v1/posts/" + post.ID + "/vote", "/api/v1/comments/" + comment.ID + "/vote"} {
+        steps := []struct {
+            method string
+            req    api.VoteRequest
+            want   api.VoteResponse
+        }{
+            // POST toggles: the same arrow twice retracts the vote
+            {"POST", api.VoteRequest{IsUpvote: true}, api.VoteResponse{Upvotes: 1, Score: 1, UserVote: 1}},
+            {"POST", api.VoteRequest{IsUpvote: true}, api.VoteResponse{}},
+            {"POST", api.VoteRequest{IsUpvote: false}, api.VoteResponse{Downvotes: 1, Score: -1, UserVote: -1}},
+            // PUT sets the direction, so repeating it changes nothing
+            {"PUT", api.VoteRequest{Direction: &up}, api.VoteResponse{Upvotes: 1, Score: 1, UserVote: 1}},
+            {"PUT", api.VoteRequest{Direction: &up}, api.VoteResponse{Upvotes: 1, Score: 1, UserVote: 1}},
+            {"PUT", api.VoteRequest{Direction: &down}, api.VoteResponse{Downvotes: 1, Score: -1, UserVote: -1}},
+            {"PUT", api.VoteRequest{IsUpvote: true}, api.VoteResponse{Upvotes: 1, Score: 1, UserVote: 1}},
+            {"PUT", api.VoteRequest{Direction: &retract}, api.VoteResponse{}},
+            {"PUT", api.VoteRequest{Direction: &retract}, api.VoteResponse{}},
+        }
+        for i, step := range steps {
+            rec := serve(t, s, step.method, path, bob.ID, step.req)
+            wantStatus(t, rec, http.StatusOK)
+            var got api.VoteResponse
+            decodeBody(t, rec, &got)
+            if got != step.want {
+                t.Errorf("%s step %d (%s): got %+v, want %+v", path, i, step.method, got, step.want)
+            }
+        }
+    }
+
+    bad := 2
+    rec := serve(t, s, "PUT", "/api/v1/posts/"+post.ID+"/vote", bob.ID, api.VoteRequest{Direction: &bad})
+    wantStatus(t, rec, http.StatusBadRequest)
+}
\ No newline at end of file
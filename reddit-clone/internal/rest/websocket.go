@@ -0,0 +1,108 @@
+// internal/rest/websocket.go
+package rest
+
+import (
+    "errors"
+    "net/http"
+    "time"
+
+    "github.com/gorilla/websocket"
+
+    "reddit-clone/internal/engine"
+)
+
+// feedSocketPingInterval is how often an idle feed socket is pinged;
+// a client that misses a pong for twice as long is dropped
+const feedSocketPingInterval = 30 * time.Second
+
+// feedSocketWriteTimeout bounds each write to a feed socket
+const feedSocketWriteTimeout = 10 * time.Second
+
+// feedSocketLimitCloseCode closes a feed socket opened while the user
+// already has as many as the engine allows
+const feedSocketLimitCloseCode = websocket.ClosePolicyViolation
+
+// feedUpgrader accepts any origin: like the CORS policy, it relies on the
+// bearer token rather than cookies, so cross-site pages gain nothing
+var feedUpgrader = websocket.Upgrader{
+    CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// handleFeedSocket pushes each new post in the user's subreddits to a
+// WebSocket as a PostResponse JSON message until either side hangs up.
+// A socket beyond the user's limit is accepted and at once closed with
+// feedSocketLimitCloseCode, since browsers can't read an HTTP error
+// returned instead of the upgrade.
+func (s *Server) handleFeedSocket(w http.ResponseWriter, r *http.Request) {
+    userID, ok := userIDFromContext(r)
+    if !ok {
+        respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+        return
+    }
+    newPosts, cancel, err := s.engine.SubscribeFeed(userID)
+    if errors.Is(err, engine.ErrTooManyFeedSubscriptions) {
+        rejectFeedSocket(w, r, err)
+        return
+    }
+    if err != nil {
+        respondWithAppError(w, r, http.StatusNotFound, err)
+        return
+    }
+    defer cancel()
+
+    conn, err := feedUpgrader.Upgrade(w, r, nil)
+    if err != nil {
+        return // Upgrade has already answered the client
+    }
+    defer conn.Close()
+
+    // The client sends nothing, but reading is what processes its pongs
+    // and notices when it closes the connection
+    closed := make(chan struct{})
+    conn.SetReadDeadline(time.Now().Add(2 * feedSocketPingInterval))
+    conn.SetPongHandler(func(string) error {
+        return conn.SetReadDeadline(time.Now().Add(2 * feedSocketPingInterval))
+    })
+    go func() {
+        defer close(closed)
+        for {
+            if _, _, err := conn.ReadMessage(); err != nil {
+                return
+            }
+        }
+    }()
+
+    ping := time.NewTicker(feedSocketPingInterval)
+    defer ping.Stop()
+    for {
+        select {
+        case <-closed:
+            return
+        case post, ok := <-newPosts:
+            if !ok {
+                return
+            }
+            conn.SetWriteDeadline(time.Now().Add(feedSocketWriteTimeout))
+            if err := conn.WriteJSON(s.newPostResponse(r, post)); err != nil {
+                return
+            }
+        case <-ping.C:
+            deadline := time.Now().Add(feedSocketWriteTimeout)
+            if err := conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+                return
+            }
+        }
+    }
+}
+
+// rejectFeedSocket completes the upgrade only to close the socket with
+// feedSocketLimitCloseCode and err as the reason
+func rejectFeedSocket(w http.ResponseWriter, r *http.Request, err error) {
+    conn, upgradeErr := feedUpgrader.Upgrade(w, r, nil)
+    if upgradeErr != nil {
+        return // Upgrade has already answered the client
+    }
+    defer conn.Close()
+    message := websocket.FormatCloseMessage(feedSocketLimitCloseCode, err.Error())
+    conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(feedSocketWriteTimeout))
+}
\ No newline at end of file
@@ -0,0 +1,54 @@
+// internal/rest/websocket_test.go
+package rest
+
+import (
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/gorilla/websocket"
+
+    "reddit-clone/internal/engine"
+)
+
+// dialFeedSocket opens the feed WebSocket on srv as userID
+func dialFeedSocket(t *testing.T, srv *httptest.Server, userID string) *websocket.Conn {
+    t.Helper()
+    url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/v1/ws"
+    header := http.Header{}
+    header.Set("Authorization", "Bearer "+userID)
+    conn, _, err := websocket.DefaultDialer.Dial(url, header)
+    if err != nil {
+        t.Fatalf("dialing feed socket: %v", err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    return conn
+}
+
+func TestFeedSocketOverLimitIsClosed(t *testing.T) {
+    cfg := engine.NewDefaultConfig()
+    cfg.MaxFeedSubscriptionsPerUser = 1
+    e := engine.NewRedditEngineWithConfig(cfg)
+    s := NewServer(e)
+    srv := httptest.NewServer(s.router)
+    t.Cleanup(srv.Close)
+    alice := mustRegister(t, e, "alice")
+
+    dialFeedSocket(t, srv, alice.ID)
+
+    // The second socket is accepted and then closed with the limit code
+    over := dialFeedSocket(t, srv, alice.ID)
+    _, _, err := over.ReadMessage()
+    var closeErr *websocket.CloseError
+    if !errors.As(err, &closeErr) {
+        t.Fatalf("over-limit socket read: err = %v, want a close frame", err)
+    }
+    if closeErr.Code != feedSocketLimitCloseCode {
+        t.Errorf("close code = %d, want %d", closeErr.Code, feedSocketLimitCloseCode)
+    }
+    if !strings.Contains(closeErr.Text, engine.ErrTooManyFeedSubscriptions.Error()) {
+        t.Errorf("close reason = %q, want it to name the limit", closeErr.Text)
+    }
+}
\ No newline at end of file
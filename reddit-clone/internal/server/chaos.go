@@ -0,0 +1,44 @@
+// internal/server/chaos.go
+package server
+
+import (
+    "context"
+    "math/rand"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+)
+
+// ChaosConfig controls the faults injected by ChaosInterceptor
+type ChaosConfig struct {
+    Latency   time.Duration // Added before every call
+    ErrorRate float64       // Probability (0-1) that a call fails with Unavailable
+}
+
+// Enabled reports whether the config injects any faults
+func (c ChaosConfig) Enabled() bool {
+    return c.Latency > 0 || c.ErrorRate > 0
+}
+
+// ChaosInterceptor returns a unary interceptor that delays calls and fails a
+// fraction of them with codes.Unavailable before they reach the handler.
+// Chain it inside MetricsInterceptor so injected faults show up in metrics.
+func ChaosInterceptor(config ChaosConfig) grpc.UnaryServerInterceptor {
+    return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+        if config.Latency > 0 {
+            select {
+            case <-time.After(config.Latency):
+            case <-ctx.Done():
+                return nil, status.FromContextError(ctx.Err()).Err()
+            }
+        }
+
+        if config.ErrorRate > 0 && rand.Float64() < config.ErrorRate {
+            return nil, status.Error(codes.Unavailable, "chaos: injected failure")
+        }
+
+        return handler(ctx, req)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,87 @@
+// internal/server/chaos_test.go
+package server
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+)
+
+var chaosInfo = &grpc.UnaryServerInfo{FullMethod: "/reddit.RedditService/GetPost"}
+
+// countingHandler counts the calls that got past the interceptor
+func countingHandler(calls *int) grpc.UnaryHandler {
+    return func(ctx context.Context, req interface{}) (interface{}, error) {
+        *calls++
+        return "ok", nil
+    }
+}
+
+func TestChaosInterceptorAddsLatency(t *testing.T) {
+    const latency = 20 * time.Millisecond
+    interceptor := ChaosInterceptor(ChaosConfig{Latency: latency})
+
+    var calls int
+    start := time.Now()
+    resp, err := interceptor(context.Background(), nil, chaosInfo, countingHandler(&calls))
+    if err != nil || resp != "ok" || calls != 1 {
+        t.Fatalf("interceptor = %v, %v after %d calls; want the handler's response", resp, err, calls)
+    }
+    if elapsed := time.Since(start); elapsed < latency {
+        t.Errorf("call took %v, want at least %v", elapsed, latency)
+    }
+
+    // A call cancelled during the delay never reaches the handler
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+    if _, err := interceptor(ctx, nil, chaosInfo, countingHandler(&calls)); status.Code(err) != codes.Canceled {
+        t.Errorf("cancelled call: err = %v, want Canceled", err)
+    }
+    if calls != 1 {
+        t.Errorf("handler ran %d times, want 1", calls)
+    }
+}
+
+func TestChaosInterceptorErrorRate(t *testing.T) {
+    tests := []struct {
+        rate     float64
+        min, max int
+    }{
+        {0, 0, 0},
+        {1, 1000, 1000},
+        // Loose bounds, so the test can't flake on an unlucky run
+        {0.3, 200, 400},
+    }
+    for _, tt := range tests {
+        interceptor := ChaosInterceptor(ChaosConfig{ErrorRate: tt.rate})
+        var calls, failures int
+        for i := 0; i < 1000; i++ {
+            _, err := interceptor(context.Background(), nil, chaosInfo, countingHandler(&calls))
+            if err != nil {
+                if status.Code(err) != codes.Unavailable {
+                    t.Fatalf("injected error = %v, want Unavailable", err)
+                }
+                failures++
+            }
+        }
+        if failures < tt.min || failures > tt.max {
+            t.Errorf("rate %v failed %d of 1000 calls, want %d to %d", tt.rate, failures, tt.min, tt.max)
+        }
+        if calls+failures != 1000 {
+            t.Errorf("rate %v: %d calls reached the handler and %d failed, want 1000 in all", tt.rate, calls, failures)
+        }
+    }
+}
+
+func TestChaosConfigEnabled(t *testing.T) {
+    if (ChaosConfig{}).Enabled() {
+        t.Error("zero config reports enabled")
+    }
+    if !(ChaosConfig{Latency: time.Millisecond}).Enabled() || !(ChaosConfig{ErrorRate: 0.1}).Enabled() {
+        t.Error("config with faults reports disabled")
+    }
+}
\ No newline at end of file
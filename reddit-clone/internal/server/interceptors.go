@@ -0,0 +1,99 @@
+// internal/server/interceptors.go
+package server
+
+import (
+    "context"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/proto"
+)
+
+// MetricsInterceptor returns a unary interceptor that records the latency of
+// every call in the server's collector, keyed by the full method name. A call
+// counts as an error if it returns a non-OK status, or a StatusResponse with
+// Success unset, which is how Vote and Join/Leave report failures.
+func (s *RedditServer) MetricsInterceptor() grpc.UnaryServerInterceptor {
+    return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+        start := time.Now()
+        resp, err := handler(ctx, req)
+        s.metrics.RecordLatency(info.FullMethod, time.Since(start))
+
+        if status.Code(err) != codes.OK {
+            s.metrics.RecordError(info.FullMethod)
+        } else if statusResp, ok := resp.(*proto.StatusResponse); ok && !statusResp.Success {
+            s.metrics.RecordError(info.FullMethod)
+        }
+        return resp, err
+    }
+}
+
+// RateBudgetInterceptor returns a unary interceptor that charges each call
+// to the user it acts for, answering ResourceExhausted once that user's
+// budget is spent. Requests that name no user aren't charged.
+func RateBudgetInterceptor(budget *engine.RateBudget) grpc.UnaryServerInterceptor {
+    return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+        if _, err := budget.Charge(requestUserID(req)); err != nil {
+            return nil, status.Error(codes.ResourceExhausted, err.Error())
+        }
+        return handler(ctx, req)
+    }
+}
+
+// RateBudgetStreamInterceptor returns a stream interceptor that charges
+// each call, when its first message arrives, to the user that message
+// acts for. The snapshot RPCs act for no user: a call carrying the admin
+// key is charged to the operators' budget, and one without it is left
+// for the handler to refuse.
+func (s *RedditServer) RateBudgetStreamInterceptor() grpc.StreamServerInterceptor {
+    budget := s.engine.RateBudget()
+    return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+        charge := func(msg interface{}) error {
+            var err error
+            if userID := requestUserID(msg); userID != "" {
+                _, err = budget.Charge(userID)
+            } else if s.checkAdmin(ss.Context()) == nil {
+                _, err = budget.ChargeOperator()
+            }
+            if err != nil {
+                return status.Error(codes.ResourceExhausted, err.Error())
+            }
+            return nil
+        }
+        return handler(srv, &chargedStream{ServerStream: ss, charge: charge})
+    }
+}
+
+// chargedStream charges its call once, on the first message received
+type chargedStream struct {
+    grpc.ServerStream
+    charge  func(msg interface{}) error
+    charged bool
+}
+
+func (cs *chargedStream) RecvMsg(m interface{}) error {
+    if err := cs.ServerStream.RecvMsg(m); err != nil || cs.charged {
+        return err
+    }
+    cs.charged = true
+    return cs.charge(m)
+}
+
+// requestUserID returns the user a request acts for, whichever field of
+// its message names them
+func requestUserID(req interface{}) string {
+    switch r := req.(type) {
+    case interface{ GetUserId() string }:
+        return r.GetUserId()
+    case interface{ GetAuthorId() string }:
+        return r.GetAuthorId()
+    case interface{ GetFromId() string }:
+        return r.GetFromId()
+    case interface{ GetCreatorId() string }:
+        return r.GetCreatorId()
+    }
+    return ""
+}
\ No newline at end of file
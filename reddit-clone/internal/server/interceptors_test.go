@@ -0,0 +1,66 @@
+// internal/server/interceptors_test.go
+package server
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/proto"
+    "reddit-clone/pkg/metrics"
+)
+
+func TestMetricsInterceptorRecordsCallsAndErrors(t *testing.T) {
+    collector := metrics.NewCollector()
+    interceptor := NewRedditServer(engine.NewRedditEngine(), collector).MetricsInterceptor()
+
+    const latency = 5 * time.Millisecond
+    calls := []struct {
+        method string
+        resp   interface{}
+        err    error
+    }{
+        {"/reddit.RedditService/GetPost", &proto.PostResponse{}, nil},
+        {"/reddit.RedditService/GetPost", nil, status.Error(codes.NotFound, "post not found")},
+        {"/reddit.RedditService/Vote", &proto.StatusResponse{Success: true}, nil},
+        // Vote reports failure in the response rather than the status
+        {"/reddit.RedditService/Vote", &proto.StatusResponse{Success: false}, nil},
+        {"/reddit.RedditService/Vote", &proto.StatusResponse{Success: false}, nil},
+    }
+    for _, call := range calls {
+        handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+            time.Sleep(latency)
+            return call.resp, call.err
+        }
+        resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: call.method}, handler)
+        if resp != call.resp || err != call.err {
+            t.Errorf("%s: interceptor changed the result to %v, %v", call.method, resp, err)
+        }
+    }
+
+    stats := collector.GetStats()
+    want := map[string][2]int64{
+        "/reddit.RedditService/GetPost": {2, 1},
+        "/reddit.RedditService/Vote":    {3, 2},
+    }
+    for method, counts := range want {
+        endpoint, ok := stats.EndpointStats[method]
+        if !ok {
+            t.Errorf("no stats for %s", method)
+            continue
+        }
+        if endpoint.CallCount != counts[0] || endpoint.ErrorCount != counts[1] {
+            t.Errorf("%s: %d calls, %d errors; want %d, %d", method, endpoint.CallCount, endpoint.ErrorCount, counts[0], counts[1])
+        }
+        if endpoint.AverageLatency < latency {
+            t.Errorf("%s: average latency %v, want at least %v", method, endpoint.AverageLatency, latency)
+        }
+    }
+    if stats.ErrorCount != 3 {
+        t.Errorf("ErrorCount = %d, want 3", stats.ErrorCount)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,148 @@
+// internal/server/metrics_test.go
+package server
+
+import (
+    "context"
+    "fmt"
+    "testing"
+
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/proto"
+    "reddit-clone/pkg/metrics"
+)
+
+// newMetricsServer returns a server with its own collector over an engine
+// without flood control
+func newMetricsServer(t *testing.T) (*RedditServer, *metrics.Collector) {
+    t.Helper()
+    cfg := engine.NewDefaultConfig()
+    cfg.PostCooldown = 0
+    cfg.CommentCooldown = 0
+    collector := metrics.NewCollector()
+    return NewRedditServer(engine.NewRedditEngineWithConfig(cfg), collector), collector
+}
+
+func TestPushMetricsReportsEngineCounts(t *testing.T) {
+    s, collector := newMetricsServer(t)
+    ctx := context.Background()
+
+    alice, err := s.RegisterAccount(ctx, &proto.RegisterRequest{Username: "alice", Password: "password123"})
+    if err != nil {
+        t.Fatalf("RegisterAccount: %v", err)
+    }
+    bob, err := s.RegisterAccount(ctx, &proto.RegisterRequest{Username: "bob", Password: "password123"})
+    if err != nil {
+        t.Fatalf("RegisterAccount: %v", err)
+    }
+    sub, err := s.CreateSubreddit(ctx, &proto.SubredditRequest{Name: "golang", Description: "Go", CreatorId: alice.Id})
+    if err != nil {
+        t.Fatalf("CreateSubreddit: %v", err)
+    }
+    var postIDs []string
+    for i := 0; i < 3; i++ {
+        post, err := s.CreatePost(ctx, &proto.PostRequest{Title: fmt.Sprintf("Post %d", i), Content: "Content", AuthorId: alice.Id, SubredditId: sub.Id})
+        if err != nil {
+            t.Fatalf("CreatePost: %v", err)
+        }
+        postIDs = append(postIDs, post.Id)
+    }
+    if _, err := s.CreateComment(ctx, &proto.CommentRequest{Content: "Nice", AuthorId: bob.Id, PostId: postIDs[0]}); err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+    if resp, err := s.Vote(ctx, &proto.VoteRequest{UserId: bob.Id, TargetId: postIDs[1], IsUpvote: true}); err != nil || !resp.Success {
+        t.Fatalf("Vote: %v, %v", resp, err)
+    }
+
+    // Nothing reaches the collector until the engine's counts are pushed
+    if stats := collector.GetStats(); stats.TotalPosts != 0 {
+        t.Fatalf("TotalPosts before a push = %d, want 0", stats.TotalPosts)
+    }
+
+    s.PushMetrics()
+    stats := collector.GetStats()
+    if stats.TotalUsers != 2 || stats.TotalPosts != 3 || stats.TotalComments != 1 || stats.TotalVotes != 1 {
+        t.Errorf("totals = %d users, %d posts, %d comments, %d votes; want 2, 3, 1, 1",
+            stats.TotalUsers, stats.TotalPosts, stats.TotalComments, stats.TotalVotes)
+    }
+    subStats := stats.SubredditStats[sub.Id]
+    if subStats == nil || subStats.Name != "golang" || subStats.PostCount != 3 || subStats.CommentCount != 1 || subStats.VoteCount != 1 {
+        t.Errorf("subreddit stats = %+v, want golang with 3 posts, 1 comment, 1 vote", subStats)
+    }
+
+    // Later pushes pick up new content
+    if _, err := s.CreatePost(ctx, &proto.PostRequest{Title: "Post 3", Content: "Content", AuthorId: alice.Id, SubredditId: sub.Id}); err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    s.PushMetrics()
+    if got := collector.GetStats().TotalPosts; got != 4 {
+        t.Errorf("TotalPosts after another post = %d, want 4", got)
+    }
+}
+
+func TestHandlersRecordSubredditOperations(t *testing.T) {
+    s, collector := newMetricsServer(t)
+    ctx := context.Background()
+
+    alice, err := s.RegisterAccount(ctx, &proto.RegisterRequest{Username: "alice", Password: "password123"})
+    if err != nil {
+        t.Fatalf("RegisterAccount: %v", err)
+    }
+    bob, err := s.RegisterAccount(ctx, &proto.RegisterRequest{Username: "bob", Password: "password123"})
+    if err != nil {
+        t.Fatalf("RegisterAccount: %v", err)
+    }
+    sub, err := s.CreateSubreddit(ctx, &proto.SubredditRequest{Name: "golang", Description: "Go", CreatorId: alice.Id})
+    if err != nil {
+        t.Fatalf("CreateSubreddit: %v", err)
+    }
+
+    if resp, err := s.JoinSubreddit(ctx, &proto.JoinRequest{UserId: bob.Id, SubredditId: sub.Id}); err != nil || !resp.Success {
+        t.Fatalf("JoinSubreddit: %v, %v", resp, err)
+    }
+    post, err := s.CreatePost(ctx, &proto.PostRequest{Title: "Hello", Content: "Content", AuthorId: bob.Id, SubredditId: sub.Id})
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    if resp, err := s.Vote(ctx, &proto.VoteRequest{UserId: alice.Id, TargetId: post.Id, IsUpvote: true}); err != nil || !resp.Success {
+        t.Fatalf("Vote: %v, %v", resp, err)
+    }
+    if resp, err := s.LeaveSubreddit(ctx, &proto.JoinRequest{UserId: bob.Id, SubredditId: sub.Id}); err != nil || !resp.Success {
+        t.Fatalf("LeaveSubreddit: %v, %v", resp, err)
+    }
+    // Bob is no longer a member, so this one fails but still counts
+    if _, err := s.CreatePost(ctx, &proto.PostRequest{Title: "Again", Content: "Content", AuthorId: bob.Id, SubredditId: sub.Id}); err == nil {
+        t.Fatal("non-member posted")
+    }
+
+    // Votes on comments and calls naming unknown subreddits aren't attributed
+    comment, err := s.CreateComment(ctx, &proto.CommentRequest{Content: "Nice", AuthorId: alice.Id, PostId: post.Id})
+    if err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+    s.Vote(ctx, &proto.VoteRequest{UserId: bob.Id, TargetId: comment.Id, IsUpvote: true})
+    s.CreatePost(ctx, &proto.PostRequest{Title: "Lost", Content: "Content", AuthorId: alice.Id, SubredditId: "no-such-subreddit"})
+    s.JoinSubreddit(ctx, &proto.JoinRequest{UserId: bob.Id, SubredditId: "no-such-subreddit"})
+
+    // Pushing engine counts doesn't reset the operation counters
+    s.PushMetrics()
+    stats := collector.GetStats()
+    if len(stats.SubredditStats) != 1 {
+        t.Errorf("stats cover %d subreddits, want only golang", len(stats.SubredditStats))
+    }
+    subStats := stats.SubredditStats[sub.Id]
+    if subStats == nil {
+        t.Fatal("no stats for golang")
+    }
+    want := map[string]int64{"JoinSubreddit": 1, "CreatePost": 2, "Vote": 1, "LeaveSubreddit": 1}
+    if len(subStats.Operations) != len(want) {
+        t.Errorf("operations = %v, want %v", subStats.Operations, want)
+    }
+    for op, n := range want {
+        if subStats.Operations[op] != n {
+            t.Errorf("%s count = %d, want %d", op, subStats.Operations[op], n)
+        }
+    }
+    if subStats.OperationErrors != 1 {
+        t.Errorf("OperationErrors = %d, want 1", subStats.OperationErrors)
+    }
+}
\ No newline at end of file
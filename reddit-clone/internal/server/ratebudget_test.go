@@ -0,0 +1,151 @@
+// internal/server/ratebudget_test.go
+package server
+
+import (
+    "context"
+    "io"
+    "net"
+    "testing"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/credentials/insecure"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+    "google.golang.org/grpc/test/bufconn"
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/proto"
+    "reddit-clone/pkg/metrics"
+)
+
+const testAdminKey = "test-admin-key"
+
+// newBudgetedClient serves e over an in-memory connection with the rate
+// budget interceptors installed, and returns a client for it
+func newBudgetedClient(t *testing.T, e *engine.RedditEngine) proto.RedditServiceClient {
+    t.Helper()
+    redditServer := NewRedditServer(e, metrics.NewCollector()).WithAdminKey(testAdminKey)
+    grpcServer := grpc.NewServer(
+        grpc.ChainUnaryInterceptor(RateBudgetInterceptor(e.RateBudget())),
+        grpc.ChainStreamInterceptor(redditServer.RateBudgetStreamInterceptor()),
+    )
+    proto.RegisterRedditServiceServer(grpcServer, redditServer)
+
+    lis := bufconn.Listen(1 << 20)
+    go grpcServer.Serve(lis)
+    t.Cleanup(grpcServer.Stop)
+
+    conn, err := grpc.NewClient("passthrough:///bufnet",
+        grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+            return lis.DialContext(ctx)
+        }),
+        grpc.WithTransportCredentials(insecure.NewCredentials()),
+    )
+    if err != nil {
+        t.Fatalf("grpc.NewClient: %v", err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    return proto.NewRedditServiceClient(conn)
+}
+
+func newBudgetedEngine(size int) *engine.RedditEngine {
+    cfg := engine.NewDefaultConfig()
+    cfg.RateBudgetSize = size
+    return engine.NewRedditEngineWithConfig(cfg)
+}
+
+func TestStreamFeedIsCharged(t *testing.T) {
+    e := newBudgetedEngine(1)
+    client := newBudgetedClient(t, e)
+    alice, err := e.RegisterAccount("alice", "password123")
+    if err != nil {
+        t.Fatalf("RegisterAccount: %v", err)
+    }
+    subreddit, err := e.CreateSubReddit("golang", "Test subreddit", alice.ID)
+    if err != nil {
+        t.Fatalf("CreateSubReddit: %v", err)
+    }
+    if _, err := e.CreatePost("Hello", "World", alice.ID, subreddit.ID); err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+
+    // The first stream is within budget and delivers the feed
+    ctx, cancel := context.WithCancel(context.Background())
+    stream, err := client.StreamFeed(ctx, &proto.FeedRequest{UserId: alice.ID})
+    if err != nil {
+        t.Fatalf("StreamFeed: %v", err)
+    }
+    if _, err := stream.Recv(); err != nil {
+        t.Fatalf("first stream Recv: %v", err)
+    }
+    cancel()
+
+    stream, err = client.StreamFeed(context.Background(), &proto.FeedRequest{UserId: alice.ID})
+    if err != nil {
+        t.Fatalf("StreamFeed: %v", err)
+    }
+    if _, err := stream.Recv(); status.Code(err) != codes.ResourceExhausted {
+        t.Fatalf("second stream Recv: err = %v, want ResourceExhausted", err)
+    }
+}
+
+func TestSnapshotRPCsChargeOperators(t *testing.T) {
+    e := newBudgetedEngine(1)
+    client := newBudgetedClient(t, e)
+
+    export := func(ctx context.Context) error {
+        stream, err := client.ExportSnapshot(ctx, &proto.ExportSnapshotRequest{})
+        if err != nil {
+            return err
+        }
+        for {
+            if _, err := stream.Recv(); err != nil {
+                if err == io.EOF {
+                    return nil
+                }
+                return err
+            }
+        }
+    }
+
+    // Calls without the admin key are refused by the handler, not the
+    // budget, and don't use up the operators' allowance
+    for i := 0; i < 3; i++ {
+        if err := export(context.Background()); status.Code(err) != codes.Unauthenticated {
+            t.Fatalf("export without key: err = %v, want Unauthenticated", err)
+        }
+    }
+
+    adminCtx := metadata.AppendToOutgoingContext(context.Background(), AdminKeyMetadata, testAdminKey)
+    if err := export(adminCtx); err != nil {
+        t.Fatalf("first export: %v", err)
+    }
+    if err := export(adminCtx); status.Code(err) != codes.ResourceExhausted {
+        t.Fatalf("second export: err = %v, want ResourceExhausted", err)
+    }
+
+    stream, err := client.ImportSnapshot(adminCtx)
+    if err != nil {
+        t.Fatalf("ImportSnapshot: %v", err)
+    }
+    if err := stream.Send(&proto.SnapshotChunk{Data: []byte("{}")}); err != nil && err != io.EOF {
+        t.Fatalf("Send: %v", err)
+    }
+    if _, err := stream.CloseAndRecv(); status.Code(err) != codes.ResourceExhausted {
+        t.Fatalf("import: err = %v, want ResourceExhausted", err)
+    }
+}
+
+func TestUnknownUsersAreNotCharged(t *testing.T) {
+    e := newBudgetedEngine(1)
+    client := newBudgetedClient(t, e)
+
+    for i := 0; i < 3; i++ {
+        if _, err := client.GetFeed(context.Background(), &proto.FeedRequest{UserId: "made-up"}); status.Code(err) == codes.ResourceExhausted {
+            t.Fatalf("GetFeed for an unknown user was charged: %v", err)
+        }
+    }
+    if n := e.Stats().MapSizes["rate_budgets"]; n != 0 {
+        t.Errorf("rate_budgets has %d entries after unknown-user calls, want 0", n)
+    }
+}
\ No newline at end of file
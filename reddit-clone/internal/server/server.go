@@ -3,16 +3,36 @@ package server
 
 import (
     "context"
+    "errors"
     "time"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
     "reddit-clone/internal/engine"
+    "reddit-clone/internal/models"
     "reddit-clone/internal/proto"
     "reddit-clone/pkg/metrics"
 )
 
+// Register the gRPC service and its interceptors so RedditEngine.Start can serve it
+func init() {
+    engine.RegisterServices(func(grpcServer *grpc.Server, e *engine.RedditEngine) {
+        proto.RegisterRedditServiceServer(grpcServer, NewRedditServer(e, metrics.NewCollector()))
+    })
+    engine.RegisterInterceptors(func(e *engine.RedditEngine) []grpc.UnaryServerInterceptor {
+        return []grpc.UnaryServerInterceptor{RateBudgetInterceptor(e.RateBudget())}
+    })
+    engine.RegisterStreamInterceptors(func(e *engine.RedditEngine) []grpc.StreamServerInterceptor {
+        return []grpc.StreamServerInterceptor{NewRedditServer(e, nil).RateBudgetStreamInterceptor()}
+    })
+}
+
 type RedditServer struct {
     proto.UnimplementedRedditServiceServer
     engine  *engine.RedditEngine
     metrics *metrics.Collector
+
+    adminKey string // Required by the snapshot RPCs; empty disables them
 }
 
 func NewRedditServer(engine *engine.RedditEngine, metrics *metrics.Collector) *RedditServer {
@@ -22,17 +42,47 @@ func NewRedditServer(engine *engine.RedditEngine, metrics *metrics.Collector) *R
     }
 }
 
+// PushMetrics copies the engine's live content counts into the collector,
+// so the metrics page reports the engine's state rather than zeros
+func (s *RedditServer) PushMetrics() {
+    s.metrics.Update(s.engine.LiveMetrics())
+}
+
+// recordSubredditOp counts a call against the subreddit it acted on.
+// Unknown IDs are skipped so bad requests can't grow the stats map.
+func (s *RedditServer) recordSubredditOp(subredditID, operation string, err error) {
+    if _, lookupErr := s.engine.GetSubReddit(subredditID); lookupErr != nil {
+        return
+    }
+    s.metrics.RecordSubredditOp(subredditID, operation, err != nil)
+}
+
+// floodControlStatus maps engine flood control rejections to ResourceExhausted
+// and duplicate posts to AlreadyExists
+func floodControlStatus(err error) error {
+    if errors.Is(err, engine.ErrPostingTooFast) {
+        return status.Error(codes.ResourceExhausted, err.Error())
+    }
+    if errors.Is(err, engine.ErrDuplicatePost) {
+        return status.Error(codes.AlreadyExists, err.Error())
+    }
+    return maintenanceStatus(err)
+}
+
+// maintenanceStatus maps the engine's maintenance mode to Unavailable, which
+// clients treat as retryable
+func maintenanceStatus(err error) error {
+    if errors.Is(err, engine.ErrReadOnly) {
+        return status.Error(codes.Unavailable, err.Error())
+    }
+    return err
+}
+
 // RegisterAccount handles user registration
 func (s *RedditServer) RegisterAccount(ctx context.Context, req *proto.RegisterRequest) (*proto.UserResponse, error) {
-    start := time.Now()
-    defer func() {
-        s.metrics.RecordLatency("RegisterAccount", time.Since(start))
-    }()
-
     user, err := s.engine.RegisterAccount(req.Username, req.Password)
     if err != nil {
-        s.metrics.RecordError("RegisterAccount")
-        return nil, err
+        return nil, maintenanceStatus(err)
     }
 
     return &proto.UserResponse{
@@ -46,15 +96,12 @@ func (s *RedditServer) RegisterAccount(ctx context.Context, req *proto.RegisterR
 
 // CreateSubreddit handles subreddit creation
 func (s *RedditServer) CreateSubreddit(ctx context.Context, req *proto.SubredditRequest) (*proto.SubredditResponse, error) {
-    start := time.Now()
-    defer func() {
-        s.metrics.RecordLatency("CreateSubreddit", time.Since(start))
-    }()
-
     subreddit, err := s.engine.CreateSubReddit(req.Name, req.Description, req.CreatorId)
+    if errors.Is(err, engine.ErrSubredditLimit) {
+        return nil, status.Error(codes.ResourceExhausted, err.Error())
+    }
     if err != nil {
-        s.metrics.RecordError("CreateSubreddit")
-        return nil, err
+        return nil, maintenanceStatus(err)
     }
 
     return &proto.SubredditResponse{
@@ -69,33 +116,32 @@ func (s *RedditServer) CreateSubreddit(ctx context.Context, req *proto.Subreddit
 
 // JoinSubreddit handles joining a subreddit
 func (s *RedditServer) JoinSubreddit(ctx context.Context, req *proto.JoinRequest) (*proto.StatusResponse, error) {
-    start := time.Now()
-    defer func() {
-        s.metrics.RecordLatency("JoinSubreddit", time.Since(start))
-    }()
-
-    err := s.engine.JoinSubReddit(req.UserId, req.SubredditId)
+    joined, err := s.engine.JoinSubReddit(req.UserId, req.SubredditId)
+    s.recordSubredditOp(req.SubredditId, "JoinSubreddit", err)
+    if errors.Is(err, engine.ErrReadOnly) {
+        return nil, maintenanceStatus(err)
+    }
     if err != nil {
-        s.metrics.RecordError("JoinSubreddit")
         return &proto.StatusResponse{
             Success: false,
             Message: err.Error(),
         }, nil
     }
+    if !joined {
+        return &proto.StatusResponse{Success: true, Message: "already a member"}, nil
+    }
 
     return &proto.StatusResponse{Success: true}, nil
 }
 
 // LeaveSubreddit handles leaving a subreddit
 func (s *RedditServer) LeaveSubreddit(ctx context.Context, req *proto.JoinRequest) (*proto.StatusResponse, error) {
-    start := time.Now()
-    defer func() {
-        s.metrics.RecordLatency("LeaveSubreddit", time.Since(start))
-    }()
-
     err := s.engine.LeaveSubReddit(req.UserId, req.SubredditId)
+    s.recordSubredditOp(req.SubredditId, "LeaveSubreddit", err)
+    if errors.Is(err, engine.ErrReadOnly) {
+        return nil, maintenanceStatus(err)
+    }
     if err != nil {
-        s.metrics.RecordError("LeaveSubreddit")
         return &proto.StatusResponse{
             Success: false,
             Message: err.Error(),
@@ -107,40 +153,36 @@ func (s *RedditServer) LeaveSubreddit(ctx context.Context, req *proto.JoinReques
 
 // CreatePost handles post creation
 func (s *RedditServer) CreatePost(ctx context.Context, req *proto.PostRequest) (*proto.PostResponse, error) {
-    start := time.Now()
-    defer func() {
-        s.metrics.RecordLatency("CreatePost", time.Since(start))
-    }()
-
     post, err := s.engine.CreatePost(req.Title, req.Content, req.AuthorId, req.SubredditId)
+    s.recordSubredditOp(req.SubredditId, "CreatePost", err)
     if err != nil {
-        s.metrics.RecordError("CreatePost")
-        return nil, err
+        return nil, floodControlStatus(err)
     }
 
+    return s.postResponse(post), nil
+}
+
+// postResponse converts a post to its gRPC form
+func (s *RedditServer) postResponse(post *models.Post) *proto.PostResponse {
+    upvotes, downvotes := post.Votes()
+    upvotes, downvotes = s.engine.DisplayVotes(post.ID, upvotes, downvotes)
     return &proto.PostResponse{
         Id:          post.ID,
-        Title:       post.Title,
-        Content:     post.Content,
-        AuthorId:    post.AuthorID,
+        Title:       s.engine.RenderTitle(post.Title),
+        Content:     s.engine.RenderContent(post.Content),
+        AuthorId:    s.engine.PostAuthorFor("", post),
         SubredditId: post.SubRedditID,
-        Upvotes:     post.Upvotes,
-        Downvotes:   post.Downvotes,
+        Upvotes:     upvotes,
+        Downvotes:   downvotes,
         CreatedAt:   post.CreatedAt.Unix(),
-    }, nil
+    }
 }
 
 // CreateComment handles comment creation
-unc (s *RedditServer) CreateComment(ctx context.Context, req *proto.CommentRequest) (*proto.CommentResponse, error) {
-    start := time.Now()
-    defer func() {
-        s.metrics.RecordLatency("CreateComment", time.Since(start))
-    }()
-
+func (s *RedditServer) CreateComment(ctx context.Context, req *proto.CommentRequest) (*proto.CommentResponse, error) {
     comment, err := s.engine.CreateComment(req.Content, req.AuthorId, req.PostId, req.ParentId)
     if err != nil {
-        s.metrics.RecordError("CreateComment")
-        return nil, err
+        return nil, floodControlStatus(err)
     }
 
     // Handle the optional ParentID
@@ -149,29 +191,31 @@ unc (s *RedditServer) CreateComment(ctx context.Context, req *proto.CommentReque
         parentId = *comment.ParentID
     }
 
+    upvotes, downvotes := comment.Votes()
+    upvotes, downvotes = s.engine.DisplayVotes(comment.ID, upvotes, downvotes)
     return &proto.CommentResponse{
         Id:        comment.ID,
-        Content:   comment.Content,
-        AuthorId:  comment.AuthorID,
+        Content:   s.engine.RenderContent(comment.Content),
+        AuthorId:  s.engine.CommentAuthorFor("", comment),
         PostId:    comment.PostID,
         ParentId:  parentId,          // Now using string instead of *string
         Depth:     int32(comment.Depth),
-        Upvotes:   comment.Upvotes,
-        Downvotes: comment.Downvotes,
+        Upvotes:   upvotes,
+        Downvotes: downvotes,
         CreatedAt: comment.CreatedAt.Unix(),
     }, nil
 }
 
 // Vote handles voting on posts and comments
 func (s *RedditServer) Vote(ctx context.Context, req *proto.VoteRequest) (*proto.StatusResponse, error) {
-    start := time.Now()
-    defer func() {
-        s.metrics.RecordLatency("Vote", time.Since(start))
-    }()
-
     err := s.engine.Vote(req.UserId, req.TargetId, req.IsUpvote)
+    if post, lookupErr := s.engine.GetPost(req.TargetId); lookupErr == nil {
+        s.recordSubredditOp(post.SubRedditID, "Vote", err)
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        return nil, maintenanceStatus(err)
+    }
     if err != nil {
-        s.metrics.RecordError("Vote")
         return &proto.StatusResponse{
             Success: false,
             Message: err.Error(),
@@ -183,45 +227,65 @@ func (s *RedditServer) Vote(ctx context.Context, req *proto.VoteRequest) (*proto
 
 // GetFeed handles retrieving a user's feed
 func (s *RedditServer) GetFeed(ctx context.Context, req *proto.FeedRequest) (*proto.FeedResponse, error) {
-    start := time.Now()
-    defer func() {
-        s.metrics.RecordLatency("GetFeed", time.Since(start))
-    }()
-
-    posts, err := s.engine.GetFeed(req.UserId)
+    posts, err := s.engine.GetFeedWithOptions(ctx, req.UserId, engine.FeedOptions{})
     if err != nil {
-        s.metrics.RecordError("GetFeed")
         return nil, err
     }
 
     protoPosts := make([]*proto.PostResponse, len(posts))
     for i, post := range posts {
-        protoPosts[i] = &proto.PostResponse{
-            Id:          post.ID,
-            Title:       post.Title,
-            Content:     post.Content,
-            AuthorId:    post.AuthorID,
-            SubredditId: post.SubRedditID,
-            Upvotes:     post.Upvotes,
-            Downvotes:   post.Downvotes,
-            CreatedAt:   post.CreatedAt.Unix(),
-        }
+        protoPosts[i] = s.postResponse(post)
     }
 
     return &proto.FeedResponse{Posts: protoPosts}, nil
 }
 
+// StreamFeed sends the user's current feed, then each new post created in
+// their subreddits until the client goes away
+func (s *RedditServer) StreamFeed(req *proto.FeedRequest, stream proto.RedditService_StreamFeedServer) error {
+    // Subscribe before reading the feed so no post falls in between
+    newPosts, cancel, err := s.engine.SubscribeFeed(req.UserId)
+    if errors.Is(err, engine.ErrTooManyFeedSubscriptions) {
+        return status.Error(codes.ResourceExhausted, err.Error())
+    }
+    if err != nil {
+        return status.Error(codes.NotFound, err.Error())
+    }
+    defer cancel()
+
+    posts, err := s.engine.GetFeed(req.UserId)
+    if err != nil {
+        return err
+    }
+    sent := make(map[string]bool, len(posts))
+    for _, post := range posts {
+        if err := stream.Send(s.postResponse(post)); err != nil {
+            return err
+        }
+        sent[post.ID] = true
+    }
+
+    for {
+        select {
+        case <-stream.Context().Done():
+            return nil
+        case post := <-newPosts:
+            if sent[post.ID] {
+                continue
+            }
+            if err := stream.Send(s.postResponse(post)); err != nil {
+                return err
+            }
+        }
+    }
+}
+
 // SendMessage handles sending direct messages
 func (s *RedditServer) SendMessage(ctx context.Context, req *proto.MessageRequest) (*proto.MessageResponse, error) {
-    start := time.Now()
-    defer func() {
-        s.metrics.RecordLatency("SendMessage", time.Since(start))
-    }()
-
-    msg, err := s.engine.SendDirectMessage(req.FromId, req.ToId, req.Content)
+    ttl := time.Duration(req.TtlSeconds) * time.Second
+    msg, err := s.engine.SendDirectMessageWithTTL(req.FromId, req.ToId, req.Content, ttl)
     if err != nil {
-        s.metrics.RecordError("SendMessage")
-        return nil, err
+        return nil, maintenanceStatus(err)
     }
 
     return &proto.MessageResponse{
@@ -231,21 +295,17 @@ func (s *RedditServer) SendMessage(ctx context.Context, req *proto.MessageReques
         Content:   msg.Content,
         IsRead:    msg.IsRead,
         CreatedAt: msg.CreatedAt.Unix(),
+        ExpiresAt: expiresAtUnix(msg.ExpiresAt),
     }, nil
 }
 
 // GetUserMessages handles retrieving a user's messages
 func (s *RedditServer) GetUserMessages(ctx context.Context, req *proto.UserRequest) (*proto.MessagesResponse, error) {
-    start := time.Now()
-    defer func() {
-        s.metrics.RecordLatency("GetUserMessages", time.Since(start))
-    }()
-
-    messages, err := s.engine.GetUserMessages(req.UserId)
+    page, err := s.engine.GetUserMessages(req.UserId, 1, 0, false)
     if err != nil {
-        s.metrics.RecordError("GetUserMessages")
         return nil, err
     }
+    messages := page.Messages
 
     protoMessages := make([]*proto.MessageResponse, len(messages))
     for i, msg := range messages {
@@ -256,8 +316,17 @@ func (s *RedditServer) GetUserMessages(ctx context.Context, req *proto.UserReque
             Content:   msg.Content,
             IsRead:    msg.IsRead,
             CreatedAt: msg.CreatedAt.Unix(),
+            ExpiresAt: expiresAtUnix(msg.ExpiresAt),
         }
     }
 
     return &proto.MessagesResponse{Messages: protoMessages}, nil
+}
+
+// expiresAtUnix converts an optional expiry into unix seconds (0 for none)
+func expiresAtUnix(expiresAt *time.Time) int64 {
+    if expiresAt == nil {
+        return 0
+    }
+    return expiresAt.Unix()
 }
\ No newline at end of file
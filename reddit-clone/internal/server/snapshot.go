@@ -0,0 +1,148 @@
+// internal/server/snapshot.go
+package server
+
+import (
+    "context"
+    "crypto/subtle"
+    "errors"
+    "io"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/proto"
+)
+
+// AdminKeyMetadata is the metadata key that carries the admin credential
+// on ExportSnapshot and ImportSnapshot calls
+const AdminKeyMetadata = "x-admin-key"
+
+// snapshotChunkSize is how much serialized state one ExportSnapshot message holds
+const snapshotChunkSize = 64 * 1024
+
+// WithAdminKey sets the credential the admin RPCs require and returns the
+// server. Without one every admin call is refused.
+func (s *RedditServer) WithAdminKey(key string) *RedditServer {
+    s.adminKey = key
+    return s
+}
+
+// checkAdmin verifies the admin key in the call's metadata
+func (s *RedditServer) checkAdmin(ctx context.Context) error {
+    if s.adminKey == "" {
+        return status.Error(codes.PermissionDenied, "admin RPCs are disabled on this server")
+    }
+    md, _ := metadata.FromIncomingContext(ctx)
+    keys := md.Get(AdminKeyMetadata)
+    if len(keys) == 0 || subtle.ConstantTimeCompare([]byte(keys[0]), []byte(s.adminKey)) != 1 {
+        return status.Error(codes.Unauthenticated, "invalid admin key")
+    }
+    return nil
+}
+
+// ExportSnapshot streams the engine's state in the format SaveState writes,
+// so another engine can import it without access to this one's disk
+func (s *RedditServer) ExportSnapshot(req *proto.ExportSnapshotRequest, stream grpc.ServerStreamingServer[proto.SnapshotChunk]) error {
+    if err := s.checkAdmin(stream.Context()); err != nil {
+        return err
+    }
+
+    pr, pw := io.Pipe()
+    go func() {
+        pw.CloseWithError(s.engine.WriteSnapshot(pw))
+    }()
+    // Closing the reader unblocks the encoder if the client goes away
+    defer pr.Close()
+
+    buf := make([]byte, snapshotChunkSize)
+    for {
+        n, err := io.ReadFull(pr, buf)
+        if n > 0 {
+            if err := stream.Send(&proto.SnapshotChunk{Data: buf[:n]}); err != nil {
+                return err
+            }
+        }
+        if err == io.EOF || err == io.ErrUnexpectedEOF {
+            return nil
+        }
+        if err != nil {
+            return status.Error(codes.Internal, err.Error())
+        }
+    }
+}
+
+// ImportSnapshot restores a snapshot streamed by ExportSnapshot. The first
+// chunk's merge flag picks between refusing a non-empty engine and merging
+// into it.
+func (s *RedditServer) ImportSnapshot(stream grpc.ClientStreamingServer[proto.SnapshotChunk, proto.ImportSnapshotResponse]) error {
+    if err := s.checkAdmin(stream.Context()); err != nil {
+        return err
+    }
+
+    first, err := stream.Recv()
+    if err == io.EOF {
+        return status.Error(codes.InvalidArgument, "no snapshot data received")
+    }
+    if err != nil {
+        return err
+    }
+
+    read := s.engine.ReadSnapshot
+    if first.Merge {
+        read = s.engine.MergeSnapshot
+    }
+    if err := read(&chunkReader{stream: stream, buf: first.Data}); err != nil {
+        return importStatus(err)
+    }
+
+    counts, err := s.engine.GlobalStats()
+    if err != nil {
+        return status.Error(codes.Internal, err.Error())
+    }
+    return stream.SendAndClose(&proto.ImportSnapshotResponse{
+        TotalUsers:      counts.TotalUsers,
+        TotalSubreddits: counts.TotalSubreddits,
+        TotalPosts:      counts.TotalPosts,
+        TotalComments:   counts.TotalComments,
+    })
+}
+
+// importStatus maps a refused import to FailedPrecondition, a merge
+// conflict to AlreadyExists and anything else to InvalidArgument, since
+// it means the streamed data didn't decode
+func importStatus(err error) error {
+    if errors.Is(err, engine.ErrEngineNotEmpty) {
+        return status.Error(codes.FailedPrecondition, err.Error())
+    }
+    if errors.Is(err, engine.ErrMergeConflict) {
+        return status.Error(codes.AlreadyExists, err.Error())
+    }
+    if errors.Is(err, engine.ErrReadOnly) {
+        return maintenanceStatus(err)
+    }
+    if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+        return err
+    }
+    return status.Error(codes.InvalidArgument, err.Error())
+}
+
+// chunkReader reads the data of an ImportSnapshot stream's chunks in order
+type chunkReader struct {
+    stream grpc.ClientStreamingServer[proto.SnapshotChunk, proto.ImportSnapshotResponse]
+    buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+    for len(r.buf) == 0 {
+        chunk, err := r.stream.Recv()
+        if err != nil {
+            return 0, err
+        }
+        r.buf = chunk.Data
+    }
+    n := copy(p, r.buf)
+    r.buf = r.buf[n:]
+    return n, nil
+}
\ No newline at end of file
@@ -0,0 +1,141 @@
+// internal/server/snapshot_test.go
+package server
+
+import (
+    "context"
+    "io"
+    "testing"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/proto"
+)
+
+func adminContext() context.Context {
+    return metadata.AppendToOutgoingContext(context.Background(), AdminKeyMetadata, testAdminKey)
+}
+
+// exportChunks reads a full ExportSnapshot stream
+func exportChunks(t *testing.T, client proto.RedditServiceClient) []*proto.SnapshotChunk {
+    t.Helper()
+    stream, err := client.ExportSnapshot(adminContext(), &proto.ExportSnapshotRequest{})
+    if err != nil {
+        t.Fatalf("ExportSnapshot: %v", err)
+    }
+    var chunks []*proto.SnapshotChunk
+    for {
+        chunk, err := stream.Recv()
+        if err == io.EOF {
+            return chunks
+        }
+        if err != nil {
+            t.Fatalf("export Recv: %v", err)
+        }
+        chunks = append(chunks, chunk)
+    }
+}
+
+// importChunks streams chunks into ImportSnapshot and returns its result
+func importChunks(t *testing.T, client proto.RedditServiceClient, chunks []*proto.SnapshotChunk) (*proto.ImportSnapshotResponse, error) {
+    t.Helper()
+    stream, err := client.ImportSnapshot(adminContext())
+    if err != nil {
+        t.Fatalf("ImportSnapshot: %v", err)
+    }
+    for _, chunk := range chunks {
+        if err := stream.Send(chunk); err == io.EOF {
+            break
+        } else if err != nil {
+            t.Fatalf("import Send: %v", err)
+        }
+    }
+    return stream.CloseAndRecv()
+}
+
+func TestSnapshotExportImportBetweenEngines(t *testing.T) {
+    source := engine.NewRedditEngine()
+    alice, err := source.RegisterAccount("alice", "password123")
+    if err != nil {
+        t.Fatalf("RegisterAccount: %v", err)
+    }
+    bob, err := source.RegisterAccount("bob", "password123")
+    if err != nil {
+        t.Fatalf("RegisterAccount: %v", err)
+    }
+    subreddit, err := source.CreateSubReddit("golang", "Test subreddit", alice.ID)
+    if err != nil {
+        t.Fatalf("CreateSubReddit: %v", err)
+    }
+    if _, err := source.JoinSubReddit(bob.ID, subreddit.ID); err != nil {
+        t.Fatalf("JoinSubReddit: %v", err)
+    }
+    post, err := source.CreatePost("Hello", "World", alice.ID, subreddit.ID)
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    if _, err := source.CreateComment("Nice post", bob.ID, post.ID, nil); err != nil {
+        t.Fatalf("CreateComment: %v", err)
+    }
+    if err := source.Vote(bob.ID, post.ID, true); err != nil {
+        t.Fatalf("Vote: %v", err)
+    }
+
+    chunks := exportChunks(t, newBudgetedClient(t, source))
+    if len(chunks) == 0 {
+        t.Fatal("export sent no chunks")
+    }
+
+    target := engine.NewRedditEngine()
+    resp, err := importChunks(t, newBudgetedClient(t, target), chunks)
+    if err != nil {
+        t.Fatalf("import: %v", err)
+    }
+    want, err := source.GlobalStats()
+    if err != nil {
+        t.Fatalf("GlobalStats: %v", err)
+    }
+    if resp.TotalUsers != want.TotalUsers || resp.TotalSubreddits != want.TotalSubreddits ||
+        resp.TotalPosts != want.TotalPosts || resp.TotalComments != want.TotalComments {
+        t.Errorf("import counts = %+v, want %+v", resp, want)
+    }
+
+    imported, err := target.GetUserByUsername("bob")
+    if err != nil || imported.ID != bob.ID {
+        t.Fatalf("imported bob = %+v, %v; want ID %s", imported, err, bob.ID)
+    }
+    importedPost, err := target.GetPost(post.ID)
+    if err != nil {
+        t.Fatalf("GetPost after import: %v", err)
+    }
+    if importedPost.Title != "Hello" {
+        t.Errorf("imported post title = %q, want %q", importedPost.Title, "Hello")
+    }
+    if up, _ := importedPost.Votes(); up != 1 {
+        t.Errorf("imported post upvotes = %d, want 1", up)
+    }
+
+    // A second plain import is refused now that the target holds data
+    if _, err := importChunks(t, newBudgetedClient(t, target), chunks); status.Code(err) != codes.FailedPrecondition {
+        t.Errorf("import into a non-empty engine: err = %v, want FailedPrecondition", err)
+    }
+}
+
+func TestSnapshotImportRefusedInMaintenance(t *testing.T) {
+    source := engine.NewRedditEngine()
+    if _, err := source.RegisterAccount("alice", "password123"); err != nil {
+        t.Fatalf("RegisterAccount: %v", err)
+    }
+    chunks := exportChunks(t, newBudgetedClient(t, source))
+
+    target := engine.NewRedditEngine()
+    target.SetReadOnly(true)
+    if _, err := importChunks(t, newBudgetedClient(t, target), chunks); status.Code(err) != codes.Unavailable {
+        t.Fatalf("import in maintenance: err = %v, want Unavailable", err)
+    }
+    target.SetReadOnly(false)
+    if _, err := target.GetUserByUsername("alice"); err == nil {
+        t.Error("import in maintenance restored data")
+    }
+}
\ No newline at end of file
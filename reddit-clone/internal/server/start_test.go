@@ -0,0 +1,68 @@
+// internal/server/start_test.go
+package server
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/credentials/insecure"
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/proto"
+)
+
+// The engine's own Start serves the services this package registers in init
+func TestEngineStartServesRPCs(t *testing.T) {
+    e := engine.NewRedditEngine()
+    served := make(chan error, 1)
+    go func() { served <- e.Start("127.0.0.1:0") }()
+    t.Cleanup(e.Stop)
+
+    deadline := time.Now().Add(5 * time.Second)
+    for e.Addr() == nil {
+        select {
+        case err := <-served:
+            t.Fatalf("Start returned before listening: %v", err)
+        default:
+        }
+        if time.Now().After(deadline) {
+            t.Fatal("engine didn't start listening")
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+
+    conn, err := grpc.NewClient(e.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+    if err != nil {
+        t.Fatalf("grpc.NewClient: %v", err)
+    }
+    defer conn.Close()
+    client := proto.NewRedditServiceClient(conn)
+
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    user, err := client.RegisterAccount(ctx, &proto.RegisterRequest{Username: "alice", Password: "password123"})
+    if err != nil {
+        t.Fatalf("RegisterAccount: %v", err)
+    }
+    if _, err := e.GetUser(user.Id); err != nil {
+        t.Errorf("registered user isn't in the engine: %v", err)
+    }
+
+    if err := e.Start("127.0.0.1:0"); err == nil {
+        t.Error("second Start succeeded, want an error")
+    }
+
+    e.Stop()
+    select {
+    case err := <-served:
+        if err != nil {
+            t.Errorf("Start returned %v after Stop, want nil", err)
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("Start didn't return after Stop")
+    }
+    if e.Addr() != nil {
+        t.Errorf("Addr = %v after Stop, want nil", e.Addr())
+    }
+}
\ No newline at end of file
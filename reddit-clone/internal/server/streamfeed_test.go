@@ -0,0 +1,144 @@
+// internal/server/streamfeed_test.go
+package server
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "reddit-clone/internal/engine"
+    "reddit-clone/internal/proto"
+)
+
+// newFeedEngine returns an engine that lets tests post freely and allows
+// each user maxStreams live feeds
+func newFeedEngine(maxStreams int) *engine.RedditEngine {
+    cfg := engine.NewDefaultConfig()
+    cfg.PostCooldown = 0
+    cfg.DuplicatePostWindow = 0
+    cfg.MaxFeedSubscriptionsPerUser = maxStreams
+    return engine.NewRedditEngineWithConfig(cfg)
+}
+
+func TestStreamFeedSendsFeedThenNewPosts(t *testing.T) {
+    e := newFeedEngine(engine.DefaultMaxFeedSubscriptionsPerUser)
+    client := newBudgetedClient(t, e)
+    alice, err := e.RegisterAccount("alice", "password123")
+    if err != nil {
+        t.Fatalf("RegisterAccount: %v", err)
+    }
+    bob, err := e.RegisterAccount("bob", "password123")
+    if err != nil {
+        t.Fatalf("RegisterAccount: %v", err)
+    }
+    golang, err := e.CreateSubReddit("golang", "Subscribed", alice.ID)
+    if err != nil {
+        t.Fatalf("CreateSubReddit: %v", err)
+    }
+    rust, err := e.CreateSubReddit("rust", "Not subscribed", alice.ID)
+    if err != nil {
+        t.Fatalf("CreateSubReddit: %v", err)
+    }
+    if _, err := e.JoinSubReddit(bob.ID, golang.ID); err != nil {
+        t.Fatalf("JoinSubReddit: %v", err)
+    }
+    existing, err := e.CreatePost("Existing", "Already there", alice.ID, golang.ID)
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    stream, err := client.StreamFeed(ctx, &proto.FeedRequest{UserId: bob.ID})
+    if err != nil {
+        t.Fatalf("StreamFeed: %v", err)
+    }
+    post, err := stream.Recv()
+    if err != nil {
+        t.Fatalf("Recv: %v", err)
+    }
+    if post.Id != existing.ID {
+        t.Fatalf("first post = %s, want the existing %s", post.Id, existing.ID)
+    }
+
+    // The stream is subscribed once the feed has been sent, so a post
+    // elsewhere is skipped and the next subscribed one arrives live
+    if _, err := e.CreatePost("Elsewhere", "Not for bob", alice.ID, rust.ID); err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    live, err := e.CreatePost("Live", "Just posted", alice.ID, golang.ID)
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    post, err = stream.Recv()
+    if err != nil {
+        t.Fatalf("Recv: %v", err)
+    }
+    if post.Id != live.ID || post.Title != "Live" {
+        t.Errorf("live post = %s %q, want %s %q", post.Id, post.Title, live.ID, "Live")
+    }
+}
+
+func TestStreamFeedRefusals(t *testing.T) {
+    e := newFeedEngine(1)
+    client := newBudgetedClient(t, e)
+    alice, err := e.RegisterAccount("alice", "password123")
+    if err != nil {
+        t.Fatalf("RegisterAccount: %v", err)
+    }
+
+    stream, err := client.StreamFeed(context.Background(), &proto.FeedRequest{UserId: "ghost"})
+    if err != nil {
+        t.Fatalf("StreamFeed: %v", err)
+    }
+    if _, err := stream.Recv(); status.Code(err) != codes.NotFound {
+        t.Errorf("stream for an unknown user: err = %v, want NotFound", err)
+    }
+
+    // Receiving alice's post shows a stream holds its subscription
+    golang, err := e.CreateSubReddit("golang", "Go", alice.ID)
+    if err != nil {
+        t.Fatalf("CreateSubReddit: %v", err)
+    }
+    if _, err := e.CreatePost("Hello", "World", alice.ID, golang.ID); err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    openStream := func(ctx context.Context) error {
+        stream, err := client.StreamFeed(ctx, &proto.FeedRequest{UserId: alice.ID})
+        if err != nil {
+            return err
+        }
+        _, err = stream.Recv()
+        return err
+    }
+
+    first, cancelFirst := context.WithCancel(context.Background())
+    if err := openStream(first); err != nil {
+        t.Fatalf("first stream: %v", err)
+    }
+    if err := openStream(context.Background()); status.Code(err) != codes.ResourceExhausted {
+        t.Errorf("second stream: err = %v, want ResourceExhausted", err)
+    }
+
+    // Closing the first stream frees its slot once the server sees it go
+    cancelFirst()
+    deadline := time.Now().Add(5 * time.Second)
+    for {
+        _, cancel, err := e.SubscribeFeed(alice.ID)
+        if err == nil {
+            cancel()
+            break
+        }
+        if time.Now().After(deadline) {
+            t.Fatalf("slot not freed after the first stream closed: %v", err)
+        }
+        time.Sleep(time.Millisecond)
+    }
+    second, cancelSecond := context.WithCancel(context.Background())
+    defer cancelSecond()
+    if err := openStream(second); err != nil {
+        t.Errorf("stream after the first closed: %v", err)
+    }
+}
\ No newline at end of file
@@ -5,6 +5,7 @@ import (
     "fmt"
     "log"
     "math/rand"
+    "sort"
     "sync"
     "time"
     
@@ -12,6 +13,36 @@ import (
     "reddit-clone/internal/models"
 )
 
+// DefaultRepostProbability is the chance that an active user reposts on a tick
+const DefaultRepostProbability = 0.2
+
+// Action is one thing an active simulated user does on a tick
+type Action int
+
+const (
+    ActionPost Action = iota
+    ActionComment
+    ActionVote
+    ActionMessage
+    ActionRepost
+)
+
+// PickAction draws an active user's next action: a repost with probability
+// repostProb, otherwise posting, commenting, voting or messaging with equal
+// chance. The engine benchmark harness uses it to replay the same mix.
+func PickAction(rng *rand.Rand, repostProb float64) Action {
+    if rng.Float64() < repostProb {
+        return ActionRepost
+    }
+    return Action(rng.Intn(4))
+}
+
+// Options tunes the simulated workload
+type Options struct {
+    Seed              int64   // Seeds the random source; 0 seeds from the clock
+    RepostProbability float64 // Chance that an active user reposts on a tick
+}
+
 type Simulator struct {
     client         *client.RedditClient
     users          []*models.User
@@ -24,6 +55,7 @@ type Simulator struct {
     commentCount   map[string]int      // map[subredditID]count
     voteCount      map[string]int      // map[subredditID]count
     rng            *rand.Rand
+    repostProb     float64
     wg             sync.WaitGroup
     stopChan       chan struct{}
     metrics        *models.Metrics
@@ -31,6 +63,15 @@ type Simulator struct {
 }
 
 func NewSimulator(client *client.RedditClient, numUsers int) *Simulator {
+    return NewSimulatorWithOptions(client, numUsers, Options{RepostProbability: DefaultRepostProbability})
+}
+
+// NewSimulatorWithOptions creates a simulator with the given workload options
+func NewSimulatorWithOptions(client *client.RedditClient, numUsers int, opts Options) *Simulator {
+    seed := opts.Seed
+    if seed == 0 {
+        seed = time.Now().UnixNano()
+    }
     return &Simulator{
         client:         client,
         numUsers:       numUsers,
@@ -39,7 +80,8 @@ func NewSimulator(client *client.RedditClient, numUsers int) *Simulator {
         postCount:      make(map[string]int),
         commentCount:   make(map[string]int),
         voteCount:      make(map[string]int),
-        rng:           rand.New(rand.NewSource(time.Now().UnixNano())),
+        rng:           rand.New(rand.NewSource(seed)),
+        repostProb:    opts.RepostProbability,
         stopChan:      make(chan struct{}),
         metrics:       &models.Metrics{
             StartTime:      time.Now(),
@@ -160,17 +202,17 @@ func (s *Simulator) simulateUserActivity(user *models.User) {
             }
 
             // Perform random actions
-            switch s.rng.Intn(5) {
-            case 0:
+            switch PickAction(s.rng, s.repostProb) {
+            case ActionPost:
                 s.simulatePosting(user)
-            case 1:
+            case ActionComment:
                 s.simulateCommenting(user)
-            case 2:
+            case ActionVote:
                 s.simulateVoting(user)
-            case 3:
-                s.simulateRepost(user)
-            case 4:
+            case ActionMessage:
                 s.simulateDirectMessage(user)
+            case ActionRepost:
+                s.simulateRepost(user)
             }
         }
     }
@@ -278,19 +320,19 @@ func (s *Simulator) simulateRepost(user *models.User) {
         return
     }
 
-    // Find a popular post to repost
-    var popularPosts []*models.Post
+    // Pick someone else's post, favouring popular ones
+    var candidates []*models.Post
     for _, post := range feed {
-        if post.Upvotes > 10 && post.AuthorID != user.ID {
-            popularPosts = append(popularPosts, post)
+        if post.AuthorID != user.ID {
+            candidates = append(candidates, post)
         }
     }
 
-    if len(popularPosts) == 0 {
+    if len(candidates) == 0 {
         return
     }
 
-    originalPost := popularPosts[s.rng.Intn(len(popularPosts))]
+    originalPost := s.pickPopularPost(candidates)
     userSubs := s.userSubs[user.ID]
     if len(userSubs) == 0 {
         return
@@ -341,6 +383,23 @@ func (s *Simulator) generateZipfDistribution() *rand.Zipf {
     return rand.NewZipf(s.rng, 1.5, 1, uint64(max(1, len(s.subreddits))))
 }
 
+// pickPopularPost ranks posts by score, newest first among equal scores, and
+// samples a rank from a Zipf distribution so the most popular posts are
+// picked most often. With few votes cast the ranking falls back to recency.
+func (s *Simulator) pickPopularPost(posts []*models.Post) *models.Post {
+    ranked := make([]*models.Post, len(posts))
+    copy(ranked, posts)
+    sort.SliceStable(ranked, func(i, j int) bool {
+        if ranked[i].Score() != ranked[j].Score() {
+            return ranked[i].Score() > ranked[j].Score()
+        }
+        return ranked[i].CreatedAt.After(ranked[j].CreatedAt)
+    })
+
+    zipf := rand.NewZipf(s.rng, 1.5, 1, uint64(len(ranked)-1))
+    return ranked[zipf.Uint64()]
+}
+
 func (s *Simulator) GetMetrics() *models.Metrics {
     s.mtx.RLock()
     defer s.mtx.RUnlock()
@@ -0,0 +1,70 @@
+// internal/simulator/simulator_test.go
+package simulator
+
+import (
+    "fmt"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+// newTestSimulator returns a seeded simulator that never talks to a server
+func newTestSimulator(seed int64) *Simulator {
+    return NewSimulatorWithOptions(nil, 0, Options{Seed: seed})
+}
+
+func TestPickPopularPostFavorsTopScores(t *testing.T) {
+    s := newTestSimulator(1)
+    start := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+    // Post i scores i; they are handed over oldest and lowest first
+    const numPosts = 10
+    posts := make([]*models.Post, numPosts)
+    for i := range posts {
+        posts[i] = &models.Post{ID: fmt.Sprintf("post%d", i), Upvotes: int64(i), CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+    }
+
+    const draws = 10000
+    picks := make(map[string]int)
+    for i := 0; i < draws; i++ {
+        picks[s.pickPopularPost(posts).ID]++
+    }
+
+    // Rank r is drawn with weight (r+1)^-1.5, so the top post gets about
+    // half the picks and each lower rank fewer than the one above it
+    byRank := make([]int, numPosts)
+    for r := range byRank {
+        byRank[r] = picks[posts[numPosts-1-r].ID]
+    }
+    if share := float64(byRank[0]) / draws; share < 0.4 || share > 0.6 {
+        t.Errorf("top post drawn %.2f of the time, want about 0.5", share)
+    }
+    for r := 1; r < 4; r++ {
+        if byRank[r] >= byRank[r-1] {
+            t.Errorf("rank %d drawn %d times, not fewer than rank %d's %d", r, byRank[r], r-1, byRank[r-1])
+        }
+    }
+    if byRank[numPosts-1] == 0 {
+        t.Error("the lowest-ranked post was never drawn")
+    }
+}
+
+func TestPickPopularPostBreaksTiesByRecency(t *testing.T) {
+    s := newTestSimulator(1)
+    start := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+    older := &models.Post{ID: "older", CreatedAt: start}
+    newer := &models.Post{ID: "newer", CreatedAt: start.Add(time.Minute)}
+
+    picks := make(map[string]int)
+    for i := 0; i < 1000; i++ {
+        picks[s.pickPopularPost([]*models.Post{older, newer}).ID]++
+    }
+    if picks["newer"] <= picks["older"] {
+        t.Errorf("with no votes the newer post was drawn %d times, the older %d", picks["newer"], picks["older"])
+    }
+
+    if got := s.pickPopularPost([]*models.Post{older}); got != older {
+        t.Errorf("only post not picked: %v", got)
+    }
+}
\ No newline at end of file
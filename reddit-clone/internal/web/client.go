@@ -6,6 +6,8 @@ import (
     "encoding/json"
     "fmt"
     "net/http"
+    "net/url"
+    "strconv"
     "time"
     
     "reddit-clone/api/v1"
@@ -40,17 +42,12 @@ func (c *Client) Register(username, password string) error {
 }
 
 func (c *Client) Login(username, password string) (string, error) {
-    req := struct {
-        Username string `json:"username"`
-        Password string `json:"password"`
-    }{
+    req := api.LoginRequest{
         Username: username,
         Password: password,
     }
     
-    var resp struct {
-        Token string `json:"token"`
-    }
+    var resp api.LoginResponse
     
     err := c.post("/api/v1/users/login", req, &resp)
     if err != nil {
@@ -76,6 +73,17 @@ func (c *Client) CreateSubreddit(name, description string) (*api.SubredditRespon
     return &resp, nil
 }
 
+// ListSubreddits returns one page of subreddits by name; a limit of 0
+// returns them all. PageInfo.HasNext tells whether to ask for page+1.
+func (c *Client) ListSubreddits(page, limit int) (*api.SubredditListResponse, error) {
+    var resp api.SubredditListResponse
+    err := c.get("/api/v1/subreddits?"+pageQuery(url.Values{}, page, limit), &resp)
+    if err != nil {
+        return nil, err
+    }
+    return &resp, nil
+}
+
 func (c *Client) JoinSubreddit(subredditID string) error {
     return c.post(fmt.Sprintf("/api/v1/subreddits/%s/join", subredditID), nil, nil)
 }
@@ -100,6 +108,17 @@ func (c *Client) CreatePost(title, content, subredditID string) (*api.PostRespon
     return &resp, nil
 }
 
+// ListPosts returns one page of a subreddit's posts, newest first
+func (c *Client) ListPosts(subredditID string, page, limit int) (*api.PostListResponse, error) {
+    query := url.Values{"subreddit_id": {subredditID}}
+    var resp api.PostListResponse
+    err := c.get("/api/v1/posts?"+pageQuery(query, page, limit), &resp)
+    if err != nil {
+        return nil, err
+    }
+    return &resp, nil
+}
+
 func (c *Client) GetPost(postID string) (*api.PostResponse, error) {
     var resp api.PostResponse
     err := c.get(fmt.Sprintf("/api/v1/posts/%s", postID), &resp)
@@ -137,12 +156,29 @@ func (c *Client) CreateComment(content, postID string, parentID *string) (*api.C
     return &resp, nil
 }
 
+// GetComments returns one page of a post's comment tree, paginated by
+// top-level comment
+func (c *Client) GetComments(postID string, page, limit int) (*api.CommentListResponse, error) {
+    var resp api.CommentListResponse
+    err := c.get(fmt.Sprintf("/api/v1/posts/%s/comments?%s", postID, pageQuery(url.Values{}, page, limit)), &resp)
+    if err != nil {
+        return nil, err
+    }
+    return &resp, nil
+}
+
 // Vote methods
-func (c *Client) Vote(targetID string, isUpvote bool) error {
+func (c *Client) Vote(targetID string, isUpvote bool) (*api.VoteResponse, error) {
     req := api.VoteRequest{
         IsUpvote: isUpvote,
     }
-    return c.post(fmt.Sprintf("/api/v1/posts/%s/vote", targetID), req, nil)
+
+    var resp api.VoteResponse
+    err := c.post(fmt.Sprintf("/api/v1/posts/%s/vote", targetID), req, &resp)
+    if err != nil {
+        return nil, err
+    }
+    return &resp, nil
 }
 
 // Message methods
@@ -164,18 +200,40 @@ func (c *Client) SendMessage(toID, content string) (*api.MessageResponse, error)
 }
 
 func (c *Client) GetMessages() ([]api.MessageResponse, error) {
-    var resp []api.MessageResponse
-    err := c.get("/api/v1/messages", &resp)
+    resp, err := c.GetMessagesPage(0, 0)
     if err != nil {
         return nil, err
     }
-    if resp == nil {
-        resp = make([]api.MessageResponse, 0)
+    if resp.Messages == nil {
+        resp.Messages = make([]api.MessageResponse, 0)
     }
-    return resp, nil
+    return resp.Messages, nil
+}
+
+// GetMessagesPage returns one page of the user's messages, newest first
+func (c *Client) GetMessagesPage(page, limit int) (*api.MessageListResponse, error) {
+    var resp api.MessageListResponse
+    err := c.get("/api/v1/messages?"+pageQuery(url.Values{}, page, limit), &resp)
+    if err != nil {
+        return nil, err
+    }
+    return &resp, nil
 }
 
 // Helper methods
+
+// pageQuery adds the page and limit parameters to query, leaving out
+// zeros so the server's defaults apply
+func pageQuery(query url.Values, page, limit int) string {
+    if page > 0 {
+        query.Set("page", strconv.Itoa(page))
+    }
+    if limit > 0 {
+        query.Set("limit", strconv.Itoa(limit))
+    }
+    return query.Encode()
+}
+
 func (c *Client) get(path string, response interface{}) error {
     return c.doRequest(http.MethodGet, path, nil, response)
 }
@@ -225,6 +283,9 @@ func (c *Client) doRequest(method, path string, body interface{}, response inter
         if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
             return fmt.Errorf("request failed with status %d", resp.StatusCode)
         }
+        if len(errResp.Details) > 0 {
+            return fmt.Errorf("request failed: %s", (&api.ValidationError{Fields: errResp.Details}).Error())
+        }
         return fmt.Errorf("request failed: %s", errResp.Error)
     }
 
@@ -0,0 +1,121 @@
+// internal/web/feed.go
+package web
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "strings"
+    "time"
+
+    "github.com/gorilla/websocket"
+
+    "reddit-clone/api/v1"
+)
+
+// Reconnect delays for SubscribeFeed: the first retry waits
+// feedInitialBackoff, and each attempt that ends before a post arrives
+// doubles the wait up to feedMaxBackoff. A socket the server closes for
+// exceeding the user's limit waits feedMaxBackoff straight away.
+const (
+    feedInitialBackoff = 500 * time.Millisecond
+    feedMaxBackoff     = 30 * time.Second
+)
+
+// feedBufferSize is how many posts SubscribeFeed holds for a slow reader
+// before it stops reading from the socket
+const feedBufferSize = 64
+
+// SubscribeFeed streams new posts from the user's subreddits over the
+// /api/v1/ws WebSocket. A dropped connection is redialed with backoff, so
+// posts made while it is down are missed. Past the server's socket limit
+// it keeps retrying at feedMaxBackoff. Cancelling ctx closes the
+// connection and the channel. The first dial must succeed; its error,
+// such as a rejected token, is returned.
+func (c *Client) SubscribeFeed(ctx context.Context) (<-chan api.PostResponse, error) {
+    conn, err := c.dialFeed(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    posts := make(chan api.PostResponse, feedBufferSize)
+    go func() {
+        defer close(posts)
+        backoff := feedInitialBackoff
+        for {
+            if conn != nil {
+                received, closeCode := c.readFeed(ctx, conn, posts)
+                conn.Close()
+                conn = nil
+                if received {
+                    backoff = feedInitialBackoff
+                }
+                if closeCode == websocket.ClosePolicyViolation {
+                    backoff = feedMaxBackoff
+                }
+            }
+            if ctx.Err() != nil {
+                return
+            }
+
+            select {
+            case <-ctx.Done():
+                return
+            case <-time.After(backoff):
+            }
+            backoff = min(2*backoff, feedMaxBackoff)
+            conn, err = c.dialFeed(ctx)
+        }
+    }()
+    return posts, nil
+}
+
+// readFeed copies posts from conn to posts until the connection fails or
+// ctx is cancelled. It reports whether any post arrived and the code of
+// the server's close frame, or 0 if the connection ended without one.
+func (c *Client) readFeed(ctx context.Context, conn *websocket.Conn, posts chan<- api.PostResponse) (received bool, closeCode int) {
+    // Closing the connection unblocks a pending read once ctx is done
+    stop := context.AfterFunc(ctx, func() { conn.Close() })
+    defer stop()
+
+    for {
+        var post api.PostResponse
+        if err := conn.ReadJSON(&post); err != nil {
+            var closeErr *websocket.CloseError
+            if errors.As(err, &closeErr) {
+                return received, closeErr.Code
+            }
+            return received, 0
+        }
+        received = true
+        select {
+        case posts <- post:
+        case <-ctx.Done():
+            return received, 0
+        }
+    }
+}
+
+// dialFeed opens the feed WebSocket with the client's bearer token
+func (c *Client) dialFeed(ctx context.Context) (*websocket.Conn, error) {
+    url := "ws" + strings.TrimPrefix(c.baseURL, "http") + "/api/v1/ws"
+    header := http.Header{}
+    if c.token != "" {
+        header.Set("Authorization", "Bearer "+c.token)
+    }
+
+    conn, resp, err := websocket.DefaultDialer.DialContext(ctx, url, header)
+    if err != nil {
+        if resp != nil {
+            var errResp api.ErrorResponse
+            if json.NewDecoder(resp.Body).Decode(&errResp) == nil && errResp.Error != "" {
+                return nil, fmt.Errorf("feed subscription failed: %s", errResp.Error)
+            }
+            return nil, fmt.Errorf("feed subscription failed with status %d", resp.StatusCode)
+        }
+        return nil, fmt.Errorf("failed to connect to feed: %w", err)
+    }
+    return conn, nil
+}
\ No newline at end of file
@@ -0,0 +1,158 @@
+// internal/web/feed_test.go
+package web
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/gorilla/websocket"
+
+    "reddit-clone/api/v1"
+)
+
+// overLimitServer closes every feed socket the way the REST server closes
+// one opened past the user's limit, and counts the dials
+func overLimitServer(t *testing.T, dials *atomic.Int32) *httptest.Server {
+    t.Helper()
+    upgrader := websocket.Upgrader{}
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        conn, err := upgrader.Upgrade(w, r, nil)
+        if err != nil {
+            return
+        }
+        defer conn.Close()
+        dials.Add(1)
+        message := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "too many open feed subscriptions")
+        conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+        // Wait for the client to hang up so the close frame isn't lost
+        conn.ReadMessage()
+    }))
+    t.Cleanup(srv.Close)
+    return srv
+}
+
+func TestReadFeedReportsCloseCode(t *testing.T) {
+    var dials atomic.Int32
+    c := NewClient(overLimitServer(t, &dials).URL)
+
+    conn, err := c.dialFeed(context.Background())
+    if err != nil {
+        t.Fatalf("dialFeed: %v", err)
+    }
+    defer conn.Close()
+    received, closeCode := c.readFeed(context.Background(), conn, make(chan api.PostResponse, 1))
+    if received {
+        t.Error("readFeed reported a post from a socket that sent none")
+    }
+    if closeCode != websocket.ClosePolicyViolation {
+        t.Errorf("closeCode = %d, want %d", closeCode, websocket.ClosePolicyViolation)
+    }
+}
+
+func TestSubscribeFeedBacksOffOverLimit(t *testing.T) {
+    var dials atomic.Int32
+    c := NewClient(overLimitServer(t, &dials).URL)
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    posts, err := c.SubscribeFeed(ctx)
+    if err != nil {
+        t.Fatalf("SubscribeFeed: %v", err)
+    }
+
+    // A client that reset its backoff would redial every
+    // feedInitialBackoff; one that honours the limit waits feedMaxBackoff
+    time.Sleep(3 * feedInitialBackoff)
+    if n := dials.Load(); n != 1 {
+        t.Errorf("dialed %d times while over the limit, want 1", n)
+    }
+
+    cancel()
+    for range posts {
+    }
+}
+
+// dropAfterOneServer sends each connection one post, numbered by dial,
+// then drops it, so the client has to reconnect for the next
+func dropAfterOneServer(t *testing.T, dials *atomic.Int32, auth *atomic.Value) *httptest.Server {
+    t.Helper()
+    upgrader := websocket.Upgrader{}
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        auth.Store(r.Header.Get("Authorization"))
+        conn, err := upgrader.Upgrade(w, r, nil)
+        if err != nil {
+            return
+        }
+        defer conn.Close()
+        n := dials.Add(1)
+        conn.WriteJSON(api.PostResponse{ID: fmt.Sprintf("post%d", n)})
+    }))
+    t.Cleanup(srv.Close)
+    return srv
+}
+
+func TestSubscribeFeedDeliversAndReconnects(t *testing.T) {
+    var dials atomic.Int32
+    var auth atomic.Value
+    c := NewClient(dropAfterOneServer(t, &dials, &auth).URL)
+    c.SetToken("secret-token")
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    posts, err := c.SubscribeFeed(ctx)
+    if err != nil {
+        t.Fatalf("SubscribeFeed: %v", err)
+    }
+
+    for _, want := range []string{"post1", "post2"} {
+        select {
+        case post, ok := <-posts:
+            if !ok {
+                t.Fatalf("feed closed before %s", want)
+            }
+            if post.ID != want {
+                t.Errorf("received %s, want %s", post.ID, want)
+            }
+        case <-time.After(5 * time.Second):
+            t.Fatalf("no %s within 5s", want)
+        }
+    }
+    if got := auth.Load(); got != "Bearer secret-token" {
+        t.Errorf("Authorization = %v, want the bearer token", got)
+    }
+
+    // Cancelling closes the channel
+    cancel()
+    deadline := time.After(5 * time.Second)
+    for {
+        select {
+        case _, ok := <-posts:
+            if !ok {
+                return
+            }
+        case <-deadline:
+            t.Fatal("feed not closed after cancel")
+        }
+    }
+}
+
+func TestSubscribeFeedReturnsFirstDialError(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(api.ErrorResponse{Error: "Authentication required"})
+    }))
+    t.Cleanup(srv.Close)
+
+    _, err := NewClient(srv.URL).SubscribeFeed(context.Background())
+    if err == nil || !strings.Contains(err.Error(), "Authentication required") {
+        t.Errorf("SubscribeFeed err = %v, want the server's error", err)
+    }
+}
\ No newline at end of file
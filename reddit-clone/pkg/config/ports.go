@@ -1,13 +1,21 @@
 // pkg/config/ports.go
 package config
 
-import "fmt"
+import (
+    "fmt"
+    "time"
+
+    "reddit-clone/api/v1"
+)
 
 const (
     // Default ports for different services
     DefaultEnginePort  = 50051  // Main gRPC server port
     DefaultMetricsPort = 50052  // Metrics server port
     DefaultClientPort  = 50053  // Client metrics port
+
+    // DefaultRequestTimeout bounds how long a REST handler may run
+    DefaultRequestTimeout = 30 * time.Second
 )
 
 // ServiceConfig holds configuration for all services
@@ -21,6 +29,13 @@ type ServiceConfig struct {
     // Additional configuration if needed
     MaxConnections    int
     ConnectionTimeout int
+    RequestTimeout    time.Duration // REST handler deadline; 0 disables it
+
+    // Longest text, in characters, the REST API accepts; 0 removes the limit
+    MaxTitleLength       int
+    MaxPostContentLength int
+    MaxCommentLength     int
+    MaxMessageLength     int
 }
 
 // NewDefaultConfig creates a ServiceConfig with default values
@@ -32,6 +47,12 @@ func NewDefaultConfig() *ServiceConfig {
         ClientPort:        DefaultClientPort,
         MaxConnections:    1000,
         ConnectionTimeout: 30,
+        RequestTimeout:    DefaultRequestTimeout,
+
+        MaxTitleLength:       api.MaxTitleLength,
+        MaxPostContentLength: api.MaxPostContentLength,
+        MaxCommentLength:     api.MaxCommentLength,
+        MaxMessageLength:     api.MaxMessageLength,
     }
 }
 
@@ -58,5 +79,21 @@ func (c *ServiceConfig) Validate() error {
     if c.MaxConnections < 1 {
         return fmt.Errorf("max connections must be positive")
     }
+    if c.RequestTimeout < 0 {
+        return fmt.Errorf("request timeout cannot be negative")
+    }
+    if c.MaxTitleLength < 0 || c.MaxPostContentLength < 0 || c.MaxCommentLength < 0 || c.MaxMessageLength < 0 {
+        return fmt.Errorf("length limits cannot be negative")
+    }
     return nil
+}
+
+// Limits returns the configured length limits for request validation
+func (c *ServiceConfig) Limits() api.Limits {
+    return api.Limits{
+        MaxTitleLength:       c.MaxTitleLength,
+        MaxPostContentLength: c.MaxPostContentLength,
+        MaxCommentLength:     c.MaxCommentLength,
+        MaxMessageLength:     c.MaxMessageLength,
+    }
 }
\ No newline at end of file
@@ -24,6 +24,14 @@ type Stats struct {
     AverageLatency time.Duration
     EndpointStats  map[string]*EndpointStats  // Stats per endpoint
     SubredditStats map[string]*SubredditStats // Stats per subreddit
+    ThroughputSeries []BucketStat             // Oldest bucket first
+}
+
+// BucketStat counts the calls and errors seen in one time bucket
+type BucketStat struct {
+    Start    time.Time
+    Requests int64
+    Errors   int64
 }
 
 // EndpointStats tracks metrics for each gRPC endpoint
@@ -45,6 +53,22 @@ type SubredditStats struct {
     VoteCount     int64
     ActiveUsers   int64
     PopularPosts  []string // IDs of most upvoted posts
+
+    // Calls the gRPC server handled for the subreddit, by operation, and
+    // how many of them failed. Update leaves these alone.
+    Operations      map[string]int64
+    OperationErrors int64
+}
+
+const (
+    DefaultBucketWidth = time.Minute
+    DefaultRetention   = time.Hour
+)
+
+// CollectorOptions sizes the throughput ring; zero fields take the defaults
+type CollectorOptions struct {
+    BucketWidth time.Duration
+    Retention   time.Duration
 }
 
 // Collector manages metrics collection
@@ -54,9 +78,25 @@ type Collector struct {
     latencies     []time.Duration
     lastUpdate    time.Time
     requestCounts map[string]int64 // requests per second tracking
+    bucketWidth   time.Duration
+    buckets       []BucketStat // ring indexed by bucket number modulo its length
+    now           func() time.Time
 }
 
 func NewCollector() *Collector {
+    return NewCollectorWithOptions(CollectorOptions{})
+}
+
+// NewCollectorWithOptions creates a collector that keeps Retention worth
+// of BucketWidth throughput buckets
+func NewCollectorWithOptions(opts CollectorOptions) *Collector {
+    if opts.BucketWidth <= 0 {
+        opts.BucketWidth = DefaultBucketWidth
+    }
+    if opts.Retention <= 0 {
+        opts.Retention = DefaultRetention
+    }
+    size := int((opts.Retention + opts.BucketWidth - 1) / opts.BucketWidth)
     return &Collector{
         stats: &Stats{
             StartTime:      time.Now(),
@@ -65,7 +105,38 @@ func NewCollector() *Collector {
         },
         latencies:     make([]time.Duration, 0),
         requestCounts: make(map[string]int64),
+        bucketWidth:   opts.BucketWidth,
+        buckets:       make([]BucketStat, size),
+        now:           time.Now,
+    }
+}
+
+// currentBucket returns the ring slot for the present bucket, clearing it
+// if it still holds a bucket from a previous lap
+func (c *Collector) currentBucket() *BucketStat {
+    start := c.now().Truncate(c.bucketWidth)
+    slot := &c.buckets[int(start.UnixNano()/int64(c.bucketWidth))%len(c.buckets)]
+    if !slot.Start.Equal(start) {
+        *slot = BucketStat{Start: start}
+    }
+    return slot
+}
+
+// throughputSeries lists every bucket in the retention window, oldest first,
+// with empty buckets for periods that saw no calls
+func (c *Collector) throughputSeries() []BucketStat {
+    newest := c.now().Truncate(c.bucketWidth)
+    series := make([]BucketStat, len(c.buckets))
+    for i := range series {
+        start := newest.Add(-time.Duration(len(series)-1-i) * c.bucketWidth)
+        slot := c.buckets[int(start.UnixNano()/int64(c.bucketWidth))%len(c.buckets)]
+        if slot.Start.Equal(start) {
+            series[i] = slot
+        } else {
+            series[i] = BucketStat{Start: start}
+        }
     }
+    return series
 }
 
 // RecordLatency records the latency for a specific endpoint
@@ -83,6 +154,7 @@ func (c *Collector) RecordLatency(endpoint string, duration time.Duration) {
     stats.TotalLatency += duration
     stats.AverageLatency = stats.TotalLatency / time.Duration(stats.CallCount)
     stats.LastCall = time.Now()
+    c.currentBucket().Requests++
 
     c.latencies = append(c.latencies, duration)
     c.updateAverageLatency()
@@ -101,6 +173,28 @@ func (c *Collector) RecordError(endpoint string) {
 
     stats.ErrorCount++
     c.stats.ErrorCount++
+    c.currentBucket().Errors++
+}
+
+// RecordSubredditOp counts a server call that acted on a subreddit, such
+// as a post created in it or a join, so load can be traced to the
+// subreddits causing it
+func (c *Collector) RecordSubredditOp(subredditID, operation string, failed bool) {
+    c.mtx.Lock()
+    defer c.mtx.Unlock()
+
+    stats, exists := c.stats.SubredditStats[subredditID]
+    if !exists {
+        stats = &SubredditStats{}
+        c.stats.SubredditStats[subredditID] = stats
+    }
+    if stats.Operations == nil {
+        stats.Operations = make(map[string]int64)
+    }
+    stats.Operations[operation]++
+    if failed {
+        stats.OperationErrors++
+    }
 }
 
 // Update updates the overall metrics
@@ -123,6 +217,8 @@ func (c *Collector) Update(metrics *models.Metrics) {
         }
         
         subredditStats := c.stats.SubredditStats[id]
+        // RecordSubredditOp may have created the entry without a name
+        subredditStats.Name = stats.Name
         subredditStats.MemberCount = stats.MemberCount
         subredditStats.PostCount = stats.PostCount
         subredditStats.CommentCount = stats.CommentCount
@@ -177,6 +273,7 @@ func (c *Collector) GetStats() *Stats {
         AverageLatency: c.stats.AverageLatency,
         EndpointStats:  make(map[string]*EndpointStats),
         SubredditStats: make(map[string]*SubredditStats),
+        ThroughputSeries: c.throughputSeries(),
     }
 
     // Copy endpoint stats
@@ -201,6 +298,12 @@ func (c *Collector) GetStats() *Stats {
             VoteCount:    v.VoteCount,
             ActiveUsers:  v.ActiveUsers,
             PopularPosts: append([]string{}, v.PopularPosts...),
+
+            Operations:      make(map[string]int64, len(v.Operations)),
+            OperationErrors: v.OperationErrors,
+        }
+        for op, n := range v.Operations {
+            statsCopy.SubredditStats[k].Operations[op] = n
         }
     }
 
@@ -256,6 +359,15 @@ func (s *MetricsServer) writeHTMLMetrics(w http.ResponseWriter, stats *Stats) {
     fmt.Fprintf(w, "<li>Total Votes: %d</li>", stats.TotalVotes)
     fmt.Fprintf(w, "</ul>")
 
+    fmt.Fprintf(w, "<h2>Throughput</h2>")
+    fmt.Fprintf(w, "<table border='1'>")
+    fmt.Fprintf(w, "<tr><th>Bucket</th><th>Requests</th><th>Errors</th></tr>")
+    for _, bucket := range stats.ThroughputSeries {
+        fmt.Fprintf(w, "<tr><td>%s</td><td>%d</td><td>%d</td></tr>",
+            bucket.Start.Format(time.RFC3339), bucket.Requests, bucket.Errors)
+    }
+    fmt.Fprintf(w, "</table>")
+
     fmt.Fprintf(w, "<h2>Endpoint Statistics</h2>")
     fmt.Fprintf(w, "<table border='1'>")
     fmt.Fprintf(w, "<tr><th>Endpoint</th><th>Calls</th><th>Errors</th><th>Avg Latency</th><th>Last Call</th></tr>")
@@ -267,10 +379,14 @@ func (s *MetricsServer) writeHTMLMetrics(w http.ResponseWriter, stats *Stats) {
 
     fmt.Fprintf(w, "<h2>Subreddit Statistics</h2>")
     fmt.Fprintf(w, "<table border='1'>")
-    fmt.Fprintf(w, "<tr><th>Name</th><th>Members</th><th>Posts</th><th>Comments</th><th>Votes</th><th>Active Users</th></tr>")
+    fmt.Fprintf(w, "<tr><th>Name</th><th>Members</th><th>Posts</th><th>Comments</th><th>Votes</th><th>Active Users</th><th>Calls</th><th>Call Errors</th></tr>")
     for _, stat := range stats.SubredditStats {
-        fmt.Fprintf(w, "<tr><td>%s</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td></tr>",
-            stat.Name, stat.MemberCount, stat.PostCount, stat.CommentCount, stat.VoteCount, stat.ActiveUsers)
+        var calls int64
+        for _, n := range stat.Operations {
+            calls += n
+        }
+        fmt.Fprintf(w, "<tr><td>%s</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td></tr>",
+            stat.Name, stat.MemberCount, stat.PostCount, stat.CommentCount, stat.VoteCount, stat.ActiveUsers, calls, stat.OperationErrors)
     }
     fmt.Fprintf(w, "</table>")
     fmt.Fprintf(w, "</body></html>")
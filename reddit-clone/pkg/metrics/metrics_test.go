@@ -0,0 +1,72 @@
+// pkg/metrics/metrics_test.go
+package metrics
+
+import (
+    "fmt"
+    "testing"
+    "time"
+)
+
+// counts renders a series as requests/errors per bucket, oldest first
+func counts(series []BucketStat) string {
+    out := make([]string, len(series))
+    for i, b := range series {
+        out[i] = fmt.Sprintf("%d/%d", b.Requests, b.Errors)
+    }
+    return fmt.Sprint(out)
+}
+
+func TestThroughputSeries(t *testing.T) {
+    start := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+    now := start
+    c := NewCollectorWithOptions(CollectorOptions{BucketWidth: time.Minute, Retention: 5 * time.Minute})
+    c.now = func() time.Time { return now }
+    at := func(minutes float64) {
+        now = start.Add(time.Duration(minutes * float64(time.Minute)))
+    }
+
+    at(0)
+    c.RecordLatency("CreatePost", time.Millisecond)
+    at(0.5)
+    c.RecordLatency("CreatePost", time.Millisecond)
+    c.RecordError("CreatePost")
+    at(2)
+    c.RecordLatency("GetFeed", time.Millisecond)
+
+    series := c.GetStats().ThroughputSeries
+    if got, want := counts(series), "[0/0 0/0 2/1 0/0 1/0]"; got != want {
+        t.Errorf("series = %s, want %s", got, want)
+    }
+    for i, b := range series {
+        if want := start.Add(time.Duration(i-2) * time.Minute); !b.Start.Equal(want) {
+            t.Errorf("bucket %d starts %v, want %v", i, b.Start, want)
+        }
+    }
+
+    // Buckets older than the retention window are dropped, and minute 7,
+    // which reuses minute 2's ring slot, starts from zero
+    at(6.9)
+    c.RecordLatency("GetFeed", time.Millisecond)
+    at(7)
+    c.RecordLatency("GetFeed", time.Millisecond)
+    if got, want := counts(c.GetStats().ThroughputSeries), "[0/0 0/0 0/0 1/0 1/0]"; got != want {
+        t.Errorf("series a lap later = %s, want %s", got, want)
+    }
+
+    // A quiet stretch longer than the window leaves only empty buckets
+    at(30)
+    series = c.GetStats().ThroughputSeries
+    if got, want := counts(series), "[0/0 0/0 0/0 0/0 0/0]"; got != want {
+        t.Errorf("series after a quiet stretch = %s, want %s", got, want)
+    }
+    if len(series) != 5 || !series[4].Start.Equal(start.Add(30*time.Minute)) {
+        t.Errorf("newest bucket starts %v, want %v", series[len(series)-1].Start, start.Add(30*time.Minute))
+    }
+}
+
+func TestCollectorDefaults(t *testing.T) {
+    c := NewCollector()
+    if got := len(c.GetStats().ThroughputSeries); got != int(DefaultRetention/DefaultBucketWidth) {
+        t.Errorf("default series has %d buckets, want %d", got, DefaultRetention/DefaultBucketWidth)
+    }
+}
\ No newline at end of file
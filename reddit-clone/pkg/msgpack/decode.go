@@ -0,0 +1,564 @@
+// pkg/msgpack/decode.go
+package msgpack
+
+import (
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "math"
+    "reflect"
+    "strings"
+    "time"
+)
+
+// ErrTruncated is returned when the data ends in the middle of a value
+var ErrTruncated = errors.New("msgpack: unexpected end of data")
+
+// ErrTooDeep is returned when arrays and maps nest more than maxDepth deep
+var ErrTooDeep = errors.New("msgpack: exceeded max depth")
+
+// maxDepth bounds how deeply arrays and maps may nest, as encoding/json
+// does, so a run of one-byte array headers can't exhaust the stack
+const maxDepth = 10000
+
+// Unmarshal decodes the single MessagePack value in data into v, which
+// must be a non-nil pointer. Map keys are matched to struct fields the way
+// encoding/json matches them, preferring an exact name and falling back to
+// a case-insensitive one; unknown keys are skipped. Decoding into an
+// interface{} yields map[string]interface{}, []interface{}, int64, uint64,
+// float64, string, []byte, bool, time.Time or nil.
+func Unmarshal(data []byte, v interface{}) error {
+    rv := reflect.ValueOf(v)
+    if rv.Kind() != reflect.Pointer || rv.IsNil() {
+        return fmt.Errorf("msgpack: Unmarshal needs a non-nil pointer, got %T", v)
+    }
+    d := &decoder{data: data}
+    if err := d.decode(rv.Elem()); err != nil {
+        return err
+    }
+    if d.off != len(d.data) {
+        return fmt.Errorf("msgpack: %d bytes of trailing data", len(d.data)-d.off)
+    }
+    return nil
+}
+
+type decoder struct {
+    data  []byte
+    off   int
+    depth int // Arrays and maps currently open
+}
+
+// enter opens an array or map, failing once they nest too deeply; the
+// caller must leave it again
+func (d *decoder) enter() error {
+    d.depth++
+    if d.depth > maxDepth {
+        return ErrTooDeep
+    }
+    return nil
+}
+
+func (d *decoder) leave() {
+    d.depth--
+}
+
+// kind groups the MessagePack formats into the shapes a value can take
+type kind int
+
+const (
+    kindNil kind = iota
+    kindBool
+    kindInt
+    kindUint
+    kindFloat
+    kindString
+    kindBinary
+    kindArray
+    kindMap
+    kindExt
+)
+
+// token is a decoded value header. Scalars carry their value; strings,
+// binary data, arrays, maps and extensions carry their length, and
+// extensions their type.
+type token struct {
+    kind    kind
+    b       bool
+    i       int64
+    u       uint64
+    f       float64
+    n       int
+    extType int8
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+    if n < 0 || len(d.data)-d.off < n {
+        return nil, ErrTruncated
+    }
+    b := d.data[d.off : d.off+n]
+    d.off += n
+    return b, nil
+}
+
+func (d *decoder) uint(size int) (uint64, error) {
+    b, err := d.next(size)
+    if err != nil {
+        return 0, err
+    }
+    switch size {
+    case 1:
+        return uint64(b[0]), nil
+    case 2:
+        return uint64(binary.BigEndian.Uint16(b)), nil
+    case 4:
+        return uint64(binary.BigEndian.Uint32(b)), nil
+    }
+    return binary.BigEndian.Uint64(b), nil
+}
+
+// length reads a size-byte length and checks it against what's left, so a
+// corrupt header can't make the decoder allocate more than the input holds
+func (d *decoder) length(size int) (int, error) {
+    n, err := d.uint(size)
+    if err != nil {
+        return 0, err
+    }
+    if n > uint64(len(d.data)-d.off) {
+        return 0, ErrTruncated
+    }
+    return int(n), nil
+}
+
+func (d *decoder) readToken() (token, error) {
+    b, err := d.next(1)
+    if err != nil {
+        return token{}, err
+    }
+    c := b[0]
+    switch {
+    case c <= 0x7f:
+        return token{kind: kindUint, u: uint64(c)}, nil
+    case c >= 0xe0:
+        return token{kind: kindInt, i: int64(int8(c))}, nil
+    case c&0xe0 == 0xa0:
+        return d.sized(kindString, int(c&0x1f))
+    case c&0xf0 == 0x90:
+        return d.sized(kindArray, int(c&0x0f))
+    case c&0xf0 == 0x80:
+        return d.sized(kindMap, int(c&0x0f))
+    }
+
+    var t token
+    switch c {
+    case 0xc0:
+        return token{kind: kindNil}, nil
+    case 0xc2, 0xc3:
+        return token{kind: kindBool, b: c == 0xc3}, nil
+    case 0xcc, 0xcd, 0xce, 0xcf:
+        t.kind = kindUint
+        t.u, err = d.uint(1 << (c - 0xcc))
+    case 0xd0, 0xd1, 0xd2, 0xd3:
+        var u uint64
+        u, err = d.uint(1 << (c - 0xd0))
+        t.kind = kindInt
+        switch c {
+        case 0xd0:
+            t.i = int64(int8(u))
+        case 0xd1:
+            t.i = int64(int16(u))
+        case 0xd2:
+            t.i = int64(int32(u))
+        default:
+            t.i = int64(u)
+        }
+    case 0xca:
+        var u uint64
+        u, err = d.uint(4)
+        t.kind, t.f = kindFloat, float64(math.Float32frombits(uint32(u)))
+    case 0xcb:
+        var u uint64
+        u, err = d.uint(8)
+        t.kind, t.f = kindFloat, math.Float64frombits(u)
+    case 0xd9, 0xda, 0xdb:
+        t.kind = kindString
+        t.n, err = d.length(1 << (c - 0xd9))
+    case 0xc4, 0xc5, 0xc6:
+        t.kind = kindBinary
+        t.n, err = d.length(1 << (c - 0xc4))
+    case 0xdc, 0xdd:
+        t.kind = kindArray
+        t.n, err = d.length(2 << (c - 0xdc))
+    case 0xde, 0xdf:
+        t.kind = kindMap
+        t.n, err = d.length(2 << (c - 0xde))
+    case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+        t.kind, t.n = kindExt, 1<<(c-0xd4)
+        t.extType, err = d.extType()
+    case 0xc7, 0xc8, 0xc9:
+        t.kind = kindExt
+        if t.n, err = d.length(1 << (c - 0xc7)); err == nil {
+            t.extType, err = d.extType()
+        }
+    default:
+        return token{}, fmt.Errorf("msgpack: invalid format byte 0x%02x", c)
+    }
+    return t, err
+}
+
+// sized returns a string, array or map token whose length came from its
+// fixed-size marker, checked against the remaining input like any other
+func (d *decoder) sized(k kind, n int) (token, error) {
+    if n > len(d.data)-d.off {
+        return token{}, ErrTruncated
+    }
+    return token{kind: k, n: n}, nil
+}
+
+func (d *decoder) extType() (int8, error) {
+    b, err := d.next(1)
+    if err != nil {
+        return 0, err
+    }
+    return int8(b[0]), nil
+}
+
+func (d *decoder) decode(v reflect.Value) error {
+    t, err := d.readToken()
+    if err != nil {
+        return err
+    }
+    return d.decodeToken(t, v)
+}
+
+func (d *decoder) decodeToken(t token, v reflect.Value) error {
+    if t.kind == kindNil {
+        switch v.Kind() {
+        case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+            v.SetZero()
+        }
+        return nil
+    }
+    if v.Kind() == reflect.Pointer {
+        if v.IsNil() {
+            v.Set(reflect.New(v.Type().Elem()))
+        }
+        return d.decodeToken(t, v.Elem())
+    }
+    if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+        value, err := d.decodeAny(t)
+        if err != nil {
+            return err
+        }
+        if value == nil {
+            v.SetZero()
+        } else {
+            v.Set(reflect.ValueOf(value))
+        }
+        return nil
+    }
+    if v.Type() == timeType {
+        tm, err := d.decodeTime(t)
+        if err != nil {
+            return err
+        }
+        v.Set(reflect.ValueOf(tm))
+        return nil
+    }
+
+    switch t.kind {
+    case kindBool:
+        if v.Kind() != reflect.Bool {
+            return mismatch("bool", v)
+        }
+        v.SetBool(t.b)
+    case kindInt, kindUint, kindFloat:
+        return setNumber(t, v)
+    case kindString:
+        b, err := d.next(t.n)
+        if err != nil {
+            return err
+        }
+        if v.Kind() != reflect.String {
+            return mismatch("string", v)
+        }
+        v.SetString(string(b))
+    case kindBinary:
+        b, err := d.next(t.n)
+        if err != nil {
+            return err
+        }
+        if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Uint8 {
+            return mismatch("binary", v)
+        }
+        v.SetBytes(append([]byte(nil), b...))
+    case kindArray:
+        return d.decodeArray(t.n, v)
+    case kindMap:
+        return d.decodeMap(t.n, v)
+    case kindExt:
+        return mismatch("extension", v)
+    }
+    return nil
+}
+
+func mismatch(what string, v reflect.Value) error {
+    return fmt.Errorf("msgpack: cannot decode %s into %s", what, v.Type())
+}
+
+// setNumber stores an integer or float token in a numeric field, refusing
+// values that don't fit
+func setNumber(t token, v reflect.Value) error {
+    switch v.Kind() {
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        var n int64
+        switch t.kind {
+        case kindInt:
+            n = t.i
+        case kindUint:
+            if t.u > math.MaxInt64 {
+                return overflow(t, v)
+            }
+            n = int64(t.u)
+        default:
+            return mismatch("float", v)
+        }
+        if v.OverflowInt(n) {
+            return overflow(t, v)
+        }
+        v.SetInt(n)
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+        var n uint64
+        switch t.kind {
+        case kindUint:
+            n = t.u
+        case kindInt:
+            if t.i < 0 {
+                return overflow(t, v)
+            }
+            n = uint64(t.i)
+        default:
+            return mismatch("float", v)
+        }
+        if v.OverflowUint(n) {
+            return overflow(t, v)
+        }
+        v.SetUint(n)
+    case reflect.Float32, reflect.Float64:
+        switch t.kind {
+        case kindInt:
+            v.SetFloat(float64(t.i))
+        case kindUint:
+            v.SetFloat(float64(t.u))
+        default:
+            v.SetFloat(t.f)
+        }
+    default:
+        return mismatch("number", v)
+    }
+    return nil
+}
+
+func overflow(t token, v reflect.Value) error {
+    if t.kind == kindInt {
+        return fmt.Errorf("msgpack: %d overflows %s", t.i, v.Type())
+    }
+    return fmt.Errorf("msgpack: %d overflows %s", t.u, v.Type())
+}
+
+func (d *decoder) decodeArray(n int, v reflect.Value) error {
+    if err := d.enter(); err != nil {
+        return err
+    }
+    defer d.leave()
+
+    switch v.Kind() {
+    case reflect.Slice:
+        v.Set(reflect.MakeSlice(v.Type(), n, n))
+    case reflect.Array:
+        if n > v.Len() {
+            return fmt.Errorf("msgpack: %d items overflow %s", n, v.Type())
+        }
+    default:
+        return mismatch("array", v)
+    }
+    for i := 0; i < n; i++ {
+        if err := d.decode(v.Index(i)); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (d *decoder) decodeMap(n int, v reflect.Value) error {
+    if err := d.enter(); err != nil {
+        return err
+    }
+    defer d.leave()
+
+    switch {
+    case v.Kind() == reflect.Struct:
+        return d.decodeStruct(n, v)
+    case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+    default:
+        return mismatch("map", v)
+    }
+
+    if v.IsNil() {
+        v.Set(reflect.MakeMapWithSize(v.Type(), n))
+    }
+    for i := 0; i < n; i++ {
+        key, err := d.decodeKey()
+        if err != nil {
+            return err
+        }
+        elem := reflect.New(v.Type().Elem()).Elem()
+        if err := d.decode(elem); err != nil {
+            return err
+        }
+        v.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+    }
+    return nil
+}
+
+func (d *decoder) decodeStruct(n int, v reflect.Value) error {
+    fields := cachedFields(v.Type())
+    for i := 0; i < n; i++ {
+        key, err := d.decodeKey()
+        if err != nil {
+            return err
+        }
+        f := matchField(fields, key)
+        if f == nil {
+            if err := d.skip(); err != nil {
+                return err
+            }
+            continue
+        }
+        if err := d.decode(allocFieldByIndex(v, f.index)); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// matchField finds the field for a map key, exact names first
+func matchField(fields []field, key string) *field {
+    for i := range fields {
+        if fields[i].name == key {
+            return &fields[i]
+        }
+    }
+    for i := range fields {
+        if strings.EqualFold(fields[i].name, key) {
+            return &fields[i]
+        }
+    }
+    return nil
+}
+
+// allocFieldByIndex follows index through v, allocating nil embedded
+// pointers on the way
+func allocFieldByIndex(v reflect.Value, index []int) reflect.Value {
+    for i, x := range index {
+        if i > 0 && v.Kind() == reflect.Pointer {
+            if v.IsNil() {
+                v.Set(reflect.New(v.Type().Elem()))
+            }
+            v = v.Elem()
+        }
+        v = v.Field(x)
+    }
+    return v
+}
+
+func (d *decoder) decodeKey() (string, error) {
+    t, err := d.readToken()
+    if err != nil {
+        return "", err
+    }
+    if t.kind != kindString {
+        return "", errors.New("msgpack: map keys must be strings")
+    }
+    b, err := d.next(t.n)
+    return string(b), err
+}
+
+func (d *decoder) decodeTime(t token) (time.Time, error) {
+    if t.kind != kindExt || t.extType != timestampExt {
+        return time.Time{}, errors.New("msgpack: expected a timestamp")
+    }
+    b, err := d.next(t.n)
+    if err != nil {
+        return time.Time{}, err
+    }
+    switch t.n {
+    case 4:
+        return time.Unix(int64(binary.BigEndian.Uint32(b)), 0).UTC(), nil
+    case 8:
+        u := binary.BigEndian.Uint64(b)
+        return time.Unix(int64(u&(1<<34-1)), int64(u>>34)).UTC(), nil
+    case 12:
+        nsec := binary.BigEndian.Uint32(b)
+        return time.Unix(int64(binary.BigEndian.Uint64(b[4:])), int64(nsec)).UTC(), nil
+    }
+    return time.Time{}, fmt.Errorf("msgpack: invalid timestamp length %d", t.n)
+}
+
+// decodeAny decodes a value into its natural Go type, for interface{}
+// destinations
+func (d *decoder) decodeAny(t token) (interface{}, error) {
+    switch t.kind {
+    case kindNil:
+        return nil, nil
+    case kindBool:
+        return t.b, nil
+    case kindInt:
+        return t.i, nil
+    case kindUint:
+        return t.u, nil
+    case kindFloat:
+        return t.f, nil
+    case kindString:
+        b, err := d.next(t.n)
+        return string(b), err
+    case kindBinary:
+        b, err := d.next(t.n)
+        return append([]byte(nil), b...), err
+    case kindArray:
+        if err := d.enter(); err != nil {
+            return nil, err
+        }
+        defer d.leave()
+        items := make([]interface{}, t.n)
+        for i := range items {
+            if err := d.decode(reflect.ValueOf(&items[i]).Elem()); err != nil {
+                return nil, err
+            }
+        }
+        return items, nil
+    case kindMap:
+        m := make(map[string]interface{}, t.n)
+        if err := d.decodeMap(t.n, reflect.ValueOf(m)); err != nil {
+            return nil, err
+        }
+        return m, nil
+    }
+    if t.extType == timestampExt {
+        return d.decodeTime(t)
+    }
+    return nil, fmt.Errorf("msgpack: unsupported extension type %d", t.extType)
+}
+
+// skip discards the next value, for keys no struct field claims
+func (d *decoder) skip() error {
+    var discard interface{}
+    t, err := d.readToken()
+    if err != nil {
+        return err
+    }
+    if t.kind == kindExt && t.extType != timestampExt {
+        _, err := d.next(t.n)
+        return err
+    }
+    return d.decodeToken(t, reflect.ValueOf(&discard).Elem())
+}
\ No newline at end of file
@@ -0,0 +1,350 @@
+// pkg/msgpack/encode.go
+
+// Package msgpack encodes and decodes MessagePack, a binary counterpart to
+// JSON. It covers what the API types need: structs, which are encoded as
+// maps keyed by their json tags, slices, string-keyed maps, pointers and
+// the scalar types. time.Time uses the MessagePack timestamp extension.
+package msgpack
+
+import (
+    "encoding/binary"
+    "fmt"
+    "math"
+    "reflect"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+)
+
+// ContentType is the media type clients use to ask for MessagePack
+const ContentType = "application/msgpack"
+
+// timestampExt is the extension type MessagePack reserves for timestamps
+const timestampExt = -1
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Marshal returns the MessagePack encoding of v. Struct fields follow the
+// encoding/json rules for names, "-" and omitempty, so a value encodes to
+// the same keys it would in JSON.
+func Marshal(v interface{}) ([]byte, error) {
+    var e encoder
+    if err := e.encode(reflect.ValueOf(v)); err != nil {
+        return nil, err
+    }
+    return e.buf, nil
+}
+
+type encoder struct {
+    buf []byte
+}
+
+func (e *encoder) encode(v reflect.Value) error {
+    if !v.IsValid() {
+        e.buf = append(e.buf, 0xc0)
+        return nil
+    }
+    if v.Type() == timeType {
+        e.encodeTime(v.Interface().(time.Time))
+        return nil
+    }
+
+    switch v.Kind() {
+    case reflect.Bool:
+        if v.Bool() {
+            e.buf = append(e.buf, 0xc3)
+        } else {
+            e.buf = append(e.buf, 0xc2)
+        }
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        e.encodeInt(v.Int())
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+        e.encodeUint(v.Uint())
+    case reflect.Float32:
+        e.buf = append(e.buf, 0xca)
+        e.buf = binary.BigEndian.AppendUint32(e.buf, math.Float32bits(float32(v.Float())))
+    case reflect.Float64:
+        e.buf = append(e.buf, 0xcb)
+        e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(v.Float()))
+    case reflect.String:
+        e.encodeString(v.String())
+    case reflect.Pointer, reflect.Interface:
+        if v.IsNil() {
+            e.buf = append(e.buf, 0xc0)
+            return nil
+        }
+        return e.encode(v.Elem())
+    case reflect.Slice:
+        if v.IsNil() {
+            e.buf = append(e.buf, 0xc0)
+            return nil
+        }
+        if v.Type().Elem().Kind() == reflect.Uint8 {
+            e.encodeBytes(v.Bytes())
+            return nil
+        }
+        return e.encodeArray(v)
+    case reflect.Array:
+        return e.encodeArray(v)
+    case reflect.Map:
+        return e.encodeMap(v)
+    case reflect.Struct:
+        return e.encodeStruct(v)
+    default:
+        return fmt.Errorf("msgpack: unsupported type %s", v.Type())
+    }
+    return nil
+}
+
+// encodeInt writes n in the smallest signed or unsigned form that holds it
+func (e *encoder) encodeInt(n int64) {
+    switch {
+    case n >= 0:
+        e.encodeUint(uint64(n))
+    case n >= -32:
+        e.buf = append(e.buf, byte(n))
+    case n >= math.MinInt8:
+        e.buf = append(e.buf, 0xd0, byte(n))
+    case n >= math.MinInt16:
+        e.buf = append(e.buf, 0xd1)
+        e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+    case n >= math.MinInt32:
+        e.buf = append(e.buf, 0xd2)
+        e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+    default:
+        e.buf = append(e.buf, 0xd3)
+        e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(n))
+    }
+}
+
+func (e *encoder) encodeUint(n uint64) {
+    switch {
+    case n <= 0x7f:
+        e.buf = append(e.buf, byte(n))
+    case n <= math.MaxUint8:
+        e.buf = append(e.buf, 0xcc, byte(n))
+    case n <= math.MaxUint16:
+        e.buf = append(e.buf, 0xcd)
+        e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+    case n <= math.MaxUint32:
+        e.buf = append(e.buf, 0xce)
+        e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+    default:
+        e.buf = append(e.buf, 0xcf)
+        e.buf = binary.BigEndian.AppendUint64(e.buf, n)
+    }
+}
+
+func (e *encoder) encodeString(s string) {
+    e.encodeLength(len(s), 0xa0, 31, 0xd9, 0xda, 0xdb)
+    e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) encodeBytes(b []byte) {
+    e.encodeLength(len(b), 0, 0, 0xc4, 0xc5, 0xc6)
+    e.buf = append(e.buf, b...)
+}
+
+// encodeLength writes the header of a string, binary, array or map of n
+// items. fix is the fixed-size marker, used when n <= fixMax, and 0 when
+// the family has none; len8 is 0 when it has no 8-bit form.
+func (e *encoder) encodeLength(n int, fix byte, fixMax int, len8, len16, len32 byte) {
+    switch {
+    case fix != 0 && n <= fixMax:
+        e.buf = append(e.buf, fix|byte(n))
+    case len8 != 0 && n <= math.MaxUint8:
+        e.buf = append(e.buf, len8, byte(n))
+    case n <= math.MaxUint16:
+        e.buf = append(e.buf, len16)
+        e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+    default:
+        e.buf = append(e.buf, len32)
+        e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+    }
+}
+
+func (e *encoder) encodeArray(v reflect.Value) error {
+    e.encodeLength(v.Len(), 0x90, 15, 0, 0xdc, 0xdd)
+    for i := 0; i < v.Len(); i++ {
+        if err := e.encode(v.Index(i)); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// encodeMap writes a string-keyed map with its keys sorted, so equal maps
+// encode to equal bytes
+func (e *encoder) encodeMap(v reflect.Value) error {
+    if v.IsNil() {
+        e.buf = append(e.buf, 0xc0)
+        return nil
+    }
+    if v.Type().Key().Kind() != reflect.String {
+        return fmt.Errorf("msgpack: unsupported map key type %s", v.Type().Key())
+    }
+    keys := v.MapKeys()
+    sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+    e.encodeLength(len(keys), 0x80, 15, 0, 0xde, 0xdf)
+    for _, key := range keys {
+        e.encodeString(key.String())
+        if err := e.encode(v.MapIndex(key)); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (e *encoder) encodeStruct(v reflect.Value) error {
+    fields := cachedFields(v.Type())
+    present := make([]reflect.Value, 0, len(fields))
+    for _, f := range fields {
+        fv, ok := fieldByIndex(v, f.index)
+        if !ok || (f.omitEmpty && isEmptyValue(fv)) {
+            present = append(present, reflect.Value{})
+            continue
+        }
+        present = append(present, fv)
+    }
+
+    n := 0
+    for _, fv := range present {
+        if fv.IsValid() {
+            n++
+        }
+    }
+    e.encodeLength(n, 0x80, 15, 0, 0xde, 0xdf)
+    for i, fv := range present {
+        if !fv.IsValid() {
+            continue
+        }
+        e.encodeString(fields[i].name)
+        if err := e.encode(fv); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// encodeTime writes t as a timestamp extension, in the 32-bit form when it
+// falls on a whole second that fits, the 64-bit form up to 2514, and the
+// 96-bit form otherwise
+func (e *encoder) encodeTime(t time.Time) {
+    sec, nsec := t.Unix(), uint32(t.Nanosecond())
+    switch {
+    case nsec == 0 && sec >= 0 && sec <= math.MaxUint32:
+        e.buf = append(e.buf, 0xd6, byte(timestampExt&0xff))
+        e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(sec))
+    case sec >= 0 && sec>>34 == 0:
+        e.buf = append(e.buf, 0xd7, byte(timestampExt&0xff))
+        e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(nsec)<<34|uint64(sec))
+    default:
+        e.buf = append(e.buf, 0xc7, 12, byte(timestampExt&0xff))
+        e.buf = binary.BigEndian.AppendUint32(e.buf, nsec)
+        e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(sec))
+    }
+}
+
+// field is an encoded struct field, named as encoding/json would name it
+type field struct {
+    name      string
+    index     []int
+    omitEmpty bool
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+func cachedFields(t reflect.Type) []field {
+    if fields, ok := fieldCache.Load(t); ok {
+        return fields.([]field)
+    }
+    fields, _ := fieldCache.LoadOrStore(t, typeFields(t, nil))
+    return fields.([]field)
+}
+
+// typeFields lists t's exported fields in declaration order. An embedded
+// struct without a tag contributes its own fields, as in encoding/json;
+// a name already taken at a shallower depth wins.
+func typeFields(t reflect.Type, index []int) []field {
+    var fields []field
+    var embedded [][]field
+    for i := 0; i < t.NumField(); i++ {
+        sf := t.Field(i)
+        tag := sf.Tag.Get("json")
+        if tag == "-" {
+            continue
+        }
+        name, opts, _ := strings.Cut(tag, ",")
+        fieldIndex := append(append([]int(nil), index...), i)
+
+        ft := sf.Type
+        if ft.Kind() == reflect.Pointer {
+            ft = ft.Elem()
+        }
+        if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+            embedded = append(embedded, typeFields(ft, fieldIndex))
+            continue
+        }
+        if !sf.IsExported() {
+            continue
+        }
+        if name == "" {
+            name = sf.Name
+        }
+        fields = append(fields, field{
+            name:      name,
+            index:     fieldIndex,
+            omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+        })
+    }
+
+    taken := make(map[string]bool, len(fields))
+    for _, f := range fields {
+        taken[f.name] = true
+    }
+    for _, inner := range embedded {
+        for _, f := range inner {
+            if !taken[f.name] {
+                taken[f.name] = true
+                fields = append(fields, f)
+            }
+        }
+    }
+    return fields
+}
+
+// fieldByIndex follows index through v, reporting false if it passes a nil
+// embedded pointer
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+    for i, x := range index {
+        if i > 0 && v.Kind() == reflect.Pointer {
+            if v.IsNil() {
+                return reflect.Value{}, false
+            }
+            v = v.Elem()
+        }
+        v = v.Field(x)
+    }
+    return v, true
+}
+
+// isEmptyValue matches encoding/json's definition of empty for omitempty
+func isEmptyValue(v reflect.Value) bool {
+    switch v.Kind() {
+    case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+        return v.Len() == 0
+    case reflect.Bool:
+        return !v.Bool()
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        return v.Int() == 0
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+        return v.Uint() == 0
+    case reflect.Float32, reflect.Float64:
+        return v.Float() == 0
+    case reflect.Pointer, reflect.Interface:
+        return v.IsNil()
+    }
+    return false
+}
\ No newline at end of file
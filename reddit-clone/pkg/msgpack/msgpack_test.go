@@ -0,0 +1,208 @@
+// pkg/msgpack/msgpack_test.go
+package msgpack
+
+import (
+    "bytes"
+    "errors"
+    "math"
+    "reflect"
+    "strings"
+    "testing"
+    "time"
+)
+
+type inner struct {
+    Label string `json:"label"`
+}
+
+type Embedded struct {
+    Source string `json:"source"`
+}
+
+type sample struct {
+    Embedded
+    Name      string            `json:"name"`
+    Count     int               `json:"count"`
+    Negative  int64             `json:"negative"`
+    Big       uint64            `json:"big"`
+    Ratio     float64           `json:"ratio"`
+    Small     float32           `json:"small"`
+    OK        bool              `json:"ok"`
+    Data      []byte            `json:"data"`
+    Tags      []string          `json:"tags"`
+    Scores    map[string]int    `json:"scores"`
+    Child     *inner            `json:"child"`
+    Children  []inner           `json:"children"`
+    When      time.Time         `json:"when"`
+    Precise   time.Time         `json:"precise"`
+    Ancient   time.Time         `json:"ancient"`
+    Optional  string            `json:"optional,omitempty"`
+    Skipped   string            `json:"-"`
+    Anything  interface{}       `json:"anything"`
+    Labels    map[string]string `json:"labels"`
+}
+
+func TestRoundTrip(t *testing.T) {
+    in := sample{
+        Embedded: Embedded{Source: "api"},
+        Name:     strings.Repeat("n", 300),
+        Count:    70000,
+        Negative: math.MinInt64,
+        Big:      math.MaxUint64,
+        Ratio:    3.25,
+        Small:    1.5,
+        OK:       true,
+        Data:     []byte{0, 1, 2, 0xff},
+        Tags:     []string{"a", "b", "c"},
+        Scores:   map[string]int{"x": -1, "y": 200, "z": -40000},
+        Child:    &inner{Label: "child"},
+        Children: []inner{{Label: "one"}, {Label: "two"}},
+        When:     time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+        Precise:  time.Date(2024, time.March, 1, 12, 0, 0, 123456789, time.UTC),
+        Ancient:  time.Date(1900, time.January, 1, 0, 0, 0, 5, time.UTC),
+        Skipped:  "not encoded",
+        Anything: map[string]interface{}{"list": []interface{}{int64(-3), "s", nil, true}},
+    }
+
+    data, err := Marshal(in)
+    if err != nil {
+        t.Fatalf("Marshal: %v", err)
+    }
+    var out sample
+    if err := Unmarshal(data, &out); err != nil {
+        t.Fatalf("Unmarshal: %v", err)
+    }
+
+    want := in
+    want.Skipped = ""
+    if !reflect.DeepEqual(out, want) {
+        t.Errorf("round trip mismatch\n got %+v\nwant %+v", out, want)
+    }
+}
+
+func TestUnmarshalIntoInterface(t *testing.T) {
+    data, err := Marshal(map[string]interface{}{
+        "n":    -5,
+        "u":    uint(7),
+        "f":    0.5,
+        "s":    "str",
+        "b":    []byte("raw"),
+        "list": []int{1, 2},
+        "nil":  nil,
+    })
+    if err != nil {
+        t.Fatalf("Marshal: %v", err)
+    }
+    var out interface{}
+    if err := Unmarshal(data, &out); err != nil {
+        t.Fatalf("Unmarshal: %v", err)
+    }
+    want := map[string]interface{}{
+        "n":    int64(-5),
+        "u":    uint64(7),
+        "f":    0.5,
+        "s":    "str",
+        "b":    []byte("raw"),
+        "list": []interface{}{uint64(1), uint64(2)},
+        "nil":  nil,
+    }
+    if !reflect.DeepEqual(out, want) {
+        t.Errorf("got %#v, want %#v", out, want)
+    }
+}
+
+func TestUnmarshalSkipsUnknownKeys(t *testing.T) {
+    data, err := Marshal(map[string]interface{}{
+        "label":   "kept",
+        "unknown": map[string]interface{}{"deep": []interface{}{1, "two"}},
+        "stamp":   time.Unix(0, 0),
+    })
+    if err != nil {
+        t.Fatalf("Marshal: %v", err)
+    }
+    var out inner
+    if err := Unmarshal(data, &out); err != nil {
+        t.Fatalf("Unmarshal: %v", err)
+    }
+    if out.Label != "kept" {
+        t.Errorf("label = %q, want kept", out.Label)
+    }
+}
+
+func TestUnmarshalMalformed(t *testing.T) {
+    valid, err := Marshal(sample{Name: "x", Tags: []string{"a"}, When: time.Unix(1, 0)})
+    if err != nil {
+        t.Fatalf("Marshal: %v", err)
+    }
+    // Every proper prefix of a valid encoding is truncated
+    for i := 0; i < len(valid); i++ {
+        var out sample
+        if err := Unmarshal(valid[:i], &out); err == nil {
+            t.Errorf("Unmarshal of %d-byte prefix succeeded", i)
+        }
+    }
+
+    tests := []struct {
+        name string
+        data []byte
+        into interface{}
+    }{
+        {"empty", nil, new(interface{})},
+        {"reserved format byte", []byte{0xc1}, new(interface{})},
+        {"trailing data", []byte{0x01, 0x02}, new(int)},
+        {"string length past end", []byte{0xdb, 0xff, 0xff, 0xff, 0xff}, new(string)},
+        {"array length past end", []byte{0xdd, 0xff, 0xff, 0xff, 0xff}, new([]int)},
+        {"map length past end", []byte{0xdf, 0xff, 0xff, 0xff, 0xff}, new(map[string]int)},
+        {"non-string map key", []byte{0x81, 0x01, 0x02}, new(map[string]int)},
+        {"int overflow", []byte{0xcd, 0x01, 0x00}, new(int8)},
+        {"negative into uint", []byte{0xff}, new(uint)},
+        {"string into int", []byte{0xa1, 'x'}, new(int)},
+        {"bad timestamp length", []byte{0xd5, 0xff, 0, 0}, new(time.Time)},
+        {"unknown extension", []byte{0xd4, 0x05, 0x00}, new(interface{})},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if err := Unmarshal(tt.data, tt.into); err == nil {
+                t.Errorf("Unmarshal(% x) succeeded", tt.data)
+            }
+        })
+    }
+
+    if err := Unmarshal([]byte{0x01}, 1); err == nil {
+        t.Error("Unmarshal into a non-pointer succeeded")
+    }
+}
+
+func TestUnmarshalDepthLimit(t *testing.T) {
+    // One-element arrays nested far past the limit, ending in nil
+    deepArrays := append(bytes.Repeat([]byte{0x91}, 100*maxDepth), 0xc0)
+    // One-key maps, {"a": {"a": ...}}, nested the same way
+    deepMaps := append(bytes.Repeat([]byte{0x81, 0xa1, 'a'}, 2*maxDepth), 0xc0)
+
+    tests := []struct {
+        name string
+        data []byte
+        into interface{}
+    }{
+        {"arrays into interface", deepArrays, new(interface{})},
+        {"maps into interface", deepMaps, new(interface{})},
+        {"arrays into slice", deepArrays, new([]interface{})},
+        {"maps into map", deepMaps, new(map[string]interface{})},
+        // Unknown struct keys are skipped, which walks the value too
+        {"skipped field", append([]byte{0x81, 0xa1, 'z'}, deepArrays...), new(inner)},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if err := Unmarshal(tt.data, tt.into); !errors.Is(err, ErrTooDeep) {
+                t.Errorf("err = %v, want ErrTooDeep", err)
+            }
+        })
+    }
+
+    // Nesting right up to the limit still decodes
+    atLimit := append(bytes.Repeat([]byte{0x91}, maxDepth), 0xc0)
+    var out interface{}
+    if err := Unmarshal(atLimit, &out); err != nil {
+        t.Errorf("nesting %d deep: %v", maxDepth, err)
+    }
+}
\ No newline at end of file
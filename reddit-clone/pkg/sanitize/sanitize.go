@@ -0,0 +1,247 @@
+// pkg/sanitize/sanitize.go
+package sanitize
+
+import (
+    "html"
+    "regexp"
+    "strings"
+    "unicode"
+)
+
+// safeSchemes are the link schemes left alone; any other scheme, such as
+// javascript: or data:, is turned into a fragment link
+var safeSchemes = map[string]bool{
+    "http":   true,
+    "https":  true,
+    "mailto": true,
+}
+
+// referenceDefinition matches the label of a markdown link reference
+// definition, e.g. "[docs]: ", including one inside block quotes or list
+// items such as "> - [docs]: "
+var referenceDefinition = regexp.MustCompile(`^[ \t]*(?:(?:>|[-+*][ \t]|\d{1,9}[.)][ \t])[ \t]*)*\[(?:\\.|[^\\\]])+\]:`)
+
+// asciiPunctuation holds the characters a markdown backslash can escape
+const asciiPunctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// escapeTags turns every '<' into an entity, so no HTML tag, comment or
+// autolink can open. A backslash-escaped '<' already renders as text.
+var escapeTags = strings.NewReplacer(`\<`, "&lt;", "<", "&lt;")
+
+// Markdown makes user-written markdown safe to hand to a markdown renderer.
+// Raw HTML is escaped so tags and event handlers show up as text, and links
+// using a scheme other than http, https or mailto are disarmed. Emphasis,
+// lists, quotes, links and code are kept; code spans and fenced code blocks
+// are left untouched because renderers escape their contents.
+func Markdown(s string) string {
+    lines := strings.Split(s, "\n")
+    fence := ""      // Marker of the open fenced code block, if any
+    pending := false // The previous line ended before a link destination
+    for i, line := range lines {
+        if fence != "" {
+            if closesFence(line, fence) {
+                fence = ""
+            }
+            continue
+        }
+        if marker, info, ok := openFence(line); ok {
+            fence = marker
+            pending = false
+            // The info string is rendered as a class name, so it is text
+            lines[i] = line[:len(line)-len(info)] + escapeTags.Replace(info)
+            continue
+        }
+        // A destination may start on the line after its opener, behind
+        // the quote markers of the container it's in
+        if pending {
+            line = disarmDestination(line, " \t>")
+        }
+        lines[i], pending = sanitizeLine(line)
+    }
+    return strings.Join(lines, "\n")
+}
+
+// openFence reports whether line opens a fenced code block, returning the
+// fence marker and the info string after it
+func openFence(line string) (marker, info string, ok bool) {
+    trimmed := strings.TrimLeft(line, " ")
+    if len(line)-len(trimmed) > 3 || len(trimmed) < 3 {
+        return "", "", false
+    }
+    c := trimmed[0]
+    if c != '`' && c != '~' {
+        return "", "", false
+    }
+    n := 0
+    for n < len(trimmed) && trimmed[n] == c {
+        n++
+    }
+    info = trimmed[n:]
+    // A backtick fence's info string may not contain backticks; such a
+    // line is an inline code span instead
+    if n < 3 || (c == '`' && strings.Contains(info, "`")) {
+        return "", "", false
+    }
+    return trimmed[:n], info, true
+}
+
+// closesFence reports whether line closes the block opened by marker: the
+// same character repeated at least as often, with nothing else after it
+func closesFence(line, marker string) bool {
+    trimmed := strings.TrimLeft(line, " ")
+    if len(line)-len(trimmed) > 3 {
+        return false
+    }
+    n := 0
+    for n < len(trimmed) && trimmed[n] == marker[0] {
+        n++
+    }
+    return n >= len(marker) && strings.TrimRight(trimmed[n:], " \t\r") == ""
+}
+
+// sanitizeLine sanitizes one line outside code blocks, leaving its code
+// spans as written. pending reports whether the line ends with a link
+// opener whose destination would be on the next line.
+func sanitizeLine(line string) (sanitized string, pending bool) {
+    var b strings.Builder
+    if loc := referenceDefinition.FindStringIndex(line); loc != nil {
+        b.WriteString(escapeTags.Replace(line[:loc[1]]))
+        line = disarmDestination(line[loc[1]:], " \t")
+        pending = strings.TrimLeft(line, " \t\r<") == ""
+    }
+
+    text := 0 // Start of the text not yet written
+    for i := 0; i < len(line); {
+        switch line[i] {
+        case '\\':
+            // An escaped backtick doesn't open a code span
+            i += 2
+            continue
+        case '`':
+            n := runLength(line, i)
+            end := closingRun(line, i+n, n)
+            if end < 0 {
+                i += n
+                continue
+            }
+            b.WriteString(sanitizeText(line[text:i]))
+            b.WriteString(line[i : end+n])
+            i = end + n
+            text = i
+            continue
+        }
+        i++
+    }
+    if text < len(line) {
+        b.WriteString(sanitizeText(line[text:]))
+    }
+    if strings.HasSuffix(strings.TrimRight(line[text:], " \t\r<"), "](") {
+        pending = true
+    }
+    return b.String(), pending
+}
+
+// runLength counts the backticks starting at i
+func runLength(line string, i int) int {
+    n := 0
+    for i+n < len(line) && line[i+n] == '`' {
+        n++
+    }
+    return n
+}
+
+// closingRun returns the index of the next run of exactly n backticks at
+// or after from, or -1 if there is none
+func closingRun(line string, from, n int) int {
+    for i := from; i < len(line); {
+        if line[i] != '`' {
+            i++
+            continue
+        }
+        run := runLength(line, i)
+        if run == n {
+            return i
+        }
+        i += run
+    }
+    return -1
+}
+
+// sanitizeText disarms unsafe link destinations in text outside code and
+// escapes its HTML
+func sanitizeText(text string) string {
+    var b strings.Builder
+    for {
+        i := strings.Index(text, "](")
+        if i < 0 {
+            break
+        }
+        b.WriteString(text[:i+2])
+        text = disarmDestination(text[i+2:], " \t")
+    }
+    b.WriteString(text)
+    return escapeTags.Replace(b.String())
+}
+
+// disarmDestination prefixes the link destination at the start of text
+// with '#' if its scheme is unsafe. Leading characters in skip, and the
+// '<' of a bracketed destination, come before the destination.
+func disarmDestination(text, skip string) string {
+    start := len(text) - len(strings.TrimLeft(text, skip))
+    start += len(text[start:]) - len(strings.TrimLeft(text[start:], "<"))
+    if !unsafeScheme(text[start:]) {
+        return text
+    }
+    return text[:start] + "#" + text[start:]
+}
+
+// unsafeScheme reports whether a link destination starts with a scheme
+// that isn't in safeSchemes. The destination is read as a browser would
+// get it from the renderer: backslash escapes and entities such as
+// &#106; or &colon; are decoded, leading control characters and spaces
+// are dropped, and tabs and newlines inside the scheme are ignored. As in
+// a URL, a scheme starts with a letter, so "12:30" has none.
+func unsafeScheme(dest string) bool {
+    controlOrSpace := func(r rune) bool { return r <= ' ' }
+    // A destination ends at a space or closing bracket; stopping there
+    // keeps each link's check to its own text
+    dest = strings.TrimLeftFunc(dest, controlOrSpace)
+    if end := strings.IndexAny(dest, " )>"); end >= 0 {
+        dest = dest[:end]
+    }
+    dest = html.UnescapeString(unescapeBackslashes(dest))
+    dest = strings.TrimLeftFunc(dest, controlOrSpace)
+
+    var scheme strings.Builder
+    for _, r := range dest {
+        switch {
+        case r == '\t' || r == '\n' || r == '\r':
+            continue
+        case r == ':':
+            return scheme.Len() > 0 && !safeSchemes[scheme.String()]
+        case scheme.Len() == 0 && !(r < unicode.MaxASCII && unicode.IsLetter(r)):
+            return false
+        case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '-' || r == '.'):
+            scheme.WriteRune(unicode.ToLower(r))
+        default:
+            return false
+        }
+    }
+    return false
+}
+
+// unescapeBackslashes drops the backslash from each escaped ASCII
+// punctuation character, as markdown renderers do
+func unescapeBackslashes(s string) string {
+    if !strings.Contains(s, `\`) {
+        return s
+    }
+    var b strings.Builder
+    for i := 0; i < len(s); i++ {
+        if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(asciiPunctuation, s[i+1]) >= 0 {
+            i++
+        }
+        b.WriteByte(s[i])
+    }
+    return b.String()
+}
\ No newline at end of file
@@ -0,0 +1,59 @@
+// pkg/sanitize/sanitize_test.go
+package sanitize
+
+import "testing"
+
+func TestMarkdown(t *testing.T) {
+    tests := []struct {
+        name string
+        in   string
+        want string
+    }{
+        {"plain text", "Hello *world*", "Hello *world*"},
+        {"html tag", "<script>alert(1)</script>", "&lt;script>alert(1)&lt;/script>"},
+        {"safe link", "[docs](https://go.dev)", "[docs](https://go.dev)"},
+        {"relative link", "[next](/r/golang)", "[next](/r/golang)"},
+        {"javascript link", "[x](javascript:alert(1))", "[x](#javascript:alert(1))"},
+        {"uppercase scheme", "[x](JavaScript:alert(1))", "[x](#JavaScript:alert(1))"},
+        {"tab in scheme", "[x](java\tscript:alert(1))", "[x](#java\tscript:alert(1))"},
+        {"entity colon", "[x](javascript&colon;alert(1))", "[x](#javascript&colon;alert(1))"},
+        {"entity letter", "[x](&#106;avascript:alert(1))", "[x](#&#106;avascript:alert(1))"},
+        {"hex entity letter", "[x](&#x6A;avascript:alert(1))", "[x](#&#x6A;avascript:alert(1))"},
+        {"escaped colon", `[x](javascript\:alert(1))`, `[x](#javascript\:alert(1))`},
+        {"leading control", "[x](&#1;javascript:alert(1))", "[x](#&#1;javascript:alert(1))"},
+        {"bracketed", "[x](<javascript:alert(1)>)", "[x](&lt;#javascript:alert(1)>)"},
+        {"bracketed with space", "[x](< javascript:alert(1)>)", "[x](&lt;# javascript:alert(1)>)"},
+        {"image", "![x](data:text/html,hi)", "![x](#data:text/html,hi)"},
+        {"destination on next line", "[x](\njavascript:alert(1))", "[x](\n#javascript:alert(1))"},
+        {"reference definition", "[a]: javascript:alert(1)", "[a]: #javascript:alert(1)"},
+        {"reference in quote", "> [a]: javascript:alert(1)", "> [a]: #javascript:alert(1)"},
+        {"reference in list", "- [a]: javascript:alert(1)", "- [a]: #javascript:alert(1)"},
+        {"reference in nested list", "> 1. [a]: javascript:alert(1)", "> 1. [a]: #javascript:alert(1)"},
+        {"reference on next line", "> [a]:\n> javascript:alert(1)", "> [a]:\n> #javascript:alert(1)"},
+        {"reference with escaped bracket", `[a\]]: javascript:alert(1)`, `[a\]]: #javascript:alert(1)`},
+        {"time is not a scheme", "[Meeting]: 12:30 today", "[Meeting]: 12:30 today"},
+        {"code span kept", "`<b>` and `[x](javascript:y)`", "`<b>` and `[x](javascript:y)`"},
+        {"fenced code kept", "```html\n<b>[x](javascript:y)</b>\n```\n<i>", "```html\n<b>[x](javascript:y)</b>\n```\n&lt;i>"},
+        {"fence info escaped", "```<img>\ncode\n```", "```&lt;img>\ncode\n```"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Markdown(tt.in); got != tt.want {
+                t.Errorf("Markdown(%q) = %q, want %q", tt.in, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestMarkdownIsIdempotent(t *testing.T) {
+    for _, in := range []string{
+        "<b>bold</b> [x](javascript:alert(1))",
+        "> [a]: &#106;avascript:alert(1)",
+        "[x](\njavascript:alert(1))",
+    } {
+        once := Markdown(in)
+        if twice := Markdown(once); twice != once {
+            t.Errorf("Markdown(Markdown(%q)) = %q, want %q", in, twice, once)
+        }
+    }
+}
\ No newline at end of file
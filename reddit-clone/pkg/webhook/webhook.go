@@ -0,0 +1,217 @@
+// pkg/webhook/webhook.go
+package webhook
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "log"
+    "net/http"
+    "time"
+
+    "reddit-clone/internal/models"
+)
+
+const (
+    DefaultQueueSize      = 256                    // Deliveries waiting before the oldest is dropped
+    DefaultWorkers        = 4                      // Concurrent deliveries
+    DefaultMaxAttempts    = 4                      // Tries per delivery, including the first
+    DefaultInitialBackoff = 500 * time.Millisecond // Wait before the first retry, doubled after each
+    DefaultTimeout        = 5 * time.Second        // Per-attempt HTTP timeout
+)
+
+// Event names sent in the payload and the X-Webhook-Event header
+const (
+    EventPostCreated    = "post_created"
+    EventCommentCreated = "comment_created"
+)
+
+// Payload is the JSON body posted to a webhook URL
+type Payload struct {
+    Event       string          `json:"event"`
+    SubredditID string          `json:"subreddit_id"`
+    Post        *models.Post    `json:"post,omitempty"`
+    Comment     *models.Comment `json:"comment,omitempty"`
+    SentAt      time.Time       `json:"sent_at"`
+}
+
+// Source looks up webhook registrations; the engine satisfies it
+type Source interface {
+    GetWebhooks(subredditID string) []*models.Webhook
+    GetPost(postID string) (*models.Post, error)
+}
+
+// Options tunes delivery; zero fields take the defaults
+type Options struct {
+    QueueSize      int
+    Workers        int
+    MaxAttempts    int
+    InitialBackoff time.Duration
+    Client         *http.Client
+}
+
+// delivery is one payload bound for one webhook URL
+type delivery struct {
+    url   string
+    event string
+    body  []byte
+}
+
+// Dispatcher is an engine listener that posts new posts and comments to
+// their subreddit's webhooks. Deliveries are queued and sent by Run's
+// workers, so a slow endpoint never holds up posting; when the queue is
+// full the oldest delivery is dropped.
+type Dispatcher struct {
+    source Source
+    opts   Options
+    queue  chan delivery
+}
+
+// NewDispatcher creates a dispatcher; register it with the engine's
+// AddListener and start it with Run
+func NewDispatcher(source Source, opts Options) *Dispatcher {
+    if opts.QueueSize <= 0 {
+        opts.QueueSize = DefaultQueueSize
+    }
+    if opts.Workers <= 0 {
+        opts.Workers = DefaultWorkers
+    }
+    if opts.MaxAttempts <= 0 {
+        opts.MaxAttempts = DefaultMaxAttempts
+    }
+    if opts.InitialBackoff <= 0 {
+        opts.InitialBackoff = DefaultInitialBackoff
+    }
+    if opts.Client == nil {
+        opts.Client = &http.Client{Timeout: DefaultTimeout}
+    }
+    return &Dispatcher{
+        source: source,
+        opts:   opts,
+        queue:  make(chan delivery, opts.QueueSize),
+    }
+}
+
+// Run delivers queued payloads until the context is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+    done := make(chan struct{})
+    for i := 0; i < d.opts.Workers; i++ {
+        go func() {
+            defer func() { done <- struct{}{} }()
+            for {
+                select {
+                case <-ctx.Done():
+                    return
+                case del := <-d.queue:
+                    d.deliver(ctx, del)
+                }
+            }
+        }()
+    }
+    for i := 0; i < d.opts.Workers; i++ {
+        <-done
+    }
+}
+
+// OnPostCreated queues the post for its subreddit's webhooks
+func (d *Dispatcher) OnPostCreated(post models.Post) {
+    d.publish(post.SubRedditID, Payload{
+        Event:       EventPostCreated,
+        SubredditID: post.SubRedditID,
+        Post:        &post,
+    })
+}
+
+// OnComment queues the comment for the webhooks of its post's subreddit
+func (d *Dispatcher) OnComment(comment models.Comment) {
+    post, err := d.source.GetPost(comment.PostID)
+    if err != nil {
+        return
+    }
+    d.publish(post.SubRedditID, Payload{
+        Event:       EventCommentCreated,
+        SubredditID: post.SubRedditID,
+        Comment:     &comment,
+    })
+}
+
+func (d *Dispatcher) OnUserRegistered(models.User) {}
+func (d *Dispatcher) OnVote(models.Vote)           {}
+
+// publish encodes the payload once and queues it for each of the subreddit's webhooks
+func (d *Dispatcher) publish(subredditID string, payload Payload) {
+    webhooks := d.source.GetWebhooks(subredditID)
+    if len(webhooks) == 0 {
+        return
+    }
+
+    payload.SentAt = time.Now()
+    body, err := json.Marshal(payload)
+    if err != nil {
+        log.Printf("webhook: encoding %s payload: %v", payload.Event, err)
+        return
+    }
+    for _, webhook := range webhooks {
+        d.enqueue(delivery{url: webhook.URL, event: payload.Event, body: body})
+    }
+}
+
+// enqueue adds a delivery, dropping the oldest queued one to make room
+func (d *Dispatcher) enqueue(del delivery) {
+    for {
+        select {
+        case d.queue <- del:
+            return
+        default:
+        }
+        select {
+        case old := <-d.queue:
+            log.Printf("webhook: queue full, dropping %s delivery to %s", old.event, old.url)
+        default:
+        }
+    }
+}
+
+// deliver posts the payload, retrying with exponential backoff on errors
+// and non-2xx responses
+func (d *Dispatcher) deliver(ctx context.Context, del delivery) {
+    backoff := d.opts.InitialBackoff
+    var err error
+    for attempt := 1; attempt <= d.opts.MaxAttempts; attempt++ {
+        if err = d.post(ctx, del); err == nil {
+            return
+        }
+        if attempt == d.opts.MaxAttempts {
+            break
+        }
+
+        select {
+        case <-ctx.Done():
+            return
+        case <-time.After(backoff):
+        }
+        backoff *= 2
+    }
+    log.Printf("webhook: giving up on %s delivery to %s after %d attempts: %v", del.event, del.url, d.opts.MaxAttempts, err)
+}
+
+// post makes a single delivery attempt
+func (d *Dispatcher) post(ctx context.Context, del delivery) error {
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, del.url, bytes.NewReader(del.body))
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("X-Webhook-Event", del.event)
+
+    resp, err := d.opts.Client.Do(req)
+    if err != nil {
+        return err
+    }
+    resp.Body.Close()
+    if resp.StatusCode < 200 || resp.StatusCode > 299 {
+        return fmt.Errorf("unexpected status %s", resp.Status)
+    }
+    return nil
+}
\ No newline at end of file
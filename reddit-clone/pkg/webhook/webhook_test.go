@@ -0,0 +1,83 @@
+// pkg/webhook/webhook_test.go
+package webhook
+
+import (
+    "context"
+    "encoding/json"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "reddit-clone/internal/engine"
+)
+
+// received is one request seen by the test receiver
+type received struct {
+    event   string
+    payload Payload
+}
+
+func TestDispatcherDeliversNewPostsWithRetry(t *testing.T) {
+    // The receiver fails the first attempt, so the delivery has to be retried
+    var attempts atomic.Int32
+    deliveries := make(chan received, 8)
+    receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if attempts.Add(1) == 1 {
+            w.WriteHeader(http.StatusServiceUnavailable)
+            return
+        }
+        body, _ := io.ReadAll(r.Body)
+        var payload Payload
+        if err := json.Unmarshal(body, &payload); err != nil {
+            t.Errorf("decoding payload: %v", err)
+        }
+        deliveries <- received{event: r.Header.Get("X-Webhook-Event"), payload: payload}
+    }))
+    defer receiver.Close()
+
+    cfg := engine.NewDefaultConfig()
+    cfg.PostCooldown = 0
+    e := engine.NewRedditEngineWithConfig(cfg)
+    dispatcher := NewDispatcher(e, Options{InitialBackoff: 10 * time.Millisecond})
+    e.AddListener(dispatcher)
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    go dispatcher.Run(ctx)
+
+    alice, err := e.RegisterAccount("alice", "password123")
+    if err != nil {
+        t.Fatalf("RegisterAccount: %v", err)
+    }
+    hooked, _ := e.CreateSubReddit("golang", "Go", alice.ID)
+    quiet, _ := e.CreateSubReddit("rust", "Rust", alice.ID)
+    if _, err := e.AddWebhook(alice.ID, hooked.ID, receiver.URL); err != nil {
+        t.Fatalf("AddWebhook: %v", err)
+    }
+
+    // A post in a subreddit without webhooks sends nothing
+    if _, err := e.CreatePost("Unhooked", "Test content", alice.ID, quiet.ID); err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+    post, err := e.CreatePost("Hooked", "Test content", alice.ID, hooked.ID)
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+
+    select {
+    case got := <-deliveries:
+        if got.event != EventPostCreated || got.payload.Event != EventPostCreated {
+            t.Errorf("event = %q / %q, want %q", got.event, got.payload.Event, EventPostCreated)
+        }
+        if got.payload.SubredditID != hooked.ID || got.payload.Post == nil || got.payload.Post.ID != post.ID {
+            t.Errorf("payload = %+v, want post %s in %s", got.payload, post.ID, hooked.ID)
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("timed out waiting for the webhook delivery")
+    }
+    if n := attempts.Load(); n != 2 {
+        t.Errorf("receiver saw %d attempts, want 2", n)
+    }
+}
\ No newline at end of file